@@ -0,0 +1,124 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package deliverycapture
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+const DefaultMaxMessages = 1000
+
+// DefaultInbox is used by the capture services and the devinbox recipe if no inbox is passed to them
+var DefaultInbox = MakeInbox(DefaultMaxMessages)
+
+// MakeInbox returns an empty inbox. Once it has maxMessages messages, the oldest ones are dropped.
+func MakeInbox(maxMessages int) *Inbox {
+	if maxMessages <= 0 {
+		maxMessages = DefaultMaxMessages
+	}
+	return &Inbox{
+		messages:    []Message{},
+		maxMessages: maxMessages,
+	}
+}
+
+// GetInbox returns inbox, or DefaultInbox if it is nil
+func GetInbox(inbox *Inbox) *Inbox {
+	if inbox == nil {
+		return DefaultInbox
+	}
+	return inbox
+}
+
+// Add stores the message and returns it with its ID and CreatedAt set
+func (inbox *Inbox) Add(message Message) Message {
+	inbox.mutex.Lock()
+	defer inbox.mutex.Unlock()
+
+	inbox.lastID++
+	message.ID = strconv.FormatUint(inbox.lastID, 10)
+	message.CreatedAt = time.Now().UnixMilli()
+	if message.Links == nil {
+		message.Links = []string{}
+	}
+	if message.Codes == nil {
+		message.Codes = []string{}
+	}
+
+	inbox.messages = append(inbox.messages, message)
+	if len(inbox.messages) > inbox.maxMessages {
+		inbox.messages = inbox.messages[len(inbox.messages)-inbox.maxMessages:]
+	}
+	return message
+}
+
+// GetMessages returns the messages sent to recipient (or all messages if it is nil), newest first
+func (inbox *Inbox) GetMessages(recipient *string) []Message {
+	inbox.mutex.Lock()
+	defer inbox.mutex.Unlock()
+
+	result := []Message{}
+	for i := len(inbox.messages) - 1; i >= 0; i-- {
+		if recipient == nil || isSameRecipient(inbox.messages[i].Recipient, *recipient) {
+			result = append(result, inbox.messages[i])
+		}
+	}
+	return result
+}
+
+// GetLatestMessage returns the last message sent to recipient, or nil if there is none
+func (inbox *Inbox) GetLatestMessage(recipient string) *Message {
+	messages := inbox.GetMessages(&recipient)
+	if len(messages) == 0 {
+		return nil
+	}
+	return &messages[0]
+}
+
+// Clear removes the messages sent to recipient, or all messages if it is nil
+func (inbox *Inbox) Clear(recipient *string) {
+	inbox.mutex.Lock()
+	defer inbox.mutex.Unlock()
+
+	if recipient == nil {
+		inbox.messages = []Message{}
+		return
+	}
+	remaining := []Message{}
+	for _, message := range inbox.messages {
+		if !isSameRecipient(message.Recipient, *recipient) {
+			remaining = append(remaining, message)
+		}
+	}
+	inbox.messages = remaining
+}
+
+func isSameRecipient(a string, b string) bool {
+	return normaliseRecipient(a) == normaliseRecipient(b)
+}
+
+// normaliseRecipient makes emails case insensitive and ignores formatting in phone numbers.
+// The + is ignored as well, since it is decoded as a space if it is not escaped in a query param.
+func normaliseRecipient(recipient string) string {
+	recipient = strings.ToLower(strings.TrimSpace(recipient))
+	if !strings.Contains(recipient, "@") {
+		recipient = strings.NewReplacer(" ", "", "-", "", "(", "", ")", "", "+", "").Replace(recipient)
+	}
+	return recipient
+}
@@ -0,0 +1,55 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package deliverycapture
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInboxDropsOldestMessages(t *testing.T) {
+	inbox := MakeInbox(2)
+	inbox.Add(Message{Type: EmailMessage, Recipient: "a@example.com", Body: "1"})
+	inbox.Add(Message{Type: EmailMessage, Recipient: "a@example.com", Body: "2"})
+	inbox.Add(Message{Type: EmailMessage, Recipient: "b@example.com", Body: "3"})
+
+	messages := inbox.GetMessages(nil)
+	assert.Len(t, messages, 2)
+	assert.Equal(t, "3", messages[0].Body)
+	assert.Equal(t, "2", messages[1].Body)
+	assert.Equal(t, "3", messages[0].ID)
+	assert.Equal(t, []string{}, messages[0].Links)
+}
+
+func TestInboxMatchesRecipients(t *testing.T) {
+	inbox := MakeInbox(10)
+	inbox.Add(Message{Type: EmailMessage, Recipient: "Test@Example.com", Body: "email"})
+	inbox.Add(Message{Type: SmsMessage, Recipient: "+1 (202) 555-0123", Body: "sms"})
+
+	assert.Equal(t, "email", inbox.GetLatestMessage("test@example.com").Body)
+	assert.Equal(t, "sms", inbox.GetLatestMessage("+12025550123").Body)
+	assert.Nil(t, inbox.GetLatestMessage("other@example.com"))
+
+	recipient := "TEST@example.com"
+	inbox.Clear(&recipient)
+	assert.Nil(t, inbox.GetLatestMessage("test@example.com"))
+	assert.Len(t, inbox.GetMessages(nil), 1)
+
+	inbox.Clear(nil)
+	assert.Len(t, inbox.GetMessages(nil), 0)
+}
@@ -0,0 +1,52 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package deliverycapture
+
+import "sync"
+
+type MessageType string
+
+const (
+	EmailMessage MessageType = "EMAIL"
+	SmsMessage   MessageType = "SMS"
+)
+
+type Message struct {
+	ID        string      `json:"id"`
+	Type      MessageType `json:"type"`
+	Recipient string      `json:"recipient"`
+	TenantId  string      `json:"tenantId"`
+	Subject   string      `json:"subject,omitempty"`
+	Body      string      `json:"body"`
+	TextBody  string      `json:"textBody,omitempty"`
+	IsHtml    bool        `json:"isHtml"`
+	// Links and Codes are taken from the input of the email or SMS (for example the password
+	// reset link or the passwordless OTP), so they do not depend on how the body is rendered
+	Links []string `json:"links"`
+	Codes []string `json:"codes"`
+	// CreatedAt is in milliseconds since epoch
+	CreatedAt int64 `json:"createdAt"`
+}
+
+// Inbox keeps the messages sent by the capture services in memory. It is meant to be used
+// in development and end-to-end tests only.
+type Inbox struct {
+	mutex       sync.Mutex
+	messages    []Message
+	maxMessages int
+	lastID      uint64
+}
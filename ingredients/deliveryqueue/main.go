@@ -0,0 +1,214 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package deliveryqueue
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+// Queue sends messages in the background, retrying failed attempts with exponential backoff
+type Queue[T any] struct {
+	send   func(input T, userContext supertokens.UserContext) error
+	config typeNormalisedInput[T]
+
+	// mutex guards stopped, so that no job is added to waitGroup once Stop is waiting for it
+	mutex     sync.Mutex
+	stopped   bool
+	stop      chan struct{}
+	waitGroup sync.WaitGroup
+}
+
+// MakeQueue creates a queue that uses send to deliver the messages. Jobs left in the store
+// by a previous queue are resumed. Since messages are sent after the API request is done,
+// send is called with a new user context. Its request only has the KeptHeaders of the request
+// that enqueued the message, and other values of the original user context are not available.
+func MakeQueue[T any](send func(input T, userContext supertokens.UserContext) error, config *TypeInput[T]) (*Queue[T], error) {
+	queue := &Queue[T]{
+		send:   send,
+		config: validateAndNormaliseUserInput(config),
+		stop:   make(chan struct{}),
+	}
+
+	jobs, err := queue.config.store.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	for _, job := range jobs {
+		supertokens.LogDebugMessage("deliveryqueue: resuming job " + job.ID)
+		queue.waitGroup.Add(1)
+		queue.process(job)
+	}
+
+	return queue, nil
+}
+
+// Enqueue adds the message to the queue, keeping the KeptHeaders of the request in the user
+// context. It returns an error if the queue was stopped or if the job could not be stored.
+func (q *Queue[T]) Enqueue(input T, userContext supertokens.UserContext) (Job[T], error) {
+	q.mutex.Lock()
+	if q.stopped {
+		q.mutex.Unlock()
+		return Job[T]{}, errors.New("delivery queue has been stopped")
+	}
+	q.waitGroup.Add(1)
+	q.mutex.Unlock()
+
+	now := time.Now()
+	job := Job[T]{
+		ID:            uuid.NewString(),
+		Input:         input,
+		CreatedAt:     now,
+		NextAttemptAt: now,
+		Headers:       q.getKeptHeaders(userContext),
+	}
+	err := q.config.store.Save(job)
+	if err != nil {
+		q.waitGroup.Done()
+		return Job[T]{}, err
+	}
+	q.notifyStatusChange(job, StatusQueued)
+	q.process(job)
+	return job, nil
+}
+
+// Stop stops the queue and waits for attempts that are in progress. Jobs that have not been
+// sent yet are kept in the store, so they are resumed by the next queue using the same store.
+// Enqueue returns an error once Stop was called.
+func (q *Queue[T]) Stop() {
+	q.mutex.Lock()
+	if !q.stopped {
+		q.stopped = true
+		close(q.stop)
+	}
+	q.mutex.Unlock()
+	q.waitGroup.Wait()
+}
+
+// Wait blocks until all the jobs added to the queue are either sent or failed. It must not be
+// called while messages are being enqueued.
+func (q *Queue[T]) Wait() {
+	q.waitGroup.Wait()
+}
+
+// process sends the job in a new goroutine. The caller must have added it to waitGroup.
+func (q *Queue[T]) process(job Job[T]) {
+	go func() {
+		defer q.waitGroup.Done()
+		for {
+			timer := time.NewTimer(time.Until(job.NextAttemptAt))
+			select {
+			case <-q.stop:
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+
+			err := q.send(job.Input, makeUserContext(job))
+			job.Attempts++
+
+			if err == nil {
+				supertokens.LogDebugMessage(fmt.Sprintf("deliveryqueue: job %s sent after %d attempt(s)", job.ID, job.Attempts))
+				q.deleteJob(job)
+				q.notifyStatusChange(job, StatusSent)
+				return
+			}
+
+			job.LastError = err.Error()
+			if job.Attempts >= q.config.maxAttempts {
+				supertokens.LogDebugMessage(fmt.Sprintf("deliveryqueue: job %s failed after %d attempt(s): %s", job.ID, job.Attempts, err.Error()))
+				q.deleteJob(job)
+				q.notifyStatusChange(job, StatusFailed)
+				if q.config.onDeadLetter != nil {
+					q.config.onDeadLetter(job, err)
+				}
+				return
+			}
+
+			job.NextAttemptAt = time.Now().Add(q.getBackoff(job.Attempts))
+			supertokens.LogDebugMessage(fmt.Sprintf("deliveryqueue: attempt %d for job %s failed, retrying at %s: %s", job.Attempts, job.ID, job.NextAttemptAt.Format(time.RFC3339), err.Error()))
+			saveErr := q.config.store.Save(job)
+			if saveErr != nil {
+				supertokens.LogDebugMessage("deliveryqueue: could not update job " + job.ID + ": " + saveErr.Error())
+			}
+			q.notifyStatusChange(job, StatusRetrying)
+		}
+	}()
+}
+
+func (q *Queue[T]) getKeptHeaders(userContext supertokens.UserContext) map[string]string {
+	req := supertokens.GetRequestFromUserContext(userContext)
+	if req == nil {
+		return nil
+	}
+	headers := map[string]string{}
+	for _, name := range q.config.keptHeaders {
+		if value := req.Header.Get(name); value != "" {
+			headers[name] = value
+		}
+	}
+	return headers
+}
+
+// makeUserContext returns the user context for sending the job, with a request that has the
+// headers kept from the request that enqueued it
+func makeUserContext[T any](job Job[T]) supertokens.UserContext {
+	if len(job.Headers) == 0 {
+		return &map[string]interface{}{}
+	}
+	req, err := http.NewRequest(http.MethodGet, "/", nil)
+	if err != nil {
+		return &map[string]interface{}{}
+	}
+	for name, value := range job.Headers {
+		req.Header.Set(name, value)
+	}
+	return supertokens.MakeDefaultUserContextFromAPI(req)
+}
+
+func (q *Queue[T]) getBackoff(attempts int) time.Duration {
+	backoff := q.config.initialBackoff
+	for i := 1; i < attempts; i++ {
+		backoff *= 2
+		if backoff >= q.config.maxBackoff {
+			return q.config.maxBackoff
+		}
+	}
+	if backoff > q.config.maxBackoff {
+		return q.config.maxBackoff
+	}
+	return backoff
+}
+
+func (q *Queue[T]) deleteJob(job Job[T]) {
+	err := q.config.store.Delete(job.ID)
+	if err != nil {
+		supertokens.LogDebugMessage("deliveryqueue: could not delete job " + job.ID + ": " + err.Error())
+	}
+}
+
+func (q *Queue[T]) notifyStatusChange(job Job[T], status Status) {
+	if q.config.onStatusChange != nil {
+		q.config.onStatusChange(job, status)
+	}
+}
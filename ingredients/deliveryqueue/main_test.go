@@ -0,0 +1,227 @@
+package deliveryqueue
+
+import (
+	"errors"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+type statusRecorder struct {
+	mutex    sync.Mutex
+	statuses []Status
+}
+
+func (r *statusRecorder) record(job Job[string], status Status) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.statuses = append(r.statuses, status)
+}
+
+func (r *statusRecorder) get() []Status {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return append([]Status{}, r.statuses...)
+}
+
+func makeTestConfig(recorder *statusRecorder, maxAttempts int, store *Store[string]) *TypeInput[string] {
+	backoff := 10 * time.Millisecond
+	return &TypeInput[string]{
+		Store:          store,
+		MaxAttempts:    &maxAttempts,
+		InitialBackoff: &backoff,
+		MaxBackoff:     &backoff,
+		OnStatusChange: recorder.record,
+	}
+}
+
+func TestQueueSendsMessages(t *testing.T) {
+	recorder := &statusRecorder{}
+	sent := make(chan string, 1)
+	queue, err := MakeQueue(func(input string, userContext supertokens.UserContext) error {
+		sent <- input
+		return nil
+	}, makeTestConfig(recorder, 3, nil))
+	assert.NoError(t, err)
+
+	job, err := queue.Enqueue("hello", &map[string]interface{}{})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, job.ID)
+
+	queue.Wait()
+	assert.Equal(t, "hello", <-sent)
+	assert.Equal(t, []Status{StatusQueued, StatusSent}, recorder.get())
+}
+
+func TestQueueRetriesFailedAttempts(t *testing.T) {
+	recorder := &statusRecorder{}
+	attempts := 0
+	queue, err := MakeQueue(func(input string, userContext supertokens.UserContext) error {
+		attempts++
+		if attempts < 3 {
+			return errors.New("temporary error")
+		}
+		return nil
+	}, makeTestConfig(recorder, 5, nil))
+	assert.NoError(t, err)
+
+	_, err = queue.Enqueue("hello", &map[string]interface{}{})
+	assert.NoError(t, err)
+
+	queue.Wait()
+	assert.Equal(t, 3, attempts)
+	assert.Equal(t, []Status{StatusQueued, StatusRetrying, StatusRetrying, StatusSent}, recorder.get())
+}
+
+func TestQueueCallsDeadLetterHook(t *testing.T) {
+	recorder := &statusRecorder{}
+	store := MakeInMemoryStore[string]()
+	config := makeTestConfig(recorder, 2, &store)
+
+	var deadLetterJob *Job[string]
+	var deadLetterErr error
+	config.OnDeadLetter = func(job Job[string], err error) {
+		deadLetterJob = &job
+		deadLetterErr = err
+	}
+
+	queue, err := MakeQueue(func(input string, userContext supertokens.UserContext) error {
+		return errors.New("permanent error")
+	}, config)
+	assert.NoError(t, err)
+
+	_, err = queue.Enqueue("hello", &map[string]interface{}{})
+	assert.NoError(t, err)
+
+	queue.Wait()
+	assert.Equal(t, []Status{StatusQueued, StatusRetrying, StatusFailed}, recorder.get())
+	assert.NotNil(t, deadLetterJob)
+	assert.Equal(t, "hello", deadLetterJob.Input)
+	assert.Equal(t, 2, deadLetterJob.Attempts)
+	assert.Equal(t, "permanent error", deadLetterJob.LastError)
+	assert.EqualError(t, deadLetterErr, "permanent error")
+
+	jobs, err := store.GetAll()
+	assert.NoError(t, err)
+	assert.Len(t, jobs, 0)
+}
+
+func TestQueueResumesJobsFromStore(t *testing.T) {
+	store := MakeInMemoryStore[string]()
+	longBackoff := time.Hour
+	maxAttempts := 5
+
+	queue, err := MakeQueue(func(input string, userContext supertokens.UserContext) error {
+		return errors.New("service is down")
+	}, &TypeInput[string]{
+		Store:          &store,
+		MaxAttempts:    &maxAttempts,
+		InitialBackoff: &longBackoff,
+	})
+	assert.NoError(t, err)
+
+	_, err = queue.Enqueue("hello", &map[string]interface{}{})
+	assert.NoError(t, err)
+
+	// Wait for the first attempt to fail
+	for i := 0; i < 100; i++ {
+		jobs, _ := store.GetAll()
+		if len(jobs) == 1 && jobs[0].Attempts == 1 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	queue.Stop()
+
+	_, err = queue.Enqueue("world", &map[string]interface{}{})
+	assert.Error(t, err)
+
+	jobs, err := store.GetAll()
+	assert.NoError(t, err)
+	assert.Len(t, jobs, 1)
+	assert.Equal(t, 1, jobs[0].Attempts)
+
+	// The job is retried by a new queue using the same store
+	jobs[0].NextAttemptAt = time.Now()
+	assert.NoError(t, store.Save(jobs[0]))
+
+	sent := []string{}
+	recorder := &statusRecorder{}
+	newQueue, err := MakeQueue(func(input string, userContext supertokens.UserContext) error {
+		sent = append(sent, input)
+		return nil
+	}, makeTestConfig(recorder, 5, &store))
+	assert.NoError(t, err)
+
+	newQueue.Wait()
+	assert.Equal(t, []string{"hello"}, sent)
+	assert.Equal(t, []Status{StatusSent}, recorder.get())
+}
+
+func TestQueueStopWhileEnqueueing(t *testing.T) {
+	store := MakeInMemoryStore[string]()
+	queue, err := MakeQueue(func(input string, userContext supertokens.UserContext) error {
+		return nil
+	}, &TypeInput[string]{
+		Store: &store,
+	})
+	assert.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// jobs enqueued before Stop are either sent or kept in the store
+			_, _ = queue.Enqueue("hello", &map[string]interface{}{})
+		}()
+	}
+	queue.Stop()
+	wg.Wait()
+
+	_, err = queue.Enqueue("world", &map[string]interface{}{})
+	assert.EqualError(t, err, "delivery queue has been stopped")
+	queue.Stop()
+}
+
+func TestQueueBackoff(t *testing.T) {
+	initialBackoff := time.Second
+	maxBackoff := 5 * time.Second
+	queue, err := MakeQueue(func(input string, userContext supertokens.UserContext) error {
+		return nil
+	}, &TypeInput[string]{
+		InitialBackoff: &initialBackoff,
+		MaxBackoff:     &maxBackoff,
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, time.Second, queue.getBackoff(1))
+	assert.Equal(t, 2*time.Second, queue.getBackoff(2))
+	assert.Equal(t, 4*time.Second, queue.getBackoff(3))
+	assert.Equal(t, 5*time.Second, queue.getBackoff(4))
+	assert.Equal(t, 5*time.Second, queue.getBackoff(20))
+}
+
+func TestSendGetsTheKeptHeadersOfTheRequest(t *testing.T) {
+	recorder := &statusRecorder{}
+	acceptLanguage := make(chan string, 1)
+	queue, err := MakeQueue(func(input string, userContext supertokens.UserContext) error {
+		acceptLanguage <- supertokens.GetRequestFromUserContext(userContext).Header.Get("Accept-Language")
+		return nil
+	}, makeTestConfig(recorder, 3, nil))
+	assert.NoError(t, err)
+
+	req := httptest.NewRequest("POST", "/auth/signup", nil)
+	req.Header.Set("Accept-Language", "de-CH")
+	req.Header.Set("Authorization", "Bearer token")
+	job, err := queue.Enqueue("hello", supertokens.MakeDefaultUserContextFromAPI(req))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"Accept-Language": "de-CH"}, job.Headers)
+
+	queue.Wait()
+	assert.Equal(t, "de-CH", <-acceptLanguage)
+}
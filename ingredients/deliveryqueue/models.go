@@ -0,0 +1,86 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package deliveryqueue
+
+import (
+	"time"
+)
+
+type Status string
+
+const (
+	// StatusQueued is used when a message is added to the queue
+	StatusQueued Status = "QUEUED"
+	// StatusRetrying is used when sending a message failed and it will be retried
+	StatusRetrying Status = "RETRYING"
+	// StatusSent is used when a message was sent successfully
+	StatusSent Status = "SENT"
+	// StatusFailed is used when a message could not be sent after all the attempts.
+	// The message is then passed to the dead-letter hook and removed from the store.
+	StatusFailed Status = "FAILED"
+)
+
+type Job[T any] struct {
+	ID            string
+	Input         T
+	Attempts      int
+	LastError     string
+	CreatedAt     time.Time
+	NextAttemptAt time.Time
+	// Headers are the headers of the request that enqueued the job which are listed in
+	// KeptHeaders. They are set on the request in the user context passed to send.
+	Headers map[string]string
+}
+
+// Store persists the jobs that have not been sent yet, so that they can be resumed
+// when the queue is created again (for example after a restart).
+type Store[T any] struct {
+	// Save inserts the job, or updates it if a job with the same ID exists
+	Save   func(job Job[T]) error
+	Delete func(jobId string) error
+	// GetAll returns all the jobs in the store
+	GetAll func() ([]Job[T], error)
+}
+
+type TypeInput[T any] struct {
+	// Store defaults to an in-memory store
+	Store *Store[T]
+	// MaxAttempts is the number of times sending a message is tried, including the first attempt. Defaults to 5.
+	MaxAttempts *int
+	// InitialBackoff is the delay before the first retry, which is doubled for every following retry. Defaults to 1 second.
+	InitialBackoff *time.Duration
+	// MaxBackoff is the maximum delay between two attempts. Defaults to 5 minutes.
+	MaxBackoff *time.Duration
+	// OnStatusChange is called every time the status of a job changes
+	OnStatusChange func(job Job[T], status Status)
+	// OnDeadLetter is called with the last error when a job failed all its attempts
+	OnDeadLetter func(job Job[T], err error)
+	// KeptHeaders are the headers of the request that are kept with the job, so that send can
+	// read them from the request in its user context. Defaults to Accept-Language, which is used
+	// to choose the locale of the email templates.
+	KeptHeaders []string
+}
+
+type typeNormalisedInput[T any] struct {
+	store          Store[T]
+	maxAttempts    int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	onStatusChange func(job Job[T], status Status)
+	onDeadLetter   func(job Job[T], err error)
+	keptHeaders    []string
+}
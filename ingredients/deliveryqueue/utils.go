@@ -0,0 +1,87 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package deliveryqueue
+
+import (
+	"sync"
+	"time"
+)
+
+func validateAndNormaliseUserInput[T any](config *TypeInput[T]) typeNormalisedInput[T] {
+	result := typeNormalisedInput[T]{
+		store:          MakeInMemoryStore[T](),
+		maxAttempts:    5,
+		initialBackoff: time.Second,
+		maxBackoff:     5 * time.Minute,
+		keptHeaders:    []string{"Accept-Language"},
+	}
+
+	if config == nil {
+		return result
+	}
+
+	if config.Store != nil {
+		result.store = *config.Store
+	}
+	if config.MaxAttempts != nil && *config.MaxAttempts > 0 {
+		result.maxAttempts = *config.MaxAttempts
+	}
+	if config.InitialBackoff != nil {
+		result.initialBackoff = *config.InitialBackoff
+	}
+	if config.MaxBackoff != nil {
+		result.maxBackoff = *config.MaxBackoff
+	}
+	result.onStatusChange = config.OnStatusChange
+	result.onDeadLetter = config.OnDeadLetter
+	if config.KeptHeaders != nil {
+		result.keptHeaders = config.KeptHeaders
+	}
+
+	return result
+}
+
+// MakeInMemoryStore creates a store that keeps the jobs in memory. Jobs are lost if the
+// process exits, so a persistent store should be used if messages must not be lost.
+func MakeInMemoryStore[T any]() Store[T] {
+	var mutex sync.Mutex
+	jobs := map[string]Job[T]{}
+
+	return Store[T]{
+		Save: func(job Job[T]) error {
+			mutex.Lock()
+			defer mutex.Unlock()
+			jobs[job.ID] = job
+			return nil
+		},
+		Delete: func(jobId string) error {
+			mutex.Lock()
+			defer mutex.Unlock()
+			delete(jobs, jobId)
+			return nil
+		},
+		GetAll: func() ([]Job[T], error) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			result := []Job[T]{}
+			for _, job := range jobs {
+				result = append(result, job)
+			}
+			return result, nil
+		},
+	}
+}
@@ -0,0 +1,80 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package emaildelivery
+
+import (
+	"github.com/supertokens/supertokens-golang/ingredients/deliverycapture"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+// MakeCaptureService returns a service that stores the rendered emails in inbox (or
+// deliverycapture.DefaultInbox if it is nil) instead of sending them. It is meant to be used
+// in development and end-to-end tests only.
+func MakeCaptureService(inbox *deliverycapture.Inbox, getDefaultContent GetContentFunc) *EmailDeliveryInterface {
+	inbox = deliverycapture.GetInbox(inbox)
+
+	sendEmail := func(input EmailType, userContext supertokens.UserContext) error {
+		content, err := getDefaultContent(input, userContext)
+		if err != nil {
+			return err
+		}
+
+		message := deliverycapture.Message{
+			Type:      deliverycapture.EmailMessage,
+			Recipient: content.ToEmail,
+			Subject:   content.Subject,
+			Body:      content.Body,
+			TextBody:  content.TextBody,
+			IsHtml:    content.IsHtml,
+			Links:     []string{},
+			Codes:     []string{},
+		}
+		if input.EmailVerification != nil {
+			message.TenantId = input.EmailVerification.TenantId
+			message.Links = append(message.Links, input.EmailVerification.EmailVerifyLink)
+		} else if input.PasswordReset != nil {
+			message.TenantId = input.PasswordReset.TenantId
+			message.Links = append(message.Links, input.PasswordReset.PasswordResetLink)
+		} else if input.PasswordlessLogin != nil {
+			message.TenantId = input.PasswordlessLogin.TenantId
+			if input.PasswordlessLogin.UrlWithLinkCode != nil {
+				message.Links = append(message.Links, *input.PasswordlessLogin.UrlWithLinkCode)
+			}
+			if input.PasswordlessLogin.UserInputCode != nil {
+				message.Codes = append(message.Codes, *input.PasswordlessLogin.UserInputCode)
+			}
+		} else if input.EmailChange != nil {
+			message.TenantId = input.EmailChange.TenantId
+			message.Links = append(message.Links, input.EmailChange.EmailChangeLink)
+		} else if input.EmailChangeNotification != nil {
+			message.TenantId = input.EmailChangeNotification.TenantId
+		} else if input.PasswordChanged != nil {
+			message.TenantId = input.PasswordChanged.TenantId
+		} else if input.Invitation != nil {
+			message.TenantId = input.Invitation.TenantId
+			message.Links = append(message.Links, input.Invitation.InviteLink)
+		}
+
+		inbox.Add(message)
+		supertokens.LogDebugMessage("Captured email to " + content.ToEmail)
+		return nil
+	}
+
+	return &EmailDeliveryInterface{
+		SendEmail: &sendEmail,
+	}
+}
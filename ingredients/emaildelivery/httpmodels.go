@@ -0,0 +1,153 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package emaildelivery
+
+import (
+	"errors"
+
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+const (
+	DefaultSendGridBaseURL = "https://api.sendgrid.com"
+	DefaultPostmarkBaseURL = "https://api.postmarkapp.com"
+	DefaultMailgunBaseURL  = "https://api.mailgun.net"
+)
+
+// EmailServiceInterface is the equivalent of SMTPInterface for the services that send
+// emails using an HTTP API (SES, SendGrid, Postmark and Mailgun)
+type EmailServiceInterface struct {
+	SendRawEmail *func(input EmailContent, userContext supertokens.UserContext) error
+	GetContent   *func(input EmailType, userContext supertokens.UserContext) (EmailContent, error)
+}
+
+type SESSettings struct {
+	// Region is used for the default base URL (https://email.<region>.amazonaws.com) and for signing the requests
+	Region          string
+	AccessKeyId     string
+	SecretAccessKey string
+	SessionToken    *string
+	From            SMTPFrom
+	// ConfigurationSetName is the SES configuration set used for sending the emails
+	ConfigurationSetName *string
+	BaseURL              *string
+}
+
+type SESServiceConfig struct {
+	Settings  SESSettings
+	Templates *TemplateConfig
+	Override  func(originalImplementation EmailServiceInterface) EmailServiceInterface
+}
+
+type SendGridSettings struct {
+	APIKey  string
+	From    SMTPFrom
+	BaseURL *string
+}
+
+type SendGridServiceConfig struct {
+	Settings  SendGridSettings
+	Templates *TemplateConfig
+	Override  func(originalImplementation EmailServiceInterface) EmailServiceInterface
+}
+
+type PostmarkSettings struct {
+	ServerToken string
+	From        SMTPFrom
+	// MessageStream defaults to the "outbound" transactional stream if not set
+	MessageStream *string
+	BaseURL       *string
+}
+
+type PostmarkServiceConfig struct {
+	Settings  PostmarkSettings
+	Templates *TemplateConfig
+	Override  func(originalImplementation EmailServiceInterface) EmailServiceInterface
+}
+
+type MailgunSettings struct {
+	APIKey string
+	Domain string
+	From   SMTPFrom
+	// BaseURL should be set to https://api.eu.mailgun.net for domains in the EU region
+	BaseURL *string
+}
+
+type MailgunServiceConfig struct {
+	Settings  MailgunSettings
+	Templates *TemplateConfig
+	Override  func(originalImplementation EmailServiceInterface) EmailServiceInterface
+}
+
+func NormaliseSESServiceConfig(input SESServiceConfig) (SESServiceConfig, error) {
+	if input.Settings.Region == "" {
+		return SESServiceConfig{}, errors.New("'Region' must be set")
+	}
+	if input.Settings.AccessKeyId == "" || input.Settings.SecretAccessKey == "" {
+		return SESServiceConfig{}, errors.New("'AccessKeyId' and 'SecretAccessKey' must be set")
+	}
+	if input.Settings.From.Email == "" {
+		return SESServiceConfig{}, errors.New("'From.Email' must be set")
+	}
+	if input.Settings.BaseURL == nil {
+		baseURL := "https://email." + input.Settings.Region + ".amazonaws.com"
+		input.Settings.BaseURL = &baseURL
+	}
+	return input, nil
+}
+
+func NormaliseSendGridServiceConfig(input SendGridServiceConfig) (SendGridServiceConfig, error) {
+	if input.Settings.APIKey == "" {
+		return SendGridServiceConfig{}, errors.New("'APIKey' must be set")
+	}
+	if input.Settings.From.Email == "" {
+		return SendGridServiceConfig{}, errors.New("'From.Email' must be set")
+	}
+	if input.Settings.BaseURL == nil {
+		baseURL := DefaultSendGridBaseURL
+		input.Settings.BaseURL = &baseURL
+	}
+	return input, nil
+}
+
+func NormalisePostmarkServiceConfig(input PostmarkServiceConfig) (PostmarkServiceConfig, error) {
+	if input.Settings.ServerToken == "" {
+		return PostmarkServiceConfig{}, errors.New("'ServerToken' must be set")
+	}
+	if input.Settings.From.Email == "" {
+		return PostmarkServiceConfig{}, errors.New("'From.Email' must be set")
+	}
+	if input.Settings.BaseURL == nil {
+		baseURL := DefaultPostmarkBaseURL
+		input.Settings.BaseURL = &baseURL
+	}
+	return input, nil
+}
+
+func NormaliseMailgunServiceConfig(input MailgunServiceConfig) (MailgunServiceConfig, error) {
+	if input.Settings.APIKey == "" || input.Settings.Domain == "" {
+		return MailgunServiceConfig{}, errors.New("'APIKey' and 'Domain' must be set")
+	}
+	if input.Settings.From.Email == "" {
+		return MailgunServiceConfig{}, errors.New("'From.Email' must be set")
+	}
+	if input.Settings.BaseURL == nil {
+		baseURL := DefaultMailgunBaseURL
+		input.Settings.BaseURL = &baseURL
+	}
+	return input, nil
+}
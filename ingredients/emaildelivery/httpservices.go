@@ -0,0 +1,290 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package emaildelivery
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/supertokens/supertokens-golang/ingredients/internal/awssigv4"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+// GetContentFunc returns the default content of an email. Every recipe passes its own
+// implementation to the Make*Service functions below.
+type GetContentFunc func(input EmailType, userContext supertokens.UserContext) (EmailContent, error)
+
+var emailHTTPClient = &http.Client{Timeout: 30 * time.Second}
+
+func MakeSESService(config SESServiceConfig, getDefaultContent GetContentFunc) (*EmailDeliveryInterface, error) {
+	config, err := NormaliseSESServiceConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	settings := config.Settings
+	sendRawEmail := func(input EmailContent, userContext supertokens.UserContext) error {
+		return SendSESEmail(settings, input, userContext)
+	}
+	return makeHTTPEmailService(sendRawEmail, getDefaultContent, config.Templates, config.Override), nil
+}
+
+func MakeSendGridService(config SendGridServiceConfig, getDefaultContent GetContentFunc) (*EmailDeliveryInterface, error) {
+	config, err := NormaliseSendGridServiceConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	settings := config.Settings
+	sendRawEmail := func(input EmailContent, userContext supertokens.UserContext) error {
+		return SendSendGridEmail(settings, input, userContext)
+	}
+	return makeHTTPEmailService(sendRawEmail, getDefaultContent, config.Templates, config.Override), nil
+}
+
+func MakePostmarkService(config PostmarkServiceConfig, getDefaultContent GetContentFunc) (*EmailDeliveryInterface, error) {
+	config, err := NormalisePostmarkServiceConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	settings := config.Settings
+	sendRawEmail := func(input EmailContent, userContext supertokens.UserContext) error {
+		return SendPostmarkEmail(settings, input, userContext)
+	}
+	return makeHTTPEmailService(sendRawEmail, getDefaultContent, config.Templates, config.Override), nil
+}
+
+func MakeMailgunService(config MailgunServiceConfig, getDefaultContent GetContentFunc) (*EmailDeliveryInterface, error) {
+	config, err := NormaliseMailgunServiceConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	settings := config.Settings
+	sendRawEmail := func(input EmailContent, userContext supertokens.UserContext) error {
+		return SendMailgunEmail(settings, input, userContext)
+	}
+	return makeHTTPEmailService(sendRawEmail, getDefaultContent, config.Templates, config.Override), nil
+}
+
+func makeHTTPEmailService(sendRawEmail func(input EmailContent, userContext supertokens.UserContext) error, getDefaultContent GetContentFunc, templates *TemplateConfig, override func(originalImplementation EmailServiceInterface) EmailServiceInterface) *EmailDeliveryInterface {
+	getContent := func(input EmailType, userContext supertokens.UserContext) (EmailContent, error) {
+		if templates != nil {
+			return GetContentFromTemplates(*templates, input, userContext)
+		}
+		return getDefaultContent(input, userContext)
+	}
+
+	serviceImpl := EmailServiceInterface{
+		SendRawEmail: &sendRawEmail,
+		GetContent:   &getContent,
+	}
+	if override != nil {
+		serviceImpl = override(serviceImpl)
+	}
+
+	sendEmail := func(input EmailType, userContext supertokens.UserContext) error {
+		content, err := (*serviceImpl.GetContent)(input, userContext)
+		if err != nil {
+			return err
+		}
+		return (*serviceImpl.SendRawEmail)(content, userContext)
+	}
+
+	return &EmailDeliveryInterface{
+		SendEmail: &sendEmail,
+	}
+}
+
+func SendSESEmail(settings SESSettings, content EmailContent, userContext supertokens.UserContext) error {
+	body := map[string]interface{}{}
+	if content.IsHtml {
+		body["Html"] = map[string]interface{}{"Data": content.Body, "Charset": "UTF-8"}
+		if content.TextBody != "" {
+			body["Text"] = map[string]interface{}{"Data": content.TextBody, "Charset": "UTF-8"}
+		}
+	} else {
+		body["Text"] = map[string]interface{}{"Data": content.Body, "Charset": "UTF-8"}
+	}
+	data := map[string]interface{}{
+		"FromEmailAddress": formatFromAddress(settings.From),
+		"Destination": map[string]interface{}{
+			"ToAddresses": []string{content.ToEmail},
+		},
+		"Content": map[string]interface{}{
+			"Simple": map[string]interface{}{
+				"Subject": map[string]interface{}{"Data": content.Subject, "Charset": "UTF-8"},
+				"Body":    body,
+			},
+		},
+	}
+	if settings.ConfigurationSetName != nil {
+		data["ConfigurationSetName"] = *settings.ConfigurationSetName
+	}
+
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(supertokens.GetContextFromUserContext(userContext), "POST", getBaseURL(settings.BaseURL, "https://email."+settings.Region+".amazonaws.com")+"/v2/email/outbound-emails", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("content-type", "application/json")
+	awssigv4.Sign(req, jsonData, awssigv4.Credentials{
+		AccessKeyId:     settings.AccessKeyId,
+		SecretAccessKey: settings.SecretAccessKey,
+		SessionToken:    settings.SessionToken,
+	}, settings.Region, "ses", time.Now())
+
+	return doEmailRequest(req, "SES", content.ToEmail)
+}
+
+func SendSendGridEmail(settings SendGridSettings, content EmailContent, userContext supertokens.UserContext) error {
+	from := map[string]interface{}{"email": settings.From.Email}
+	if settings.From.Name != "" {
+		from["name"] = settings.From.Name
+	}
+	// SendGrid requires the text/plain content to be the first one
+	contents := []map[string]interface{}{}
+	if content.IsHtml {
+		if content.TextBody != "" {
+			contents = append(contents, map[string]interface{}{"type": "text/plain", "value": content.TextBody})
+		}
+		contents = append(contents, map[string]interface{}{"type": "text/html", "value": content.Body})
+	} else {
+		contents = append(contents, map[string]interface{}{"type": "text/plain", "value": content.Body})
+	}
+	data := map[string]interface{}{
+		"personalizations": []map[string]interface{}{
+			{"to": []map[string]interface{}{{"email": content.ToEmail}}},
+		},
+		"from":    from,
+		"subject": content.Subject,
+		"content": contents,
+	}
+
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(supertokens.GetContextFromUserContext(userContext), "POST", getBaseURL(settings.BaseURL, DefaultSendGridBaseURL)+"/v3/mail/send", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("content-type", "application/json")
+	req.Header.Set("authorization", "Bearer "+settings.APIKey)
+
+	return doEmailRequest(req, "SendGrid", content.ToEmail)
+}
+
+func SendPostmarkEmail(settings PostmarkSettings, content EmailContent, userContext supertokens.UserContext) error {
+	data := map[string]interface{}{
+		"From":    formatFromAddress(settings.From),
+		"To":      content.ToEmail,
+		"Subject": content.Subject,
+	}
+	if content.IsHtml {
+		data["HtmlBody"] = content.Body
+		if content.TextBody != "" {
+			data["TextBody"] = content.TextBody
+		}
+	} else {
+		data["TextBody"] = content.Body
+	}
+	if settings.MessageStream != nil {
+		data["MessageStream"] = *settings.MessageStream
+	}
+
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(supertokens.GetContextFromUserContext(userContext), "POST", getBaseURL(settings.BaseURL, DefaultPostmarkBaseURL)+"/email", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("content-type", "application/json")
+	req.Header.Set("accept", "application/json")
+	req.Header.Set("X-Postmark-Server-Token", settings.ServerToken)
+
+	return doEmailRequest(req, "Postmark", content.ToEmail)
+}
+
+func SendMailgunEmail(settings MailgunSettings, content EmailContent, userContext supertokens.UserContext) error {
+	form := url.Values{}
+	form.Set("from", formatFromAddress(settings.From))
+	form.Set("to", content.ToEmail)
+	form.Set("subject", content.Subject)
+	if content.IsHtml {
+		form.Set("html", content.Body)
+		if content.TextBody != "" {
+			form.Set("text", content.TextBody)
+		}
+	} else {
+		form.Set("text", content.Body)
+	}
+
+	endpoint := getBaseURL(settings.BaseURL, DefaultMailgunBaseURL) + "/v3/" + url.PathEscape(settings.Domain) + "/messages"
+	req, err := http.NewRequestWithContext(supertokens.GetContextFromUserContext(userContext), "POST", endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("content-type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth("api", settings.APIKey)
+
+	return doEmailRequest(req, "Mailgun", content.ToEmail)
+}
+
+func doEmailRequest(req *http.Request, provider string, toEmail string) error {
+	resp, err := emailHTTPClient.Do(req)
+	if err != nil {
+		supertokens.LogDebugMessage(fmt.Sprintf("Error sending email using %s: %s", provider, err.Error()))
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 300 {
+		supertokens.LogDebugMessage(fmt.Sprintf("Email sent to %s using %s", toEmail, provider))
+		return nil
+	}
+
+	body, readErr := ioutil.ReadAll(resp.Body)
+	if readErr != nil {
+		supertokens.LogDebugMessage(fmt.Sprintf("Error: %s", readErr.Error()))
+	} else {
+		supertokens.LogDebugMessage(fmt.Sprintf("Error response: %s", string(body)))
+	}
+	return fmt.Errorf("Error sending email. %s API returned %d status.", provider, resp.StatusCode)
+}
+
+func formatFromAddress(from SMTPFrom) string {
+	if from.Name == "" {
+		return from.Email
+	}
+	return fmt.Sprintf("%s <%s>", from.Name, from.Email)
+}
+
+func getBaseURL(baseURL *string, defaultBaseURL string) string {
+	if baseURL == nil {
+		return defaultBaseURL
+	}
+	return strings.TrimSuffix(*baseURL, "/")
+}
@@ -0,0 +1,249 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package emaildelivery
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+type capturedRequest struct {
+	Method string
+	Path   string
+	Header http.Header
+	Body   []byte
+}
+
+func startTestEmailServer(t *testing.T, status int) (*httptest.Server, *[]capturedRequest) {
+	requests := []capturedRequest{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		assert.NoError(t, err)
+		requests = append(requests, capturedRequest{
+			Method: r.Method,
+			Path:   r.URL.Path,
+			Header: r.Header,
+			Body:   body,
+		})
+		w.WriteHeader(status)
+	}))
+	return server, &requests
+}
+
+func getTestDefaultContent(input EmailType, userContext supertokens.UserContext) (EmailContent, error) {
+	return EmailContent{
+		Body:     "<p>" + input.PasswordReset.PasswordResetLink + "</p>",
+		TextBody: input.PasswordReset.PasswordResetLink,
+		IsHtml:   true,
+		Subject:  "Reset your password",
+		ToEmail:  input.PasswordReset.User.Email,
+	}, nil
+}
+
+var testPasswordResetInput = EmailType{
+	PasswordReset: &PasswordResetType{
+		User: User{
+			ID:    "someId",
+			Email: "test@example.com",
+		},
+		PasswordResetLink: "https://supertokens.io/reset",
+		TenantId:          "public",
+	},
+}
+
+func TestSESService(t *testing.T) {
+	server, requests := startTestEmailServer(t, 200)
+	defer server.Close()
+
+	sessionToken := "token"
+	service, err := MakeSESService(SESServiceConfig{
+		Settings: SESSettings{
+			Region:          "eu-west-1",
+			AccessKeyId:     "AKID",
+			SecretAccessKey: "secret",
+			SessionToken:    &sessionToken,
+			From:            SMTPFrom{Name: "SuperTokens", Email: "no-reply@supertokens.io"},
+			BaseURL:         &server.URL,
+		},
+	}, getTestDefaultContent)
+	assert.NoError(t, err)
+
+	err = (*service.SendEmail)(testPasswordResetInput, &map[string]interface{}{})
+	assert.NoError(t, err)
+	assert.Len(t, *requests, 1)
+
+	req := (*requests)[0]
+	assert.Equal(t, "POST", req.Method)
+	assert.Equal(t, "/v2/email/outbound-emails", req.Path)
+	assert.True(t, strings.HasPrefix(req.Header.Get("authorization"), "AWS4-HMAC-SHA256 Credential=AKID/"))
+	assert.Contains(t, req.Header.Get("authorization"), "/eu-west-1/ses/aws4_request, SignedHeaders=content-type;host;x-amz-date;x-amz-security-token, ")
+	assert.Equal(t, "token", req.Header.Get("x-amz-security-token"))
+
+	body := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal(req.Body, &body))
+	assert.Equal(t, "SuperTokens <no-reply@supertokens.io>", body["FromEmailAddress"])
+	assert.Equal(t, []interface{}{"test@example.com"}, body["Destination"].(map[string]interface{})["ToAddresses"])
+	simple := body["Content"].(map[string]interface{})["Simple"].(map[string]interface{})
+	assert.Equal(t, "Reset your password", simple["Subject"].(map[string]interface{})["Data"])
+	assert.Equal(t, "<p>https://supertokens.io/reset</p>", simple["Body"].(map[string]interface{})["Html"].(map[string]interface{})["Data"])
+	assert.Equal(t, "https://supertokens.io/reset", simple["Body"].(map[string]interface{})["Text"].(map[string]interface{})["Data"])
+}
+
+func TestSendGridService(t *testing.T) {
+	server, requests := startTestEmailServer(t, 202)
+	defer server.Close()
+
+	service, err := MakeSendGridService(SendGridServiceConfig{
+		Settings: SendGridSettings{
+			APIKey:  "key",
+			From:    SMTPFrom{Name: "SuperTokens", Email: "no-reply@supertokens.io"},
+			BaseURL: &server.URL,
+		},
+	}, getTestDefaultContent)
+	assert.NoError(t, err)
+
+	err = (*service.SendEmail)(testPasswordResetInput, &map[string]interface{}{})
+	assert.NoError(t, err)
+	assert.Len(t, *requests, 1)
+
+	req := (*requests)[0]
+	assert.Equal(t, "/v3/mail/send", req.Path)
+	assert.Equal(t, "Bearer key", req.Header.Get("authorization"))
+	assert.JSONEq(t, `{
+		"personalizations": [{"to": [{"email": "test@example.com"}]}],
+		"from": {"email": "no-reply@supertokens.io", "name": "SuperTokens"},
+		"subject": "Reset your password",
+		"content": [
+			{"type": "text/plain", "value": "https://supertokens.io/reset"},
+			{"type": "text/html", "value": "<p>https://supertokens.io/reset</p>"}
+		]
+	}`, string(req.Body))
+}
+
+func TestPostmarkService(t *testing.T) {
+	server, requests := startTestEmailServer(t, 200)
+	defer server.Close()
+
+	messageStream := "auth"
+	service, err := MakePostmarkService(PostmarkServiceConfig{
+		Settings: PostmarkSettings{
+			ServerToken:   "token",
+			From:          SMTPFrom{Email: "no-reply@supertokens.io"},
+			MessageStream: &messageStream,
+			BaseURL:       &server.URL,
+		},
+	}, getTestDefaultContent)
+	assert.NoError(t, err)
+
+	err = (*service.SendEmail)(testPasswordResetInput, &map[string]interface{}{})
+	assert.NoError(t, err)
+	assert.Len(t, *requests, 1)
+
+	req := (*requests)[0]
+	assert.Equal(t, "/email", req.Path)
+	assert.Equal(t, "token", req.Header.Get("X-Postmark-Server-Token"))
+	assert.JSONEq(t, `{
+		"From": "no-reply@supertokens.io",
+		"To": "test@example.com",
+		"Subject": "Reset your password",
+		"HtmlBody": "<p>https://supertokens.io/reset</p>",
+		"TextBody": "https://supertokens.io/reset",
+		"MessageStream": "auth"
+	}`, string(req.Body))
+}
+
+func TestMailgunService(t *testing.T) {
+	server, requests := startTestEmailServer(t, 200)
+	defer server.Close()
+
+	service, err := MakeMailgunService(MailgunServiceConfig{
+		Settings: MailgunSettings{
+			APIKey:  "key",
+			Domain:  "mg.supertokens.io",
+			From:    SMTPFrom{Name: "SuperTokens", Email: "no-reply@supertokens.io"},
+			BaseURL: &server.URL,
+		},
+	}, getTestDefaultContent)
+	assert.NoError(t, err)
+
+	err = (*service.SendEmail)(testPasswordResetInput, &map[string]interface{}{})
+	assert.NoError(t, err)
+	assert.Len(t, *requests, 1)
+
+	req := (*requests)[0]
+	assert.Equal(t, "/v3/mg.supertokens.io/messages", req.Path)
+	username, password, ok := (&http.Request{Header: req.Header}).BasicAuth()
+	assert.True(t, ok)
+	assert.Equal(t, "api", username)
+	assert.Equal(t, "key", password)
+	assert.Equal(t, "from=SuperTokens+%3Cno-reply%40supertokens.io%3E&html=%3Cp%3Ehttps%3A%2F%2Fsupertokens.io%2Freset%3C%2Fp%3E&subject=Reset+your+password&text=https%3A%2F%2Fsupertokens.io%2Freset&to=test%40example.com", string(req.Body))
+}
+
+func TestHTTPServiceOverrideAndErrorStatus(t *testing.T) {
+	server, requests := startTestEmailServer(t, 401)
+	defer server.Close()
+
+	service, err := MakeSendGridService(SendGridServiceConfig{
+		Settings: SendGridSettings{
+			APIKey:  "key",
+			From:    SMTPFrom{Email: "no-reply@supertokens.io"},
+			BaseURL: &server.URL,
+		},
+		Override: func(originalImplementation EmailServiceInterface) EmailServiceInterface {
+			originalGetContent := *originalImplementation.GetContent
+			getContent := func(input EmailType, userContext supertokens.UserContext) (EmailContent, error) {
+				content, err := originalGetContent(input, userContext)
+				content.Subject = "Custom subject"
+				return content, err
+			}
+			originalImplementation.GetContent = &getContent
+			return originalImplementation
+		},
+	}, getTestDefaultContent)
+	assert.NoError(t, err)
+
+	err = (*service.SendEmail)(testPasswordResetInput, &map[string]interface{}{})
+	assert.EqualError(t, err, "Error sending email. SendGrid API returned 401 status.")
+	assert.Len(t, *requests, 1)
+	assert.Contains(t, string((*requests)[0].Body), `"subject":"Custom subject"`)
+}
+
+func TestHTTPServiceConfigValidation(t *testing.T) {
+	_, err := MakeSESService(SESServiceConfig{
+		Settings: SESSettings{
+			AccessKeyId:     "AKID",
+			SecretAccessKey: "secret",
+			From:            SMTPFrom{Email: "no-reply@supertokens.io"},
+		},
+	}, getTestDefaultContent)
+	assert.EqualError(t, err, "'Region' must be set")
+
+	_, err = MakeMailgunService(MailgunServiceConfig{
+		Settings: MailgunSettings{
+			APIKey: "key",
+			From:   SMTPFrom{Email: "no-reply@supertokens.io"},
+		},
+	}, getTestDefaultContent)
+	assert.EqualError(t, err, "'APIKey' and 'Domain' must be set")
+}
@@ -46,7 +46,10 @@ func SendSMTPEmail(settings SMTPSettings, content EmailContent) error {
 	m.SetHeader("To", content.ToEmail)
 	m.SetHeader("Subject", content.Subject)
 
-	if content.IsHtml {
+	if content.IsHtml && content.TextBody != "" {
+		m.SetBody("text/plain", content.TextBody)
+		m.AddAlternative("text/html", content.Body)
+	} else if content.IsHtml {
 		m.SetBody("text/html", content.Body)
 	} else {
 		m.SetBody("text/plain", content.Body)
@@ -36,6 +36,13 @@ type EmailType struct {
 	EmailVerification *EmailVerificationType
 	PasswordReset     *PasswordResetType
 	PasswordlessLogin *PasswordlessLoginType
+	EmailChange       *EmailChangeType
+	// EmailChangeNotification is sent to the previous email of the user once the email is changed
+	EmailChangeNotification *EmailChangeNotificationType
+	// PasswordChanged is sent to the user once their password is changed
+	PasswordChanged *PasswordChangedType
+	// Invitation is sent to invite someone to sign up
+	Invitation *InvitationType
 }
 
 type EmailVerificationType struct {
@@ -50,6 +57,38 @@ type PasswordResetType struct {
 	TenantId          string
 }
 
+// EmailChangeType is sent to the new email of the user. The email of the user is only
+// changed once the link is opened. User.Email is the new email.
+type EmailChangeType struct {
+	User            User
+	PreviousEmail   string
+	EmailChangeLink string
+	TenantId        string
+}
+
+// EmailChangeNotificationType is sent to the previous email of the user. User.Email is the
+// previous email.
+type EmailChangeNotificationType struct {
+	User     User
+	NewEmail string
+	TenantId string
+}
+
+type PasswordChangedType struct {
+	User     User
+	TenantId string
+}
+
+// InvitationType is sent to the invited email, which has no user yet
+type InvitationType struct {
+	InviteId   string
+	Email      string
+	InviteLink string
+	// InviteLifetime is in milliseconds
+	InviteLifetime uint64
+	TenantId       string
+}
+
 type PasswordlessLoginType struct {
 	Email            string
 	UserInputCode    *string
@@ -0,0 +1,47 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package emaildelivery
+
+import (
+	"github.com/supertokens/supertokens-golang/ingredients/deliveryqueue"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+// MakeQueuedService wraps service so that SendEmail only adds the email to a delivery queue.
+// Emails are sent in the background and failed attempts are retried with exponential backoff.
+// The returned queue can be used to stop it when the app shuts down.
+//
+// service is called after the API request is done, with a new user context whose request only
+// has the KeptHeaders of the original request (Accept-Language by default, so the locale of the
+// templates is kept). Overrides of service must not rely on other values of the user context.
+func MakeQueuedService(service EmailDeliveryInterface, config *deliveryqueue.TypeInput[EmailType]) (*EmailDeliveryInterface, *deliveryqueue.Queue[EmailType], error) {
+	queue, err := deliveryqueue.MakeQueue(func(input EmailType, userContext supertokens.UserContext) error {
+		return (*service.SendEmail)(input, userContext)
+	}, config)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sendEmail := func(input EmailType, userContext supertokens.UserContext) error {
+		_, err := queue.Enqueue(input, userContext)
+		return err
+	}
+
+	return &EmailDeliveryInterface{
+		SendEmail: &sendEmail,
+	}, queue, nil
+}
@@ -42,6 +42,9 @@ type EmailContent struct {
 	IsHtml  bool
 	Subject string
 	ToEmail string
+	// TextBody is an optional plain text alternative for HTML emails. If it is set,
+	// a multipart/alternative email is sent.
+	TextBody string
 }
 
 type SMTPInterface struct {
@@ -51,5 +54,8 @@ type SMTPInterface struct {
 
 type SMTPServiceConfig struct {
 	Settings SMTPSettings
-	Override func(originalImplementation SMTPInterface) SMTPInterface
+	// Templates can be used to render the emails from custom (and localised) templates
+	// instead of the built-in ones
+	Templates *TemplateConfig
+	Override  func(originalImplementation SMTPInterface) SMTPInterface
 }
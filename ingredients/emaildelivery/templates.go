@@ -0,0 +1,320 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package emaildelivery
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	htmlTemplate "html/template"
+	"io/fs"
+	"path"
+	"strings"
+	textTemplate "text/template"
+
+	"github.com/supertokens/supertokens-golang/supertokens"
+	"golang.org/x/text/language"
+)
+
+const (
+	EmailVerificationTemplateName = "email_verification"
+	PasswordResetTemplateName     = "password_reset"
+	PasswordlessLoginTemplateName = "passwordless_login"
+	EmailChangeTemplateName       = "email_change"
+	// EmailChangeNotificationTemplateName is used for the email sent to the previous email of the user
+	EmailChangeNotificationTemplateName = "email_change_notification"
+	PasswordChangedTemplateName         = "password_changed"
+	InvitationTemplateName              = "invitation"
+)
+
+//go:embed templates
+var defaultTemplatesFS embed.FS
+
+// TemplateConfig configures how emails are rendered from templates. For every email type the
+// templates are looked up in FS, in the following files:
+//   - <locale>/<name>.subject.txt: the subject (required)
+//   - <locale>/<name>.html: the HTML body, rendered using html/template
+//   - <locale>/<name>.txt: the plain text body, rendered using text/template
+//
+// where name is one of email_verification, password_reset, passwordless_login, email_change,
+// email_change_notification, password_changed or invitation. At least one of the bodies must exist, and if
+// both exist, a multipart email is sent. Templates in tenants/<tenantId>/<locale>/ take
+// precedence for that tenant. If no template is found for any of the locales, the built-in
+// English templates are used.
+//
+// Templates are rendered with TemplateData.
+type TemplateConfig struct {
+	FS fs.FS
+	// DefaultLocale is used if none of the locales requested by the user have templates. Defaults to "en".
+	DefaultLocale *string
+	// GetLocale returns the locale to use for the email. If it is nil or returns nil, the locales
+	// are read from the Accept-Language header of the request in the user context.
+	GetLocale func(input EmailType, userContext supertokens.UserContext) (*string, error)
+}
+
+type TemplateData struct {
+	AppName  string
+	ToEmail  string
+	TenantId string
+	Locale   string
+
+	// Only set for email verification emails
+	EmailVerifyLink string
+	// Only set for password reset emails
+	PasswordResetLink string
+	// Only set for passwordless login emails. UrlWithLinkCode and UserInputCode depend on the flow type.
+	UrlWithLinkCode string
+	UserInputCode   string
+	CodeLifetime    string
+	// Only set for email change emails (sent to the new email) and email change notifications
+	// (sent to the previous email). EmailChangeLink is only set for email change emails.
+	PreviousEmail   string
+	NewEmail        string
+	EmailChangeLink string
+	// Only set for invitation emails
+	InviteLink     string
+	InviteLifetime string
+}
+
+// GetContentFromTemplates renders the email using the templates from config
+func GetContentFromTemplates(config TemplateConfig, input EmailType, userContext supertokens.UserContext) (EmailContent, error) {
+	stInstance, err := supertokens.GetInstanceOrThrowError()
+	if err != nil {
+		return EmailContent{}, err
+	}
+	return getContentFromTemplates(config, input, stInstance.AppInfo.AppName, userContext)
+}
+
+func getContentFromTemplates(config TemplateConfig, input EmailType, appName string, userContext supertokens.UserContext) (EmailContent, error) {
+	name, data, err := getTemplateNameAndData(input)
+	if err != nil {
+		return EmailContent{}, err
+	}
+	data.AppName = appName
+
+	locales, err := getLocales(config, input, userContext)
+	if err != nil {
+		return EmailContent{}, err
+	}
+
+	templatesFS, dir, locale, err := findTemplates(config.FS, name, data.TenantId, locales)
+	if err != nil {
+		return EmailContent{}, err
+	}
+	data.Locale = locale
+	supertokens.LogDebugMessage("GetContentFromTemplates: using templates from " + path.Join(dir, name))
+
+	return renderTemplates(templatesFS, dir, name, data)
+}
+
+func getTemplateNameAndData(input EmailType) (string, TemplateData, error) {
+	if input.EmailVerification != nil {
+		return EmailVerificationTemplateName, TemplateData{
+			ToEmail:         input.EmailVerification.User.Email,
+			TenantId:        input.EmailVerification.TenantId,
+			EmailVerifyLink: input.EmailVerification.EmailVerifyLink,
+		}, nil
+	} else if input.PasswordReset != nil {
+		return PasswordResetTemplateName, TemplateData{
+			ToEmail:           input.PasswordReset.User.Email,
+			TenantId:          input.PasswordReset.TenantId,
+			PasswordResetLink: input.PasswordReset.PasswordResetLink,
+		}, nil
+	} else if input.PasswordlessLogin != nil {
+		data := TemplateData{
+			ToEmail:      input.PasswordlessLogin.Email,
+			TenantId:     input.PasswordlessLogin.TenantId,
+			CodeLifetime: supertokens.HumaniseMilliseconds(input.PasswordlessLogin.CodeLifetime),
+		}
+		if input.PasswordlessLogin.UrlWithLinkCode != nil {
+			data.UrlWithLinkCode = *input.PasswordlessLogin.UrlWithLinkCode
+		}
+		if input.PasswordlessLogin.UserInputCode != nil {
+			data.UserInputCode = *input.PasswordlessLogin.UserInputCode
+		}
+		return PasswordlessLoginTemplateName, data, nil
+	} else if input.EmailChange != nil {
+		return EmailChangeTemplateName, TemplateData{
+			ToEmail:         input.EmailChange.User.Email,
+			TenantId:        input.EmailChange.TenantId,
+			PreviousEmail:   input.EmailChange.PreviousEmail,
+			NewEmail:        input.EmailChange.User.Email,
+			EmailChangeLink: input.EmailChange.EmailChangeLink,
+		}, nil
+	} else if input.EmailChangeNotification != nil {
+		return EmailChangeNotificationTemplateName, TemplateData{
+			ToEmail:       input.EmailChangeNotification.User.Email,
+			TenantId:      input.EmailChangeNotification.TenantId,
+			PreviousEmail: input.EmailChangeNotification.User.Email,
+			NewEmail:      input.EmailChangeNotification.NewEmail,
+		}, nil
+	} else if input.PasswordChanged != nil {
+		return PasswordChangedTemplateName, TemplateData{
+			ToEmail:  input.PasswordChanged.User.Email,
+			TenantId: input.PasswordChanged.TenantId,
+		}, nil
+	} else if input.Invitation != nil {
+		return InvitationTemplateName, TemplateData{
+			ToEmail:        input.Invitation.Email,
+			TenantId:       input.Invitation.TenantId,
+			InviteLink:     input.Invitation.InviteLink,
+			InviteLifetime: supertokens.HumaniseMilliseconds(input.Invitation.InviteLifetime),
+		}, nil
+	}
+	return "", TemplateData{}, errors.New("should never come here")
+}
+
+// getLocales returns the locales to try in order of preference. For every locale with a region,
+// the base language is tried right after it (for example "de-CH" is followed by "de").
+func getLocales(config TemplateConfig, input EmailType, userContext supertokens.UserContext) ([]string, error) {
+	requested := []string{}
+
+	if config.GetLocale != nil {
+		locale, err := config.GetLocale(input, userContext)
+		if err != nil {
+			return nil, err
+		}
+		if locale != nil {
+			requested = append(requested, *locale)
+		}
+	}
+
+	if len(requested) == 0 {
+		req := supertokens.GetRequestFromUserContext(userContext)
+		if req != nil {
+			tags, _, err := language.ParseAcceptLanguage(req.Header.Get("Accept-Language"))
+			if err != nil {
+				supertokens.LogDebugMessage("GetContentFromTemplates: ignoring invalid Accept-Language header: " + err.Error())
+			}
+			for _, tag := range tags {
+				requested = append(requested, tag.String())
+			}
+		}
+	}
+
+	defaultLocale := "en"
+	if config.DefaultLocale != nil {
+		defaultLocale = *config.DefaultLocale
+	}
+	requested = append(requested, defaultLocale)
+
+	locales := []string{}
+	for _, locale := range requested {
+		locales = appendIfMissing(locales, locale)
+		tag, err := language.Parse(locale)
+		if err != nil {
+			continue
+		}
+		base, _ := tag.Base()
+		locales = appendIfMissing(locales, base.String())
+	}
+	return locales, nil
+}
+
+func appendIfMissing(values []string, value string) []string {
+	for _, v := range values {
+		if v == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
+
+func findTemplates(userFS fs.FS, name string, tenantId string, locales []string) (fs.FS, string, string, error) {
+	if userFS != nil {
+		for _, locale := range locales {
+			dirs := []string{locale}
+			if tenantId != "" {
+				dirs = []string{path.Join("tenants", tenantId, locale), locale}
+			}
+			for _, dir := range dirs {
+				if templateExists(userFS, dir, name) {
+					return userFS, dir, locale, nil
+				}
+			}
+		}
+	}
+
+	if templateExists(defaultTemplatesFS, "templates/en", name) {
+		return defaultTemplatesFS, "templates/en", "en", nil
+	}
+	return nil, "", "", errors.New("no templates found for " + name)
+}
+
+func templateExists(templatesFS fs.FS, dir string, name string) bool {
+	_, err := fs.Stat(templatesFS, path.Join(dir, name+".subject.txt"))
+	return err == nil
+}
+
+func renderTemplates(templatesFS fs.FS, dir string, name string, data TemplateData) (EmailContent, error) {
+	subject, err := renderTextTemplate(templatesFS, path.Join(dir, name+".subject.txt"), data)
+	if err != nil {
+		return EmailContent{}, err
+	}
+
+	content := EmailContent{
+		Subject: strings.TrimSpace(*subject),
+		ToEmail: data.ToEmail,
+	}
+
+	htmlPath := path.Join(dir, name+".html")
+	if _, err := fs.Stat(templatesFS, htmlPath); err == nil {
+		tmpl, err := htmlTemplate.ParseFS(templatesFS, htmlPath)
+		if err != nil {
+			return EmailContent{}, err
+		}
+		var body bytes.Buffer
+		err = tmpl.Execute(&body, data)
+		if err != nil {
+			return EmailContent{}, err
+		}
+		content.Body = body.String()
+		content.IsHtml = true
+	}
+
+	textPath := path.Join(dir, name+".txt")
+	if _, err := fs.Stat(templatesFS, textPath); err == nil {
+		textBody, err := renderTextTemplate(templatesFS, textPath, data)
+		if err != nil {
+			return EmailContent{}, err
+		}
+		if content.IsHtml {
+			content.TextBody = *textBody
+		} else {
+			content.Body = *textBody
+		}
+	}
+
+	if content.Body == "" {
+		return EmailContent{}, errors.New("no body template found for " + path.Join(dir, name))
+	}
+	return content, nil
+}
+
+func renderTextTemplate(templatesFS fs.FS, templatePath string, data TemplateData) (*string, error) {
+	tmpl, err := textTemplate.ParseFS(templatesFS, templatePath)
+	if err != nil {
+		return nil, err
+	}
+	var result bytes.Buffer
+	err = tmpl.Execute(&result, data)
+	if err != nil {
+		return nil, err
+	}
+	resultStr := result.String()
+	return &resultStr, nil
+}
@@ -0,0 +1,216 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package emaildelivery
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+var testTemplatesFS = fstest.MapFS{
+	"de/email_verification.subject.txt":            {Data: []byte("E-Mail bestätigen für {{.AppName}}\n")},
+	"de/email_verification.html":                   {Data: []byte("<a href=\"{{.EmailVerifyLink}}\">Bestätigen</a>")},
+	"de/email_verification.txt":                    {Data: []byte("Bestätigen: {{.EmailVerifyLink}}")},
+	"fr/email_verification.subject.txt":            {Data: []byte("Vérifiez votre e-mail")},
+	"fr/email_verification.txt":                    {Data: []byte("Lien: {{.EmailVerifyLink}}")},
+	"tenants/t1/de/email_verification.subject.txt": {Data: []byte("Tenant {{.TenantId}}")},
+	"tenants/t1/de/email_verification.html":        {Data: []byte("<p>{{.ToEmail}}</p>")},
+}
+
+func getTestEmailVerificationInput(tenantId string) EmailType {
+	return EmailType{
+		EmailVerification: &EmailVerificationType{
+			User: User{
+				ID:    "someId",
+				Email: "test@example.com",
+			},
+			EmailVerifyLink: "https://supertokens.io/verify?token=<token>",
+			TenantId:        tenantId,
+		},
+	}
+}
+
+func TestTemplatesUseLocaleFromAcceptLanguageHeader(t *testing.T) {
+	req, err := http.NewRequest("GET", "/", nil)
+	assert.NoError(t, err)
+	req.Header.Set("Accept-Language", "it-IT, de-CH;q=0.9, en;q=0.5")
+	userContext := supertokens.MakeDefaultUserContextFromAPI(req)
+
+	content, err := getContentFromTemplates(TemplateConfig{FS: testTemplatesFS}, getTestEmailVerificationInput("public"), "SuperTokens", userContext)
+	assert.NoError(t, err)
+	assert.Equal(t, "E-Mail bestätigen für SuperTokens", content.Subject)
+	assert.Equal(t, "test@example.com", content.ToEmail)
+	assert.True(t, content.IsHtml)
+	assert.Equal(t, "<a href=\"https://supertokens.io/verify?token=%3ctoken%3e\">Bestätigen</a>", content.Body)
+	assert.Equal(t, "Bestätigen: https://supertokens.io/verify?token=<token>", content.TextBody)
+}
+
+func TestTemplatesUseLocaleFromGetLocale(t *testing.T) {
+	req, err := http.NewRequest("GET", "/", nil)
+	assert.NoError(t, err)
+	req.Header.Set("Accept-Language", "de")
+	userContext := supertokens.MakeDefaultUserContextFromAPI(req)
+
+	config := TemplateConfig{
+		FS: testTemplatesFS,
+		GetLocale: func(input EmailType, userContext supertokens.UserContext) (*string, error) {
+			locale := "fr-CA"
+			return &locale, nil
+		},
+	}
+	content, err := getContentFromTemplates(config, getTestEmailVerificationInput("public"), "SuperTokens", userContext)
+	assert.NoError(t, err)
+	assert.Equal(t, "Vérifiez votre e-mail", content.Subject)
+	assert.False(t, content.IsHtml)
+	assert.Equal(t, "Lien: https://supertokens.io/verify?token=<token>", content.Body)
+	assert.Equal(t, "", content.TextBody)
+}
+
+func TestTemplatesForTenantTakePrecedence(t *testing.T) {
+	config := TemplateConfig{
+		FS:            testTemplatesFS,
+		DefaultLocale: strPtr("de"),
+	}
+	content, err := getContentFromTemplates(config, getTestEmailVerificationInput("t1"), "SuperTokens", &map[string]interface{}{})
+	assert.NoError(t, err)
+	assert.Equal(t, "Tenant t1", content.Subject)
+	assert.Equal(t, "<p>test@example.com</p>", content.Body)
+
+	content, err = getContentFromTemplates(config, getTestEmailVerificationInput("t2"), "SuperTokens", &map[string]interface{}{})
+	assert.NoError(t, err)
+	assert.Equal(t, "E-Mail bestätigen für SuperTokens", content.Subject)
+}
+
+func TestTemplatesFallBackToBuiltInTemplates(t *testing.T) {
+	req, err := http.NewRequest("GET", "/", nil)
+	assert.NoError(t, err)
+	req.Header.Set("Accept-Language", "es")
+	userContext := supertokens.MakeDefaultUserContextFromAPI(req)
+
+	content, err := getContentFromTemplates(TemplateConfig{FS: testTemplatesFS}, EmailType{
+		PasswordReset: &PasswordResetType{
+			User: User{
+				ID:    "someId",
+				Email: "test@example.com",
+			},
+			PasswordResetLink: "https://supertokens.io/reset",
+			TenantId:          "public",
+		},
+	}, "SuperTokens", userContext)
+	assert.NoError(t, err)
+	assert.Equal(t, "Password reset instructions", content.Subject)
+	assert.True(t, content.IsHtml)
+	assert.Contains(t, content.Body, "https://supertokens.io/reset")
+	assert.Contains(t, content.TextBody, "https://supertokens.io/reset")
+}
+
+func TestBuiltInPasswordlessLoginTemplateDependsOnTheFlowType(t *testing.T) {
+	otp := "123456"
+	link := "https://supertokens.io/auth/verify?linkCode=abc"
+	for _, flow := range []struct {
+		userInputCode   *string
+		urlWithLinkCode *string
+	}{{&otp, nil}, {nil, &link}, {&otp, &link}} {
+		content, err := getContentFromTemplates(TemplateConfig{}, EmailType{
+			PasswordlessLogin: &PasswordlessLoginType{
+				Email:           "test@example.com",
+				UserInputCode:   flow.userInputCode,
+				UrlWithLinkCode: flow.urlWithLinkCode,
+				CodeLifetime:    900000,
+				TenantId:        "public",
+			},
+		}, "SuperTokens", &map[string]interface{}{})
+		assert.NoError(t, err)
+		assert.Equal(t, "Login to your account", content.Subject)
+		assert.Contains(t, content.Body, "Login to SuperTokens")
+		assert.Contains(t, content.Body, "expires in 15 minutes")
+		assert.Equal(t, flow.userInputCode != nil, strings.Contains(content.Body, otp))
+		assert.Equal(t, flow.urlWithLinkCode != nil, strings.Contains(content.Body, link))
+	}
+}
+
+func TestBuiltInEmailChangeTemplates(t *testing.T) {
+	content, err := getContentFromTemplates(TemplateConfig{}, EmailType{
+		EmailChange: &EmailChangeType{
+			User: User{
+				ID:    "someId",
+				Email: "new@example.com",
+			},
+			PreviousEmail:   "old@example.com",
+			EmailChangeLink: "https://supertokens.io/auth/change-email?token=abc",
+			TenantId:        "public",
+		},
+	}, "SuperTokens", &map[string]interface{}{})
+	assert.NoError(t, err)
+	assert.Equal(t, "Confirm your new email address", content.Subject)
+	assert.Equal(t, "new@example.com", content.ToEmail)
+	assert.Contains(t, content.Body, "https://supertokens.io/auth/change-email?token=abc")
+	assert.Contains(t, content.TextBody, "from old@example.com to new@example.com")
+
+	content, err = getContentFromTemplates(TemplateConfig{}, EmailType{
+		EmailChangeNotification: &EmailChangeNotificationType{
+			User: User{
+				ID:    "someId",
+				Email: "old@example.com",
+			},
+			NewEmail: "new@example.com",
+			TenantId: "public",
+		},
+	}, "SuperTokens", &map[string]interface{}{})
+	assert.NoError(t, err)
+	assert.Equal(t, "The email address of your account has been changed", content.Subject)
+	assert.Equal(t, "old@example.com", content.ToEmail)
+	assert.Contains(t, content.TextBody, "on SuperTokens has been changed from old@example.com to new@example.com")
+}
+
+func TestBuiltInInvitationTemplates(t *testing.T) {
+	content, err := getContentFromTemplates(TemplateConfig{}, EmailType{
+		Invitation: &InvitationType{
+			InviteId:       "someId",
+			Email:          "invited@example.com",
+			InviteLink:     "https://supertokens.io/auth/accept-invite?token=abc&tenantId=public",
+			InviteLifetime: 7 * 24 * 60 * 60 * 1000,
+			TenantId:       "public",
+		},
+	}, "SuperTokens", &map[string]interface{}{})
+	assert.NoError(t, err)
+	assert.Equal(t, "You have been invited to SuperTokens", content.Subject)
+	assert.Equal(t, "invited@example.com", content.ToEmail)
+	assert.Contains(t, content.Body, "https://supertokens.io/auth/accept-invite?token=abc&amp;tenantId=public")
+	assert.Contains(t, content.TextBody, "https://supertokens.io/auth/accept-invite?token=abc&tenantId=public")
+	assert.Contains(t, content.TextBody, "This invitation expires in 168 hours.")
+}
+
+func TestGetLocalesAddsBaseLanguages(t *testing.T) {
+	req, err := http.NewRequest("GET", "/", nil)
+	assert.NoError(t, err)
+	req.Header.Set("Accept-Language", "de-CH, fr;q=0.8")
+	userContext := supertokens.MakeDefaultUserContextFromAPI(req)
+
+	locales, err := getLocales(TemplateConfig{}, getTestEmailVerificationInput("public"), userContext)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"de-CH", "de", "fr", "en"}, locales)
+}
+
+func strPtr(s string) *string {
+	return &s
+}
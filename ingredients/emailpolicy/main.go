@@ -0,0 +1,174 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package emailpolicy
+
+import (
+	"errors"
+	"strings"
+)
+
+func NormaliseTypeInput(input TypeInput) (TypeNormalisedInput, error) {
+	result := TypeNormalisedInput{
+		NormaliseEmail:        DefaultNormaliseEmail,
+		BlockDisposableEmails: input.BlockDisposableEmails,
+		TenantAllowedDomains:  map[string]map[string]bool{},
+		TenantDeniedDomains:   map[string]map[string]bool{},
+	}
+	if input.NormaliseEmail != nil {
+		result.NormaliseEmail = input.NormaliseEmail
+	}
+
+	var err error
+	result.DisposableDomains, err = normaliseDomains(append(append([]string{}, defaultDisposableDomains...), input.DisposableDomains...))
+	if err != nil {
+		return TypeNormalisedInput{}, err
+	}
+	if len(input.AllowedDomains) > 0 {
+		result.AllowedDomains, err = normaliseDomains(input.AllowedDomains)
+		if err != nil {
+			return TypeNormalisedInput{}, err
+		}
+	}
+	result.DeniedDomains, err = normaliseDomains(input.DeniedDomains)
+	if err != nil {
+		return TypeNormalisedInput{}, err
+	}
+	for tenantId, domains := range input.TenantAllowedDomains {
+		result.TenantAllowedDomains[tenantId], err = normaliseDomains(domains)
+		if err != nil {
+			return TypeNormalisedInput{}, err
+		}
+	}
+	for tenantId, domains := range input.TenantDeniedDomains {
+		result.TenantDeniedDomains[tenantId], err = normaliseDomains(domains)
+		if err != nil {
+			return TypeNormalisedInput{}, err
+		}
+	}
+	return result, nil
+}
+
+// DefaultNormaliseEmail trims the email and makes it lower case
+func DefaultNormaliseEmail(email string, tenantId string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// NormaliseEmailWithProviderAliases normalises the email like DefaultNormaliseEmail and removes
+// the aliases of providers that deliver them to the same inbox: plus tags for Gmail, Outlook,
+// iCloud, Fastmail and Proton, and dots for Gmail.
+func NormaliseEmailWithProviderAliases(email string, tenantId string) string {
+	email = DefaultNormaliseEmail(email, tenantId)
+	at := strings.LastIndex(email, "@")
+	if at <= 0 {
+		return email
+	}
+	localPart, domain := email[:at], email[at+1:]
+
+	switch domain {
+	case "gmail.com", "googlemail.com":
+		domain = "gmail.com"
+		localPart = strings.ReplaceAll(removePlusTag(localPart), ".", "")
+	case "outlook.com", "hotmail.com", "live.com", "icloud.com", "me.com", "mac.com",
+		"fastmail.com", "protonmail.com", "proton.me", "pm.me":
+		localPart = removePlusTag(localPart)
+	}
+	if localPart == "" {
+		return email
+	}
+	return localPart + "@" + domain
+}
+
+// Normalise returns the email normalised with the config. config can be nil, in which case the
+// email is returned as given.
+func Normalise(config *TypeNormalisedInput, email string, tenantId string) string {
+	if config == nil {
+		return email
+	}
+	return config.NormaliseEmail(email, tenantId)
+}
+
+// CheckSignUpAllowed returns the reason why the email cannot be used to sign up in the tenant,
+// or nil if it can be used. config can be nil, in which case all emails are allowed.
+func CheckSignUpAllowed(config *TypeNormalisedInput, email string, tenantId string) *Reason {
+	if config == nil {
+		return nil
+	}
+	domain := getDomain(email)
+
+	if matchesDomain(config.DeniedDomains, domain) || matchesDomain(config.TenantDeniedDomains[tenantId], domain) {
+		return makeReason(ReasonDomainDenied)
+	}
+
+	allowedDomains, ok := config.TenantAllowedDomains[tenantId]
+	if !ok {
+		allowedDomains = config.AllowedDomains
+	}
+	if allowedDomains != nil && !matchesDomain(allowedDomains, domain) {
+		return makeReason(ReasonDomainNotAllowed)
+	}
+
+	if config.BlockDisposableEmails && matchesDomain(config.DisposableDomains, domain) {
+		return makeReason(ReasonDisposableEmail)
+	}
+	return nil
+}
+
+func removePlusTag(localPart string) string {
+	if plus := strings.Index(localPart, "+"); plus >= 0 {
+		return localPart[:plus]
+	}
+	return localPart
+}
+
+func getDomain(email string) string {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return ""
+	}
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(email[at+1:])), ".")
+}
+
+// matchesDomain returns true if the domain or one of its parent domains is in domains
+func matchesDomain(domains map[string]bool, domain string) bool {
+	for domain != "" {
+		if domains[domain] {
+			return true
+		}
+		dot := strings.Index(domain, ".")
+		if dot < 0 {
+			return false
+		}
+		domain = domain[dot+1:]
+	}
+	return false
+}
+
+func makeReason(reason Reason) *Reason {
+	return &reason
+}
+
+func normaliseDomains(domains []string) (map[string]bool, error) {
+	result := map[string]bool{}
+	for _, domain := range domains {
+		normalisedDomain := strings.TrimPrefix(strings.ToLower(strings.TrimSpace(domain)), "@")
+		if normalisedDomain == "" || strings.Contains(normalisedDomain, "@") {
+			return nil, errors.New("invalid domain \"" + domain + "\" in the email policy")
+		}
+		result[normalisedDomain] = true
+	}
+	return result, nil
+}
@@ -0,0 +1,86 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package emailpolicy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultNormaliseEmail(t *testing.T) {
+	assert.Equal(t, "john.doe+work@gmail.com", DefaultNormaliseEmail(" John.Doe+Work@Gmail.com ", "public"))
+}
+
+func TestNormaliseEmailWithProviderAliases(t *testing.T) {
+	assert.Equal(t, "johndoe@gmail.com", NormaliseEmailWithProviderAliases("John.Doe+Work@Gmail.com", "public"))
+	assert.Equal(t, "johndoe@gmail.com", NormaliseEmailWithProviderAliases("j.o.h.n.doe@googlemail.com", "public"))
+	assert.Equal(t, "john.doe@outlook.com", NormaliseEmailWithProviderAliases("john.doe+news@outlook.com", "public"))
+	assert.Equal(t, "john.doe+work@example.com", NormaliseEmailWithProviderAliases("john.doe+work@example.com", "public"))
+	assert.Equal(t, "+work@gmail.com", NormaliseEmailWithProviderAliases("+work@gmail.com", "public"))
+	assert.Equal(t, "not an email", NormaliseEmailWithProviderAliases("not an email", "public"))
+}
+
+func TestNormaliseWithoutConfigKeepsTheEmail(t *testing.T) {
+	assert.Equal(t, "John@Example.com", Normalise(nil, "John@Example.com", "public"))
+
+	config, err := NormaliseTypeInput(TypeInput{})
+	assert.NoError(t, err)
+	assert.Equal(t, "john@example.com", Normalise(&config, "John@Example.com", "public"))
+}
+
+func TestCheckSignUpAllowed(t *testing.T) {
+	config, err := NormaliseTypeInput(TypeInput{
+		BlockDisposableEmails: true,
+		DisposableDomains:     []string{"throwaway.example"},
+		DeniedDomains:         []string{"@competitor.com"},
+		TenantAllowedDomains: map[string][]string{
+			"acme": {"acme.com"},
+		},
+		TenantDeniedDomains: map[string][]string{
+			"acme": {"contractors.acme.com"},
+		},
+	})
+	assert.NoError(t, err)
+
+	assert.Nil(t, CheckSignUpAllowed(&config, "john@example.com", "public"))
+	assert.Equal(t, ReasonDisposableEmail, *CheckSignUpAllowed(&config, "john@mailinator.com", "public"))
+	assert.Equal(t, ReasonDisposableEmail, *CheckSignUpAllowed(&config, "john@Throwaway.Example", "public"))
+	assert.Equal(t, ReasonDomainDenied, *CheckSignUpAllowed(&config, "john@mail.competitor.com", "public"))
+
+	assert.Nil(t, CheckSignUpAllowed(&config, "john@acme.com", "acme"))
+	assert.Nil(t, CheckSignUpAllowed(&config, "john@eu.acme.com", "acme"))
+	assert.Equal(t, ReasonDomainNotAllowed, *CheckSignUpAllowed(&config, "john@example.com", "acme"))
+	assert.Equal(t, ReasonDomainDenied, *CheckSignUpAllowed(&config, "john@contractors.acme.com", "acme"))
+	assert.Equal(t, ReasonDomainDenied, *CheckSignUpAllowed(&config, "john@competitor.com", "acme"))
+
+	assert.Nil(t, CheckSignUpAllowed(nil, "john@mailinator.com", "public"))
+}
+
+func TestDisposableEmailsAreAllowedByDefault(t *testing.T) {
+	config, err := NormaliseTypeInput(TypeInput{})
+	assert.NoError(t, err)
+	assert.Nil(t, CheckSignUpAllowed(&config, "john@mailinator.com", "public"))
+}
+
+func TestNormaliseTypeInputRejectsInvalidDomains(t *testing.T) {
+	_, err := NormaliseTypeInput(TypeInput{AllowedDomains: []string{"john@example.com"}})
+	assert.EqualError(t, err, "invalid domain \"john@example.com\" in the email policy")
+
+	_, err = NormaliseTypeInput(TypeInput{DeniedDomains: []string{" "}})
+	assert.EqualError(t, err, "invalid domain \" \" in the email policy")
+}
@@ -0,0 +1,63 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package emailpolicy
+
+// Reason tells why an email cannot be used to sign up
+type Reason string
+
+const (
+	// ReasonDisposableEmail is used when the domain of the email is a known disposable email provider
+	ReasonDisposableEmail Reason = "DISPOSABLE_EMAIL"
+	// ReasonDomainNotAllowed is used when there is an allow list and the domain is not in it
+	ReasonDomainNotAllowed Reason = "DOMAIN_NOT_ALLOWED"
+	// ReasonDomainDenied is used when the domain is in a deny list
+	ReasonDomainDenied Reason = "DOMAIN_DENIED"
+)
+
+type TypeInput struct {
+	// NormaliseEmail is applied to emails before they are stored or looked up. Defaults to
+	// DefaultNormaliseEmail. NormaliseEmailWithProviderAliases also removes the aliases of
+	// well known providers. It must return the same email when called with a normalised email.
+	// Emails that are already in the core are not changed: if no user has the normalised email,
+	// the recipes look up the email as it was given, so that users who signed up before the
+	// policy was set can still sign in.
+	NormaliseEmail func(email string, tenantId string) string
+	// BlockDisposableEmails rejects sign ups using a known disposable email provider
+	BlockDisposableEmails bool
+	// DisposableDomains are added to the built-in list of disposable email providers
+	DisposableDomains []string
+	// AllowedDomains are the only domains that can sign up, if it is not empty. Subdomains of
+	// an allowed domain are allowed.
+	AllowedDomains []string
+	// DeniedDomains cannot sign up, nor can their subdomains
+	DeniedDomains []string
+	// TenantAllowedDomains replaces AllowedDomains for a tenant
+	TenantAllowedDomains map[string][]string
+	// TenantDeniedDomains are denied for a tenant, in addition to DeniedDomains
+	TenantDeniedDomains map[string][]string
+}
+
+type TypeNormalisedInput struct {
+	NormaliseEmail        func(email string, tenantId string) string
+	BlockDisposableEmails bool
+	DisposableDomains     map[string]bool
+	// AllowedDomains is nil if all domains are allowed
+	AllowedDomains       map[string]bool
+	DeniedDomains        map[string]bool
+	TenantAllowedDomains map[string]map[string]bool
+	TenantDeniedDomains  map[string]map[string]bool
+}
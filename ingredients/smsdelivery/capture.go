@@ -0,0 +1,64 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package smsdelivery
+
+import (
+	"errors"
+
+	"github.com/supertokens/supertokens-golang/ingredients/deliverycapture"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+// MakeCaptureService returns a service that stores the rendered SMS in inbox (or
+// deliverycapture.DefaultInbox if it is nil) instead of sending them. It is meant to be used
+// in development and end-to-end tests only.
+func MakeCaptureService(inbox *deliverycapture.Inbox, getDefaultContent GetContentFunc) *SmsDeliveryInterface {
+	inbox = deliverycapture.GetInbox(inbox)
+
+	sendSms := func(input SmsType, userContext supertokens.UserContext) error {
+		if input.PasswordlessLogin == nil {
+			return errors.New("should never come here")
+		}
+		content, err := getDefaultContent(input, userContext)
+		if err != nil {
+			return err
+		}
+
+		message := deliverycapture.Message{
+			Type:      deliverycapture.SmsMessage,
+			Recipient: content.ToPhoneNumber,
+			TenantId:  input.PasswordlessLogin.TenantId,
+			Body:      content.Body,
+			Links:     []string{},
+			Codes:     []string{},
+		}
+		if input.PasswordlessLogin.UrlWithLinkCode != nil {
+			message.Links = append(message.Links, *input.PasswordlessLogin.UrlWithLinkCode)
+		}
+		if input.PasswordlessLogin.UserInputCode != nil {
+			message.Codes = append(message.Codes, *input.PasswordlessLogin.UserInputCode)
+		}
+
+		inbox.Add(message)
+		supertokens.LogDebugMessage("Captured SMS to " + content.ToPhoneNumber)
+		return nil
+	}
+
+	return &SmsDeliveryInterface{
+		SendSms: &sendSms,
+	}
+}
@@ -0,0 +1,116 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package smsdelivery
+
+import (
+	"errors"
+
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+const (
+	DefaultVonageBaseURL      = "https://rest.nexmo.com"
+	DefaultMessageBirdBaseURL = "https://rest.messagebird.com"
+)
+
+// SmsServiceInterface is the equivalent of TwilioInterface for the services that send
+// SMS using an HTTP API (SNS, Vonage and MessageBird)
+type SmsServiceInterface struct {
+	SendRawSms *func(input SMSContent, userContext supertokens.UserContext) error
+	GetContent *func(input SmsType, userContext supertokens.UserContext) (SMSContent, error)
+}
+
+type SNSSettings struct {
+	// Region is used for the default base URL (https://sns.<region>.amazonaws.com) and for signing the requests
+	Region          string
+	AccessKeyId     string
+	SecretAccessKey string
+	SessionToken    *string
+	// SenderID is the alphanumeric sender ID, which is only supported in some countries
+	SenderID *string
+	BaseURL  *string
+}
+
+type SNSServiceConfig struct {
+	Settings SNSSettings
+	Override func(originalImplementation SmsServiceInterface) SmsServiceInterface
+}
+
+type VonageSettings struct {
+	APIKey    string
+	APISecret string
+	From      string
+	BaseURL   *string
+}
+
+type VonageServiceConfig struct {
+	Settings VonageSettings
+	Override func(originalImplementation SmsServiceInterface) SmsServiceInterface
+}
+
+type MessageBirdSettings struct {
+	AccessKey  string
+	Originator string
+	BaseURL    *string
+}
+
+type MessageBirdServiceConfig struct {
+	Settings MessageBirdSettings
+	Override func(originalImplementation SmsServiceInterface) SmsServiceInterface
+}
+
+func NormaliseSNSServiceConfig(input SNSServiceConfig) (SNSServiceConfig, error) {
+	if input.Settings.Region == "" {
+		return SNSServiceConfig{}, errors.New("'Region' must be set")
+	}
+	if input.Settings.AccessKeyId == "" || input.Settings.SecretAccessKey == "" {
+		return SNSServiceConfig{}, errors.New("'AccessKeyId' and 'SecretAccessKey' must be set")
+	}
+	if input.Settings.BaseURL == nil {
+		baseURL := "https://sns." + input.Settings.Region + ".amazonaws.com"
+		input.Settings.BaseURL = &baseURL
+	}
+	return input, nil
+}
+
+func NormaliseVonageServiceConfig(input VonageServiceConfig) (VonageServiceConfig, error) {
+	if input.Settings.APIKey == "" || input.Settings.APISecret == "" {
+		return VonageServiceConfig{}, errors.New("'APIKey' and 'APISecret' must be set")
+	}
+	if input.Settings.From == "" {
+		return VonageServiceConfig{}, errors.New("'From' must be set")
+	}
+	if input.Settings.BaseURL == nil {
+		baseURL := DefaultVonageBaseURL
+		input.Settings.BaseURL = &baseURL
+	}
+	return input, nil
+}
+
+func NormaliseMessageBirdServiceConfig(input MessageBirdServiceConfig) (MessageBirdServiceConfig, error) {
+	if input.Settings.AccessKey == "" {
+		return MessageBirdServiceConfig{}, errors.New("'AccessKey' must be set")
+	}
+	if input.Settings.Originator == "" {
+		return MessageBirdServiceConfig{}, errors.New("'Originator' must be set")
+	}
+	if input.Settings.BaseURL == nil {
+		baseURL := DefaultMessageBirdBaseURL
+		input.Settings.BaseURL = &baseURL
+	}
+	return input, nil
+}
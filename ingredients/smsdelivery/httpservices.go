@@ -0,0 +1,226 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package smsdelivery
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/supertokens/supertokens-golang/ingredients/internal/awssigv4"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+// GetContentFunc returns the default content of an SMS. Every recipe passes its own
+// implementation to the Make*Service functions below.
+type GetContentFunc func(input SmsType, userContext supertokens.UserContext) (SMSContent, error)
+
+var smsHTTPClient = &http.Client{Timeout: 30 * time.Second}
+
+func MakeSNSService(config SNSServiceConfig, getDefaultContent GetContentFunc) (*SmsDeliveryInterface, error) {
+	config, err := NormaliseSNSServiceConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	settings := config.Settings
+	sendRawSms := func(input SMSContent, userContext supertokens.UserContext) error {
+		return SendSNSSms(settings, input, userContext)
+	}
+	return makeHTTPSmsService(sendRawSms, getDefaultContent, config.Override), nil
+}
+
+func MakeVonageService(config VonageServiceConfig, getDefaultContent GetContentFunc) (*SmsDeliveryInterface, error) {
+	config, err := NormaliseVonageServiceConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	settings := config.Settings
+	sendRawSms := func(input SMSContent, userContext supertokens.UserContext) error {
+		return SendVonageSms(settings, input, userContext)
+	}
+	return makeHTTPSmsService(sendRawSms, getDefaultContent, config.Override), nil
+}
+
+func MakeMessageBirdService(config MessageBirdServiceConfig, getDefaultContent GetContentFunc) (*SmsDeliveryInterface, error) {
+	config, err := NormaliseMessageBirdServiceConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	settings := config.Settings
+	sendRawSms := func(input SMSContent, userContext supertokens.UserContext) error {
+		return SendMessageBirdSms(settings, input, userContext)
+	}
+	return makeHTTPSmsService(sendRawSms, getDefaultContent, config.Override), nil
+}
+
+func makeHTTPSmsService(sendRawSms func(input SMSContent, userContext supertokens.UserContext) error, getDefaultContent GetContentFunc, override func(originalImplementation SmsServiceInterface) SmsServiceInterface) *SmsDeliveryInterface {
+	getContent := func(input SmsType, userContext supertokens.UserContext) (SMSContent, error) {
+		return getDefaultContent(input, userContext)
+	}
+
+	serviceImpl := SmsServiceInterface{
+		SendRawSms: &sendRawSms,
+		GetContent: &getContent,
+	}
+	if override != nil {
+		serviceImpl = override(serviceImpl)
+	}
+
+	sendSms := func(input SmsType, userContext supertokens.UserContext) error {
+		if input.PasswordlessLogin == nil {
+			return errors.New("should never come here")
+		}
+		content, err := (*serviceImpl.GetContent)(input, userContext)
+		if err != nil {
+			return err
+		}
+		return (*serviceImpl.SendRawSms)(content, userContext)
+	}
+
+	return &SmsDeliveryInterface{
+		SendSms: &sendSms,
+	}
+}
+
+func SendSNSSms(settings SNSSettings, content SMSContent, userContext supertokens.UserContext) error {
+	form := url.Values{}
+	form.Set("Action", "Publish")
+	form.Set("Version", "2010-03-31")
+	form.Set("PhoneNumber", content.ToPhoneNumber)
+	form.Set("Message", content.Body)
+	form.Set("MessageAttributes.entry.1.Name", "AWS.SNS.SMS.SMSType")
+	form.Set("MessageAttributes.entry.1.Value.DataType", "String")
+	form.Set("MessageAttributes.entry.1.Value.StringValue", "Transactional")
+	if settings.SenderID != nil {
+		form.Set("MessageAttributes.entry.2.Name", "AWS.SNS.SMS.SenderID")
+		form.Set("MessageAttributes.entry.2.Value.DataType", "String")
+		form.Set("MessageAttributes.entry.2.Value.StringValue", *settings.SenderID)
+	}
+	body := []byte(form.Encode())
+
+	req, err := http.NewRequestWithContext(supertokens.GetContextFromUserContext(userContext), "POST", getBaseURL(settings.BaseURL, "https://sns."+settings.Region+".amazonaws.com")+"/", bytes.NewBuffer(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("content-type", "application/x-www-form-urlencoded; charset=utf-8")
+	awssigv4.Sign(req, body, awssigv4.Credentials{
+		AccessKeyId:     settings.AccessKeyId,
+		SecretAccessKey: settings.SecretAccessKey,
+		SessionToken:    settings.SessionToken,
+	}, settings.Region, "sns", time.Now())
+
+	_, err = doSmsRequest(req, "SNS", content.ToPhoneNumber)
+	return err
+}
+
+func SendVonageSms(settings VonageSettings, content SMSContent, userContext supertokens.UserContext) error {
+	form := url.Values{}
+	form.Set("api_key", settings.APIKey)
+	form.Set("api_secret", settings.APISecret)
+	form.Set("from", settings.From)
+	// Vonage expects the number in the E.164 format without the leading +
+	form.Set("to", strings.TrimPrefix(content.ToPhoneNumber, "+"))
+	form.Set("text", content.Body)
+
+	req, err := http.NewRequestWithContext(supertokens.GetContextFromUserContext(userContext), "POST", getBaseURL(settings.BaseURL, DefaultVonageBaseURL)+"/sms/json", strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("content-type", "application/x-www-form-urlencoded")
+
+	respBody, err := doSmsRequest(req, "Vonage", content.ToPhoneNumber)
+	if err != nil {
+		return err
+	}
+
+	// The Vonage SMS API responds with 200 even if the message was not sent, the
+	// result is in the status of every message in the response
+	var result struct {
+		Messages []struct {
+			Status    string `json:"status"`
+			ErrorText string `json:"error-text"`
+		} `json:"messages"`
+	}
+	err = json.Unmarshal(respBody, &result)
+	if err != nil {
+		return err
+	}
+	for _, message := range result.Messages {
+		if message.Status != "0" {
+			return fmt.Errorf("Error sending SMS. Vonage API returned status %s: %s", message.Status, message.ErrorText)
+		}
+	}
+	return nil
+}
+
+func SendMessageBirdSms(settings MessageBirdSettings, content SMSContent, userContext supertokens.UserContext) error {
+	data := map[string]interface{}{
+		"originator": settings.Originator,
+		"recipients": []string{strings.TrimPrefix(content.ToPhoneNumber, "+")},
+		"body":       content.Body,
+	}
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(supertokens.GetContextFromUserContext(userContext), "POST", getBaseURL(settings.BaseURL, DefaultMessageBirdBaseURL)+"/messages", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("content-type", "application/json")
+	req.Header.Set("authorization", "AccessKey "+settings.AccessKey)
+
+	_, err = doSmsRequest(req, "MessageBird", content.ToPhoneNumber)
+	return err
+}
+
+// doSmsRequest sends the request and returns the body of the response if the status is 2xx
+func doSmsRequest(req *http.Request, provider string, toPhoneNumber string) ([]byte, error) {
+	resp, err := smsHTTPClient.Do(req)
+	if err != nil {
+		supertokens.LogDebugMessage(fmt.Sprintf("Error sending SMS using %s: %s", provider, err.Error()))
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode >= 300 {
+		supertokens.LogDebugMessage(fmt.Sprintf("Error response: %s", string(body)))
+		return nil, fmt.Errorf("Error sending SMS. %s API returned %d status.", provider, resp.StatusCode)
+	}
+
+	supertokens.LogDebugMessage(fmt.Sprintf("SMS to %s accepted by %s", toPhoneNumber, provider))
+	return body, nil
+}
+
+func getBaseURL(baseURL *string, defaultBaseURL string) string {
+	if baseURL == nil {
+		return defaultBaseURL
+	}
+	return strings.TrimSuffix(*baseURL, "/")
+}
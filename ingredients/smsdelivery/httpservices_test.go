@@ -0,0 +1,261 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package smsdelivery
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+type capturedRequest struct {
+	Path   string
+	Header http.Header
+	Body   []byte
+}
+
+func startTestSmsServer(t *testing.T, status int, responseBody string) (*httptest.Server, *[]capturedRequest) {
+	requests := []capturedRequest{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		assert.NoError(t, err)
+		requests = append(requests, capturedRequest{
+			Path:   r.URL.Path,
+			Header: r.Header,
+			Body:   body,
+		})
+		w.WriteHeader(status)
+		w.Write([]byte(responseBody))
+	}))
+	return server, &requests
+}
+
+func getTestDefaultContent(input SmsType, userContext supertokens.UserContext) (SMSContent, error) {
+	return SMSContent{
+		Body:          "OTP is " + *input.PasswordlessLogin.UserInputCode,
+		ToPhoneNumber: input.PasswordlessLogin.PhoneNumber,
+	}, nil
+}
+
+func getTestSmsInput(phoneNumber string) SmsType {
+	userInputCode := "123456"
+	return SmsType{
+		PasswordlessLogin: &PasswordlessLoginType{
+			PhoneNumber:   phoneNumber,
+			UserInputCode: &userInputCode,
+			CodeLifetime:  900000,
+			TenantId:      "public",
+		},
+	}
+}
+
+func TestSNSService(t *testing.T) {
+	server, requests := startTestSmsServer(t, 200, "<PublishResponse></PublishResponse>")
+	defer server.Close()
+
+	senderID := "SuperTokens"
+	service, err := MakeSNSService(SNSServiceConfig{
+		Settings: SNSSettings{
+			Region:          "ap-south-1",
+			AccessKeyId:     "AKID",
+			SecretAccessKey: "secret",
+			SenderID:        &senderID,
+			BaseURL:         &server.URL,
+		},
+	}, getTestDefaultContent)
+	assert.NoError(t, err)
+
+	err = (*service.SendSms)(getTestSmsInput("+919876543210"), &map[string]interface{}{})
+	assert.NoError(t, err)
+	assert.Len(t, *requests, 1)
+
+	req := (*requests)[0]
+	assert.Equal(t, "/", req.Path)
+	assert.Contains(t, req.Header.Get("authorization"), "/ap-south-1/sns/aws4_request, SignedHeaders=content-type;host;x-amz-date, ")
+	form, err := url.ParseQuery(string(req.Body))
+	assert.NoError(t, err)
+	assert.Equal(t, "Publish", form.Get("Action"))
+	assert.Equal(t, "+919876543210", form.Get("PhoneNumber"))
+	assert.Equal(t, "OTP is 123456", form.Get("Message"))
+	assert.Equal(t, "AWS.SNS.SMS.SenderID", form.Get("MessageAttributes.entry.2.Name"))
+	assert.Equal(t, "SuperTokens", form.Get("MessageAttributes.entry.2.Value.StringValue"))
+}
+
+func TestVonageService(t *testing.T) {
+	server, requests := startTestSmsServer(t, 200, `{"message-count":"1","messages":[{"status":"0"}]}`)
+	defer server.Close()
+
+	service, err := MakeVonageService(VonageServiceConfig{
+		Settings: VonageSettings{
+			APIKey:    "key",
+			APISecret: "secret",
+			From:      "SuperTokens",
+			BaseURL:   &server.URL,
+		},
+	}, getTestDefaultContent)
+	assert.NoError(t, err)
+
+	err = (*service.SendSms)(getTestSmsInput("+447700900000"), &map[string]interface{}{})
+	assert.NoError(t, err)
+	assert.Len(t, *requests, 1)
+
+	req := (*requests)[0]
+	assert.Equal(t, "/sms/json", req.Path)
+	form, err := url.ParseQuery(string(req.Body))
+	assert.NoError(t, err)
+	assert.Equal(t, "key", form.Get("api_key"))
+	assert.Equal(t, "secret", form.Get("api_secret"))
+	assert.Equal(t, "447700900000", form.Get("to"))
+	assert.Equal(t, "OTP is 123456", form.Get("text"))
+}
+
+func TestVonageServiceReturnsErrorForFailedMessage(t *testing.T) {
+	server, _ := startTestSmsServer(t, 200, `{"message-count":"1","messages":[{"status":"4","error-text":"Bad Credentials"}]}`)
+	defer server.Close()
+
+	service, err := MakeVonageService(VonageServiceConfig{
+		Settings: VonageSettings{
+			APIKey:    "key",
+			APISecret: "wrong",
+			From:      "SuperTokens",
+			BaseURL:   &server.URL,
+		},
+	}, getTestDefaultContent)
+	assert.NoError(t, err)
+
+	err = (*service.SendSms)(getTestSmsInput("+447700900000"), &map[string]interface{}{})
+	assert.EqualError(t, err, "Error sending SMS. Vonage API returned status 4: Bad Credentials")
+}
+
+func TestMessageBirdService(t *testing.T) {
+	server, requests := startTestSmsServer(t, 201, `{}`)
+	defer server.Close()
+
+	service, err := MakeMessageBirdService(MessageBirdServiceConfig{
+		Settings: MessageBirdSettings{
+			AccessKey:  "key",
+			Originator: "SuperTokens",
+			BaseURL:    &server.URL,
+		},
+	}, getTestDefaultContent)
+	assert.NoError(t, err)
+
+	err = (*service.SendSms)(getTestSmsInput("+31612345678"), &map[string]interface{}{})
+	assert.NoError(t, err)
+	assert.Len(t, *requests, 1)
+
+	req := (*requests)[0]
+	assert.Equal(t, "/messages", req.Path)
+	assert.Equal(t, "AccessKey key", req.Header.Get("authorization"))
+	assert.JSONEq(t, `{"originator":"SuperTokens","recipients":["31612345678"],"body":"OTP is 123456"}`, string(req.Body))
+}
+
+func TestMessageBirdServiceWithOverride(t *testing.T) {
+	server, requests := startTestSmsServer(t, 422, `{"errors":[]}`)
+	defer server.Close()
+
+	service, err := MakeMessageBirdService(MessageBirdServiceConfig{
+		Settings: MessageBirdSettings{
+			AccessKey:  "key",
+			Originator: "SuperTokens",
+			BaseURL:    &server.URL,
+		},
+		Override: func(originalImplementation SmsServiceInterface) SmsServiceInterface {
+			getContent := func(input SmsType, userContext supertokens.UserContext) (SMSContent, error) {
+				return SMSContent{
+					Body:          "Custom",
+					ToPhoneNumber: input.PasswordlessLogin.PhoneNumber,
+				}, nil
+			}
+			originalImplementation.GetContent = &getContent
+			return originalImplementation
+		},
+	}, getTestDefaultContent)
+	assert.NoError(t, err)
+
+	err = (*service.SendSms)(getTestSmsInput("+31612345678"), &map[string]interface{}{})
+	assert.EqualError(t, err, "Error sending SMS. MessageBird API returned 422 status.")
+	assert.Contains(t, string((*requests)[0].Body), `"body":"Custom"`)
+}
+
+func makeTestRoutingService(name string, err error, calls *[]string) SmsDeliveryInterface {
+	sendSms := func(input SmsType, userContext supertokens.UserContext) error {
+		*calls = append(*calls, name)
+		return err
+	}
+	return SmsDeliveryInterface{SendSms: &sendSms}
+}
+
+func TestRoutingServicePicksServiceByCountryPrefix(t *testing.T) {
+	calls := []string{}
+	india := makeTestRoutingService("india", nil, &calls)
+	usCanada := makeTestRoutingService("usCanada", nil, &calls)
+	canada := makeTestRoutingService("canada", nil, &calls)
+	fallback := makeTestRoutingService("fallback", nil, &calls)
+
+	service, err := MakeRoutingService(RoutingServiceConfig{
+		Routes: []SmsRoute{
+			{CountryPrefixes: []string{"91"}, Services: []SmsDeliveryInterface{india}},
+			{CountryPrefixes: []string{"+1"}, Services: []SmsDeliveryInterface{usCanada}},
+			{CountryPrefixes: []string{"+1416", "+1647"}, Services: []SmsDeliveryInterface{canada}},
+		},
+		DefaultServices: []SmsDeliveryInterface{fallback},
+	})
+	assert.NoError(t, err)
+
+	for _, phoneNumber := range []string{"+919876543210", "+12025550123", "+14165550123", "+447700900000"} {
+		assert.NoError(t, (*service.SendSms)(getTestSmsInput(phoneNumber), &map[string]interface{}{}))
+	}
+	assert.Equal(t, []string{"india", "usCanada", "canada", "fallback"}, calls)
+}
+
+func TestRoutingServiceFallsBackOnFailure(t *testing.T) {
+	calls := []string{}
+	primary := makeTestRoutingService("primary", errors.New("primary failed"), &calls)
+	secondary := makeTestRoutingService("secondary", errors.New("secondary failed"), &calls)
+	fallback := makeTestRoutingService("fallback", nil, &calls)
+
+	service, err := MakeRoutingService(RoutingServiceConfig{
+		Routes: []SmsRoute{
+			{CountryPrefixes: []string{"+91"}, Services: []SmsDeliveryInterface{primary, secondary}},
+		},
+		DefaultServices: []SmsDeliveryInterface{secondary, fallback},
+	})
+	assert.NoError(t, err)
+
+	assert.NoError(t, (*service.SendSms)(getTestSmsInput("+919876543210"), &map[string]interface{}{}))
+	assert.Equal(t, []string{"primary", "secondary", "fallback"}, calls)
+
+	failing, err := MakeRoutingService(RoutingServiceConfig{
+		DefaultServices: []SmsDeliveryInterface{primary, secondary},
+	})
+	assert.NoError(t, err)
+	err = (*failing.SendSms)(getTestSmsInput("+919876543210"), &map[string]interface{}{})
+	assert.EqualError(t, err, "secondary failed")
+}
+
+func TestRoutingServiceConfigValidation(t *testing.T) {
+	_, err := MakeRoutingService(RoutingServiceConfig{})
+	assert.True(t, strings.Contains(err.Error(), "DefaultServices"))
+}
@@ -0,0 +1,127 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package smsdelivery
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+type SmsRoute struct {
+	// CountryPrefixes are matched against the start of the phone number, for example "+91"
+	// or "+1". If the prefixes of several routes match, the longest one is used.
+	CountryPrefixes []string
+	// Services are tried in order until one of them sends the SMS
+	Services []SmsDeliveryInterface
+}
+
+type RoutingServiceConfig struct {
+	Routes []SmsRoute
+	// DefaultServices are used for phone numbers that do not match any route. They are
+	// also tried (in order) if all the services of the matching route fail.
+	DefaultServices []SmsDeliveryInterface
+}
+
+func NormaliseRoutingServiceConfig(input RoutingServiceConfig) (RoutingServiceConfig, error) {
+	if len(input.DefaultServices) == 0 {
+		return RoutingServiceConfig{}, errors.New("at least one service must be set in 'DefaultServices'")
+	}
+	routes := []SmsRoute{}
+	for _, route := range input.Routes {
+		if len(route.CountryPrefixes) == 0 || len(route.Services) == 0 {
+			return RoutingServiceConfig{}, errors.New("every route must have at least one country prefix and one service")
+		}
+		prefixes := []string{}
+		for _, prefix := range route.CountryPrefixes {
+			prefix = strings.TrimSpace(prefix)
+			if !strings.HasPrefix(prefix, "+") {
+				prefix = "+" + prefix
+			}
+			prefixes = append(prefixes, prefix)
+		}
+		routes = append(routes, SmsRoute{
+			CountryPrefixes: prefixes,
+			Services:        route.Services,
+		})
+	}
+	input.Routes = routes
+	return input, nil
+}
+
+// MakeRoutingService returns a service that picks the services to send an SMS with based on
+// the country prefix of the phone number, falling back to the next service if one fails.
+func MakeRoutingService(config RoutingServiceConfig) (*SmsDeliveryInterface, error) {
+	config, err := NormaliseRoutingServiceConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	sendSms := func(input SmsType, userContext supertokens.UserContext) error {
+		if input.PasswordlessLogin == nil {
+			return errors.New("should never come here")
+		}
+
+		var lastErr error
+		for _, service := range getServicesForPhoneNumber(config, input.PasswordlessLogin.PhoneNumber) {
+			lastErr = (*service.SendSms)(input, userContext)
+			if lastErr == nil {
+				return nil
+			}
+			supertokens.LogDebugMessage("MakeRoutingService: service failed, trying the next one: " + lastErr.Error())
+		}
+		return lastErr
+	}
+
+	return &SmsDeliveryInterface{
+		SendSms: &sendSms,
+	}, nil
+}
+
+func getServicesForPhoneNumber(config RoutingServiceConfig, phoneNumber string) []SmsDeliveryInterface {
+	phoneNumber = strings.ReplaceAll(phoneNumber, " ", "")
+
+	var matchedRoute *SmsRoute
+	matchedPrefixLength := 0
+	for i, route := range config.Routes {
+		for _, prefix := range route.CountryPrefixes {
+			if strings.HasPrefix(phoneNumber, prefix) && len(prefix) > matchedPrefixLength {
+				matchedRoute = &config.Routes[i]
+				matchedPrefixLength = len(prefix)
+			}
+		}
+	}
+
+	services := []SmsDeliveryInterface{}
+	if matchedRoute != nil {
+		services = append(services, matchedRoute.Services...)
+	}
+	for _, service := range config.DefaultServices {
+		alreadyAdded := false
+		for _, added := range services {
+			if added.SendSms == service.SendSms {
+				alreadyAdded = true
+				break
+			}
+		}
+		if !alreadyAdded {
+			services = append(services, service)
+		}
+	}
+	return services
+}
@@ -0,0 +1,38 @@
+package api
+
+import (
+	"github.com/supertokens/supertokens-golang/recipe/dashboard/dashboardmodels"
+	"github.com/supertokens/supertokens-golang/recipe/invitations"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+type inviteDeleteResponse struct {
+	Status         string `json:"status"`
+	DidInviteExist bool   `json:"didInviteExist"`
+}
+
+func InviteDelete(apiInterface dashboardmodels.APIInterface, tenantId string, options dashboardmodels.APIOptions, userContext supertokens.UserContext) (inviteDeleteResponse, error) {
+	inviteId := options.Req.URL.Query().Get("inviteId")
+
+	if inviteId == "" {
+		return inviteDeleteResponse{}, supertokens.BadInputError{
+			Msg: "Missing required parameter 'inviteId'",
+		}
+	}
+
+	if invitations.GetRecipeInstance() == nil {
+		return inviteDeleteResponse{
+			Status: "FEATURE_NOT_ENABLED_ERROR",
+		}, nil
+	}
+
+	response, err := invitations.RevokeInvite(inviteId, userContext)
+	if err != nil {
+		return inviteDeleteResponse{}, err
+	}
+
+	return inviteDeleteResponse{
+		Status:         "OK",
+		DidInviteExist: response.OK.DidInviteExist,
+	}, nil
+}
@@ -0,0 +1,65 @@
+package api
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/supertokens/supertokens-golang/recipe/dashboard/dashboardmodels"
+	"github.com/supertokens/supertokens-golang/recipe/invitations"
+	"github.com/supertokens/supertokens-golang/recipe/invitations/invitationsmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+type invitePostResponse struct {
+	Status string                    `json:"status"`
+	Invite *invitationsmodels.Invite `json:"invite,omitempty"`
+	Role   string                    `json:"role,omitempty"`
+}
+
+type invitePostRequestBody struct {
+	Email *string  `json:"email"`
+	Roles []string `json:"roles"`
+}
+
+// InvitePost creates an invite in the tenant and sends the invitation email
+func InvitePost(apiInterface dashboardmodels.APIInterface, tenantId string, options dashboardmodels.APIOptions, userContext supertokens.UserContext) (invitePostResponse, error) {
+	body, err := supertokens.ReadFromRequest(options.Req)
+	if err != nil {
+		return invitePostResponse{}, err
+	}
+
+	var readBody invitePostRequestBody
+	err = json.Unmarshal(body, &readBody)
+	if err != nil {
+		return invitePostResponse{}, err
+	}
+
+	if readBody.Email == nil || strings.TrimSpace(*readBody.Email) == "" {
+		return invitePostResponse{}, supertokens.BadInputError{
+			Msg: "Required parameter 'email' is missing",
+		}
+	}
+
+	if invitations.GetRecipeInstance() == nil {
+		return invitePostResponse{
+			Status: "FEATURE_NOT_ENABLED_ERROR",
+		}, nil
+	}
+
+	response, err := invitations.CreateInvite(tenantId, *readBody.Email, readBody.Roles, userContext)
+	if err != nil {
+		return invitePostResponse{}, err
+	}
+
+	if response.UnknownRoleError != nil {
+		return invitePostResponse{
+			Status: "UNKNOWN_ROLE_ERROR",
+			Role:   response.UnknownRoleError.Role,
+		}, nil
+	}
+
+	return invitePostResponse{
+		Status: "OK",
+		Invite: &response.OK.Invite,
+	}, nil
+}
@@ -0,0 +1,32 @@
+package api
+
+import (
+	"github.com/supertokens/supertokens-golang/recipe/dashboard/dashboardmodels"
+	"github.com/supertokens/supertokens-golang/recipe/invitations"
+	"github.com/supertokens/supertokens-golang/recipe/invitations/invitationsmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+type invitesGetResponse struct {
+	Status  string                     `json:"status"`
+	Invites []invitationsmodels.Invite `json:"invites"`
+}
+
+// InvitesGet lists the invites of the tenant that have not been used and have not expired
+func InvitesGet(apiInterface dashboardmodels.APIInterface, tenantId string, options dashboardmodels.APIOptions, userContext supertokens.UserContext) (invitesGetResponse, error) {
+	if invitations.GetRecipeInstance() == nil {
+		return invitesGetResponse{
+			Status: "FEATURE_NOT_ENABLED_ERROR",
+		}, nil
+	}
+
+	invites, err := invitations.ListInvites(tenantId, userContext)
+	if err != nil {
+		return invitesGetResponse{}, err
+	}
+
+	return invitesGetResponse{
+		Status:  "OK",
+		Invites: invites,
+	}, nil
+}
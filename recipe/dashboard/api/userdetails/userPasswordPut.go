@@ -21,13 +21,16 @@ import (
 
 	"github.com/supertokens/supertokens-golang/recipe/dashboard/dashboardmodels"
 	"github.com/supertokens/supertokens-golang/recipe/emailpassword"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/api"
 	"github.com/supertokens/supertokens-golang/recipe/emailpassword/epmodels"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/passwordpolicy"
 	"github.com/supertokens/supertokens-golang/supertokens"
 )
 
 type userPasswordPutResponse struct {
-	Status string `json:"status,omitempty"`
-	Error  string `json:"error,omitempty"`
+	Status     string                     `json:"status,omitempty"`
+	Error      string                     `json:"error,omitempty"`
+	Violations []passwordpolicy.Violation `json:"violations,omitempty"`
 }
 
 type userPasswordPutRequestBody struct {
@@ -90,6 +93,25 @@ func UserPasswordPut(apiInterface dashboardmodels.APIInterface, tenantId string,
 		}, nil
 	}
 
+	if emailPasswordInstance.Config.PasswordPolicy != nil || emailPasswordInstance.Config.BreachedPasswordCheck != nil {
+		userInputs := []string{}
+		user, err := emailpassword.GetUserByID(*readBody.UserId, userContext)
+		if err != nil {
+			return userPasswordPutResponse{}, err
+		}
+		if user != nil {
+			userInputs = append(userInputs, user.Email)
+		}
+		violations := api.ValidatePassword(emailPasswordInstance.Config, *readBody.NewPassword, userInputs, tenantId, userContext)
+		if len(violations) > 0 {
+			return userPasswordPutResponse{
+				Status:     "INVALID_PASSWORD_ERROR",
+				Error:      passwordpolicy.GetFailureReason(violations),
+				Violations: violations,
+			}, nil
+		}
+	}
+
 	passwordResetToken, resetTokenErr := emailpassword.CreateResetPasswordToken(tenantId, *readBody.UserId, userContext)
 
 	if resetTokenErr != nil {
@@ -107,6 +129,14 @@ func UserPasswordPut(apiInterface dashboardmodels.APIInterface, tenantId string,
 		return userPasswordPutResponse{}, passwordResetErr
 	}
 
+	if passwordResetResponse.PasswordPolicyViolatedError != nil {
+		return userPasswordPutResponse{
+			Status:     "INVALID_PASSWORD_ERROR",
+			Error:      passwordResetResponse.PasswordPolicyViolatedError.FailureReason,
+			Violations: passwordResetResponse.PasswordPolicyViolatedError.Violations,
+		}, nil
+	}
+
 	if passwordResetResponse.ResetPasswordInvalidTokenError != nil {
 		return userPasswordPutResponse{}, errors.New("Should never come here")
 	}
@@ -160,6 +160,20 @@ func updatePhoneForRecipeId(recipeId string, userId string, phone string, tenant
 
 		passwordlessConfig := passwordless.GetRecipeInstance().Config
 
+		if passwordlessConfig.PhoneNumber != nil {
+			normalisedPhone, phoneValidationError, err := passwordless.NormalisePhoneNumber(tenantId, phone)
+			if err != nil {
+				return updatePhoneResponse{}, err
+			}
+			if phoneValidationError != nil {
+				return updatePhoneResponse{
+					Status: "INVALID_PHONE_ERROR",
+					Error:  phoneValidationError.Message,
+				}, nil
+			}
+			phone = normalisedPhone
+		}
+
 		if passwordlessConfig.ContactMethodEmail.Enabled {
 			validationResult := passwordless.DefaultValidatePhoneNumber(phone, tenantId)
 
@@ -0,0 +1,82 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package userdetails
+
+import (
+	"encoding/json"
+
+	"github.com/supertokens/supertokens-golang/recipe/dashboard/dashboardmodels"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+type userUnlockPostResponse struct {
+	Status string `json:"status"`
+}
+
+type userUnlockPostRequestBody struct {
+	UserId *string `json:"userId"`
+}
+
+// UserUnlockPost unlocks the emailpassword account of the user in all its tenants
+func UserUnlockPost(apiInterface dashboardmodels.APIInterface, tenantId string, options dashboardmodels.APIOptions, userContext supertokens.UserContext) (userUnlockPostResponse, error) {
+	body, err := supertokens.ReadFromRequest(options.Req)
+
+	if err != nil {
+		return userUnlockPostResponse{}, err
+	}
+
+	var readBody userUnlockPostRequestBody
+	err = json.Unmarshal(body, &readBody)
+	if err != nil {
+		return userUnlockPostResponse{}, err
+	}
+
+	if readBody.UserId == nil {
+		return userUnlockPostResponse{}, supertokens.BadInputError{
+			Msg: "Required parameter 'userId' is missing",
+		}
+	}
+
+	if emailpassword.GetRecipeInstance() == nil {
+		return userUnlockPostResponse{
+			Status: "UNKNOWN_USER_ID_ERROR",
+		}, nil
+	}
+
+	user, err := emailpassword.GetUserByID(*readBody.UserId, userContext)
+	if err != nil {
+		return userUnlockPostResponse{}, err
+	}
+
+	if user == nil {
+		return userUnlockPostResponse{
+			Status: "UNKNOWN_USER_ID_ERROR",
+		}, nil
+	}
+
+	for _, userTenantId := range user.TenantIds {
+		err = emailpassword.UnlockAccount(userTenantId, user.Email, userContext)
+		if err != nil {
+			return userUnlockPostResponse{}, err
+		}
+	}
+
+	return userUnlockPostResponse{
+		Status: "OK",
+	}, nil
+}
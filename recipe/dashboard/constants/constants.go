@@ -26,7 +26,10 @@ const UserEmailVerifyAPI = "/api/user/email/verify"
 const UserMetadataAPI = "/api/user/metadata"
 const UserSessionsAPI = "/api/user/sessions"
 const UserPasswordAPI = "/api/user/password"
+const UserUnlockAPI = "/api/user/unlock"
 const UserEmailVerifyTokenAPI = "/api/user/email/verify/token"
 const SearchTagsAPI = "/api/search/tags"
 const DashboardAnalyticsAPI = "/api/analytics"
 const TenantsListAPI = "/api/tenants/list"
+const InviteAPI = "/api/invite"
+const InvitesAPI = "/api/invites"
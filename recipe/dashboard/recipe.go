@@ -128,6 +128,10 @@ func (r *Recipe) getAPIsHandled() ([]supertokens.APIHandled, error) {
 	if err != nil {
 		return nil, err
 	}
+	userUnlockAPI, err := supertokens.NewNormalisedURLPath(constants.UserUnlockAPI)
+	if err != nil {
+		return nil, err
+	}
 	searchTagsAPI, err := supertokens.NewNormalisedURLPath(constants.SearchTagsAPI)
 	if err != nil {
 		return nil, err
@@ -140,6 +144,14 @@ func (r *Recipe) getAPIsHandled() ([]supertokens.APIHandled, error) {
 	if err != nil {
 		return nil, err
 	}
+	inviteAPI, err := supertokens.NewNormalisedURLPath(constants.InviteAPI)
+	if err != nil {
+		return nil, err
+	}
+	invitesAPI, err := supertokens.NewNormalisedURLPath(constants.InvitesAPI)
+	if err != nil {
+		return nil, err
+	}
 
 	return []supertokens.APIHandled{
 		{
@@ -244,6 +256,12 @@ func (r *Recipe) getAPIsHandled() ([]supertokens.APIHandled, error) {
 			Method:                 http.MethodPut,
 			Disabled:               false,
 		},
+		{
+			ID:                     constants.UserUnlockAPI,
+			PathWithoutAPIBasePath: dashboardApiBasePath.AppendPath(userUnlockAPI),
+			Method:                 http.MethodPost,
+			Disabled:               false,
+		},
 		{
 			ID:                     constants.UserEmailVerifyTokenAPI,
 			PathWithoutAPIBasePath: dashboardApiBasePath.AppendPath(userEmailVerifyTokenAPI),
@@ -268,6 +286,24 @@ func (r *Recipe) getAPIsHandled() ([]supertokens.APIHandled, error) {
 			Method:                 http.MethodGet,
 			Disabled:               false,
 		},
+		{
+			ID:                     constants.InviteAPI,
+			PathWithoutAPIBasePath: dashboardApiBasePath.AppendPath(inviteAPI),
+			Method:                 http.MethodPost,
+			Disabled:               false,
+		},
+		{
+			ID:                     constants.InviteAPI,
+			PathWithoutAPIBasePath: dashboardApiBasePath.AppendPath(inviteAPI),
+			Method:                 http.MethodDelete,
+			Disabled:               false,
+		},
+		{
+			ID:                     constants.InvitesAPI,
+			PathWithoutAPIBasePath: dashboardApiBasePath.AppendPath(invitesAPI),
+			Method:                 http.MethodGet,
+			Disabled:               false,
+		},
 	}, nil
 }
 
@@ -335,6 +371,8 @@ func (r *Recipe) handleAPIRequest(id string, tenantId string, req *http.Request,
 			return userdetails.UserEmailVerifyTokenPost(r.APIImpl, tenantId, options, userContext)
 		} else if id == constants.UserPasswordAPI {
 			return userdetails.UserPasswordPut(r.APIImpl, tenantId, options, userContext)
+		} else if id == constants.UserUnlockAPI {
+			return userdetails.UserUnlockPost(r.APIImpl, tenantId, options, userContext)
 		} else if id == constants.SearchTagsAPI {
 			return search.SearchTagsGet(r.APIImpl, tenantId, options, userContext)
 		} else if id == constants.SignOutAPI {
@@ -343,6 +381,16 @@ func (r *Recipe) handleAPIRequest(id string, tenantId string, req *http.Request,
 			return api.AnalyticsPost(r.APIImpl, tenantId, options, userContext)
 		} else if id == constants.TenantsListAPI {
 			return api.TenantsListGet(r.APIImpl, tenantId, options, userContext)
+		} else if id == constants.InviteAPI {
+			if req.Method == http.MethodPost {
+				return api.InvitePost(r.APIImpl, tenantId, options, userContext)
+			}
+
+			if req.Method == http.MethodDelete {
+				return api.InviteDelete(r.APIImpl, tenantId, options, userContext)
+			}
+		} else if id == constants.InvitesAPI {
+			return api.InvitesGet(r.APIImpl, tenantId, options, userContext)
 		}
 		return nil, errors.New("should never come here")
 	})
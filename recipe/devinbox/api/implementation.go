@@ -0,0 +1,68 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package api
+
+import (
+	"github.com/supertokens/supertokens-golang/recipe/devinbox/devinboxmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+// ListMessages responds with the captured messages (newest first). If the recipient query
+// param is set, only the messages sent to that email or phone number are returned.
+func ListMessages(options devinboxmodels.APIOptions) error {
+	messages := options.Config.Inbox.GetMessages(getRecipient(options))
+	return supertokens.Send200Response(options.Res, map[string]interface{}{
+		"status":   "OK",
+		"messages": messages,
+	})
+}
+
+// GetLatestMessage responds with the last message sent to the recipient in the query params
+func GetLatestMessage(options devinboxmodels.APIOptions) error {
+	recipient := getRecipient(options)
+	if recipient == nil {
+		return supertokens.BadInputError{Msg: "Please provide the recipient as a query param"}
+	}
+
+	message := options.Config.Inbox.GetLatestMessage(*recipient)
+	if message == nil {
+		return supertokens.Send200Response(options.Res, map[string]interface{}{
+			"status": "NO_MESSAGE_FOUND_ERROR",
+		})
+	}
+	return supertokens.Send200Response(options.Res, map[string]interface{}{
+		"status":  "OK",
+		"message": message,
+	})
+}
+
+// ClearMessages removes the messages sent to the recipient in the query params, or all
+// messages if it is not set
+func ClearMessages(options devinboxmodels.APIOptions) error {
+	options.Config.Inbox.Clear(getRecipient(options))
+	return supertokens.Send200Response(options.Res, map[string]interface{}{
+		"status": "OK",
+	})
+}
+
+func getRecipient(options devinboxmodels.APIOptions) *string {
+	recipient := options.Req.URL.Query().Get("recipient")
+	if recipient == "" {
+		return nil
+	}
+	return &recipient
+}
@@ -0,0 +1,153 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package devinbox
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/ingredients/emaildelivery"
+	"github.com/supertokens/supertokens-golang/ingredients/smsdelivery"
+	"github.com/supertokens/supertokens-golang/recipe/devinbox/devinboxmodels"
+	"github.com/supertokens/supertokens-golang/recipe/passwordless"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func initDevInboxForTest(t *testing.T, enabled bool) *httptest.Server {
+	err := supertokens.Init(supertokens.TypeInput{
+		AppInfo: supertokens.AppInfo{
+			APIDomain:     "api.supertokens.io",
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{
+			Init(&devinboxmodels.TypeInput{
+				Enabled: enabled,
+			}),
+		},
+	})
+	assert.NoError(t, err)
+
+	mux := http.NewServeMux()
+	return httptest.NewServer(supertokens.Middleware(mux))
+}
+
+func sendTestMessages(t *testing.T) {
+	userInputCode := "123456"
+	urlWithLinkCode := "https://supertokens.io/auth/verify?preAuthSessionId=abc#linkCode"
+	emailService := passwordless.MakeCaptureEmailService(nil)
+	err := (*emailService.SendEmail)(emaildelivery.EmailType{
+		PasswordlessLogin: &emaildelivery.PasswordlessLoginType{
+			Email:           "Test@Example.com",
+			UserInputCode:   &userInputCode,
+			UrlWithLinkCode: &urlWithLinkCode,
+			CodeLifetime:    900000,
+			TenantId:        "public",
+		},
+	}, &map[string]interface{}{})
+	assert.NoError(t, err)
+
+	smsService := passwordless.MakeCaptureSMSService(nil)
+	err = (*smsService.SendSms)(smsdelivery.SmsType{
+		PasswordlessLogin: &smsdelivery.PasswordlessLoginType{
+			PhoneNumber:   "+919876543210",
+			UserInputCode: &userInputCode,
+			CodeLifetime:  900000,
+			TenantId:      "public",
+		},
+	}, &map[string]interface{}{})
+	assert.NoError(t, err)
+}
+
+func doInboxRequest(t *testing.T, method string, url string) (int, map[string]interface{}) {
+	req, err := http.NewRequest(method, url, nil)
+	assert.NoError(t, err)
+	res, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	defer res.Body.Close()
+
+	body, err := ioutil.ReadAll(res.Body)
+	assert.NoError(t, err)
+	result := map[string]interface{}{}
+	json.Unmarshal(body, &result)
+	return res.StatusCode, result
+}
+
+func TestInboxAPIsReturnCapturedMessages(t *testing.T) {
+	BeforeEach()
+	defer AfterEach()
+	server := initDevInboxForTest(t, true)
+	defer server.Close()
+
+	sendTestMessages(t)
+
+	status, result := doInboxRequest(t, "GET", server.URL+"/auth/dev/inbox")
+	assert.Equal(t, 200, status)
+	assert.Equal(t, "OK", result["status"])
+	assert.Len(t, result["messages"], 2)
+
+	status, result = doInboxRequest(t, "GET", server.URL+"/auth/dev/inbox/latest?recipient=test@example.com")
+	assert.Equal(t, 200, status)
+	message := result["message"].(map[string]interface{})
+	assert.Equal(t, "EMAIL", message["type"])
+	assert.Equal(t, "Login to your account", message["subject"])
+	assert.Equal(t, []interface{}{"https://supertokens.io/auth/verify?preAuthSessionId=abc#linkCode"}, message["links"])
+	assert.Equal(t, []interface{}{"123456"}, message["codes"])
+
+	// the + is not escaped on purpose, since test clients often forget to do it
+	status, result = doInboxRequest(t, "GET", server.URL+"/auth/dev/inbox/latest?recipient=+919876543210")
+	assert.Equal(t, 200, status)
+	message = result["message"].(map[string]interface{})
+	assert.Equal(t, "SMS", message["type"])
+	assert.Equal(t, "+919876543210", message["recipient"])
+	assert.Equal(t, []interface{}{"123456"}, message["codes"])
+	assert.Contains(t, message["body"], "123456")
+
+	status, result = doInboxRequest(t, "DELETE", server.URL+"/auth/dev/inbox?recipient="+url.QueryEscape("+919876543210"))
+	assert.Equal(t, 200, status)
+	assert.Equal(t, "OK", result["status"])
+
+	_, result = doInboxRequest(t, "GET", server.URL+"/auth/dev/inbox/latest?recipient=%2B919876543210")
+	assert.Equal(t, "NO_MESSAGE_FOUND_ERROR", result["status"])
+
+	_, result = doInboxRequest(t, "GET", server.URL+"/auth/dev/inbox")
+	assert.Len(t, result["messages"], 1)
+
+	status, _ = doInboxRequest(t, "GET", server.URL+"/auth/dev/inbox/latest")
+	assert.Equal(t, 400, status)
+}
+
+func TestInboxAPIsAreNotExposedUnlessEnabled(t *testing.T) {
+	BeforeEach()
+	defer AfterEach()
+	server := initDevInboxForTest(t, false)
+	defer server.Close()
+
+	sendTestMessages(t)
+
+	status, _ := doInboxRequest(t, "GET", server.URL+"/auth/dev/inbox")
+	assert.Equal(t, 404, status)
+
+	inbox, err := GetInbox()
+	assert.NoError(t, err)
+	assert.Len(t, inbox.GetMessages(nil), 2)
+}
@@ -0,0 +1,44 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package devinboxmodels
+
+import (
+	"net/http"
+
+	"github.com/supertokens/supertokens-golang/ingredients/deliverycapture"
+)
+
+type TypeInput struct {
+	// Enabled must be set to true for the inbox APIs to be exposed. This makes it harder
+	// to expose the captured messages (which contain login links and codes) by mistake.
+	Enabled bool
+	// Inbox is the inbox used by the capture services. Defaults to deliverycapture.DefaultInbox
+	Inbox *deliverycapture.Inbox
+}
+
+type TypeNormalisedInput struct {
+	Enabled bool
+	Inbox   *deliverycapture.Inbox
+}
+
+type APIOptions struct {
+	Config       TypeNormalisedInput
+	RecipeID     string
+	Req          *http.Request
+	Res          http.ResponseWriter
+	OtherHandler http.HandlerFunc
+}
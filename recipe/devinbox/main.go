@@ -0,0 +1,43 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package devinbox
+
+import (
+	"github.com/supertokens/supertokens-golang/ingredients/deliverycapture"
+	"github.com/supertokens/supertokens-golang/recipe/devinbox/devinboxmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+// Init exposes the messages stored by the capture email and SMS services (for example
+// passwordless.MakeCaptureEmailService) through the following APIs:
+//   - GET /dev/inbox?recipient=<email or phone number>: lists the messages, newest first
+//   - GET /dev/inbox/latest?recipient=<email or phone number>: returns the last message
+//   - DELETE /dev/inbox?recipient=<email or phone number>: clears the messages
+//
+// The APIs are only exposed if Enabled is true, and must never be enabled in production.
+func Init(config *devinboxmodels.TypeInput) supertokens.Recipe {
+	return recipeInit(config)
+}
+
+// GetInbox returns the inbox used by the recipe
+func GetInbox() (*deliverycapture.Inbox, error) {
+	instance, err := getRecipeInstanceOrThrowError()
+	if err != nil {
+		return nil, err
+	}
+	return instance.Config.Inbox, nil
+}
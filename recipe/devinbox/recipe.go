@@ -0,0 +1,132 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package devinbox
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/supertokens/supertokens-golang/recipe/devinbox/api"
+	"github.com/supertokens/supertokens-golang/recipe/devinbox/devinboxmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+const RECIPE_ID = "devinbox"
+
+type Recipe struct {
+	RecipeModule supertokens.RecipeModule
+	Config       devinboxmodels.TypeNormalisedInput
+}
+
+var singletonInstance *Recipe
+
+func MakeRecipe(recipeId string, appInfo supertokens.NormalisedAppinfo, config *devinboxmodels.TypeInput, onSuperTokensAPIError func(err error, req *http.Request, res http.ResponseWriter)) (Recipe, error) {
+	r := &Recipe{}
+	r.Config = validateAndNormaliseUserInput(config)
+
+	if r.Config.Enabled {
+		supertokens.LogDebugMessage("devinbox: the inbox APIs are enabled. They expose login links and codes, so they must not be enabled in production")
+	}
+
+	recipeModuleInstance := supertokens.MakeRecipeModule(recipeId, appInfo, r.handleAPIRequest, r.getAllCORSHeaders, r.getAPIsHandled, nil, r.handleError, onSuperTokensAPIError)
+	r.RecipeModule = recipeModuleInstance
+
+	r.RecipeModule.ResetForTest = ResetForTest
+
+	return *r, nil
+}
+
+func getRecipeInstanceOrThrowError() (*Recipe, error) {
+	if singletonInstance != nil {
+		return singletonInstance, nil
+	}
+	return nil, errors.New("Initialisation not done. Did you forget to call the init function?")
+}
+
+func recipeInit(config *devinboxmodels.TypeInput) supertokens.Recipe {
+	return func(appInfo supertokens.NormalisedAppinfo, onSuperTokensAPIError func(err error, req *http.Request, res http.ResponseWriter)) (*supertokens.RecipeModule, error) {
+		if singletonInstance == nil {
+			recipe, err := MakeRecipe(RECIPE_ID, appInfo, config, onSuperTokensAPIError)
+			if err != nil {
+				return nil, err
+			}
+			singletonInstance = &recipe
+			return &singletonInstance.RecipeModule, nil
+		}
+		return nil, errors.New("Dev inbox recipe has already been initialised. Please check your code for bugs.")
+	}
+}
+
+// implement RecipeModule
+
+func (r *Recipe) getAPIsHandled() ([]supertokens.APIHandled, error) {
+	inboxAPINormalised, err := supertokens.NewNormalisedURLPath(InboxAPI)
+	if err != nil {
+		return nil, err
+	}
+	latestInboxAPINormalised, err := supertokens.NewNormalisedURLPath(LatestInboxAPI)
+	if err != nil {
+		return nil, err
+	}
+
+	return []supertokens.APIHandled{{
+		Method:                 http.MethodGet,
+		PathWithoutAPIBasePath: inboxAPINormalised,
+		ID:                     listMessagesAPIID,
+		Disabled:               !r.Config.Enabled,
+	}, {
+		Method:                 http.MethodDelete,
+		PathWithoutAPIBasePath: inboxAPINormalised,
+		ID:                     clearMessagesAPIID,
+		Disabled:               !r.Config.Enabled,
+	}, {
+		Method:                 http.MethodGet,
+		PathWithoutAPIBasePath: latestInboxAPINormalised,
+		ID:                     latestMessageAPIID,
+		Disabled:               !r.Config.Enabled,
+	}}, nil
+}
+
+func (r *Recipe) handleAPIRequest(id string, tenantId string, req *http.Request, res http.ResponseWriter, theirHandler http.HandlerFunc, _ supertokens.NormalisedURLPath, _ string, userContext supertokens.UserContext) error {
+	options := devinboxmodels.APIOptions{
+		Config:       r.Config,
+		RecipeID:     r.RecipeModule.GetRecipeID(),
+		Req:          req,
+		Res:          res,
+		OtherHandler: theirHandler,
+	}
+	if id == listMessagesAPIID {
+		return api.ListMessages(options)
+	} else if id == clearMessagesAPIID {
+		return api.ClearMessages(options)
+	} else if id == latestMessageAPIID {
+		return api.GetLatestMessage(options)
+	}
+	return errors.New("should never come here")
+}
+
+func (r *Recipe) getAllCORSHeaders() []string {
+	return []string{}
+}
+
+func (r *Recipe) handleError(err error, req *http.Request, res http.ResponseWriter, userContext supertokens.UserContext) (bool, error) {
+	return false, nil
+}
+
+func ResetForTest() {
+	singletonInstance = nil
+}
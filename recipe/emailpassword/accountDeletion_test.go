@@ -0,0 +1,214 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package emailpassword
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/accountdeletion"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/accountlockout"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/epmodels"
+	"github.com/supertokens/supertokens-golang/recipe/session"
+	"github.com/supertokens/supertokens-golang/supertokens"
+	"github.com/supertokens/supertokens-golang/test/unittesting"
+)
+
+func getSignInFormFieldsBody(email string, password string) map[string]interface{} {
+	return map[string]interface{}{
+		"formFields": []map[string]interface{}{
+			{"id": "email", "value": email},
+			{"id": "password", "value": password},
+		},
+	}
+}
+
+func TestAccountDeletionAPIsAreDisabledByDefault(t *testing.T) {
+	resetAll()
+	defer resetAll()
+	testServer := initWithoutCoreForTest(t, nil)
+	defer testServer.Close()
+
+	result, err := postRequestWithSessionForTest(testServer.URL, "/auth/user/delete", map[string]interface{}{}, "", "")
+	assert.NoError(t, err)
+	assert.Equal(t, 404, result["statusCode"])
+
+	result, err = postRequestWithSessionForTest(testServer.URL, "/auth/user/delete/cancel", getSignInFormFieldsBody("test@example.com", "validpass123"), "", "")
+	assert.NoError(t, err)
+	assert.Equal(t, 404, result["statusCode"])
+}
+
+func TestAccountDeletionConfigIsValidated(t *testing.T) {
+	resetAll()
+	defer resetAll()
+	gracePeriod := -time.Hour
+	err := supertokens.Init(supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: "http://localhost:8080",
+		},
+		AppInfo: supertokens.AppInfo{
+			APIDomain:     "api.supertokens.io",
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{
+			Init(&epmodels.TypeInput{
+				AccountDeletion: &accountdeletion.TypeInput{GracePeriod: &gracePeriod},
+			}),
+		},
+	})
+	assert.EqualError(t, err, "GracePeriod must not be negative")
+}
+
+func TestAccountDeletionCancelToLockedAccount(t *testing.T) {
+	resetAll()
+	defer resetAll()
+
+	maxFailedAttempts := 1
+	testServer := initWithoutCoreForTest(t, &epmodels.TypeInput{
+		AccountDeletion: &accountdeletion.TypeInput{},
+		AccountLockout: &accountlockout.TypeInput{
+			Default: accountlockout.Policy{MaxFailedAttempts: &maxFailedAttempts},
+		},
+	})
+	defer testServer.Close()
+
+	instance, err := GetRecipeInstanceOrThrowError()
+	assert.NoError(t, err)
+	lockedUntil, err := accountlockout.RecordFailedAttempt(*instance.Config.AccountLockout, "public", "test@example.com", &map[string]interface{}{})
+	assert.NoError(t, err)
+	assert.NotNil(t, lockedUntil)
+
+	// the core is not called for locked accounts
+	result, err := postRequestWithSessionForTest(testServer.URL, "/auth/user/delete/cancel", getSignInFormFieldsBody("test@example.com", "validpass123"), "", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "ACCOUNT_LOCKED_ERROR", result["status"])
+	assert.Equal(t, float64(*lockedUntil), result["lockedUntil"])
+}
+
+func TestAccountDeletionFlow(t *testing.T) {
+	BeforeEach()
+	connectionURI := unittesting.StartUpST("localhost", "8080")
+	defer AfterEach()
+
+	gracePeriod := time.Hour
+	testServer := supertokensInitForTest(t, connectionURI,
+		Init(&epmodels.TypeInput{
+			AccountDeletion: &accountdeletion.TypeInput{GracePeriod: &gracePeriod},
+		}),
+		session.Init(nil),
+	)
+	defer testServer.Close()
+
+	resp, err := unittesting.SignupRequest("test@example.com", "validpass123", testServer.URL)
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	cookies := unittesting.ExtractInfoFromResponse(resp)
+
+	result, err := postRequestWithSessionForTest(testServer.URL, "/auth/user/delete", map[string]interface{}{}, cookies["sAccessToken"], cookies["antiCsrf"])
+	assert.NoError(t, err)
+	assert.Equal(t, "OK", result["status"])
+	deleteAt := result["deleteAt"]
+	assert.NotNil(t, deleteAt)
+
+	user, err := GetUserByEmail("public", "test@example.com")
+	assert.NoError(t, err)
+	assert.NotNil(t, user)
+	sessionHandles, err := session.GetAllSessionHandlesForUser(user.ID, nil)
+	assert.NoError(t, err)
+	assert.Len(t, sessionHandles, 0)
+
+	result, err = postRequestWithSessionForTest(testServer.URL, "/auth/signin", getSignInFormFieldsBody("test@example.com", "validpass123"), "", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "ACCOUNT_DELETION_SCHEDULED_ERROR", result["status"])
+	assert.Equal(t, deleteAt, result["deleteAt"])
+
+	result, err = postRequestWithSessionForTest(testServer.URL, "/auth/user/delete/cancel", getSignInFormFieldsBody("test@example.com", "wrongpass123"), "", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "WRONG_CREDENTIALS_ERROR", result["status"])
+
+	result, err = postRequestWithSessionForTest(testServer.URL, "/auth/user/delete/cancel", getSignInFormFieldsBody("test@example.com", "validpass123"), "", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "OK", result["status"])
+
+	result, err = postRequestWithSessionForTest(testServer.URL, "/auth/signin", getSignInFormFieldsBody("test@example.com", "validpass123"), "", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "OK", result["status"])
+}
+
+func TestSignInCancelsTheDeletionWithCancelOnSignIn(t *testing.T) {
+	BeforeEach()
+	connectionURI := unittesting.StartUpST("localhost", "8080")
+	defer AfterEach()
+
+	testServer := supertokensInitForTest(t, connectionURI,
+		Init(&epmodels.TypeInput{
+			AccountDeletion: &accountdeletion.TypeInput{CancelOnSignIn: true},
+		}),
+		session.Init(nil),
+	)
+	defer testServer.Close()
+
+	signUpResponse, err := SignUp("public", "test@example.com", "validpass123")
+	assert.NoError(t, err)
+	user := signUpResponse.OK.User
+
+	scheduleResponse, err := ScheduleAccountDeletion("public", user.ID)
+	assert.NoError(t, err)
+	assert.NotNil(t, scheduleResponse.OK)
+
+	result, err := postRequestWithSessionForTest(testServer.URL, "/auth/signin", getSignInFormFieldsBody("test@example.com", "validpass123"), "", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "OK", result["status"])
+
+	instance, err := GetRecipeInstanceOrThrowError()
+	assert.NoError(t, err)
+	job, err := accountdeletion.GetScheduledDeletion(*instance.Config.AccountDeletion, user.ID, &map[string]interface{}{})
+	assert.NoError(t, err)
+	assert.Nil(t, job)
+}
+
+func TestScheduledDeletionDeletesTheUser(t *testing.T) {
+	BeforeEach()
+	connectionURI := unittesting.StartUpST("localhost", "8080")
+	defer AfterEach()
+
+	gracePeriod := time.Duration(0)
+	checkInterval := 50 * time.Millisecond
+	testServer := supertokensInitForTest(t, connectionURI,
+		Init(&epmodels.TypeInput{
+			AccountDeletion: &accountdeletion.TypeInput{
+				GracePeriod:   &gracePeriod,
+				CheckInterval: &checkInterval,
+			},
+		}),
+		session.Init(nil),
+	)
+	defer testServer.Close()
+
+	signUpResponse, err := SignUp("public", "test@example.com", "validpass123")
+	assert.NoError(t, err)
+	scheduleResponse, err := ScheduleAccountDeletion("public", signUpResponse.OK.User.ID)
+	assert.NoError(t, err)
+	assert.NotNil(t, scheduleResponse.OK)
+
+	assert.Eventually(t, func() bool {
+		user, err := GetUserByID(signUpResponse.OK.User.ID)
+		return err == nil && user == nil
+	}, 5*time.Second, 50*time.Millisecond)
+}
@@ -0,0 +1,74 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package emailpassword
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/accountlockout"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/epmodels"
+)
+
+func TestSignInToLockedAccount(t *testing.T) {
+	resetAll()
+	defer resetAll()
+
+	maxFailedAttempts := 1
+	testServer := initWithoutCoreForTest(t, &epmodels.TypeInput{
+		AccountLockout: &accountlockout.TypeInput{
+			Default: accountlockout.Policy{MaxFailedAttempts: &maxFailedAttempts},
+		},
+	})
+	defer testServer.Close()
+
+	instance, err := GetRecipeInstanceOrThrowError()
+	assert.NoError(t, err)
+	lockedUntil, err := accountlockout.RecordFailedAttempt(*instance.Config.AccountLockout, "public", "test@example.com", &map[string]interface{}{})
+	assert.NoError(t, err)
+	assert.NotNil(t, lockedUntil)
+
+	postBody, err := json.Marshal(map[string][]map[string]string{
+		"formFields": {
+			{"id": "email", "value": "test@example.com"},
+			{"id": "password", "value": "validpass123"},
+		},
+	})
+	assert.NoError(t, err)
+
+	// the core is not called for locked accounts
+	resp, err := http.Post(testServer.URL+"/auth/signin", "application/json", bytes.NewBuffer(postBody))
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	dataInBytes, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	resp.Body.Close()
+
+	var result map[string]interface{}
+	assert.NoError(t, json.Unmarshal(dataInBytes, &result))
+	assert.Equal(t, "ACCOUNT_LOCKED_ERROR", result["status"])
+	assert.Equal(t, float64(*lockedUntil), result["lockedUntil"])
+
+	assert.NoError(t, UnlockAccount("public", "test@example.com"))
+	current, err := accountlockout.GetLockedUntil(*instance.Config.AccountLockout, "public", "test@example.com", &map[string]interface{}{})
+	assert.NoError(t, err)
+	assert.Nil(t, current)
+}
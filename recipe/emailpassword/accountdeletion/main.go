@@ -0,0 +1,229 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package accountdeletion
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/supertokens/supertokens-golang/recipe/usermetadata"
+	"github.com/supertokens/supertokens-golang/recipe/userroles"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func NormaliseTypeInput(input TypeInput) (TypeNormalisedInput, error) {
+	result := TypeNormalisedInput{
+		GracePeriod:   30 * 24 * time.Hour,
+		CheckInterval: time.Hour,
+		Store:         MakeInMemoryStore(),
+		CleanupHooks:  DefaultCleanupHooks(),
+	}
+	result.CancelOnSignIn = input.CancelOnSignIn
+	if input.GracePeriod != nil {
+		if *input.GracePeriod < 0 {
+			return TypeNormalisedInput{}, errors.New("GracePeriod must not be negative")
+		}
+		result.GracePeriod = *input.GracePeriod
+	}
+	if input.CheckInterval != nil {
+		if *input.CheckInterval <= 0 {
+			return TypeNormalisedInput{}, errors.New("CheckInterval must be greater than 0")
+		}
+		result.CheckInterval = *input.CheckInterval
+	}
+	if input.Store != nil {
+		result.Store = *input.Store
+	}
+	if input.CleanupHooks != nil {
+		result.CleanupHooks = input.CleanupHooks
+	}
+	return result, nil
+}
+
+// DefaultCleanupHooks returns the hooks that remove the user metadata and the roles of a deleted
+// user. The hooks of recipes that are not initialised do nothing.
+func DefaultCleanupHooks() []CleanupHook {
+	return []CleanupHook{UserMetadataCleanupHook, UserRolesCleanupHook}
+}
+
+func UserMetadataCleanupHook(userId string, tenantIds []string, userContext supertokens.UserContext) error {
+	_, err := usermetadata.GetRecipeInstanceOrThrowError()
+	if err != nil {
+		return nil
+	}
+	return usermetadata.ClearUserMetadata(userId, userContext)
+}
+
+func UserRolesCleanupHook(userId string, tenantIds []string, userContext supertokens.UserContext) error {
+	if userroles.GetRecipeInstance() == nil {
+		return nil
+	}
+	for _, tenantId := range tenantIds {
+		rolesResponse, err := userroles.GetRolesForUser(tenantId, userId, userContext)
+		if err != nil {
+			return err
+		}
+		for _, role := range rolesResponse.OK.Roles {
+			_, err = userroles.RemoveUserRole(tenantId, userId, role, userContext)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Schedule schedules the deletion of the user after the grace period. If a deletion is already
+// scheduled for the user, it is kept as is.
+func Schedule(config TypeNormalisedInput, userId string, tenantId string, userContext supertokens.UserContext) (Job, error) {
+	existing, err := config.Store.Get(userId, userContext)
+	if err != nil {
+		return Job{}, err
+	}
+	if existing != nil {
+		return *existing, nil
+	}
+
+	now := getCurrentTimeInMS()
+	job := Job{
+		UserId:      userId,
+		TenantId:    tenantId,
+		RequestedAt: now,
+		DeleteAt:    now + config.GracePeriod.Milliseconds(),
+	}
+	err = config.Store.Save(job, userContext)
+	if err != nil {
+		return Job{}, err
+	}
+	supertokens.LogDebugMessage(fmt.Sprintf("accountdeletion: scheduled the deletion of user %s at %d", userId, job.DeleteAt))
+	return job, nil
+}
+
+// GetScheduledDeletion returns nil if no deletion is scheduled for the user
+func GetScheduledDeletion(config TypeNormalisedInput, userId string, userContext supertokens.UserContext) (*Job, error) {
+	return config.Store.Get(userId, userContext)
+}
+
+func Cancel(config TypeNormalisedInput, userId string, userContext supertokens.UserContext) error {
+	return config.Store.Delete(userId, userContext)
+}
+
+// DeleteUser calls the cleanup hooks, deletes the user from the core and then removes their user
+// ID mapping. The mapping is removed last, so that a deletion that failed part way can be retried
+// with the same user ID.
+func DeleteUser(config TypeNormalisedInput, userId string, tenantIds []string, userContext supertokens.UserContext) error {
+	for _, hook := range config.CleanupHooks {
+		err := hook(userId, tenantIds, userContext)
+		if err != nil {
+			return err
+		}
+	}
+
+	superTokensUserId := userId
+	userIdType := supertokens.UserIdTypeAny
+	mapping, err := supertokens.GetUserIdMapping(userId, &userIdType, userContext)
+	if err != nil {
+		return err
+	}
+	if mapping.OK != nil {
+		superTokensUserId = mapping.OK.SupertokensUserId
+	}
+	err = supertokens.DeleteUser(superTokensUserId, userContext)
+	if err != nil {
+		return err
+	}
+
+	if mapping.OK != nil {
+		force := true
+		_, err = supertokens.DeleteUserIdMapping(userId, &userIdType, &force, userContext)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ProcessDueJobs calls deleteUser for every job that is due, and removes the job from the store
+// if it succeeds. Failed jobs are kept, so they are tried again the next time.
+func ProcessDueJobs(config TypeNormalisedInput, now int64, deleteUser func(job Job, userContext supertokens.UserContext) error) error {
+	userContext := &map[string]interface{}{}
+	jobs, err := config.Store.GetDue(now, userContext)
+	if err != nil {
+		return err
+	}
+	var lastErr error
+	for _, job := range jobs {
+		err = deleteUser(job, userContext)
+		if err != nil {
+			supertokens.LogDebugMessage(fmt.Sprintf("accountdeletion: deleting user %s failed: %s", job.UserId, err.Error()))
+			lastErr = err
+			continue
+		}
+		err = config.Store.Delete(job.UserId, userContext)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		supertokens.LogDebugMessage("accountdeletion: deleted user " + job.UserId)
+	}
+	return lastErr
+}
+
+// Scheduler calls ProcessDueJobs every CheckInterval in the background
+type Scheduler struct {
+	stop      chan struct{}
+	stopOnce  sync.Once
+	waitGroup sync.WaitGroup
+}
+
+func StartScheduler(config TypeNormalisedInput, deleteUser func(job Job, userContext supertokens.UserContext) error) *Scheduler {
+	scheduler := &Scheduler{
+		stop: make(chan struct{}),
+	}
+	scheduler.waitGroup.Add(1)
+	go func() {
+		defer scheduler.waitGroup.Done()
+		ticker := time.NewTicker(config.CheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-scheduler.stop:
+				return
+			case <-ticker.C:
+			}
+			err := ProcessDueJobs(config, getCurrentTimeInMS(), deleteUser)
+			if err != nil {
+				supertokens.LogDebugMessage("accountdeletion: " + err.Error())
+			}
+		}
+	}()
+	return scheduler
+}
+
+// Stop stops the scheduler and waits for the deletions that are in progress
+func (s *Scheduler) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.stop)
+	})
+	s.waitGroup.Wait()
+}
+
+func getCurrentTimeInMS() int64 {
+	return time.Now().UnixNano() / int64(time.Millisecond)
+}
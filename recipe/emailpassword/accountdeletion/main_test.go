@@ -0,0 +1,124 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package accountdeletion
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func TestScheduleAndCancel(t *testing.T) {
+	gracePeriod := 24 * time.Hour
+	config, err := NormaliseTypeInput(TypeInput{GracePeriod: &gracePeriod})
+	assert.NoError(t, err)
+	userContext := &map[string]interface{}{}
+
+	before := getCurrentTimeInMS()
+	job, err := Schedule(config, "userId", "public", userContext)
+	assert.NoError(t, err)
+	assert.Equal(t, "userId", job.UserId)
+	assert.Equal(t, "public", job.TenantId)
+	assert.GreaterOrEqual(t, job.DeleteAt, before+gracePeriod.Milliseconds())
+
+	// scheduling again keeps the original deletion time
+	again, err := Schedule(config, "userId", "tenant1", userContext)
+	assert.NoError(t, err)
+	assert.Equal(t, job, again)
+
+	scheduled, err := GetScheduledDeletion(config, "userId", userContext)
+	assert.NoError(t, err)
+	assert.Equal(t, &job, scheduled)
+
+	assert.NoError(t, Cancel(config, "userId", userContext))
+	scheduled, err = GetScheduledDeletion(config, "userId", userContext)
+	assert.NoError(t, err)
+	assert.Nil(t, scheduled)
+}
+
+func TestProcessDueJobs(t *testing.T) {
+	config, err := NormaliseTypeInput(TypeInput{})
+	assert.NoError(t, err)
+	userContext := &map[string]interface{}{}
+
+	now := getCurrentTimeInMS()
+	assert.NoError(t, config.Store.Save(Job{UserId: "due", TenantId: "public", DeleteAt: now - 1}, userContext))
+	assert.NoError(t, config.Store.Save(Job{UserId: "failing", TenantId: "public", DeleteAt: now}, userContext))
+	assert.NoError(t, config.Store.Save(Job{UserId: "later", TenantId: "public", DeleteAt: now + 1000}, userContext))
+
+	deleted := []string{}
+	err = ProcessDueJobs(config, now, func(job Job, userContext supertokens.UserContext) error {
+		if job.UserId == "failing" {
+			return errors.New("deletion failed")
+		}
+		deleted = append(deleted, job.UserId)
+		return nil
+	})
+	assert.EqualError(t, err, "deletion failed")
+	assert.Equal(t, []string{"due"}, deleted)
+
+	// failed jobs are kept so that they are tried again
+	job, err := GetScheduledDeletion(config, "due", userContext)
+	assert.NoError(t, err)
+	assert.Nil(t, job)
+	job, err = GetScheduledDeletion(config, "failing", userContext)
+	assert.NoError(t, err)
+	assert.NotNil(t, job)
+	job, err = GetScheduledDeletion(config, "later", userContext)
+	assert.NoError(t, err)
+	assert.NotNil(t, job)
+}
+
+func TestSchedulerDeletesDueJobs(t *testing.T) {
+	checkInterval := 10 * time.Millisecond
+	config, err := NormaliseTypeInput(TypeInput{CheckInterval: &checkInterval})
+	assert.NoError(t, err)
+	assert.NoError(t, config.Store.Save(Job{UserId: "userId", TenantId: "public"}, &map[string]interface{}{}))
+
+	deleted := make(chan string, 1)
+	scheduler := StartScheduler(config, func(job Job, userContext supertokens.UserContext) error {
+		deleted <- job.UserId
+		return nil
+	})
+	select {
+	case userId := <-deleted:
+		assert.Equal(t, "userId", userId)
+	case <-time.After(time.Second):
+		assert.Fail(t, "the scheduler did not delete the user")
+	}
+	scheduler.Stop()
+	// stopping more than once is allowed
+	scheduler.Stop()
+}
+
+func TestConfigValidation(t *testing.T) {
+	gracePeriod := -time.Hour
+	_, err := NormaliseTypeInput(TypeInput{GracePeriod: &gracePeriod})
+	assert.EqualError(t, err, "GracePeriod must not be negative")
+
+	checkInterval := time.Duration(0)
+	_, err = NormaliseTypeInput(TypeInput{CheckInterval: &checkInterval})
+	assert.EqualError(t, err, "CheckInterval must be greater than 0")
+
+	config, err := NormaliseTypeInput(TypeInput{})
+	assert.NoError(t, err)
+	assert.Equal(t, 30*24*time.Hour, config.GracePeriod)
+	assert.Len(t, config.CleanupHooks, 2)
+}
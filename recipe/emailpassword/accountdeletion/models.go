@@ -0,0 +1,76 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package accountdeletion
+
+import (
+	"time"
+
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+// Job is a scheduled deletion of a user
+type Job struct {
+	UserId string `json:"userId"`
+	// TenantId is the tenant in which the deletion was requested
+	TenantId string `json:"tenantId"`
+	// RequestedAt is the time (in milliseconds) at which the deletion was requested
+	RequestedAt int64 `json:"requestedAt"`
+	// DeleteAt is the time (in milliseconds) after which the user is deleted
+	DeleteAt int64 `json:"deleteAt"`
+}
+
+// Store keeps the scheduled deletions. There is at most one job per user.
+type Store struct {
+	// Save inserts the job, or replaces the job of the same user
+	Save func(job Job, userContext supertokens.UserContext) error
+	// Get returns nil if no deletion is scheduled for the user
+	Get    func(userId string, userContext supertokens.UserContext) (*Job, error)
+	Delete func(userId string, userContext supertokens.UserContext) error
+	// GetDue returns the jobs with a DeleteAt before or equal to now (in milliseconds)
+	GetDue func(now int64, userContext supertokens.UserContext) ([]Job, error)
+}
+
+// CleanupHook removes the data of a user that is being deleted. tenantIds are the tenants that
+// the user belongs to. Hooks are called before the user is deleted from the core, and are called
+// again if the deletion is retried, so they must not fail if there is nothing to remove.
+type CleanupHook func(userId string, tenantIds []string, userContext supertokens.UserContext) error
+
+type TypeInput struct {
+	// GracePeriod is the time between the deletion request and the deletion of the user.
+	// Defaults to 30 days.
+	GracePeriod *time.Duration
+	// CheckInterval is how often the scheduled deletions are checked. Defaults to 1 hour.
+	CheckInterval *time.Duration
+	// Store defaults to an in-memory store, which is not shared between instances of the
+	// backend and loses the scheduled deletions when the process exits
+	Store *Store
+	// CleanupHooks default to the hooks for the usermetadata and userroles recipes (see
+	// DefaultCleanupHooks). Custom hooks can be appended to those.
+	CleanupHooks []CleanupHook
+	// CancelOnSignIn cancels a scheduled deletion when the user signs in. By default, signing in
+	// is blocked during the grace period and the deletion can only be cancelled using the account
+	// deletion cancel API.
+	CancelOnSignIn bool
+}
+
+type TypeNormalisedInput struct {
+	GracePeriod    time.Duration
+	CheckInterval  time.Duration
+	Store          Store
+	CleanupHooks   []CleanupHook
+	CancelOnSignIn bool
+}
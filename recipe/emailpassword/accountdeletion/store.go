@@ -0,0 +1,64 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package accountdeletion
+
+import (
+	"sync"
+
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+// MakeInMemoryStore creates a store that keeps the scheduled deletions in memory
+func MakeInMemoryStore() Store {
+	var mutex sync.Mutex
+	jobs := map[string]Job{}
+
+	return Store{
+		Save: func(job Job, userContext supertokens.UserContext) error {
+			mutex.Lock()
+			defer mutex.Unlock()
+			jobs[job.UserId] = job
+			return nil
+		},
+		Get: func(userId string, userContext supertokens.UserContext) (*Job, error) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			job, ok := jobs[userId]
+			if !ok {
+				return nil, nil
+			}
+			return &job, nil
+		},
+		Delete: func(userId string, userContext supertokens.UserContext) error {
+			mutex.Lock()
+			defer mutex.Unlock()
+			delete(jobs, userId)
+			return nil
+		},
+		GetDue: func(now int64, userContext supertokens.UserContext) ([]Job, error) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			result := []Job{}
+			for _, job := range jobs {
+				if job.DeleteAt <= now {
+					result = append(result, job)
+				}
+			}
+			return result, nil
+		},
+	}
+}
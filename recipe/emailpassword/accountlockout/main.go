@@ -0,0 +1,179 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package accountlockout
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func NormaliseTypeInput(input TypeInput) (TypeNormalisedInput, error) {
+	defaultPolicy, err := normalisePolicy(input.Default, Policy{})
+	if err != nil {
+		return TypeNormalisedInput{}, err
+	}
+
+	tenantPolicies := map[string]NormalisedPolicy{}
+	for tenantId, policy := range input.TenantPolicies {
+		tenantPolicy, err := normalisePolicy(policy, input.Default)
+		if err != nil {
+			return TypeNormalisedInput{}, fmt.Errorf("invalid account lockout policy for tenant %s: %s", tenantId, err.Error())
+		}
+		tenantPolicies[tenantId] = tenantPolicy
+	}
+
+	store := MakeInMemoryStore()
+	if input.Store != nil {
+		store = *input.Store
+	}
+
+	return TypeNormalisedInput{
+		Default:        defaultPolicy,
+		TenantPolicies: tenantPolicies,
+		Store:          store,
+	}, nil
+}
+
+func normalisePolicy(policy Policy, fallback Policy) (NormalisedPolicy, error) {
+	result := NormalisedPolicy{
+		MaxFailedAttempts: 5,
+		Window:            15 * time.Minute,
+		LockDuration:      15 * time.Minute,
+		BackoffMultiplier: 1,
+		MaxLockDuration:   24 * time.Hour,
+	}
+	for _, p := range []Policy{fallback, policy} {
+		if p.MaxFailedAttempts != nil {
+			result.MaxFailedAttempts = *p.MaxFailedAttempts
+		}
+		if p.Window != nil {
+			result.Window = *p.Window
+		}
+		if p.LockDuration != nil {
+			result.LockDuration = *p.LockDuration
+		}
+		if p.BackoffMultiplier != nil {
+			result.BackoffMultiplier = *p.BackoffMultiplier
+		}
+		if p.MaxLockDuration != nil {
+			result.MaxLockDuration = *p.MaxLockDuration
+		}
+	}
+
+	if result.MaxFailedAttempts < 1 {
+		return NormalisedPolicy{}, errors.New("MaxFailedAttempts must be at least 1")
+	}
+	if result.Window <= 0 || result.LockDuration <= 0 {
+		return NormalisedPolicy{}, errors.New("Window and LockDuration must be greater than 0")
+	}
+	if result.BackoffMultiplier < 1 {
+		return NormalisedPolicy{}, errors.New("BackoffMultiplier must be at least 1")
+	}
+	if result.MaxLockDuration < result.LockDuration {
+		return NormalisedPolicy{}, errors.New("MaxLockDuration must be greater than or equal to LockDuration")
+	}
+	return result, nil
+}
+
+// GetPolicy returns the policy that applies to tenantId
+func GetPolicy(config TypeNormalisedInput, tenantId string) NormalisedPolicy {
+	if policy, ok := config.TenantPolicies[tenantId]; ok {
+		return policy
+	}
+	return config.Default
+}
+
+// GetLockedUntil returns the time (in milliseconds) until which the account is locked, or nil
+// if it is not locked
+func GetLockedUntil(config TypeNormalisedInput, tenantId string, email string, userContext supertokens.UserContext) (*int64, error) {
+	state, err := config.Store.Get(tenantId, normaliseEmail(email), userContext)
+	if err != nil || state == nil {
+		return nil, err
+	}
+	if state.LockedUntil > getCurrentTimeInMS() {
+		return &state.LockedUntil, nil
+	}
+	return nil, nil
+}
+
+// RecordFailedAttempt counts a failed sign in and returns the time (in milliseconds) until
+// which the account is locked if this attempt locked it
+func RecordFailedAttempt(config TypeNormalisedInput, tenantId string, email string, userContext supertokens.UserContext) (*int64, error) {
+	email = normaliseEmail(email)
+	policy := GetPolicy(config, tenantId)
+	now := getCurrentTimeInMS()
+
+	state, err := config.Store.Get(tenantId, email, userContext)
+	if err != nil {
+		return nil, err
+	}
+	if state == nil {
+		state = &State{}
+	}
+	if state.LockedUntil > now {
+		return &state.LockedUntil, nil
+	}
+
+	if state.WindowStart == 0 || now-state.WindowStart > policy.Window.Milliseconds() {
+		state.WindowStart = now
+		state.FailedAttempts = 0
+	}
+	state.FailedAttempts++
+
+	var lockedUntil *int64
+	if state.FailedAttempts >= policy.MaxFailedAttempts {
+		state.LockedUntil = now + getLockDuration(policy, state.LockCount).Milliseconds()
+		state.LockCount++
+		state.FailedAttempts = 0
+		state.WindowStart = 0
+		lockedUntil = &state.LockedUntil
+		supertokens.LogDebugMessage(fmt.Sprintf("accountlockout: locked account in tenant %s until %d", tenantId, state.LockedUntil))
+	}
+
+	err = config.Store.Set(tenantId, email, *state, userContext)
+	if err != nil {
+		return nil, err
+	}
+	return lockedUntil, nil
+}
+
+// Reset removes the lockout state of the account, which unlocks it and resets the counters.
+// It is called after a successful sign in or password reset, and to unlock an account.
+func Reset(config TypeNormalisedInput, tenantId string, email string, userContext supertokens.UserContext) error {
+	return config.Store.Delete(tenantId, normaliseEmail(email), userContext)
+}
+
+func getLockDuration(policy NormalisedPolicy, lockCount int) time.Duration {
+	duration := float64(policy.LockDuration) * math.Pow(policy.BackoffMultiplier, float64(lockCount))
+	if duration > float64(policy.MaxLockDuration) {
+		return policy.MaxLockDuration
+	}
+	return time.Duration(duration)
+}
+
+func normaliseEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+func getCurrentTimeInMS() int64 {
+	return time.Now().UnixNano() / 1000000
+}
@@ -0,0 +1,151 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package accountlockout
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccountIsLockedAfterMaxFailedAttempts(t *testing.T) {
+	maxFailedAttempts := 3
+	config, err := NormaliseTypeInput(TypeInput{
+		Default: Policy{MaxFailedAttempts: &maxFailedAttempts},
+	})
+	assert.NoError(t, err)
+	userContext := &map[string]interface{}{}
+
+	for i := 0; i < 2; i++ {
+		lockedUntil, err := RecordFailedAttempt(config, "public", "test@example.com", userContext)
+		assert.NoError(t, err)
+		assert.Nil(t, lockedUntil)
+	}
+
+	before := getCurrentTimeInMS()
+	lockedUntil, err := RecordFailedAttempt(config, "public", "Test@Example.com ", userContext)
+	assert.NoError(t, err)
+	assert.NotNil(t, lockedUntil)
+	assert.GreaterOrEqual(t, *lockedUntil, before+15*60*1000)
+
+	current, err := GetLockedUntil(config, "public", "test@example.com", userContext)
+	assert.NoError(t, err)
+	assert.Equal(t, lockedUntil, current)
+
+	// the lock is per tenant and per email
+	current, err = GetLockedUntil(config, "tenant1", "test@example.com", userContext)
+	assert.NoError(t, err)
+	assert.Nil(t, current)
+	current, err = GetLockedUntil(config, "public", "other@example.com", userContext)
+	assert.NoError(t, err)
+	assert.Nil(t, current)
+
+	assert.NoError(t, Reset(config, "public", "TEST@example.com", userContext))
+	current, err = GetLockedUntil(config, "public", "test@example.com", userContext)
+	assert.NoError(t, err)
+	assert.Nil(t, current)
+}
+
+func TestFailedAttemptsOutsideWindowAreNotCounted(t *testing.T) {
+	maxFailedAttempts := 2
+	window := 50 * time.Millisecond
+	config, err := NormaliseTypeInput(TypeInput{
+		Default: Policy{MaxFailedAttempts: &maxFailedAttempts, Window: &window},
+	})
+	assert.NoError(t, err)
+	userContext := &map[string]interface{}{}
+
+	lockedUntil, err := RecordFailedAttempt(config, "public", "test@example.com", userContext)
+	assert.NoError(t, err)
+	assert.Nil(t, lockedUntil)
+
+	time.Sleep(100 * time.Millisecond)
+
+	lockedUntil, err = RecordFailedAttempt(config, "public", "test@example.com", userContext)
+	assert.NoError(t, err)
+	assert.Nil(t, lockedUntil)
+
+	lockedUntil, err = RecordFailedAttempt(config, "public", "test@example.com", userContext)
+	assert.NoError(t, err)
+	assert.NotNil(t, lockedUntil)
+}
+
+func TestLockDurationGrowsExponentially(t *testing.T) {
+	maxFailedAttempts := 1
+	lockDuration := 10 * time.Minute
+	multiplier := 2.0
+	maxLockDuration := 30 * time.Minute
+	store := MakeInMemoryStore()
+	config, err := NormaliseTypeInput(TypeInput{
+		Default: Policy{
+			MaxFailedAttempts: &maxFailedAttempts,
+			LockDuration:      &lockDuration,
+			BackoffMultiplier: &multiplier,
+			MaxLockDuration:   &maxLockDuration,
+		},
+		Store: &store,
+	})
+	assert.NoError(t, err)
+	userContext := &map[string]interface{}{}
+
+	expectedDurations := []time.Duration{10 * time.Minute, 20 * time.Minute, 30 * time.Minute, 30 * time.Minute}
+	for _, expected := range expectedDurations {
+		now := getCurrentTimeInMS()
+		lockedUntil, err := RecordFailedAttempt(config, "public", "test@example.com", userContext)
+		assert.NoError(t, err)
+		assert.InDelta(t, now+expected.Milliseconds(), *lockedUntil, 1000)
+
+		// simulate the lock expiring
+		state, err := store.Get("public", "test@example.com", userContext)
+		assert.NoError(t, err)
+		state.LockedUntil = now - 1
+		assert.NoError(t, store.Set("public", "test@example.com", *state, userContext))
+	}
+}
+
+func TestTenantPolicyOverridesDefault(t *testing.T) {
+	defaultMax := 5
+	tenantMax := 2
+	config, err := NormaliseTypeInput(TypeInput{
+		Default: Policy{MaxFailedAttempts: &defaultMax},
+		TenantPolicies: map[string]Policy{
+			"strict": {MaxFailedAttempts: &tenantMax},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 5, GetPolicy(config, "public").MaxFailedAttempts)
+	assert.Equal(t, 2, GetPolicy(config, "strict").MaxFailedAttempts)
+	assert.Equal(t, 15*time.Minute, GetPolicy(config, "strict").LockDuration)
+}
+
+func TestInvalidConfig(t *testing.T) {
+	zero := 0
+	_, err := NormaliseTypeInput(TypeInput{Default: Policy{MaxFailedAttempts: &zero}})
+	assert.EqualError(t, err, "MaxFailedAttempts must be at least 1")
+
+	multiplier := 0.5
+	_, err = NormaliseTypeInput(TypeInput{
+		TenantPolicies: map[string]Policy{"t1": {BackoffMultiplier: &multiplier}},
+	})
+	assert.EqualError(t, err, "invalid account lockout policy for tenant t1: BackoffMultiplier must be at least 1")
+
+	lockDuration := time.Hour
+	maxLockDuration := time.Minute
+	_, err = NormaliseTypeInput(TypeInput{Default: Policy{LockDuration: &lockDuration, MaxLockDuration: &maxLockDuration}})
+	assert.EqualError(t, err, "MaxLockDuration must be greater than or equal to LockDuration")
+}
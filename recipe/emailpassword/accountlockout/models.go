@@ -0,0 +1,80 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package accountlockout
+
+import (
+	"time"
+
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+// State is the lockout state of an account (an email in a tenant)
+type State struct {
+	// FailedAttempts is the number of failed sign in attempts since WindowStart
+	FailedAttempts int `json:"failedAttempts"`
+	// WindowStart is the time (in milliseconds) of the first failed attempt in the current window
+	WindowStart int64 `json:"windowStart"`
+	// LockedUntil is the time (in milliseconds) until which the account is locked, or 0
+	LockedUntil int64 `json:"lockedUntil"`
+	// LockCount is the number of times the account was locked since the last successful sign in.
+	// It is used to increase the lock duration if BackoffMultiplier is set.
+	LockCount int `json:"lockCount"`
+}
+
+// Store keeps the lockout state of accounts. Emails are passed in lowercase.
+type Store struct {
+	// Get returns nil if there is no state for the account
+	Get    func(tenantId string, email string, userContext supertokens.UserContext) (*State, error)
+	Set    func(tenantId string, email string, state State, userContext supertokens.UserContext) error
+	Delete func(tenantId string, email string, userContext supertokens.UserContext) error
+}
+
+type Policy struct {
+	// MaxFailedAttempts within Window lock the account. Defaults to 5.
+	MaxFailedAttempts *int
+	// Window in which failed attempts are counted. Defaults to 15 minutes.
+	Window *time.Duration
+	// LockDuration is the duration of the first lock. Defaults to 15 minutes.
+	LockDuration *time.Duration
+	// BackoffMultiplier multiplies the lock duration every time the account is locked again
+	// without a successful sign in in between. Defaults to 1 (the duration does not grow).
+	BackoffMultiplier *float64
+	// MaxLockDuration caps the lock duration when using BackoffMultiplier. Defaults to 24 hours.
+	MaxLockDuration *time.Duration
+}
+
+type TypeInput struct {
+	Default Policy
+	// TenantPolicies overrides fields of the default policy for specific tenants
+	TenantPolicies map[string]Policy
+	// Store defaults to an in-memory store, which is not shared between instances of the backend
+	Store *Store
+}
+
+type NormalisedPolicy struct {
+	MaxFailedAttempts int
+	Window            time.Duration
+	LockDuration      time.Duration
+	BackoffMultiplier float64
+	MaxLockDuration   time.Duration
+}
+
+type TypeNormalisedInput struct {
+	Default        NormalisedPolicy
+	TenantPolicies map[string]NormalisedPolicy
+	Store          Store
+}
@@ -0,0 +1,59 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package accountlockout
+
+import (
+	"sync"
+
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+// MakeInMemoryStore keeps the lockout state in memory. It is lost when the process restarts
+// and is not shared between instances, so a shared store should be used in production if
+// the backend runs on more than one instance.
+func MakeInMemoryStore() Store {
+	var mutex sync.Mutex
+	states := map[string]State{}
+
+	getKey := func(tenantId string, email string) string {
+		return tenantId + "|" + email
+	}
+
+	return Store{
+		Get: func(tenantId string, email string, userContext supertokens.UserContext) (*State, error) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			state, ok := states[getKey(tenantId, email)]
+			if !ok {
+				return nil, nil
+			}
+			return &state, nil
+		},
+		Set: func(tenantId string, email string, state State, userContext supertokens.UserContext) error {
+			mutex.Lock()
+			defer mutex.Unlock()
+			states[getKey(tenantId, email)] = state
+			return nil
+		},
+		Delete: func(tenantId string, email string, userContext supertokens.UserContext) error {
+			mutex.Lock()
+			defer mutex.Unlock()
+			delete(states, getKey(tenantId, email))
+			return nil
+		},
+	}
+}
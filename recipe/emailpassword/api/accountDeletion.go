@@ -0,0 +1,108 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package api
+
+import (
+	"encoding/json"
+
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/epmodels"
+	"github.com/supertokens/supertokens-golang/recipe/session"
+	"github.com/supertokens/supertokens-golang/recipe/session/claims"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func AccountDeletion(apiImplementation epmodels.APIInterface, tenantId string, options epmodels.APIOptions, userContext supertokens.UserContext) error {
+	if apiImplementation.AccountDeletionPOST == nil || (*apiImplementation.AccountDeletionPOST) == nil {
+		options.OtherHandler(options.Res, options.Req)
+		return nil
+	}
+
+	sessionContainer, err := session.GetSession(
+		options.Req, options.Res,
+		&sessmodels.VerifySessionOptions{
+			// users must be able to delete their account even if, for example, their email is not verified
+			OverrideGlobalClaimValidators: func(globalClaimValidators []claims.SessionClaimValidator, sessionContainer sessmodels.SessionContainer, userContext supertokens.UserContext) ([]claims.SessionClaimValidator, error) {
+				return []claims.SessionClaimValidator{}, nil
+			},
+		},
+		userContext,
+	)
+	if err != nil {
+		return err
+	}
+
+	result, err := (*apiImplementation.AccountDeletionPOST)(sessionContainer, tenantId, options, userContext)
+	if err != nil {
+		return err
+	}
+	if result.OK != nil {
+		return supertokens.Send200Response(options.Res, map[string]interface{}{
+			"status":   "OK",
+			"deleteAt": result.OK.DeleteAt,
+		})
+	} else if result.GeneralError != nil {
+		return supertokens.Send200Response(options.Res, supertokens.ConvertGeneralErrorToJsonResponse(*result.GeneralError))
+	}
+	return supertokens.ErrorIfNoResponse(options.Res)
+}
+
+func AccountDeletionCancel(apiImplementation epmodels.APIInterface, tenantId string, options epmodels.APIOptions, userContext supertokens.UserContext) error {
+	if apiImplementation.AccountDeletionCancelPOST == nil || (*apiImplementation.AccountDeletionCancelPOST) == nil {
+		options.OtherHandler(options.Res, options.Req)
+		return nil
+	}
+
+	body, err := supertokens.ReadFromRequest(options.Req)
+	if err != nil {
+		return err
+	}
+	var formFieldsRaw map[string]interface{}
+	err = json.Unmarshal(body, &formFieldsRaw)
+	if err != nil {
+		return err
+	}
+
+	// the same email and password as for signing in are required to cancel the deletion
+	formFields, err := validateFormFieldsOrThrowError(options.Config.SignInFeature.FormFields, formFieldsRaw["formFields"], tenantId)
+	if err != nil {
+		return err
+	}
+
+	result, err := (*apiImplementation.AccountDeletionCancelPOST)(formFields, tenantId, options, userContext)
+	if err != nil {
+		return err
+	}
+	if result.OK != nil {
+		return supertokens.Send200Response(options.Res, map[string]interface{}{
+			"status": "OK",
+			"user":   result.OK.User,
+		})
+	} else if result.WrongCredentialsError != nil {
+		return supertokens.Send200Response(options.Res, map[string]interface{}{
+			"status": "WRONG_CREDENTIALS_ERROR",
+		})
+	} else if result.AccountLockedError != nil {
+		return supertokens.Send200Response(options.Res, map[string]interface{}{
+			"status":      "ACCOUNT_LOCKED_ERROR",
+			"lockedUntil": result.AccountLockedError.LockedUntil,
+		})
+	} else if result.GeneralError != nil {
+		return supertokens.Send200Response(options.Res, supertokens.ConvertGeneralErrorToJsonResponse(*result.GeneralError))
+	}
+	return supertokens.ErrorIfNoResponse(options.Res)
+}
@@ -0,0 +1,163 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package api
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/epmodels"
+	"github.com/supertokens/supertokens-golang/recipe/emailverification/evclaims"
+	"github.com/supertokens/supertokens-golang/recipe/session"
+	"github.com/supertokens/supertokens-golang/recipe/session/claims"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func EmailChange(apiImplementation epmodels.APIInterface, tenantId string, options epmodels.APIOptions, userContext supertokens.UserContext) error {
+	if apiImplementation.EmailChangePOST == nil || (*apiImplementation.EmailChangePOST) == nil {
+		options.OtherHandler(options.Res, options.Req)
+		return nil
+	}
+
+	sessionContainer, err := session.GetSession(
+		options.Req, options.Res,
+		&sessmodels.VerifySessionOptions{
+			// users must be able to fix a mistyped email that they cannot verify
+			OverrideGlobalClaimValidators: func(globalClaimValidators []claims.SessionClaimValidator, sessionContainer sessmodels.SessionContainer, userContext supertokens.UserContext) ([]claims.SessionClaimValidator, error) {
+				validators := []claims.SessionClaimValidator{}
+				for _, validator := range globalClaimValidators {
+					if validator.Claim != nil && evclaims.EmailVerificationClaim != nil && validator.Claim.Key == evclaims.EmailVerificationClaim.Key {
+						continue
+					}
+					validators = append(validators, validator)
+				}
+				return validators, nil
+			},
+		},
+		userContext,
+	)
+	if err != nil {
+		return err
+	}
+
+	body, err := supertokens.ReadFromRequest(options.Req)
+	if err != nil {
+		return err
+	}
+	var formFieldsRaw map[string]interface{}
+	err = json.Unmarshal(body, &formFieldsRaw)
+	if err != nil {
+		return err
+	}
+
+	formFields, err := validateFormFieldsOrThrowError(options.Config.ResetPasswordUsingTokenFeature.FormFieldsForGenerateTokenForm, formFieldsRaw["formFields"], tenantId)
+	if err != nil {
+		return err
+	}
+	var newEmail string
+	for _, formField := range formFields {
+		if formField.ID == "email" {
+			newEmail = formField.Value.(string)
+		}
+	}
+
+	result, err := (*apiImplementation.EmailChangePOST)(newEmail, sessionContainer, tenantId, options, userContext)
+	if err != nil {
+		return err
+	}
+	if result.OK != nil {
+		return supertokens.Send200Response(options.Res, map[string]interface{}{
+			"status": "OK",
+		})
+	} else if result.EmailAlreadyExistsError != nil {
+		return supertokens.Send200Response(options.Res, map[string]interface{}{
+			"status": "EMAIL_ALREADY_EXISTS_ERROR",
+		})
+	} else if result.EmailNotAllowedError != nil {
+		return supertokens.Send200Response(options.Res, map[string]interface{}{
+			"status": "EMAIL_NOT_ALLOWED_ERROR",
+			"reason": result.EmailNotAllowedError.Reason,
+		})
+	} else if result.GeneralError != nil {
+		return supertokens.Send200Response(options.Res, supertokens.ConvertGeneralErrorToJsonResponse(*result.GeneralError))
+	}
+	return supertokens.ErrorIfNoResponse(options.Res)
+}
+
+func EmailChangeConfirm(apiImplementation epmodels.APIInterface, tenantId string, options epmodels.APIOptions, userContext supertokens.UserContext) error {
+	if apiImplementation.EmailChangeConfirmPOST == nil || (*apiImplementation.EmailChangeConfirmPOST) == nil {
+		options.OtherHandler(options.Res, options.Req)
+		return nil
+	}
+
+	// the link may be opened in a browser without a session
+	sessionRequired := false
+	sessionContainer, err := session.GetSession(
+		options.Req, options.Res,
+		&sessmodels.VerifySessionOptions{
+			SessionRequired: &sessionRequired,
+			OverrideGlobalClaimValidators: func(globalClaimValidators []claims.SessionClaimValidator, sessionContainer sessmodels.SessionContainer, userContext supertokens.UserContext) ([]claims.SessionClaimValidator, error) {
+				validators := []claims.SessionClaimValidator{}
+				return validators, nil
+			},
+		},
+		userContext,
+	)
+	if err != nil {
+		return err
+	}
+
+	body, err := supertokens.ReadFromRequest(options.Req)
+	if err != nil {
+		return err
+	}
+	var readBody map[string]interface{}
+	err = json.Unmarshal(body, &readBody)
+	if err != nil {
+		return err
+	}
+	token, ok := readBody["token"]
+	if !ok {
+		return supertokens.BadInputError{Msg: "Please provide the email change token"}
+	}
+	if reflect.TypeOf(token).Kind() != reflect.String {
+		return supertokens.BadInputError{Msg: "The email change token must be a string"}
+	}
+
+	result, err := (*apiImplementation.EmailChangeConfirmPOST)(token.(string), sessionContainer, tenantId, options, userContext)
+	if err != nil {
+		return err
+	}
+	if result.OK != nil {
+		return supertokens.Send200Response(options.Res, map[string]interface{}{
+			"status": "OK",
+			"user":   result.OK.User,
+		})
+	} else if result.EmailChangeInvalidTokenError != nil {
+		return supertokens.Send200Response(options.Res, map[string]interface{}{
+			"status": "EMAIL_CHANGE_INVALID_TOKEN_ERROR",
+		})
+	} else if result.EmailAlreadyExistsError != nil {
+		return supertokens.Send200Response(options.Res, map[string]interface{}{
+			"status": "EMAIL_ALREADY_EXISTS_ERROR",
+		})
+	} else if result.GeneralError != nil {
+		return supertokens.Send200Response(options.Res, supertokens.ConvertGeneralErrorToJsonResponse(*result.GeneralError))
+	}
+	return supertokens.ErrorIfNoResponse(options.Res)
+}
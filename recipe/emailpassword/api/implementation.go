@@ -16,10 +16,20 @@
 package api
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/supertokens/supertokens-golang/ingredients/emaildelivery"
+	"github.com/supertokens/supertokens-golang/ingredients/emailpolicy"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/accountdeletion"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/accountlockout"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/epclaims"
 	"github.com/supertokens/supertokens-golang/recipe/emailpassword/epmodels"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/passwordpolicy"
+	"github.com/supertokens/supertokens-golang/recipe/emailverification"
+	"github.com/supertokens/supertokens-golang/recipe/emailverification/evclaims"
+	"github.com/supertokens/supertokens-golang/recipe/invitations"
+	"github.com/supertokens/supertokens-golang/recipe/invitations/invitationsmodels"
 	"github.com/supertokens/supertokens-golang/recipe/session"
 	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
 	"github.com/supertokens/supertokens-golang/supertokens"
@@ -135,6 +145,10 @@ func MakeAPIImplementation() epmodels.APIInterface {
 			return epmodels.ResetPasswordPOSTResponse{
 				OK: response.OK,
 			}, nil
+		} else if response.PasswordPolicyViolatedError != nil {
+			return epmodels.ResetPasswordPOSTResponse{
+				PasswordPolicyViolatedError: response.PasswordPolicyViolatedError,
+			}, nil
 		} else {
 			return epmodels.ResetPasswordPOSTResponse{
 				ResetPasswordInvalidTokenError: response.ResetPasswordInvalidTokenError,
@@ -165,16 +179,72 @@ func MakeAPIImplementation() epmodels.APIInterface {
 			}
 		}
 
+		// the lockout is keyed on the normalised email, so that it applies to all its variants
+		lockoutEmail := emailpolicy.Normalise(options.Config.EmailPolicy, email, tenantId)
+
+		lockoutConfig := options.Config.AccountLockout
+		if lockoutConfig != nil {
+			lockedUntil, err := accountlockout.GetLockedUntil(*lockoutConfig, tenantId, lockoutEmail, userContext)
+			if err != nil {
+				return epmodels.SignInPOSTResponse{}, err
+			}
+			if lockedUntil != nil {
+				return makeAccountLockedResponse(*lockedUntil), nil
+			}
+		}
+
 		response, err := (*options.RecipeImplementation.SignIn)(email, password, tenantId, userContext)
 		if err != nil {
 			return epmodels.SignInPOSTResponse{}, err
 		}
 		if response.WrongCredentialsError != nil {
+			if lockoutConfig != nil {
+				// Failed attempts are counted for unknown emails as well, so that the
+				// response does not reveal whether an account exists
+				lockedUntil, err := accountlockout.RecordFailedAttempt(*lockoutConfig, tenantId, lockoutEmail, userContext)
+				if err != nil {
+					return epmodels.SignInPOSTResponse{}, err
+				}
+				if lockedUntil != nil {
+					return makeAccountLockedResponse(*lockedUntil), nil
+				}
+			}
 			return epmodels.SignInPOSTResponse{
 				WrongCredentialsError: &struct{}{},
 			}, nil
 		}
 
+		if lockoutConfig != nil {
+			err = accountlockout.Reset(*lockoutConfig, tenantId, lockoutEmail, userContext)
+			if err != nil {
+				return epmodels.SignInPOSTResponse{}, err
+			}
+		}
+
+		if options.Config.AccountDeletion != nil {
+			job, err := accountdeletion.GetScheduledDeletion(*options.Config.AccountDeletion, response.OK.User.ID, userContext)
+			if err != nil {
+				return epmodels.SignInPOSTResponse{}, err
+			}
+			if job != nil && !options.Config.AccountDeletion.CancelOnSignIn {
+				// the deletion has to be cancelled explicitly using the account deletion cancel API
+				return epmodels.SignInPOSTResponse{
+					AccountDeletionScheduledError: &struct {
+						DeleteAt int64
+					}{
+						DeleteAt: job.DeleteAt,
+					},
+				}, nil
+			}
+			if job != nil {
+				err = (*options.RecipeImplementation.CancelAccountDeletion)(response.OK.User.ID, userContext)
+				if err != nil {
+					return epmodels.SignInPOSTResponse{}, err
+				}
+				supertokens.LogDebugMessage("signInPOST: cancelled the scheduled deletion of user " + response.OK.User.ID)
+			}
+		}
+
 		user := response.OK.User
 		session, err := session.CreateNewSession(options.Req, options.Res, tenantId, user.ID, map[string]interface{}{}, map[string]interface{}{}, userContext)
 		if err != nil {
@@ -215,7 +285,41 @@ func MakeAPIImplementation() epmodels.APIInterface {
 			}
 		}
 
+		normalisedEmail := emailpolicy.Normalise(options.Config.EmailPolicy, email, tenantId)
+		reason := emailpolicy.CheckSignUpAllowed(options.Config.EmailPolicy, normalisedEmail, tenantId)
+		if reason != nil {
+			return epmodels.SignUpPOSTResponse{
+				EmailNotAllowedError: &struct {
+					Reason emailpolicy.Reason
+				}{
+					Reason: *reason,
+				},
+			}, nil
+		}
+
+		var invite *invitationsmodels.Invite
+		invitationsInstance := invitations.GetRecipeInstance()
+		if invitationsInstance != nil {
+			inviteForSignUp, allowed, err := invitationsInstance.GetInviteForSignUp(tenantId, normalisedEmail, options.Config.EmailPolicy, options.Req, userContext)
+			if err != nil {
+				return epmodels.SignUpPOSTResponse{}, err
+			}
+			if !allowed {
+				return epmodels.SignUpPOSTResponse{
+					InviteRequiredError: &struct{}{},
+				}, nil
+			}
+			invite = inviteForSignUp
+		}
+
 		response, err := (*options.RecipeImplementation.SignUp)(email, password, tenantId, userContext)
+		if invitationsInstance != nil && (err != nil || response.OK == nil) {
+			// no user was created, so the invite can be used again
+			cancelErr := invitationsInstance.CancelSignUp(invite, userContext)
+			if err == nil {
+				err = cancelErr
+			}
+		}
 		if err != nil {
 			return epmodels.SignUpPOSTResponse{}, err
 		}
@@ -227,6 +331,13 @@ func MakeAPIImplementation() epmodels.APIInterface {
 
 		user := response.OK.User
 
+		if invitationsInstance != nil {
+			err = invitationsInstance.CompleteSignUp(invite, user.ID, userContext)
+			if err != nil {
+				return epmodels.SignUpPOSTResponse{}, err
+			}
+		}
+
 		session, err := session.CreateNewSession(options.Req, options.Res, tenantId, user.ID, map[string]interface{}{}, map[string]interface{}{}, userContext)
 		if err != nil {
 			return epmodels.SignUpPOSTResponse{}, err
@@ -242,11 +353,412 @@ func MakeAPIImplementation() epmodels.APIInterface {
 			},
 		}, nil
 	}
+
+	passwordPolicyGET := func(tenantId string, options epmodels.APIOptions, userContext supertokens.UserContext) (epmodels.PasswordPolicyGETResponse, error) {
+		if options.Config.PasswordPolicy == nil {
+			return epmodels.PasswordPolicyGETResponse{}, errors.New("should never come here")
+		}
+		return epmodels.PasswordPolicyGETResponse{
+			OK: &struct {
+				Policy passwordpolicy.NormalisedPolicy
+			}{
+				Policy: passwordpolicy.GetPolicy(*options.Config.PasswordPolicy, tenantId),
+			},
+		}, nil
+	}
+
+	emailChangePOST := func(newEmail string, sessionContainer sessmodels.SessionContainer, tenantId string, options epmodels.APIOptions, userContext supertokens.UserContext) (epmodels.EmailChangePOSTResponse, error) {
+		userId := sessionContainer.GetUserIDWithContext(userContext)
+		user, err := (*options.RecipeImplementation.GetUserByID)(userId, userContext)
+		if err != nil {
+			return epmodels.EmailChangePOSTResponse{}, err
+		}
+		if user == nil {
+			return epmodels.EmailChangePOSTResponse{
+				GeneralError: &supertokens.GeneralErrorResponse{Message: "The email can only be changed for email password users"},
+			}, nil
+		}
+
+		normalisedEmail := emailpolicy.Normalise(options.Config.EmailPolicy, newEmail, tenantId)
+		reason := emailpolicy.CheckSignUpAllowed(options.Config.EmailPolicy, normalisedEmail, tenantId)
+		if reason != nil {
+			return epmodels.EmailChangePOSTResponse{
+				EmailNotAllowedError: &struct {
+					Reason emailpolicy.Reason
+				}{
+					Reason: *reason,
+				},
+			}, nil
+		}
+
+		// this also rejects the current email of the user
+		existingUser, err := (*options.RecipeImplementation.GetUserByEmail)(newEmail, tenantId, userContext)
+		if err != nil {
+			return epmodels.EmailChangePOSTResponse{}, err
+		}
+		if existingUser != nil {
+			return epmodels.EmailChangePOSTResponse{
+				EmailAlreadyExistsError: &struct{}{},
+			}, nil
+		}
+
+		response, err := (*options.RecipeImplementation.CreateEmailChangeToken)(userId, newEmail, tenantId, userContext)
+		if err != nil {
+			return epmodels.EmailChangePOSTResponse{}, err
+		}
+		if response.UnknownUserIdError != nil {
+			return epmodels.EmailChangePOSTResponse{}, errors.New("should never come here")
+		}
+
+		emailChangeLink, err := GetEmailChangeLink(options.AppInfo, response.OK.Token, tenantId, options.Req, userContext)
+		if err != nil {
+			return epmodels.EmailChangePOSTResponse{}, err
+		}
+
+		supertokens.LogDebugMessage(fmt.Sprintf("Sending email change email to %s", newEmail))
+		err = (*options.EmailDelivery.IngredientInterfaceImpl.SendEmail)(emaildelivery.EmailType{
+			EmailChange: &emaildelivery.EmailChangeType{
+				User: emaildelivery.User{
+					ID:    userId,
+					Email: newEmail,
+				},
+				PreviousEmail:   user.Email,
+				EmailChangeLink: emailChangeLink,
+				TenantId:        tenantId,
+			},
+		}, userContext)
+		if err != nil {
+			return epmodels.EmailChangePOSTResponse{}, err
+		}
+
+		return epmodels.EmailChangePOSTResponse{
+			OK: &struct{}{},
+		}, nil
+	}
+
+	emailChangeConfirmPOST := func(token string, sessionContainer sessmodels.SessionContainer, tenantId string, options epmodels.APIOptions, userContext supertokens.UserContext) (epmodels.EmailChangeConfirmPOSTResponse, error) {
+		response, err := (*options.RecipeImplementation.ConsumeEmailChangeToken)(token, tenantId, userContext)
+		if err != nil {
+			return epmodels.EmailChangeConfirmPOSTResponse{}, err
+		}
+		if response.InvalidTokenError != nil {
+			return epmodels.EmailChangeConfirmPOSTResponse{
+				EmailChangeInvalidTokenError: &struct{}{},
+			}, nil
+		} else if response.EmailAlreadyExistsError != nil {
+			return epmodels.EmailChangeConfirmPOSTResponse{
+				EmailAlreadyExistsError: &struct{}{},
+			}, nil
+		}
+		user := response.OK.User
+
+		if sessionContainer != nil && sessionContainer.GetUserIDWithContext(userContext) == user.ID && emailverification.GetRecipeInstance() != nil {
+			err = sessionContainer.FetchAndSetClaimWithContext(evclaims.EmailVerificationClaim, userContext)
+			if err != nil {
+				return epmodels.EmailChangeConfirmPOSTResponse{}, err
+			}
+		}
+
+		if options.Config.EmailChangeFeature != nil && options.Config.EmailChangeFeature.NotifyPreviousEmail && response.OK.PreviousEmail != user.Email {
+			supertokens.LogDebugMessage(fmt.Sprintf("Sending email change notification to %s", response.OK.PreviousEmail))
+			err = (*options.EmailDelivery.IngredientInterfaceImpl.SendEmail)(emaildelivery.EmailType{
+				EmailChangeNotification: &emaildelivery.EmailChangeNotificationType{
+					User: emaildelivery.User{
+						ID:    user.ID,
+						Email: response.OK.PreviousEmail,
+					},
+					NewEmail: user.Email,
+					TenantId: tenantId,
+				},
+			}, userContext)
+			if err != nil {
+				// the email has already been changed at this point
+				supertokens.LogDebugMessage("Error sending email change notification: " + err.Error())
+			}
+		}
+
+		return epmodels.EmailChangeConfirmPOSTResponse{
+			OK: &struct{ User epmodels.User }{
+				User: user,
+			},
+		}, nil
+	}
+
+	passwordChangePOST := func(formFields []epmodels.TypeFormField, sessionContainer sessmodels.SessionContainer, tenantId string, options epmodels.APIOptions, userContext supertokens.UserContext) (epmodels.PasswordChangePOSTResponse, error) {
+		var oldPassword string
+		var newPassword string
+		for _, formField := range formFields {
+			if formField.ID == "oldPassword" || formField.ID == "newPassword" {
+				valueAsString, parseErr := withValueAsString(formField.Value, fmt.Sprintf("%s value needs to be a string", formField.ID))
+				if parseErr != nil {
+					return epmodels.PasswordChangePOSTResponse{
+						GeneralError: &supertokens.GeneralErrorResponse{Message: parseErr.Error()},
+					}, nil
+				}
+				if formField.ID == "oldPassword" {
+					oldPassword = valueAsString
+				} else {
+					newPassword = valueAsString
+				}
+			}
+		}
+
+		userId := sessionContainer.GetUserIDWithContext(userContext)
+		user, err := (*options.RecipeImplementation.GetUserByID)(userId, userContext)
+		if err != nil {
+			return epmodels.PasswordChangePOSTResponse{}, err
+		}
+		if user == nil {
+			return epmodels.PasswordChangePOSTResponse{
+				GeneralError: &supertokens.GeneralErrorResponse{Message: "The password can only be changed for email password users"},
+			}, nil
+		}
+
+		email := emailpolicy.Normalise(options.Config.EmailPolicy, user.Email, tenantId)
+
+		// the old password is checked like in the sign in API, so it is limited by the same lockout
+		lockoutConfig := options.Config.AccountLockout
+		if lockoutConfig != nil {
+			lockedUntil, err := accountlockout.GetLockedUntil(*lockoutConfig, tenantId, email, userContext)
+			if err != nil {
+				return epmodels.PasswordChangePOSTResponse{}, err
+			}
+			if lockedUntil != nil {
+				return makePasswordChangeAccountLockedResponse(*lockedUntil), nil
+			}
+		}
+
+		signInResponse, err := (*options.RecipeImplementation.SignIn)(email, oldPassword, tenantId, userContext)
+		if err != nil {
+			return epmodels.PasswordChangePOSTResponse{}, err
+		}
+		if signInResponse.WrongCredentialsError != nil {
+			if lockoutConfig != nil {
+				lockedUntil, err := accountlockout.RecordFailedAttempt(*lockoutConfig, tenantId, email, userContext)
+				if err != nil {
+					return epmodels.PasswordChangePOSTResponse{}, err
+				}
+				if lockedUntil != nil {
+					return makePasswordChangeAccountLockedResponse(*lockedUntil), nil
+				}
+			}
+			return epmodels.PasswordChangePOSTResponse{
+				WrongCredentialsError: &struct{}{},
+			}, nil
+		}
+
+		if lockoutConfig != nil {
+			err = accountlockout.Reset(*lockoutConfig, tenantId, email, userContext)
+			if err != nil {
+				return epmodels.PasswordChangePOSTResponse{}, err
+			}
+		}
+
+		// this applies the sign up password validator, the password policy and the password history
+		updateResponse, err := (*options.RecipeImplementation.UpdateEmailOrPassword)(userId, nil, &newPassword, nil, tenantId, userContext)
+		if err != nil {
+			return epmodels.PasswordChangePOSTResponse{}, err
+		}
+		if updateResponse.PasswordPolicyViolatedError != nil {
+			return epmodels.PasswordChangePOSTResponse{
+				PasswordPolicyViolatedError: updateResponse.PasswordPolicyViolatedError,
+			}, nil
+		} else if updateResponse.OK == nil {
+			return epmodels.PasswordChangePOSTResponse{}, errors.New("should never come here")
+		}
+
+		if options.Config.PasswordChangeFeature != nil && options.Config.PasswordChangeFeature.RevokeOtherSessions {
+			_, err = session.RevokeAllSessionsForUser(userId, nil, userContext)
+			if err != nil {
+				return epmodels.PasswordChangePOSTResponse{}, err
+			}
+			sessionContainer, err = session.CreateNewSession(options.Req, options.Res, tenantId, userId, map[string]interface{}{}, map[string]interface{}{}, userContext)
+			if err != nil {
+				return epmodels.PasswordChangePOSTResponse{}, err
+			}
+		} else if options.Config.PasswordHistory != nil && options.Config.PasswordHistory.MaxAge != 0 {
+			// so that the session is no longer blocked by an expired password
+			err = sessionContainer.FetchAndSetClaimWithContext(epclaims.PasswordExpiredClaim, userContext)
+			if err != nil {
+				return epmodels.PasswordChangePOSTResponse{}, err
+			}
+		}
+
+		supertokens.LogDebugMessage(fmt.Sprintf("Sending password changed email to %s", user.Email))
+		err = (*options.EmailDelivery.IngredientInterfaceImpl.SendEmail)(emaildelivery.EmailType{
+			PasswordChanged: &emaildelivery.PasswordChangedType{
+				User: emaildelivery.User{
+					ID:    user.ID,
+					Email: user.Email,
+				},
+				TenantId: tenantId,
+			},
+		}, userContext)
+		if err != nil {
+			// the password has already been changed at this point
+			supertokens.LogDebugMessage("Error sending password changed email: " + err.Error())
+		}
+
+		return epmodels.PasswordChangePOSTResponse{
+			OK: &struct {
+				Session sessmodels.SessionContainer
+			}{
+				Session: sessionContainer,
+			},
+		}, nil
+	}
+
+	accountDeletionPOST := func(sessionContainer sessmodels.SessionContainer, tenantId string, options epmodels.APIOptions, userContext supertokens.UserContext) (epmodels.AccountDeletionPOSTResponse, error) {
+		response, err := (*options.RecipeImplementation.ScheduleAccountDeletion)(sessionContainer.GetUserIDWithContext(userContext), tenantId, userContext)
+		if err != nil {
+			return epmodels.AccountDeletionPOSTResponse{}, err
+		}
+		if response.UnknownUserIdError != nil {
+			return epmodels.AccountDeletionPOSTResponse{
+				GeneralError: &supertokens.GeneralErrorResponse{Message: "Only email password users can delete their account using this API"},
+			}, nil
+		}
+
+		// all the sessions of the user are revoked when scheduling the deletion, revoking this one
+		// as well clears the session cookies of this request
+		err = sessionContainer.RevokeSessionWithContext(userContext)
+		if err != nil {
+			return epmodels.AccountDeletionPOSTResponse{}, err
+		}
+
+		return epmodels.AccountDeletionPOSTResponse{
+			OK: &struct {
+				DeleteAt int64
+			}{
+				DeleteAt: response.OK.DeleteAt,
+			},
+		}, nil
+	}
+
+	accountDeletionCancelPOST := func(formFields []epmodels.TypeFormField, tenantId string, options epmodels.APIOptions, userContext supertokens.UserContext) (epmodels.AccountDeletionCancelPOSTResponse, error) {
+		var email string
+		var password string
+		for _, formField := range formFields {
+			if formField.ID == "email" || formField.ID == "password" {
+				valueAsString, parseErr := withValueAsString(formField.Value, fmt.Sprintf("%s value needs to be a string", formField.ID))
+				if parseErr != nil {
+					return epmodels.AccountDeletionCancelPOSTResponse{
+						WrongCredentialsError: &struct{}{},
+					}, nil
+				}
+				if formField.ID == "email" {
+					email = valueAsString
+				} else {
+					password = valueAsString
+				}
+			}
+		}
+
+		lockoutEmail := emailpolicy.Normalise(options.Config.EmailPolicy, email, tenantId)
+
+		// this API checks the password like the sign in API, so it must not be a way around the lockout
+		lockoutConfig := options.Config.AccountLockout
+		if lockoutConfig != nil {
+			lockedUntil, err := accountlockout.GetLockedUntil(*lockoutConfig, tenantId, lockoutEmail, userContext)
+			if err != nil {
+				return epmodels.AccountDeletionCancelPOSTResponse{}, err
+			}
+			if lockedUntil != nil {
+				return epmodels.AccountDeletionCancelPOSTResponse{
+					AccountLockedError: &struct {
+						LockedUntil int64
+					}{
+						LockedUntil: *lockedUntil,
+					},
+				}, nil
+			}
+		}
+
+		response, err := (*options.RecipeImplementation.SignIn)(email, password, tenantId, userContext)
+		if err != nil {
+			return epmodels.AccountDeletionCancelPOSTResponse{}, err
+		}
+		if response.WrongCredentialsError != nil {
+			if lockoutConfig != nil {
+				lockedUntil, err := accountlockout.RecordFailedAttempt(*lockoutConfig, tenantId, lockoutEmail, userContext)
+				if err != nil {
+					return epmodels.AccountDeletionCancelPOSTResponse{}, err
+				}
+				if lockedUntil != nil {
+					return epmodels.AccountDeletionCancelPOSTResponse{
+						AccountLockedError: &struct {
+							LockedUntil int64
+						}{
+							LockedUntil: *lockedUntil,
+						},
+					}, nil
+				}
+			}
+			return epmodels.AccountDeletionCancelPOSTResponse{
+				WrongCredentialsError: &struct{}{},
+			}, nil
+		}
+
+		if lockoutConfig != nil {
+			err = accountlockout.Reset(*lockoutConfig, tenantId, lockoutEmail, userContext)
+			if err != nil {
+				return epmodels.AccountDeletionCancelPOSTResponse{}, err
+			}
+		}
+
+		user := response.OK.User
+		err = (*options.RecipeImplementation.CancelAccountDeletion)(user.ID, userContext)
+		if err != nil {
+			return epmodels.AccountDeletionCancelPOSTResponse{}, err
+		}
+
+		session, err := session.CreateNewSession(options.Req, options.Res, tenantId, user.ID, map[string]interface{}{}, map[string]interface{}{}, userContext)
+		if err != nil {
+			return epmodels.AccountDeletionCancelPOSTResponse{}, err
+		}
+
+		return epmodels.AccountDeletionCancelPOSTResponse{
+			OK: &struct {
+				User    epmodels.User
+				Session sessmodels.SessionContainer
+			}{
+				User:    user,
+				Session: session,
+			},
+		}, nil
+	}
+
 	return epmodels.APIInterface{
 		EmailExistsGET:                 &emailExistsGET,
 		GeneratePasswordResetTokenPOST: &generatePasswordResetTokenPOST,
 		PasswordResetPOST:              &passwordResetPOST,
 		SignInPOST:                     &signInPOST,
 		SignUpPOST:                     &signUpPOST,
+		PasswordPolicyGET:              &passwordPolicyGET,
+		EmailChangePOST:                &emailChangePOST,
+		EmailChangeConfirmPOST:         &emailChangeConfirmPOST,
+		PasswordChangePOST:             &passwordChangePOST,
+		AccountDeletionPOST:            &accountDeletionPOST,
+		AccountDeletionCancelPOST:      &accountDeletionCancelPOST,
+	}
+}
+
+func makePasswordChangeAccountLockedResponse(lockedUntil int64) epmodels.PasswordChangePOSTResponse {
+	return epmodels.PasswordChangePOSTResponse{
+		AccountLockedError: &struct {
+			LockedUntil int64
+		}{
+			LockedUntil: lockedUntil,
+		},
+	}
+}
+
+func makeAccountLockedResponse(lockedUntil int64) epmodels.SignInPOSTResponse {
+	return epmodels.SignInPOSTResponse{
+		AccountLockedError: &struct {
+			LockedUntil int64
+		}{
+			LockedUntil: lockedUntil,
+		},
 	}
 }
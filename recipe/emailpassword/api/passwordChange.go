@@ -0,0 +1,113 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/epclaims"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/epmodels"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/errors"
+	"github.com/supertokens/supertokens-golang/recipe/session"
+	"github.com/supertokens/supertokens-golang/recipe/session/claims"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func PasswordChange(apiImplementation epmodels.APIInterface, tenantId string, options epmodels.APIOptions, userContext supertokens.UserContext) error {
+	if apiImplementation.PasswordChangePOST == nil || (*apiImplementation.PasswordChangePOST) == nil {
+		options.OtherHandler(options.Res, options.Req)
+		return nil
+	}
+
+	sessionContainer, err := session.GetSession(
+		options.Req, options.Res,
+		&sessmodels.VerifySessionOptions{
+			// users with an expired password must be able to change it
+			OverrideGlobalClaimValidators: func(globalClaimValidators []claims.SessionClaimValidator, sessionContainer sessmodels.SessionContainer, userContext supertokens.UserContext) ([]claims.SessionClaimValidator, error) {
+				validators := []claims.SessionClaimValidator{}
+				for _, validator := range globalClaimValidators {
+					if validator.Claim != nil && epclaims.PasswordExpiredClaim != nil && validator.Claim.Key == epclaims.PasswordExpiredClaim.Key {
+						continue
+					}
+					validators = append(validators, validator)
+				}
+				return validators, nil
+			},
+		},
+		userContext,
+	)
+	if err != nil {
+		return err
+	}
+
+	body, err := supertokens.ReadFromRequest(options.Req)
+	if err != nil {
+		return err
+	}
+	var formFieldsRaw map[string]interface{}
+	err = json.Unmarshal(body, &formFieldsRaw)
+	if err != nil {
+		return err
+	}
+
+	formFields, err := validateFormFieldsOrThrowError(options.Config.PasswordChangeFeature.FormFields, formFieldsRaw["formFields"], tenantId)
+	if err != nil {
+		return err
+	}
+	for i, formField := range formFields {
+		// passwords are trimmed on sign up and sign in as well
+		valueAsString, parseErr := withValueAsString(formField.Value, fmt.Sprintf("%s value must be a string", formField.ID))
+		if parseErr != nil {
+			return supertokens.BadInputError{Msg: parseErr.Error()}
+		}
+		formFields[i].Value = strings.TrimSpace(valueAsString)
+	}
+
+	result, err := (*apiImplementation.PasswordChangePOST)(formFields, sessionContainer, tenantId, options, userContext)
+	if err != nil {
+		return err
+	}
+	if result.OK != nil {
+		return supertokens.Send200Response(options.Res, map[string]interface{}{
+			"status": "OK",
+		})
+	} else if result.WrongCredentialsError != nil {
+		return supertokens.Send200Response(options.Res, map[string]interface{}{
+			"status": "WRONG_CREDENTIALS_ERROR",
+		})
+	} else if result.AccountLockedError != nil {
+		return supertokens.Send200Response(options.Res, map[string]interface{}{
+			"status":      "ACCOUNT_LOCKED_ERROR",
+			"lockedUntil": result.AccountLockedError.LockedUntil,
+		})
+	} else if result.PasswordPolicyViolatedError != nil {
+		return errors.FieldError{
+			Msg: "Error in input formFields",
+			Payload: []errors.ErrorPayload{{
+				ID:         "newPassword",
+				ErrorMsg:   result.PasswordPolicyViolatedError.FailureReason,
+				Violations: result.PasswordPolicyViolatedError.Violations,
+			}},
+		}
+	} else if result.GeneralError != nil {
+		return supertokens.Send200Response(options.Res, supertokens.ConvertGeneralErrorToJsonResponse(*result.GeneralError))
+	}
+	return supertokens.ErrorIfNoResponse(options.Res)
+}
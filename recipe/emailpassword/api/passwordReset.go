@@ -20,6 +20,7 @@ import (
 	"reflect"
 
 	"github.com/supertokens/supertokens-golang/recipe/emailpassword/epmodels"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/errors"
 	"github.com/supertokens/supertokens-golang/supertokens"
 )
 
@@ -44,6 +45,13 @@ func PasswordReset(apiImplementation epmodels.APIInterface, tenantId string, opt
 		return err
 	}
 
+	// The user is only known once the token is consumed, so the email of the user is
+	// checked by ResetPasswordUsingToken
+	err = validatePasswordOrThrowError(options, formFields, []string{}, tenantId, userContext)
+	if err != nil {
+		return err
+	}
+
 	token, ok := formFieldsRaw["token"]
 	if !ok {
 		return supertokens.BadInputError{Msg: "Please provide the password reset token"}
@@ -64,6 +72,15 @@ func PasswordReset(apiImplementation epmodels.APIInterface, tenantId string, opt
 		return supertokens.Send200Response(options.Res, map[string]interface{}{
 			"status": "RESET_PASSWORD_INVALID_TOKEN_ERROR",
 		})
+	} else if result.PasswordPolicyViolatedError != nil {
+		return errors.FieldError{
+			Msg: "Error in input formFields",
+			Payload: []errors.ErrorPayload{{
+				ID:         "password",
+				ErrorMsg:   result.PasswordPolicyViolatedError.FailureReason,
+				Violations: result.PasswordPolicyViolatedError.Violations,
+			}},
+		}
 	} else if result.GeneralError != nil {
 		return supertokens.Send200Response(options.Res, supertokens.ConvertGeneralErrorToJsonResponse(*result.GeneralError))
 	}
@@ -51,6 +51,16 @@ func SignInAPI(apiImplementation epmodels.APIInterface, tenantId string, options
 		return supertokens.Send200Response(options.Res, map[string]interface{}{
 			"status": "WRONG_CREDENTIALS_ERROR",
 		})
+	} else if result.AccountLockedError != nil {
+		return supertokens.Send200Response(options.Res, map[string]interface{}{
+			"status":      "ACCOUNT_LOCKED_ERROR",
+			"lockedUntil": result.AccountLockedError.LockedUntil,
+		})
+	} else if result.AccountDeletionScheduledError != nil {
+		return supertokens.Send200Response(options.Res, map[string]interface{}{
+			"status":   "ACCOUNT_DELETION_SCHEDULED_ERROR",
+			"deleteAt": result.AccountDeletionScheduledError.DeleteAt,
+		})
 	} else if result.OK != nil {
 		return supertokens.Send200Response(options.Res, map[string]interface{}{
 			"status": "OK",
@@ -44,6 +44,17 @@ func SignUpAPI(apiImplementation epmodels.APIInterface, tenantId string, options
 		return err
 	}
 
+	userInputs := []string{}
+	for _, formField := range formFields {
+		if formField.ID == "email" {
+			userInputs = append(userInputs, formField.Value.(string))
+		}
+	}
+	err = validatePasswordOrThrowError(options, formFields, userInputs, tenantId, userContext)
+	if err != nil {
+		return err
+	}
+
 	result, err := (*apiImplementation.SignUpPOST)(formFields, tenantId, options, userContext)
 	if err != nil {
 		return err
@@ -61,6 +72,15 @@ func SignUpAPI(apiImplementation epmodels.APIInterface, tenantId string, options
 				ErrorMsg: "This email already exists. Please sign in instead.",
 			}},
 		}
+	} else if result.EmailNotAllowedError != nil {
+		return supertokens.Send200Response(options.Res, map[string]interface{}{
+			"status": "EMAIL_NOT_ALLOWED_ERROR",
+			"reason": result.EmailNotAllowedError.Reason,
+		})
+	} else if result.InviteRequiredError != nil {
+		return supertokens.Send200Response(options.Res, map[string]interface{}{
+			"status": "INVITE_REQUIRED_ERROR",
+		})
 	} else if result.GeneralError != nil {
 		return supertokens.Send200Response(options.Res, supertokens.ConvertGeneralErrorToJsonResponse(*result.GeneralError))
 	}
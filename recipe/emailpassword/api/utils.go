@@ -21,8 +21,10 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/breachedpassword"
 	"github.com/supertokens/supertokens-golang/recipe/emailpassword/epmodels"
 	"github.com/supertokens/supertokens-golang/recipe/emailpassword/errors"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/passwordpolicy"
 	"github.com/supertokens/supertokens-golang/supertokens"
 )
 
@@ -155,6 +157,45 @@ func validateFormOrThrowError(configFormFields []epmodels.NormalisedFormField, i
 	return nil
 }
 
+// ValidatePassword checks password against the password policy and the breached password
+// check (if they are configured). userInputs are values like the email of the user that the
+// password must not contain. The breach check is only done if the policy is satisfied.
+func ValidatePassword(config epmodels.TypeNormalisedInput, password string, userInputs []string, tenantId string, userContext supertokens.UserContext) []passwordpolicy.Violation {
+	if config.PasswordPolicy != nil {
+		violations := passwordpolicy.Validate(*config.PasswordPolicy, password, userInputs, tenantId, userContext)
+		if len(violations) > 0 {
+			return violations
+		}
+	}
+	if config.BreachedPasswordCheck != nil {
+		violation := breachedpassword.Check(*config.BreachedPasswordCheck, password, tenantId, userContext)
+		if violation != nil {
+			return []passwordpolicy.Violation{*violation}
+		}
+	}
+	return nil
+}
+
+func validatePasswordOrThrowError(options epmodels.APIOptions, formFields []epmodels.TypeFormField, userInputs []string, tenantId string, userContext supertokens.UserContext) error {
+	for _, formField := range formFields {
+		if formField.ID != "password" {
+			continue
+		}
+		violations := ValidatePassword(options.Config, formField.Value.(string), userInputs, tenantId, userContext)
+		if len(violations) > 0 {
+			return errors.FieldError{
+				Msg: "Error in input formFields",
+				Payload: []errors.ErrorPayload{{
+					ID:         "password",
+					ErrorMsg:   passwordpolicy.GetFailureReason(violations),
+					Violations: violations,
+				}},
+			}
+		}
+	}
+	return nil
+}
+
 func GetPasswordResetLink(appInfo supertokens.NormalisedAppinfo, token string, tenantId string, request *http.Request, userContext supertokens.UserContext) (string, error) {
 	websiteDomain, err := appInfo.GetOrigin(request, userContext)
 	if err != nil {
@@ -168,3 +209,17 @@ func GetPasswordResetLink(appInfo supertokens.NormalisedAppinfo, token string, t
 		tenantId,
 	), nil
 }
+
+func GetEmailChangeLink(appInfo supertokens.NormalisedAppinfo, token string, tenantId string, request *http.Request, userContext supertokens.UserContext) (string, error) {
+	websiteDomain, err := appInfo.GetOrigin(request, userContext)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf(
+		"%s%s/change-email?token=%s&tenantId=%s",
+		websiteDomain.GetAsStringDangerous(),
+		appInfo.WebsiteBasePath.GetAsStringDangerous(),
+		token,
+		tenantId,
+	), nil
+}
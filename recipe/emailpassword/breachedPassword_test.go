@@ -0,0 +1,71 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package emailpassword
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/breachedpassword"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/epmodels"
+)
+
+func TestBreachedPasswordIsRejectedOnSignUp(t *testing.T) {
+	resetAll()
+	defer resetAll()
+
+	// SHA-1 of "password123" is CBFDAC6008F9CAB4083784CBD1874F76618D2A97
+	rangeServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/range/CBFDA", r.URL.Path)
+		w.Write([]byte("C6008F9CAB4083784CBD1874F76618D2A97:2500000\r\n0000000000000000000000000000000000A:0"))
+	}))
+	defer rangeServer.Close()
+
+	testServer := initWithoutCoreForTest(t, &epmodels.TypeInput{
+		BreachedPasswordCheck: &breachedpassword.TypeInput{
+			BaseURL: &rangeServer.URL,
+		},
+	})
+	defer testServer.Close()
+
+	postBody, err := json.Marshal(map[string][]map[string]string{
+		"formFields": {
+			{"id": "email", "value": "johnny@example.com"},
+			{"id": "password", "value": "password123"},
+		},
+	})
+	assert.NoError(t, err)
+
+	resp, err := http.Post(testServer.URL+"/auth/signup", "application/json", bytes.NewBuffer(postBody))
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	dataInBytes, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	resp.Body.Close()
+
+	var result map[string]interface{}
+	assert.NoError(t, json.Unmarshal(dataInBytes, &result))
+	assert.Equal(t, "FIELD_ERROR", result["status"])
+	formFieldError := result["formFields"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "password", formFieldError["id"])
+	assert.Equal(t, breachedpassword.BreachedCode, formFieldError["violations"].([]interface{})[0].(map[string]interface{})["code"])
+}
@@ -0,0 +1,166 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package breachedpassword
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"io"
+	"math"
+	"strings"
+)
+
+var bloomFilterMagic = [4]byte{'S', 'T', 'B', 'F'}
+
+// BloomFilter is an offline alternative to the range API. It is built from the SHA-1 hashes
+// of breached passwords (for example the downloadable Pwned Passwords list) and can return
+// false positives (at the rate it was created with), but never false negatives.
+type BloomFilter struct {
+	bits      []uint64
+	numBits   uint64
+	numHashes uint32
+}
+
+// MakeBloomFilter creates an empty filter sized for expectedItems hashes with the given false
+// positive rate (for example 0.001)
+func MakeBloomFilter(expectedItems uint64, falsePositiveRate float64) (*BloomFilter, error) {
+	if expectedItems == 0 {
+		return nil, errors.New("expectedItems must be greater than 0")
+	}
+	if falsePositiveRate <= 0 || falsePositiveRate >= 1 {
+		return nil, errors.New("falsePositiveRate must be between 0 and 1")
+	}
+	numBits := uint64(math.Ceil(-float64(expectedItems) * math.Log(falsePositiveRate) / (math.Ln2 * math.Ln2)))
+	numHashes := uint32(math.Max(1, math.Round(float64(numBits)/float64(expectedItems)*math.Ln2)))
+	return &BloomFilter{
+		bits:      make([]uint64, (numBits+63)/64),
+		numBits:   numBits,
+		numHashes: numHashes,
+	}, nil
+}
+
+// AddHash adds the SHA-1 hash (in hex) of a breached password to the filter
+func (f *BloomFilter) AddHash(sha1Hash string) error {
+	h1, h2, err := getBloomFilterHashes(sha1Hash)
+	if err != nil {
+		return err
+	}
+	for i := uint32(0); i < f.numHashes; i++ {
+		bit := (h1 + uint64(i)*h2) % f.numBits
+		f.bits[bit/64] |= 1 << (bit % 64)
+	}
+	return nil
+}
+
+// AddPassword adds a breached password to the filter
+func (f *BloomFilter) AddPassword(password string) {
+	// the hash is always valid hex, so this cannot fail
+	_ = f.AddHash(getSHA1Hash(password))
+}
+
+// Test returns true if the SHA-1 hash (in hex) is probably in the filter
+func (f *BloomFilter) Test(sha1Hash string) bool {
+	h1, h2, err := getBloomFilterHashes(sha1Hash)
+	if err != nil {
+		return false
+	}
+	for i := uint32(0); i < f.numHashes; i++ {
+		bit := (h1 + uint64(i)*h2) % f.numBits
+		if f.bits[bit/64]&(1<<(bit%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// AddHashesFromReader adds one hash per line from r. Lines can be in the "HASH:COUNT" format
+// of the Pwned Passwords list, in which case the count is ignored.
+func (f *BloomFilter) AddHashesFromReader(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if colon := strings.Index(line, ":"); colon != -1 {
+			line = line[:colon]
+		}
+		err := f.AddHash(line)
+		if err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// WriteTo writes the filter in a binary format that can be read with ReadBloomFilter
+func (f *BloomFilter) WriteTo(w io.Writer) (int64, error) {
+	header := make([]byte, 16)
+	copy(header, bloomFilterMagic[:])
+	binary.BigEndian.PutUint32(header[4:8], f.numHashes)
+	binary.BigEndian.PutUint64(header[8:16], f.numBits)
+	n, err := w.Write(header)
+	written := int64(n)
+	if err != nil {
+		return written, err
+	}
+	err = binary.Write(w, binary.BigEndian, f.bits)
+	if err != nil {
+		return written, err
+	}
+	return written + int64(len(f.bits)*8), nil
+}
+
+// ReadBloomFilter reads a filter that was written with WriteTo
+func ReadBloomFilter(r io.Reader) (*BloomFilter, error) {
+	header := make([]byte, 16)
+	_, err := io.ReadFull(r, header)
+	if err != nil {
+		return nil, err
+	}
+	if string(header[:4]) != string(bloomFilterMagic[:]) {
+		return nil, errors.New("not a bloom filter file")
+	}
+	f := &BloomFilter{
+		numHashes: binary.BigEndian.Uint32(header[4:8]),
+		numBits:   binary.BigEndian.Uint64(header[8:16]),
+	}
+	if f.numHashes == 0 || f.numBits == 0 {
+		return nil, errors.New("invalid bloom filter file")
+	}
+	f.bits = make([]uint64, (f.numBits+63)/64)
+	err = binary.Read(r, binary.BigEndian, f.bits)
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// getBloomFilterHashes uses two 64 bit parts of the SHA-1 hash for double hashing. The hash
+// is already uniformly distributed, so it does not need to be hashed again.
+func getBloomFilterHashes(sha1Hash string) (uint64, uint64, error) {
+	sum, err := hex.DecodeString(strings.TrimSpace(sha1Hash))
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(sum) != 20 {
+		return 0, 0, errors.New("invalid SHA-1 hash: " + sha1Hash)
+	}
+	return binary.BigEndian.Uint64(sum[0:8]), binary.BigEndian.Uint64(sum[8:16]) | 1, nil
+}
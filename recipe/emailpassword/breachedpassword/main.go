@@ -0,0 +1,155 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package breachedpassword
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/passwordpolicy"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func NormaliseTypeInput(input TypeInput) (TypeNormalisedInput, error) {
+	result := TypeNormalisedInput{
+		BaseURL:         DefaultBaseURL,
+		Timeout:         DefaultTimeout,
+		FailClosed:      input.FailClosed,
+		MinOccurrences:  1,
+		BloomFilter:     input.BloomFilter,
+		GetErrorMessage: input.GetErrorMessage,
+	}
+	if input.BaseURL != nil {
+		result.BaseURL = strings.TrimSuffix(strings.TrimSpace(*input.BaseURL), "/")
+		if result.BaseURL == "" {
+			return TypeNormalisedInput{}, errors.New("BaseURL must not be empty")
+		}
+	}
+	if input.Timeout != nil {
+		if *input.Timeout <= 0 {
+			return TypeNormalisedInput{}, errors.New("Timeout must be greater than 0")
+		}
+		result.Timeout = *input.Timeout
+	}
+	if input.MinOccurrences != nil {
+		if *input.MinOccurrences < 1 {
+			return TypeNormalisedInput{}, errors.New("MinOccurrences must be at least 1")
+		}
+		result.MinOccurrences = *input.MinOccurrences
+	}
+	return result, nil
+}
+
+// IsBreached returns true if password appears in the breach corpus, using the bloom filter
+// if one is configured, or else the range API. Only the first 5 characters of the SHA-1
+// hash of the password are sent to the range API (k-anonymity).
+func IsBreached(config TypeNormalisedInput, password string, userContext supertokens.UserContext) (bool, error) {
+	hash := getSHA1Hash(password)
+	if config.BloomFilter != nil {
+		return config.BloomFilter.Test(hash), nil
+	}
+
+	count, err := getBreachCount(config, hash, userContext)
+	if err != nil {
+		return false, err
+	}
+	return count >= config.MinOccurrences, nil
+}
+
+// Check returns a violation if password appears in the breach corpus. If the check fails,
+// the password is rejected with CheckFailedCode if FailClosed is set and accepted otherwise.
+func Check(config TypeNormalisedInput, password string, tenantId string, userContext supertokens.UserContext) *passwordpolicy.Violation {
+	breached, err := IsBreached(config, password, userContext)
+	if err != nil {
+		supertokens.LogDebugMessage("breachedpassword: check failed: " + err.Error())
+		if !config.FailClosed {
+			return nil
+		}
+		return &passwordpolicy.Violation{
+			Code:    CheckFailedCode,
+			Message: getErrorMessage(config, CheckFailedCode, tenantId, userContext),
+		}
+	}
+	if !breached {
+		return nil
+	}
+	return &passwordpolicy.Violation{
+		Code:    BreachedCode,
+		Message: getErrorMessage(config, BreachedCode, tenantId, userContext),
+	}
+}
+
+func getBreachCount(config TypeNormalisedInput, hash string, userContext supertokens.UserContext) (int, error) {
+	prefix, suffix := hash[:5], hash[5:]
+
+	req, err := http.NewRequestWithContext(supertokens.GetContextFromUserContext(userContext), "GET", config.BaseURL+"/range/"+prefix, nil)
+	if err != nil {
+		return 0, err
+	}
+	// Padding makes all the responses roughly the same size, so the prefix cannot be
+	// inferred from the size of the response. Padded entries have a count of 0.
+	req.Header.Set("Add-Padding", "true")
+
+	client := &http.Client{Timeout: config.Timeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return 0, fmt.Errorf("range API returned %d status", resp.StatusCode)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		parts := strings.SplitN(strings.TrimSpace(scanner.Text()), ":", 2)
+		if len(parts) != 2 || !strings.EqualFold(parts[0], suffix) {
+			continue
+		}
+		count, err := strconv.Atoi(parts[1])
+		if err != nil {
+			return 0, fmt.Errorf("invalid count in the response of the range API: %s", parts[1])
+		}
+		return count, nil
+	}
+	return 0, scanner.Err()
+}
+
+func getSHA1Hash(password string) string {
+	sum := sha1.Sum([]byte(password))
+	return strings.ToUpper(hex.EncodeToString(sum[:]))
+}
+
+func getErrorMessage(config TypeNormalisedInput, code string, tenantId string, userContext supertokens.UserContext) string {
+	if config.GetErrorMessage != nil {
+		message := config.GetErrorMessage(code, tenantId, userContext)
+		if message != nil {
+			return *message
+		}
+	}
+	if code == CheckFailedCode {
+		return "Unable to check the password, please try again later"
+	}
+	return "This password has appeared in a data breach, please choose a different password"
+}
@@ -0,0 +1,177 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package breachedpassword
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// startTestRangeServer serves the range API for the given passwords, with padding entries
+func startTestRangeServer(t *testing.T, breachCounts map[string]int, requestedPrefixes *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		prefix := strings.TrimPrefix(r.URL.Path, "/range/")
+		if requestedPrefixes != nil {
+			*requestedPrefixes = append(*requestedPrefixes, prefix)
+		}
+		assert.Equal(t, "true", r.Header.Get("Add-Padding"))
+
+		lines := []string{}
+		for password, count := range breachCounts {
+			hash := getSHA1Hash(password)
+			if hash[:5] == prefix {
+				lines = append(lines, fmt.Sprintf("%s:%d", hash[5:], count))
+			}
+		}
+		lines = append(lines, "0000000000000000000000000000000000A:0")
+		w.Write([]byte(strings.Join(lines, "\r\n")))
+	}))
+}
+
+func TestRangeAPIOnlySendsHashPrefix(t *testing.T) {
+	requestedPrefixes := []string{}
+	server := startTestRangeServer(t, map[string]int{"password123": 2500000}, &requestedPrefixes)
+	defer server.Close()
+
+	config, err := NormaliseTypeInput(TypeInput{BaseURL: &server.URL})
+	assert.NoError(t, err)
+
+	breached, err := IsBreached(config, "password123", &map[string]interface{}{})
+	assert.NoError(t, err)
+	assert.True(t, breached)
+
+	breached, err = IsBreached(config, "vG7#qLz!9wRt2$kP", &map[string]interface{}{})
+	assert.NoError(t, err)
+	assert.False(t, breached)
+
+	assert.Equal(t, []string{getSHA1Hash("password123")[:5], getSHA1Hash("vG7#qLz!9wRt2$kP")[:5]}, requestedPrefixes)
+	assert.Equal(t, "CBFDA", requestedPrefixes[0])
+}
+
+func TestMinOccurrences(t *testing.T) {
+	server := startTestRangeServer(t, map[string]int{"rarelyUsed99": 2}, nil)
+	defer server.Close()
+
+	minOccurrences := 3
+	config, err := NormaliseTypeInput(TypeInput{BaseURL: &server.URL, MinOccurrences: &minOccurrences})
+	assert.NoError(t, err)
+	assert.Nil(t, Check(config, "rarelyUsed99", "public", &map[string]interface{}{}))
+
+	minOccurrences = 2
+	config, err = NormaliseTypeInput(TypeInput{BaseURL: &server.URL, MinOccurrences: &minOccurrences})
+	assert.NoError(t, err)
+	violation := Check(config, "rarelyUsed99", "public", &map[string]interface{}{})
+	assert.Equal(t, BreachedCode, violation.Code)
+	assert.Equal(t, "This password has appeared in a data breach, please choose a different password", violation.Message)
+}
+
+func TestFailOpenAndFailClosed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	timeout := 50 * time.Millisecond
+	config, err := NormaliseTypeInput(TypeInput{BaseURL: &server.URL, Timeout: &timeout})
+	assert.NoError(t, err)
+
+	_, err = IsBreached(config, "password123", &map[string]interface{}{})
+	assert.Error(t, err)
+	assert.Nil(t, Check(config, "password123", "public", &map[string]interface{}{}))
+
+	config.FailClosed = true
+	violation := Check(config, "password123", "public", &map[string]interface{}{})
+	assert.Equal(t, CheckFailedCode, violation.Code)
+
+	errorServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(503)
+	}))
+	defer errorServer.Close()
+	config.BaseURL = errorServer.URL
+	_, err = IsBreached(config, "password123", &map[string]interface{}{})
+	assert.EqualError(t, err, "range API returned 503 status")
+}
+
+func TestInvalidConfig(t *testing.T) {
+	timeout := time.Duration(0)
+	_, err := NormaliseTypeInput(TypeInput{Timeout: &timeout})
+	assert.EqualError(t, err, "Timeout must be greater than 0")
+
+	minOccurrences := 0
+	_, err = NormaliseTypeInput(TypeInput{MinOccurrences: &minOccurrences})
+	assert.EqualError(t, err, "MinOccurrences must be at least 1")
+
+	config, err := NormaliseTypeInput(TypeInput{})
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultBaseURL, config.BaseURL)
+	assert.Equal(t, DefaultTimeout, config.Timeout)
+}
+
+func TestBloomFilter(t *testing.T) {
+	filter, err := MakeBloomFilter(1000, 0.001)
+	assert.NoError(t, err)
+
+	filter.AddPassword("password123")
+	err = filter.AddHashesFromReader(strings.NewReader(getSHA1Hash("qwerty") + ":3912816\n\n" + strings.ToLower(getSHA1Hash("letmein")) + "\n"))
+	assert.NoError(t, err)
+	assert.Error(t, filter.AddHash("not a hash"))
+
+	config, err := NormaliseTypeInput(TypeInput{BloomFilter: filter})
+	assert.NoError(t, err)
+
+	// no requests are made when using the bloom filter
+	config.BaseURL = "http://localhost:1"
+	for _, password := range []string{"password123", "qwerty", "letmein"} {
+		violation := Check(config, password, "public", &map[string]interface{}{})
+		assert.NotNil(t, violation)
+		assert.Equal(t, BreachedCode, violation.Code)
+	}
+
+	falsePositives := 0
+	for i := 0; i < 10000; i++ {
+		if filter.Test(getSHA1Hash(fmt.Sprintf("unbreached-%d", i))) {
+			falsePositives++
+		}
+	}
+	assert.Less(t, falsePositives, 50)
+}
+
+func TestBloomFilterSerialisation(t *testing.T) {
+	filter, err := MakeBloomFilter(100, 0.01)
+	assert.NoError(t, err)
+	filter.AddPassword("password123")
+
+	var buf bytes.Buffer
+	n, err := filter.WriteTo(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(buf.Len()), n)
+
+	readFilter, err := ReadBloomFilter(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, filter, readFilter)
+	assert.True(t, readFilter.Test(getSHA1Hash("password123")))
+
+	_, err = ReadBloomFilter(strings.NewReader("something else entirely"))
+	assert.EqualError(t, err, "not a bloom filter file")
+}
@@ -0,0 +1,59 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package breachedpassword
+
+import (
+	"time"
+
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+const (
+	BreachedCode    = "PASSWORD_BREACHED"
+	CheckFailedCode = "PASSWORD_BREACH_CHECK_FAILED"
+
+	DefaultBaseURL = "https://api.pwnedpasswords.com"
+	DefaultTimeout = 5 * time.Second
+)
+
+type TypeInput struct {
+	// BaseURL of a Have I Been Pwned compatible range API. The SHA-1 prefix of the password is
+	// sent to <BaseURL>/range/<prefix>. Defaults to https://api.pwnedpasswords.com
+	BaseURL *string
+	// Timeout of the request to the range API. Defaults to 5 seconds.
+	Timeout *time.Duration
+	// FailClosed rejects the password if the range API cannot be reached (or returns an error).
+	// By default, the password is accepted in that case.
+	FailClosed bool
+	// MinOccurrences is the number of times a password must appear in the breach corpus to be
+	// rejected. Defaults to 1. It is ignored when using BloomFilter.
+	MinOccurrences *int
+	// BloomFilter is checked instead of the range API if it is set, so that no request leaves the
+	// server. See MakeBloomFilter and ReadBloomFilter.
+	BloomFilter *BloomFilter
+	// GetErrorMessage can be used to localise the error messages. Returning nil uses the default message.
+	GetErrorMessage func(code string, tenantId string, userContext supertokens.UserContext) *string
+}
+
+type TypeNormalisedInput struct {
+	BaseURL         string
+	Timeout         time.Duration
+	FailClosed      bool
+	MinOccurrences  int
+	BloomFilter     *BloomFilter
+	GetErrorMessage func(code string, tenantId string, userContext supertokens.UserContext) *string
+}
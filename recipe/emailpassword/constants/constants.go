@@ -22,4 +22,10 @@ const (
 	PasswordResetAPI              = "/user/password/reset"
 	SignupEmailExistsAPIOld       = "/signup/email/exists"
 	SignupEmailExistsAPI          = "/emailpassword/email/exists"
+	PasswordPolicyAPI             = "/password/policy"
+	EmailChangeAPI                = "/user/email/change"
+	EmailChangeConfirmAPI         = "/user/email/change/confirm"
+	PasswordChangeAPI             = "/user/password/change"
+	AccountDeletionAPI            = "/user/delete"
+	AccountDeletionCancelAPI      = "/user/delete/cancel"
 )
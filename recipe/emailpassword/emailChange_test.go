@@ -0,0 +1,287 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package emailpassword
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/ingredients/deliverycapture"
+	"github.com/supertokens/supertokens-golang/ingredients/emaildelivery"
+	"github.com/supertokens/supertokens-golang/ingredients/emailpolicy"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/emaildelivery/backwardCompatibilityService"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/emaildelivery/smtpService"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/epmodels"
+	"github.com/supertokens/supertokens-golang/recipe/emailverification"
+	"github.com/supertokens/supertokens-golang/recipe/emailverification/evmodels"
+	"github.com/supertokens/supertokens-golang/recipe/session"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+	"github.com/supertokens/supertokens-golang/test/unittesting"
+)
+
+var testEmailChangeInput = emaildelivery.EmailType{
+	EmailChange: &emaildelivery.EmailChangeType{
+		User: emaildelivery.User{
+			ID:    "someId",
+			Email: "new@example.com",
+		},
+		PreviousEmail:   "old@example.com",
+		EmailChangeLink: "https://supertokens.io/auth/change-email?token=abc&tenantId=public",
+		TenantId:        "public",
+	},
+}
+
+func postRequestWithSessionForTest(testUrl string, path string, body map[string]interface{}, accessToken string, antiCsrf string) (map[string]interface{}, error) {
+	postBody, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest(http.MethodPost, testUrl+path, bytes.NewBuffer(postBody))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if accessToken != "" {
+		req.Header.Add("Cookie", "sAccessToken="+accessToken)
+		req.Header.Add("anti-csrf", antiCsrf)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	result := map[string]interface{}{
+		"statusCode": resp.StatusCode,
+	}
+	if resp.StatusCode == 200 {
+		err = json.Unmarshal(respBody, &result)
+	}
+	return result, err
+}
+
+func TestEmailChangeAPIsAreDisabledByDefault(t *testing.T) {
+	resetAll()
+	defer resetAll()
+	testServer := initWithoutCoreForTest(t, nil)
+	defer testServer.Close()
+
+	result, err := postRequestWithSessionForTest(testServer.URL, "/auth/user/email/change", map[string]interface{}{}, "", "")
+	assert.NoError(t, err)
+	assert.Equal(t, 404, result["statusCode"])
+
+	result, err = postRequestWithSessionForTest(testServer.URL, "/auth/user/email/change/confirm", map[string]interface{}{"token": "abc"}, "", "")
+	assert.NoError(t, err)
+	assert.Equal(t, 404, result["statusCode"])
+}
+
+func TestEmailChangeContentUsesBuiltInTemplates(t *testing.T) {
+	resetAll()
+	defer resetAll()
+	testServer := initWithoutCoreForTest(t, &epmodels.TypeInput{
+		EmailChangeFeature: &epmodels.TypeInputEmailChangeFeature{},
+	})
+	defer testServer.Close()
+
+	content, err := smtpService.GetDefaultContent(testEmailChangeInput, &map[string]interface{}{})
+	assert.NoError(t, err)
+	assert.Equal(t, "new@example.com", content.ToEmail)
+	assert.Equal(t, "Confirm your new email address", content.Subject)
+	assert.Contains(t, content.TextBody, "https://supertokens.io/auth/change-email?token=abc&tenantId=public")
+}
+
+func TestEmailChangeIsNotSentByBackwardCompatibilityService(t *testing.T) {
+	service := backwardCompatibilityService.MakeBackwardCompatibilityService(epmodels.RecipeInterface{}, supertokens.NormalisedAppinfo{}, nil)
+	err := (*service.SendEmail)(testEmailChangeInput, &map[string]interface{}{})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "email change emails cannot be sent by the default email delivery service")
+}
+
+func TestEmailChangeFlow(t *testing.T) {
+	BeforeEach()
+	connectionURI := unittesting.StartUpST("localhost", "8080")
+	defer AfterEach()
+
+	inbox := deliverycapture.MakeInbox(10)
+	testServer := supertokensInitForTest(t, connectionURI,
+		Init(&epmodels.TypeInput{
+			EmailDelivery: &emaildelivery.TypeInput{
+				Service: MakeCaptureEmailService(inbox),
+			},
+			EmailChangeFeature: &epmodels.TypeInputEmailChangeFeature{
+				NotifyPreviousEmail: true,
+			},
+		}),
+		emailverification.Init(evmodels.TypeInput{
+			Mode: evmodels.ModeRequired,
+		}),
+		session.Init(&sessmodels.TypeInput{
+			GetTokenTransferMethod: func(req *http.Request, forCreateNewSession bool, userContext supertokens.UserContext) sessmodels.TokenTransferMethod {
+				return sessmodels.CookieTransferMethod
+			},
+		}),
+	)
+	defer testServer.Close()
+
+	resp, err := unittesting.SignupRequest("old@example.com", "validpass123", testServer.URL)
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	cookies := unittesting.ExtractInfoFromResponse(resp)
+
+	_, err = SignUp("public", "taken@example.com", "validpass123")
+	assert.NoError(t, err)
+
+	result, err := postRequestWithSessionForTest(testServer.URL, "/auth/user/email/change", map[string]interface{}{
+		"formFields": []map[string]interface{}{{"id": "email", "value": "taken@example.com"}},
+	}, cookies["sAccessToken"], cookies["antiCsrf"])
+	assert.NoError(t, err)
+	assert.Equal(t, "EMAIL_ALREADY_EXISTS_ERROR", result["status"])
+
+	result, err = postRequestWithSessionForTest(testServer.URL, "/auth/user/email/change", map[string]interface{}{
+		"formFields": []map[string]interface{}{{"id": "email", "value": "not an email"}},
+	}, cookies["sAccessToken"], cookies["antiCsrf"])
+	assert.NoError(t, err)
+	assert.Equal(t, "FIELD_ERROR", result["status"])
+
+	// the email of the session is not verified, but the email can still be changed
+	result, err = postRequestWithSessionForTest(testServer.URL, "/auth/user/email/change", map[string]interface{}{
+		"formFields": []map[string]interface{}{{"id": "email", "value": "new@example.com"}},
+	}, cookies["sAccessToken"], cookies["antiCsrf"])
+	assert.NoError(t, err)
+	assert.Equal(t, "OK", result["status"])
+
+	user, err := GetUserByEmail("public", "old@example.com")
+	assert.NoError(t, err)
+	assert.NotNil(t, user)
+
+	message := inbox.GetLatestMessage("new@example.com")
+	assert.NotNil(t, message)
+	assert.Len(t, message.Links, 1)
+	link, err := url.Parse(message.Links[0])
+	assert.NoError(t, err)
+	assert.Equal(t, "/auth/change-email", link.Path)
+
+	result, err = postRequestWithSessionForTest(testServer.URL, "/auth/user/email/change/confirm", map[string]interface{}{
+		"token": link.Query().Get("token"),
+	}, "", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "OK", result["status"])
+	assert.Equal(t, "new@example.com", result["user"].(map[string]interface{})["email"])
+
+	user, err = GetUserByID(user.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "new@example.com", user.Email)
+	isVerified, err := emailverification.IsEmailVerified(user.ID, nil)
+	assert.NoError(t, err)
+	assert.True(t, isVerified)
+
+	notification := inbox.GetLatestMessage("old@example.com")
+	assert.NotNil(t, notification)
+	assert.Equal(t, "The email address of your account has been changed", notification.Subject)
+
+	result, err = postRequestWithSessionForTest(testServer.URL, "/auth/user/email/change/confirm", map[string]interface{}{
+		"token": link.Query().Get("token"),
+	}, "", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "EMAIL_CHANGE_INVALID_TOKEN_ERROR", result["status"])
+
+	// email change tokens cannot be used to verify an email
+	tokenResponse, err := CreateEmailChangeToken("public", user.ID, "other@example.com")
+	assert.NoError(t, err)
+	verifyResponse, err := emailverification.VerifyEmailUsingToken("public", tokenResponse.OK.Token)
+	assert.NoError(t, err)
+	assert.NotNil(t, verifyResponse.EmailVerificationInvalidTokenError)
+}
+
+func TestConsumeEmailChangeTokenWhenEmailWasTaken(t *testing.T) {
+	BeforeEach()
+	connectionURI := unittesting.StartUpST("localhost", "8080")
+	defer AfterEach()
+
+	testServer := supertokensInitForTest(t, connectionURI,
+		Init(&epmodels.TypeInput{
+			EmailChangeFeature: &epmodels.TypeInputEmailChangeFeature{},
+		}),
+		session.Init(nil),
+	)
+	defer testServer.Close()
+
+	signUpResponse, err := SignUp("public", "old@example.com", "validpass123")
+	assert.NoError(t, err)
+	userId := signUpResponse.OK.User.ID
+
+	tokenResponse, err := CreateEmailChangeToken("public", userId, "new@example.com")
+	assert.NoError(t, err)
+	assert.NotNil(t, tokenResponse.OK)
+
+	_, err = SignUp("public", "new@example.com", "validpass123")
+	assert.NoError(t, err)
+
+	consumeResponse, err := ConsumeEmailChangeToken("public", tokenResponse.OK.Token)
+	assert.NoError(t, err)
+	assert.NotNil(t, consumeResponse.EmailAlreadyExistsError)
+
+	user, err := GetUserByID(userId)
+	assert.NoError(t, err)
+	assert.Equal(t, "old@example.com", user.Email)
+
+	tokenResponse, err = CreateEmailChangeToken("public", "unknown", "other@example.com")
+	assert.NoError(t, err)
+	assert.NotNil(t, tokenResponse.UnknownUserIdError)
+}
+
+func TestEmailChangeRejectsEmailsNotAllowedByTheEmailPolicy(t *testing.T) {
+	BeforeEach()
+	connectionURI := unittesting.StartUpST("localhost", "8080")
+	defer AfterEach()
+
+	testServer := supertokensInitForTest(t, connectionURI,
+		Init(&epmodels.TypeInput{
+			EmailChangeFeature: &epmodels.TypeInputEmailChangeFeature{},
+			EmailPolicy: &emailpolicy.TypeInput{
+				DeniedDomains: []string{"competitor.com"},
+			},
+		}),
+		session.Init(&sessmodels.TypeInput{
+			GetTokenTransferMethod: func(req *http.Request, forCreateNewSession bool, userContext supertokens.UserContext) sessmodels.TokenTransferMethod {
+				return sessmodels.CookieTransferMethod
+			},
+		}),
+	)
+	defer testServer.Close()
+
+	resp, err := unittesting.SignupRequest("old@example.com", "validpass123", testServer.URL)
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	cookies := unittesting.ExtractInfoFromResponse(resp)
+
+	result, err := postRequestWithSessionForTest(testServer.URL, "/auth/user/email/change", map[string]interface{}{
+		"formFields": []map[string]interface{}{{"id": "email", "value": "John@Competitor.COM"}},
+	}, cookies["sAccessToken"], cookies["antiCsrf"])
+	assert.NoError(t, err)
+	assert.Equal(t, "EMAIL_NOT_ALLOWED_ERROR", result["status"])
+	assert.Equal(t, "DOMAIN_DENIED", result["reason"])
+}
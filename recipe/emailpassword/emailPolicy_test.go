@@ -0,0 +1,142 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package emailpassword
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/ingredients/emailpolicy"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/epmodels"
+	"github.com/supertokens/supertokens-golang/recipe/session"
+	"github.com/supertokens/supertokens-golang/test/unittesting"
+)
+
+func signUpWithEmailForTest(t *testing.T, url string, email string) map[string]interface{} {
+	postBody, err := json.Marshal(map[string][]map[string]string{
+		"formFields": {
+			{"id": "email", "value": email},
+			{"id": "password", "value": "validpass123"},
+		},
+	})
+	assert.NoError(t, err)
+	resp, err := http.Post(url+"/auth/signup", "application/json", bytes.NewBuffer(postBody))
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	dataInBytes, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	resp.Body.Close()
+
+	var result map[string]interface{}
+	assert.NoError(t, json.Unmarshal(dataInBytes, &result))
+	return result
+}
+
+func TestSignUpRejectsEmailsNotAllowedByTheEmailPolicy(t *testing.T) {
+	resetAll()
+	defer resetAll()
+	testServer := initWithoutCoreForTest(t, &epmodels.TypeInput{
+		EmailPolicy: &emailpolicy.TypeInput{
+			BlockDisposableEmails: true,
+			DeniedDomains:         []string{"competitor.com"},
+		},
+	})
+	defer testServer.Close()
+
+	result := signUpWithEmailForTest(t, testServer.URL, "john@mailinator.com")
+	assert.Equal(t, "EMAIL_NOT_ALLOWED_ERROR", result["status"])
+	assert.Equal(t, "DISPOSABLE_EMAIL", result["reason"])
+
+	// the domain is checked after the email is normalised
+	result = signUpWithEmailForTest(t, testServer.URL, "John@Competitor.COM")
+	assert.Equal(t, "EMAIL_NOT_ALLOWED_ERROR", result["status"])
+	assert.Equal(t, "DOMAIN_DENIED", result["reason"])
+}
+
+func TestEmailsAreNormalisedBeforeCoreLookups(t *testing.T) {
+	BeforeEach()
+	connectionURI := unittesting.StartUpST("localhost", "8080")
+	defer AfterEach()
+
+	testServer := supertokensInitForTest(t, connectionURI,
+		Init(&epmodels.TypeInput{
+			EmailPolicy: &emailpolicy.TypeInput{
+				NormaliseEmail: emailpolicy.NormaliseEmailWithProviderAliases,
+			},
+		}),
+		session.Init(nil),
+	)
+	defer testServer.Close()
+
+	result := signUpWithEmailForTest(t, testServer.URL, "John.Doe+Work@Gmail.com")
+	assert.Equal(t, "OK", result["status"])
+	assert.Equal(t, "johndoe@gmail.com", result["user"].(map[string]interface{})["email"])
+
+	// another alias of the same inbox cannot create a second account
+	result = signUpWithEmailForTest(t, testServer.URL, "j.o.h.n.doe@googlemail.com")
+	assert.Equal(t, "FIELD_ERROR", result["status"])
+
+	signInResponse, err := SignIn("public", "JohnDoe@gmail.com", "validpass123")
+	assert.NoError(t, err)
+	assert.NotNil(t, signInResponse.OK)
+
+	user, err := GetUserByEmail("public", " john.doe@gmail.com ")
+	assert.NoError(t, err)
+	assert.NotNil(t, user)
+	assert.Equal(t, signInResponse.OK.User.ID, user.ID)
+}
+
+func TestUsersThatSignedUpBeforeTheEmailPolicyCanStillSignIn(t *testing.T) {
+	BeforeEach()
+	connectionURI := unittesting.StartUpST("localhost", "8080")
+	defer AfterEach()
+
+	testServer := supertokensInitForTest(t, connectionURI, Init(nil), session.Init(nil))
+	signUpResponse, err := SignUp("public", "John.Doe@Gmail.com", "validpass123")
+	assert.NoError(t, err)
+	assert.NotNil(t, signUpResponse.OK)
+	testServer.Close()
+
+	resetAll()
+	testServer = supertokensInitForTest(t, connectionURI,
+		Init(&epmodels.TypeInput{
+			EmailPolicy: &emailpolicy.TypeInput{
+				NormaliseEmail: emailpolicy.NormaliseEmailWithProviderAliases,
+			},
+		}),
+		session.Init(nil),
+	)
+	defer testServer.Close()
+
+	// the email of the user is not normalised in the core, so it is looked up as it was given
+	signInResponse, err := SignIn("public", "John.Doe@Gmail.com", "validpass123")
+	assert.NoError(t, err)
+	assert.NotNil(t, signInResponse.OK)
+	assert.Equal(t, signUpResponse.OK.User.ID, signInResponse.OK.User.ID)
+
+	user, err := GetUserByEmail("public", "John.Doe@Gmail.com")
+	assert.NoError(t, err)
+	assert.NotNil(t, user)
+	assert.Equal(t, signUpResponse.OK.User.ID, user.ID)
+
+	result := signUpWithEmailForTest(t, testServer.URL, "John.Doe@Gmail.com")
+	assert.Equal(t, "FIELD_ERROR", result["status"])
+}
@@ -0,0 +1,87 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package emailchange
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func NormaliseTypeInput(input TypeInput) (TypeNormalisedInput, error) {
+	result := TypeNormalisedInput{
+		TokenLifetime: 24 * time.Hour,
+		Store:         MakeInMemoryStore(),
+	}
+	if input.TokenLifetime != nil {
+		if *input.TokenLifetime <= 0 {
+			return TypeNormalisedInput{}, errors.New("TokenLifetime must be greater than 0")
+		}
+		result.TokenLifetime = *input.TokenLifetime
+	}
+	if input.Store != nil {
+		result.Store = *input.Store
+	}
+	return result, nil
+}
+
+// CreateToken saves a pending change of the email of the user to newEmail and returns the token
+// that confirms it
+func CreateToken(config TypeNormalisedInput, userId string, tenantId string, newEmail string, userContext supertokens.UserContext) (string, error) {
+	tokenBytes := make([]byte, 32)
+	if _, err := rand.Read(tokenBytes); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(tokenBytes)
+
+	err := config.Store.Save(hashToken(token), PendingChange{
+		UserId:    userId,
+		TenantId:  tenantId,
+		NewEmail:  newEmail,
+		ExpiresAt: getCurrentTimeInMS() + config.TokenLifetime.Milliseconds(),
+	}, userContext)
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// ConsumeToken returns the pending change of the token and removes it. It returns nil if the
+// token is unknown, has expired or was created in another tenant.
+func ConsumeToken(config TypeNormalisedInput, tenantId string, token string, userContext supertokens.UserContext) (*PendingChange, error) {
+	change, err := config.Store.Consume(hashToken(token), userContext)
+	if err != nil || change == nil {
+		return nil, err
+	}
+	if change.TenantId != tenantId || change.ExpiresAt < getCurrentTimeInMS() {
+		return nil, nil
+	}
+	return change, nil
+}
+
+func hashToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+func getCurrentTimeInMS() int64 {
+	return time.Now().UnixNano() / int64(time.Millisecond)
+}
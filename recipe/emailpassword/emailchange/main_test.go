@@ -0,0 +1,86 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package emailchange
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateAndConsumeToken(t *testing.T) {
+	config, err := NormaliseTypeInput(TypeInput{})
+	assert.NoError(t, err)
+	userContext := &map[string]interface{}{}
+
+	token, err := CreateToken(config, "userId", "public", "new@example.com", userContext)
+	assert.NoError(t, err)
+
+	change, err := ConsumeToken(config, "public", "unknown", userContext)
+	assert.NoError(t, err)
+	assert.Nil(t, change)
+
+	change, err = ConsumeToken(config, "public", token, userContext)
+	assert.NoError(t, err)
+	assert.NotNil(t, change)
+	assert.Equal(t, "userId", change.UserId)
+	assert.Equal(t, "new@example.com", change.NewEmail)
+
+	// a token can only be used once
+	change, err = ConsumeToken(config, "public", token, userContext)
+	assert.NoError(t, err)
+	assert.Nil(t, change)
+}
+
+func TestTokenIsOnlyValidInItsTenant(t *testing.T) {
+	config, err := NormaliseTypeInput(TypeInput{})
+	assert.NoError(t, err)
+	userContext := &map[string]interface{}{}
+
+	token, err := CreateToken(config, "userId", "tenant1", "new@example.com", userContext)
+	assert.NoError(t, err)
+
+	change, err := ConsumeToken(config, "public", token, userContext)
+	assert.NoError(t, err)
+	assert.Nil(t, change)
+}
+
+func TestExpiredTokensAreRejected(t *testing.T) {
+	tokenLifetime := time.Millisecond
+	config, err := NormaliseTypeInput(TypeInput{TokenLifetime: &tokenLifetime})
+	assert.NoError(t, err)
+	userContext := &map[string]interface{}{}
+
+	token, err := CreateToken(config, "userId", "public", "new@example.com", userContext)
+	assert.NoError(t, err)
+	time.Sleep(5 * time.Millisecond)
+
+	change, err := ConsumeToken(config, "public", token, userContext)
+	assert.NoError(t, err)
+	assert.Nil(t, change)
+}
+
+func TestConfigValidation(t *testing.T) {
+	tokenLifetime := time.Duration(0)
+	_, err := NormaliseTypeInput(TypeInput{TokenLifetime: &tokenLifetime})
+	assert.EqualError(t, err, "TokenLifetime must be greater than 0")
+
+	config, err := NormaliseTypeInput(TypeInput{})
+	assert.NoError(t, err)
+	assert.Equal(t, 24*time.Hour, config.TokenLifetime)
+}
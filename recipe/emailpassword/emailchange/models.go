@@ -0,0 +1,54 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package emailchange
+
+import (
+	"time"
+
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+// PendingChange is an email change that waits for the user to open the link sent to the new email
+type PendingChange struct {
+	UserId   string `json:"userId"`
+	TenantId string `json:"tenantId"`
+	NewEmail string `json:"newEmail"`
+	// ExpiresAt is the time (in milliseconds) after which the change cannot be confirmed
+	ExpiresAt int64 `json:"expiresAt"`
+}
+
+// Store keeps the pending email changes by the SHA-256 hash of their token, so that the tokens
+// cannot be used by someone who can read the store.
+type Store struct {
+	Save func(tokenHash string, change PendingChange, userContext supertokens.UserContext) error
+	// Consume returns and deletes the pending change of the token hash in one step, so that a
+	// token cannot be used twice. It returns nil if there is no pending change for the hash.
+	Consume func(tokenHash string, userContext supertokens.UserContext) (*PendingChange, error)
+}
+
+type TypeInput struct {
+	// TokenLifetime defaults to 1 day
+	TokenLifetime *time.Duration
+	// Store defaults to an in-memory store, which is not shared between instances of the
+	// backend and loses the pending changes when the process exits
+	Store *Store
+}
+
+type TypeNormalisedInput struct {
+	TokenLifetime time.Duration
+	Store         Store
+}
@@ -40,6 +40,10 @@ func MakeBackwardCompatibilityService(recipeInterfaceImpl epmodels.RecipeInterfa
 			// will get reset by the getUserById call above.
 			user.Email = input.PasswordReset.User.Email
 			sendResetPasswordEmail(*user, input.PasswordReset.PasswordResetLink, userContext)
+		} else if input.EmailChange != nil || input.EmailChangeNotification != nil {
+			return errors.New("email change emails cannot be sent by the default email delivery service. Please set an email delivery service (for example emailpassword.MakeSMTPService) in the EmailDelivery config")
+		} else if input.PasswordChanged != nil {
+			return errors.New("password changed emails cannot be sent by the default email delivery service. Please set an email delivery service (for example emailpassword.MakeSMTPService) in the EmailDelivery config")
 		} else {
 			return errors.New("should never come here")
 		}
@@ -26,12 +26,20 @@ import (
 func MakeSMTPService(config emaildelivery.SMTPServiceConfig) *emaildelivery.EmailDeliveryInterface {
 	serviceImpl := MakeServiceImplementation(config.Settings)
 
+	if config.Templates != nil {
+		templates := *config.Templates
+		getContent := func(input emaildelivery.EmailType, userContext supertokens.UserContext) (emaildelivery.EmailContent, error) {
+			return emaildelivery.GetContentFromTemplates(templates, input, userContext)
+		}
+		serviceImpl.GetContent = &getContent
+	}
+
 	if config.Override != nil {
 		serviceImpl = config.Override(serviceImpl)
 	}
 
 	sendEmail := func(input emaildelivery.EmailType, userContext supertokens.UserContext) error {
-		if input.PasswordReset != nil {
+		if input.PasswordReset != nil || input.EmailChange != nil || input.EmailChangeNotification != nil || input.PasswordChanged != nil {
 			content, err := (*serviceImpl.GetContent)(input, userContext)
 			if err != nil {
 				return err
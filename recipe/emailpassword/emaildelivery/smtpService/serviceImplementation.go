@@ -28,16 +28,23 @@ func MakeServiceImplementation(settings emaildelivery.SMTPSettings) emaildeliver
 		return emaildelivery.SendSMTPEmail(settings, input)
 	}
 
-	getContent := func(input emaildelivery.EmailType, userContext supertokens.UserContext) (emaildelivery.EmailContent, error) {
-		if input.PasswordReset != nil {
-			return getPasswordResetEmailContent(*input.PasswordReset)
-		} else {
-			return emaildelivery.EmailContent{}, errors.New("should never come here")
-		}
-	}
+	getContent := GetDefaultContent
 
 	return emaildelivery.SMTPInterface{
 		SendRawEmail: &sendRawEmail,
 		GetContent:   &getContent,
 	}
 }
+
+// GetDefaultContent returns the built-in content of the emails sent by this recipe. It is
+// also used by the services that send emails using an HTTP API.
+func GetDefaultContent(input emaildelivery.EmailType, userContext supertokens.UserContext) (emaildelivery.EmailContent, error) {
+	if input.PasswordReset != nil {
+		return getPasswordResetEmailContent(*input.PasswordReset)
+	} else if input.EmailChange != nil || input.EmailChangeNotification != nil || input.PasswordChanged != nil {
+		// rendered using the built-in templates of the emaildelivery ingredient
+		return emaildelivery.GetContentFromTemplates(emaildelivery.TemplateConfig{}, input, userContext)
+	} else {
+		return emaildelivery.EmailContent{}, errors.New("should never come here")
+	}
+}
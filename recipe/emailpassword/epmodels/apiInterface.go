@@ -19,6 +19,8 @@ import (
 	"net/http"
 
 	"github.com/supertokens/supertokens-golang/ingredients/emaildelivery"
+	"github.com/supertokens/supertokens-golang/ingredients/emailpolicy"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/passwordpolicy"
 	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
 	"github.com/supertokens/supertokens-golang/supertokens"
 )
@@ -40,6 +42,12 @@ type APIInterface struct {
 	PasswordResetPOST              *func(formFields []TypeFormField, token string, tenantId string, options APIOptions, userContext supertokens.UserContext) (ResetPasswordPOSTResponse, error)
 	SignInPOST                     *func(formFields []TypeFormField, tenantId string, options APIOptions, userContext supertokens.UserContext) (SignInPOSTResponse, error)
 	SignUpPOST                     *func(formFields []TypeFormField, tenantId string, options APIOptions, userContext supertokens.UserContext) (SignUpPOSTResponse, error)
+	PasswordPolicyGET              *func(tenantId string, options APIOptions, userContext supertokens.UserContext) (PasswordPolicyGETResponse, error)
+	EmailChangePOST                *func(newEmail string, sessionContainer sessmodels.SessionContainer, tenantId string, options APIOptions, userContext supertokens.UserContext) (EmailChangePOSTResponse, error)
+	EmailChangeConfirmPOST         *func(token string, sessionContainer sessmodels.SessionContainer, tenantId string, options APIOptions, userContext supertokens.UserContext) (EmailChangeConfirmPOSTResponse, error)
+	PasswordChangePOST             *func(formFields []TypeFormField, sessionContainer sessmodels.SessionContainer, tenantId string, options APIOptions, userContext supertokens.UserContext) (PasswordChangePOSTResponse, error)
+	AccountDeletionPOST            *func(sessionContainer sessmodels.SessionContainer, tenantId string, options APIOptions, userContext supertokens.UserContext) (AccountDeletionPOSTResponse, error)
+	AccountDeletionCancelPOST      *func(formFields []TypeFormField, tenantId string, options APIOptions, userContext supertokens.UserContext) (AccountDeletionCancelPOSTResponse, error)
 }
 
 type ResetPasswordPOSTResponse struct {
@@ -47,6 +55,7 @@ type ResetPasswordPOSTResponse struct {
 		UserId *string
 	}
 	ResetPasswordInvalidTokenError *struct{}
+	PasswordPolicyViolatedError    *PasswordPolicyViolatedError
 	GeneralError                   *supertokens.GeneralErrorResponse
 }
 
@@ -56,7 +65,14 @@ type SignUpPOSTResponse struct {
 		Session sessmodels.SessionContainer
 	}
 	EmailAlreadyExistsError *struct{}
-	GeneralError            *supertokens.GeneralErrorResponse
+	// EmailNotAllowedError is returned if the email is rejected by the EmailPolicy
+	EmailNotAllowedError *struct {
+		Reason emailpolicy.Reason
+	}
+	// InviteRequiredError is returned if the tenant is invite only and there is no valid invite
+	// for the email in the request
+	InviteRequiredError *struct{}
+	GeneralError        *supertokens.GeneralErrorResponse
 }
 
 type SignInPOSTResponse struct {
@@ -65,7 +81,17 @@ type SignInPOSTResponse struct {
 		Session sessmodels.SessionContainer
 	}
 	WrongCredentialsError *struct{}
-	GeneralError          *supertokens.GeneralErrorResponse
+	AccountLockedError    *struct {
+		// LockedUntil is the time (in milliseconds) until which the account is locked
+		LockedUntil int64
+	}
+	// AccountDeletionScheduledError is returned during the grace period of a scheduled deletion,
+	// unless AccountDeletion.CancelOnSignIn is set
+	AccountDeletionScheduledError *struct {
+		// DeleteAt is the time (in milliseconds) after which the user is deleted
+		DeleteAt int64
+	}
+	GeneralError *supertokens.GeneralErrorResponse
 }
 
 type EmailExistsGETResponse struct {
@@ -77,3 +103,68 @@ type GeneratePasswordResetTokenPOSTResponse struct {
 	OK           *struct{}
 	GeneralError *supertokens.GeneralErrorResponse
 }
+
+type PasswordPolicyGETResponse struct {
+	OK *struct {
+		Policy passwordpolicy.NormalisedPolicy
+	}
+	GeneralError *supertokens.GeneralErrorResponse
+}
+
+type EmailChangePOSTResponse struct {
+	OK                      *struct{}
+	EmailAlreadyExistsError *struct{}
+	// EmailNotAllowedError is returned if the new email is rejected by the EmailPolicy
+	EmailNotAllowedError *struct {
+		Reason emailpolicy.Reason
+	}
+	GeneralError *supertokens.GeneralErrorResponse
+}
+
+type EmailChangeConfirmPOSTResponse struct {
+	OK *struct {
+		User User
+	}
+	EmailChangeInvalidTokenError *struct{}
+	EmailAlreadyExistsError      *struct{}
+	GeneralError                 *supertokens.GeneralErrorResponse
+}
+
+type PasswordChangePOSTResponse struct {
+	OK *struct {
+		// Session is the new session of the user if the other sessions were revoked, else the
+		// session of the request
+		Session sessmodels.SessionContainer
+	}
+	// WrongCredentialsError is returned if the current password is wrong
+	WrongCredentialsError *struct{}
+	// AccountLockedError is returned if the AccountLockout is enabled and there were too many
+	// failed attempts to sign in or to change the password
+	AccountLockedError *struct {
+		// LockedUntil is the time (in milliseconds) until which the account is locked
+		LockedUntil int64
+	}
+	PasswordPolicyViolatedError *PasswordPolicyViolatedError
+	GeneralError                *supertokens.GeneralErrorResponse
+}
+
+type AccountDeletionPOSTResponse struct {
+	OK *struct {
+		// DeleteAt is the time (in milliseconds) after which the user is deleted
+		DeleteAt int64
+	}
+	GeneralError *supertokens.GeneralErrorResponse
+}
+
+type AccountDeletionCancelPOSTResponse struct {
+	OK *struct {
+		User    User
+		Session sessmodels.SessionContainer
+	}
+	WrongCredentialsError *struct{}
+	AccountLockedError    *struct {
+		// LockedUntil is the time (in milliseconds) until which the account is locked
+		LockedUntil int64
+	}
+	GeneralError *supertokens.GeneralErrorResponse
+}
@@ -17,6 +17,15 @@ package epmodels
 
 import (
 	"github.com/supertokens/supertokens-golang/ingredients/emaildelivery"
+	"github.com/supertokens/supertokens-golang/ingredients/emailpolicy"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/accountdeletion"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/accountlockout"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/breachedpassword"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/emailchange"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/passwordhistory"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/passwordmigration"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/passwordpolicy"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/resettoken"
 )
 
 type TypeNormalisedInput struct {
@@ -25,6 +34,26 @@ type TypeNormalisedInput struct {
 	ResetPasswordUsingTokenFeature TypeNormalisedInputResetPasswordUsingTokenFeature
 	Override                       OverrideStruct
 	GetEmailDeliveryConfig         func(recipeImpl RecipeInterface) emaildelivery.TypeInputWithService
+	// PasswordPolicy is nil if no password policy is configured
+	PasswordPolicy *passwordpolicy.TypeNormalisedInput
+	// BreachedPasswordCheck is nil if breached passwords are not checked
+	BreachedPasswordCheck *breachedpassword.TypeNormalisedInput
+	// PasswordHistory is nil if the password history is not kept
+	PasswordHistory *passwordhistory.TypeNormalisedInput
+	// AccountLockout is nil if accounts are not locked after failed sign ins
+	AccountLockout *accountlockout.TypeNormalisedInput
+	// EmailChangeFeature is nil if the email change APIs are disabled
+	EmailChangeFeature *TypeNormalisedInputEmailChangeFeature
+	// PasswordChangeFeature is nil if the password change API is disabled
+	PasswordChangeFeature *TypeNormalisedInputPasswordChangeFeature
+	// AccountDeletion is nil if the account deletion APIs are disabled
+	AccountDeletion *accountdeletion.TypeNormalisedInput
+	// PasswordMigration is nil if passwords are only verified by the core
+	PasswordMigration *passwordmigration.TypeNormalisedInput
+	// EmailPolicy is nil if emails are used as given and all emails can sign up
+	EmailPolicy *emailpolicy.TypeNormalisedInput
+	// ResetPasswordTokens is only used if PasswordPolicy or PasswordHistory is set
+	ResetPasswordTokens resettoken.TypeNormalisedInput
 }
 
 type OverrideStruct struct {
@@ -61,6 +90,32 @@ type TypeNormalisedInputResetPasswordUsingTokenFeature struct {
 	FormFieldsForPasswordResetForm []NormalisedFormField
 }
 
+type TypeInputEmailChangeFeature struct {
+	// NotifyPreviousEmail sends an email to the previous email of the user once the email is changed
+	NotifyPreviousEmail bool
+	// PendingChanges configures how long the email change links are valid and where the changes
+	// are kept until they are confirmed
+	PendingChanges *emailchange.TypeInput
+}
+
+type TypeNormalisedInputEmailChangeFeature struct {
+	NotifyPreviousEmail bool
+	PendingChanges      emailchange.TypeNormalisedInput
+}
+
+type TypeInputPasswordChangeFeature struct {
+	// RevokeOtherSessions revokes all the sessions of the user once the password is changed.
+	// A new session is created for the request that changed the password.
+	RevokeOtherSessions bool
+}
+
+type TypeNormalisedInputPasswordChangeFeature struct {
+	// FormFields are oldPassword and newPassword. The new password is validated by
+	// UpdateEmailOrPassword, so only a value is required here.
+	FormFields          []NormalisedFormField
+	RevokeOtherSessions bool
+}
+
 type User struct {
 	ID         string   `json:"id"`
 	Email      string   `json:"email"`
@@ -72,6 +127,47 @@ type TypeInput struct {
 	SignUpFeature *TypeInputSignUp
 	Override      *OverrideStruct
 	EmailDelivery *emaildelivery.TypeInput
+	// PasswordPolicy replaces the default password validator (if no Validate function is set
+	// for the password form field). It is applied when signing up, resetting the password,
+	// in UpdateEmailOrPassword and when the password is changed from the dashboard.
+	PasswordPolicy *passwordpolicy.TypeInput
+	// BreachedPasswordCheck rejects passwords that appear in known data breaches. It is applied
+	// in the same places as PasswordPolicy, after the password policy is satisfied.
+	BreachedPasswordCheck *breachedpassword.TypeInput
+	// PasswordHistory keeps hashes of the previous passwords of users to reject their reuse in
+	// ResetPasswordUsingToken and UpdateEmailOrPassword, and the time at which the password was
+	// last changed for epclaims.PasswordExpiredClaim.
+	PasswordHistory *passwordhistory.TypeInput
+	// AccountLockout locks accounts (an email in a tenant) after repeated failed sign ins.
+	// Accounts are unlocked after the lock duration, a password reset or UnlockAccount.
+	AccountLockout *accountlockout.TypeInput
+	// EmailChangeFeature enables the APIs to change the email of a signed in user. The email is
+	// only changed once the user opens the link sent to the new email, so an email delivery
+	// service that can send emails of type EmailChange must be set.
+	EmailChangeFeature *TypeInputEmailChangeFeature
+	// PasswordChangeFeature enables the API to change the password of a signed in user using
+	// their current password. An email of type PasswordChanged is sent once the password is
+	// changed, so an email delivery service that can send it should be set.
+	PasswordChangeFeature *TypeInputPasswordChangeFeature
+	// AccountDeletion enables the APIs for users to delete their account. The user is deleted
+	// after a grace period, during which their sessions are revoked and signing in is blocked.
+	// The deletion can be cancelled by signing in using the cancel API, or with the sign in API
+	// if CancelOnSignIn is set.
+	AccountDeletion *accountdeletion.TypeInput
+	// PasswordMigration lets users imported from a legacy system sign in with their old
+	// password. If the core rejects a password, it is verified against the legacy hash imported
+	// with ImportLegacyPasswordHash, and then stored by the core so that the legacy hash is no
+	// longer needed. Users that do not exist in the core yet are created on their first sign in.
+	PasswordMigration *passwordmigration.TypeInput
+	// EmailPolicy normalises the emails before they are stored or looked up, and restricts the
+	// emails that can sign up
+	EmailPolicy *emailpolicy.TypeInput
+	// ResetPasswordTokens configures where the users of the password reset tokens are kept, so
+	// that the new password can be checked against the password policy and history before the
+	// core uses the token. If a token is not found (for example, because it was created by
+	// another instance of the backend while using the default in-memory store), the checks that
+	// need the user are skipped.
+	ResetPasswordTokens *resettoken.TypeInput
 }
 
 type TypeFormField struct {
@@ -15,7 +15,10 @@
 
 package epmodels
 
-import "github.com/supertokens/supertokens-golang/supertokens"
+import (
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/passwordpolicy"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
 
 type RecipeInterface struct {
 	SignUp                   *func(email string, password string, tenantId string, userContext supertokens.UserContext) (SignUpResponse, error)
@@ -25,6 +28,11 @@ type RecipeInterface struct {
 	CreateResetPasswordToken *func(userID string, tenantId string, userContext supertokens.UserContext) (CreateResetPasswordTokenResponse, error)
 	ResetPasswordUsingToken  *func(token string, newPassword string, tenantId string, userContext supertokens.UserContext) (ResetPasswordUsingTokenResponse, error)
 	UpdateEmailOrPassword    *func(userId string, email *string, password *string, applyPasswordPolicy *bool, tenantIdForPasswordPolicy string, userContext supertokens.UserContext) (UpdateEmailOrPasswordResponse, error)
+	UnlockAccount            *func(email string, tenantId string, userContext supertokens.UserContext) error
+	CreateEmailChangeToken   *func(userId string, newEmail string, tenantId string, userContext supertokens.UserContext) (CreateEmailChangeTokenResponse, error)
+	ConsumeEmailChangeToken  *func(token string, tenantId string, userContext supertokens.UserContext) (ConsumeEmailChangeTokenResponse, error)
+	ScheduleAccountDeletion  *func(userId string, tenantId string, userContext supertokens.UserContext) (ScheduleAccountDeletionResponse, error)
+	CancelAccountDeletion    *func(userId string, userContext supertokens.UserContext) error
 }
 
 type SignUpResponse struct {
@@ -53,6 +61,10 @@ type ResetPasswordUsingTokenResponse struct {
 		UserId *string
 	}
 	ResetPasswordInvalidTokenError *struct{}
+	// PasswordPolicyViolatedError is returned if the new password was used before or does not
+	// satisfy the password policy for the email of the user. The token is not used in that case,
+	// so the user can try again with another password.
+	PasswordPolicyViolatedError *PasswordPolicyViolatedError
 }
 
 type UpdateEmailOrPasswordResponse struct {
@@ -62,6 +74,34 @@ type UpdateEmailOrPasswordResponse struct {
 	PasswordPolicyViolatedError *PasswordPolicyViolatedError
 }
 
+type CreateEmailChangeTokenResponse struct {
+	OK *struct {
+		Token string
+	}
+	UnknownUserIdError *struct{}
+}
+
+type ConsumeEmailChangeTokenResponse struct {
+	OK *struct {
+		User          User
+		PreviousEmail string
+	}
+	InvalidTokenError *struct{}
+	// EmailAlreadyExistsError is returned if another user signed up with the new email after the
+	// token was created. The token is consumed in that case.
+	EmailAlreadyExistsError *struct{}
+}
+
+type ScheduleAccountDeletionResponse struct {
+	OK *struct {
+		// DeleteAt is the time (in milliseconds) after which the user is deleted
+		DeleteAt int64
+	}
+	UnknownUserIdError *struct{}
+}
+
 type PasswordPolicyViolatedError struct {
 	FailureReason string
+	// Violations is only set if a password policy is configured
+	Violations []passwordpolicy.Violation
 }
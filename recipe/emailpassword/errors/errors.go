@@ -15,6 +15,8 @@
 
 package errors
 
+import "github.com/supertokens/supertokens-golang/recipe/emailpassword/passwordpolicy"
+
 type FieldError struct {
 	Msg     string
 	Payload []ErrorPayload
@@ -23,6 +25,8 @@ type FieldError struct {
 type ErrorPayload struct {
 	ID       string `json:"id"`
 	ErrorMsg string `json:"error"`
+	// Violations is set for the password field if it does not satisfy the password policy
+	Violations []passwordpolicy.Violation `json:"violations,omitempty"`
 }
 
 func (err FieldError) Error() string {
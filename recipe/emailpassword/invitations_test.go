@@ -0,0 +1,178 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package emailpassword
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/ingredients/deliverycapture"
+	"github.com/supertokens/supertokens-golang/ingredients/emaildelivery"
+	"github.com/supertokens/supertokens-golang/recipe/invitations"
+	"github.com/supertokens/supertokens-golang/recipe/invitations/invitationsmodels"
+	"github.com/supertokens/supertokens-golang/recipe/session"
+	"github.com/supertokens/supertokens-golang/recipe/userroles"
+	"github.com/supertokens/supertokens-golang/supertokens"
+	"github.com/supertokens/supertokens-golang/test/unittesting"
+)
+
+func getInvitationsConfigForTest(inbox *deliverycapture.Inbox) *invitationsmodels.TypeInput {
+	return &invitationsmodels.TypeInput{
+		InviteOnlyTenantIds: []string{"public"},
+		EmailDelivery: &emaildelivery.TypeInput{
+			Service: invitations.MakeCaptureEmailService(inbox),
+		},
+	}
+}
+
+func signUpWithInviteForTest(t *testing.T, url string, email string, inviteToken string) map[string]interface{} {
+	postBody, err := json.Marshal(map[string][]map[string]string{
+		"formFields": {
+			{"id": "email", "value": email},
+			{"id": "password", "value": "validpass123"},
+		},
+	})
+	assert.NoError(t, err)
+	req, err := http.NewRequest("POST", url+"/auth/signup", bytes.NewBuffer(postBody))
+	assert.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	if inviteToken != "" {
+		req.Header.Set(invitationsmodels.InviteTokenHeaderKey, inviteToken)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	dataInBytes, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	resp.Body.Close()
+
+	var result map[string]interface{}
+	assert.NoError(t, json.Unmarshal(dataInBytes, &result))
+	return result
+}
+
+func TestSignUpInInviteOnlyTenantRequiresAnInvite(t *testing.T) {
+	resetAll()
+	defer resetAll()
+	inbox := deliverycapture.MakeInbox(0)
+	err := supertokens.Init(supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: "http://localhost:8080",
+		},
+		AppInfo: supertokens.AppInfo{
+			APIDomain:     "api.supertokens.io",
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{
+			Init(nil),
+			invitations.Init(getInvitationsConfigForTest(inbox)),
+		},
+	})
+	assert.NoError(t, err)
+	testServer := httptest.NewServer(supertokens.Middleware(http.NewServeMux()))
+	defer testServer.Close()
+
+	result := signUpWithInviteForTest(t, testServer.URL, "invited@example.com", "")
+	assert.Equal(t, "INVITE_REQUIRED_ERROR", result["status"])
+
+	inviteResponse, err := invitations.CreateInvite("public", "invited@example.com", nil)
+	assert.NoError(t, err)
+
+	// the invite is only valid for the invited email
+	result = signUpWithInviteForTest(t, testServer.URL, "someone@example.com", inviteResponse.OK.Token)
+	assert.Equal(t, "INVITE_REQUIRED_ERROR", result["status"])
+
+	result = signUpWithInviteForTest(t, testServer.URL, "invited@example.com", "invalid")
+	assert.Equal(t, "INVITE_REQUIRED_ERROR", result["status"])
+}
+
+func TestSignUpWithInviteAppliesTheRolesOfTheInvite(t *testing.T) {
+	BeforeEach()
+	connectionURI := unittesting.StartUpST("localhost", "8080")
+	defer AfterEach()
+
+	inbox := deliverycapture.MakeInbox(0)
+	testServer := supertokensInitForTest(t, connectionURI,
+		Init(nil),
+		session.Init(nil),
+		userroles.Init(nil),
+		invitations.Init(getInvitationsConfigForTest(inbox)),
+	)
+	defer testServer.Close()
+
+	_, err := userroles.CreateNewRoleOrAddPermissions("admin", []string{"write"})
+	assert.NoError(t, err)
+
+	inviteResponse, err := invitations.CreateInvite("public", "invited@example.com", []string{"unknown"})
+	assert.NoError(t, err)
+	assert.Equal(t, "unknown", inviteResponse.UnknownRoleError.Role)
+
+	inviteResponse, err = invitations.CreateInvite("public", "invited@example.com", []string{"admin"})
+	assert.NoError(t, err)
+	assert.NotNil(t, inviteResponse.OK)
+
+	result := signUpWithInviteForTest(t, testServer.URL, "invited@example.com", inviteResponse.OK.Token)
+	assert.Equal(t, "OK", result["status"])
+	userId := result["user"].(map[string]interface{})["id"].(string)
+
+	rolesResponse, err := userroles.GetRolesForUser("public", userId)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"admin"}, rolesResponse.OK.Roles)
+
+	// the invite can only be used once
+	invites, err := invitations.ListInvites("public")
+	assert.NoError(t, err)
+	assert.Len(t, invites, 0)
+
+	// existing users can sign in without an invite
+	signInResponse, err := SignIn("public", "invited@example.com", "validpass123")
+	assert.NoError(t, err)
+	assert.NotNil(t, signInResponse.OK)
+}
+
+func TestInviteCanBeUsedAgainWhenTheSignUpFails(t *testing.T) {
+	BeforeEach()
+	connectionURI := unittesting.StartUpST("localhost", "8080")
+	defer AfterEach()
+
+	inbox := deliverycapture.MakeInbox(0)
+	testServer := supertokensInitForTest(t, connectionURI,
+		Init(nil),
+		session.Init(nil),
+		invitations.Init(getInvitationsConfigForTest(inbox)),
+	)
+	defer testServer.Close()
+
+	inviteResponse, err := invitations.CreateInvite("public", "invited@example.com", nil)
+	assert.NoError(t, err)
+
+	// the user was created without the sign up API, so the invite is not used
+	_, err = SignUp("public", "invited@example.com", "validpass123")
+	assert.NoError(t, err)
+	result := signUpWithInviteForTest(t, testServer.URL, "invited@example.com", inviteResponse.OK.Token)
+	assert.Equal(t, "FIELD_ERROR", result["status"])
+
+	invites, err := invitations.ListInvites("public")
+	assert.NoError(t, err)
+	assert.Len(t, invites, 1)
+}
@@ -16,10 +16,15 @@
 package emailpassword
 
 import (
+	"errors"
+
+	"github.com/supertokens/supertokens-golang/ingredients/deliverycapture"
 	"github.com/supertokens/supertokens-golang/ingredients/emaildelivery"
+	"github.com/supertokens/supertokens-golang/ingredients/emailpolicy"
 	"github.com/supertokens/supertokens-golang/recipe/emailpassword/api"
 	"github.com/supertokens/supertokens-golang/recipe/emailpassword/emaildelivery/smtpService"
 	"github.com/supertokens/supertokens-golang/recipe/emailpassword/epmodels"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/passwordmigration"
 	"github.com/supertokens/supertokens-golang/supertokens"
 )
 
@@ -108,6 +113,106 @@ func UpdateEmailOrPassword(userId string, email *string, password *string, apply
 	return (*instance.RecipeImpl.UpdateEmailOrPassword)(userId, email, password, applyPasswordPolicy, *tenantIdForPasswordPolicy, userContext[0])
 }
 
+// UnlockAccount unlocks the account with the email in the tenant if it was locked after
+// repeated failed sign ins, and resets its failed attempts
+func UnlockAccount(tenantId string, email string, userContext ...supertokens.UserContext) error {
+	instance, err := GetRecipeInstanceOrThrowError()
+	if err != nil {
+		return err
+	}
+	if len(userContext) == 0 {
+		userContext = append(userContext, &map[string]interface{}{})
+	}
+	return (*instance.RecipeImpl.UnlockAccount)(email, tenantId, userContext[0])
+}
+
+// CreateEmailChangeToken creates a token to change the email of the user to newEmail. The email
+// is only changed once the token is consumed using ConsumeEmailChangeToken. EmailChangeFeature
+// must be set in the config.
+func CreateEmailChangeToken(tenantId string, userID string, newEmail string, userContext ...supertokens.UserContext) (epmodels.CreateEmailChangeTokenResponse, error) {
+	instance, err := GetRecipeInstanceOrThrowError()
+	if err != nil {
+		return epmodels.CreateEmailChangeTokenResponse{}, err
+	}
+	if len(userContext) == 0 {
+		userContext = append(userContext, &map[string]interface{}{})
+	}
+	return (*instance.RecipeImpl.CreateEmailChangeToken)(userID, newEmail, tenantId, userContext[0])
+}
+
+func ConsumeEmailChangeToken(tenantId string, token string, userContext ...supertokens.UserContext) (epmodels.ConsumeEmailChangeTokenResponse, error) {
+	instance, err := GetRecipeInstanceOrThrowError()
+	if err != nil {
+		return epmodels.ConsumeEmailChangeTokenResponse{}, err
+	}
+	if len(userContext) == 0 {
+		userContext = append(userContext, &map[string]interface{}{})
+	}
+	return (*instance.RecipeImpl.ConsumeEmailChangeToken)(token, tenantId, userContext[0])
+}
+
+// ScheduleAccountDeletion schedules the deletion of the user after the configured grace period
+// and revokes all their sessions
+func ScheduleAccountDeletion(tenantId string, userID string, userContext ...supertokens.UserContext) (epmodels.ScheduleAccountDeletionResponse, error) {
+	instance, err := GetRecipeInstanceOrThrowError()
+	if err != nil {
+		return epmodels.ScheduleAccountDeletionResponse{}, err
+	}
+	if len(userContext) == 0 {
+		userContext = append(userContext, &map[string]interface{}{})
+	}
+	return (*instance.RecipeImpl.ScheduleAccountDeletion)(userID, tenantId, userContext[0])
+}
+
+func CancelAccountDeletion(userID string, userContext ...supertokens.UserContext) error {
+	instance, err := GetRecipeInstanceOrThrowError()
+	if err != nil {
+		return err
+	}
+	if len(userContext) == 0 {
+		userContext = append(userContext, &map[string]interface{}{})
+	}
+	return (*instance.RecipeImpl.CancelAccountDeletion)(userID, userContext[0])
+}
+
+// ImportLegacyPasswordHash saves the password hash of a user from a legacy system, so that the
+// user can sign in with their old password. The user can be created beforehand (for example,
+// with supertokens.BulkImportUsers and a random password) or is created on the first sign in.
+func ImportLegacyPasswordHash(tenantId string, email string, algorithm string, hash string, userContext ...supertokens.UserContext) error {
+	instance, err := GetRecipeInstanceOrThrowError()
+	if err != nil {
+		return err
+	}
+	if instance.Config.PasswordMigration == nil {
+		return errors.New("password migration is not enabled. Please set PasswordMigration in the emailpassword config")
+	}
+	if len(userContext) == 0 {
+		userContext = append(userContext, &map[string]interface{}{})
+	}
+	return passwordmigration.ImportLegacyHash(*instance.Config.PasswordMigration, passwordmigration.LegacyPasswordHash{
+		TenantId:  tenantId,
+		Email:     emailpolicy.Normalise(instance.Config.EmailPolicy, email, tenantId),
+		Algorithm: algorithm,
+		Hash:      hash,
+	}, userContext[0])
+}
+
+// GetPasswordMigrationProgress returns the number of legacy hashes left per algorithm and the
+// number of users whose password was migrated
+func GetPasswordMigrationProgress(userContext ...supertokens.UserContext) (passwordmigration.Progress, error) {
+	instance, err := GetRecipeInstanceOrThrowError()
+	if err != nil {
+		return passwordmigration.Progress{}, err
+	}
+	if instance.Config.PasswordMigration == nil {
+		return passwordmigration.Progress{}, errors.New("password migration is not enabled. Please set PasswordMigration in the emailpassword config")
+	}
+	if len(userContext) == 0 {
+		userContext = append(userContext, &map[string]interface{}{})
+	}
+	return passwordmigration.GetProgress(*instance.Config.PasswordMigration, userContext[0])
+}
+
 func SendEmail(input emaildelivery.EmailType, userContext ...supertokens.UserContext) error {
 	instance, err := GetRecipeInstanceOrThrowError()
 	if err != nil {
@@ -202,3 +307,25 @@ func SendResetPasswordEmail(tenantId string, userID string, userContext ...super
 func MakeSMTPService(config emaildelivery.SMTPServiceConfig) *emaildelivery.EmailDeliveryInterface {
 	return smtpService.MakeSMTPService(config)
 }
+
+func MakeSESService(config emaildelivery.SESServiceConfig) (*emaildelivery.EmailDeliveryInterface, error) {
+	return emaildelivery.MakeSESService(config, smtpService.GetDefaultContent)
+}
+
+func MakeSendGridService(config emaildelivery.SendGridServiceConfig) (*emaildelivery.EmailDeliveryInterface, error) {
+	return emaildelivery.MakeSendGridService(config, smtpService.GetDefaultContent)
+}
+
+func MakePostmarkService(config emaildelivery.PostmarkServiceConfig) (*emaildelivery.EmailDeliveryInterface, error) {
+	return emaildelivery.MakePostmarkService(config, smtpService.GetDefaultContent)
+}
+
+func MakeMailgunService(config emaildelivery.MailgunServiceConfig) (*emaildelivery.EmailDeliveryInterface, error) {
+	return emaildelivery.MakeMailgunService(config, smtpService.GetDefaultContent)
+}
+
+// MakeCaptureEmailService returns a service that stores the emails in inbox instead of sending
+// them. It should only be used in development and tests.
+func MakeCaptureEmailService(inbox *deliverycapture.Inbox) *emaildelivery.EmailDeliveryInterface {
+	return emaildelivery.MakeCaptureService(inbox, smtpService.GetDefaultContent)
+}
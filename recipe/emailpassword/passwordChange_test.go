@@ -0,0 +1,145 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package emailpassword
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/ingredients/deliverycapture"
+	"github.com/supertokens/supertokens-golang/ingredients/emaildelivery"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/accountlockout"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/epmodels"
+	"github.com/supertokens/supertokens-golang/recipe/session"
+	"github.com/supertokens/supertokens-golang/test/unittesting"
+)
+
+func getPasswordChangeBody(oldPassword string, newPassword string) map[string]interface{} {
+	return map[string]interface{}{
+		"formFields": []map[string]interface{}{
+			{"id": "oldPassword", "value": oldPassword},
+			{"id": "newPassword", "value": newPassword},
+		},
+	}
+}
+
+func TestPasswordChangeAPIIsDisabledByDefault(t *testing.T) {
+	resetAll()
+	defer resetAll()
+	testServer := initWithoutCoreForTest(t, nil)
+	defer testServer.Close()
+
+	result, err := postRequestWithSessionForTest(testServer.URL, "/auth/user/password/change", getPasswordChangeBody("validpass123", "newvalidpass123"), "", "")
+	assert.NoError(t, err)
+	assert.Equal(t, 404, result["statusCode"])
+}
+
+func TestPasswordChange(t *testing.T) {
+	BeforeEach()
+	connectionURI := unittesting.StartUpST("localhost", "8080")
+	defer AfterEach()
+
+	inbox := deliverycapture.MakeInbox(10)
+	testServer := supertokensInitForTest(t, connectionURI,
+		Init(&epmodels.TypeInput{
+			EmailDelivery: &emaildelivery.TypeInput{
+				Service: MakeCaptureEmailService(inbox),
+			},
+			PasswordChangeFeature: &epmodels.TypeInputPasswordChangeFeature{
+				RevokeOtherSessions: true,
+			},
+		}),
+		session.Init(nil),
+	)
+	defer testServer.Close()
+
+	resp, err := unittesting.SignupRequest("test@example.com", "validpass123", testServer.URL)
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	cookies := unittesting.ExtractInfoFromResponse(resp)
+
+	resp, err = unittesting.SignInRequest("test@example.com", "validpass123", testServer.URL)
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	result, err := postRequestWithSessionForTest(testServer.URL, "/auth/user/password/change", getPasswordChangeBody("validpass123", "newvalidpass123"), "", "")
+	assert.NoError(t, err)
+	assert.Equal(t, 401, result["statusCode"])
+
+	result, err = postRequestWithSessionForTest(testServer.URL, "/auth/user/password/change", getPasswordChangeBody("wrongpass123", "newvalidpass123"), cookies["sAccessToken"], cookies["antiCsrf"])
+	assert.NoError(t, err)
+	assert.Equal(t, "WRONG_CREDENTIALS_ERROR", result["status"])
+
+	result, err = postRequestWithSessionForTest(testServer.URL, "/auth/user/password/change", getPasswordChangeBody("validpass123", "short"), cookies["sAccessToken"], cookies["antiCsrf"])
+	assert.NoError(t, err)
+	assert.Equal(t, "FIELD_ERROR", result["status"])
+	formFields := result["formFields"].([]interface{})
+	assert.Equal(t, "newPassword", formFields[0].(map[string]interface{})["id"])
+
+	result, err = postRequestWithSessionForTest(testServer.URL, "/auth/user/password/change", getPasswordChangeBody("validpass123", "newvalidpass123"), cookies["sAccessToken"], cookies["antiCsrf"])
+	assert.NoError(t, err)
+	assert.Equal(t, "OK", result["status"])
+
+	signInResponse, err := SignIn("public", "test@example.com", "newvalidpass123")
+	assert.NoError(t, err)
+	assert.NotNil(t, signInResponse.OK)
+
+	// only the session created for the password change request is left
+	sessionHandles, err := session.GetAllSessionHandlesForUser(signInResponse.OK.User.ID, nil)
+	assert.NoError(t, err)
+	assert.Len(t, sessionHandles, 1)
+
+	message := inbox.GetLatestMessage("test@example.com")
+	assert.NotNil(t, message)
+	assert.Equal(t, "Your password has been changed", message.Subject)
+}
+
+func TestPasswordChangeIsLimitedByTheAccountLockout(t *testing.T) {
+	BeforeEach()
+	connectionURI := unittesting.StartUpST("localhost", "8080")
+	defer AfterEach()
+
+	maxFailedAttempts := 2
+	testServer := supertokensInitForTest(t, connectionURI,
+		Init(&epmodels.TypeInput{
+			PasswordChangeFeature: &epmodels.TypeInputPasswordChangeFeature{},
+			AccountLockout: &accountlockout.TypeInput{
+				Default: accountlockout.Policy{MaxFailedAttempts: &maxFailedAttempts},
+			},
+		}),
+		session.Init(nil),
+	)
+	defer testServer.Close()
+
+	resp, err := unittesting.SignupRequest("test@example.com", "validpass123", testServer.URL)
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	cookies := unittesting.ExtractInfoFromResponse(resp)
+
+	result, err := postRequestWithSessionForTest(testServer.URL, "/auth/user/password/change", getPasswordChangeBody("wrongpass123", "newvalidpass123"), cookies["sAccessToken"], cookies["antiCsrf"])
+	assert.NoError(t, err)
+	assert.Equal(t, "WRONG_CREDENTIALS_ERROR", result["status"])
+
+	result, err = postRequestWithSessionForTest(testServer.URL, "/auth/user/password/change", getPasswordChangeBody("wrongpass123", "newvalidpass123"), cookies["sAccessToken"], cookies["antiCsrf"])
+	assert.NoError(t, err)
+	assert.Equal(t, "ACCOUNT_LOCKED_ERROR", result["status"])
+
+	// the correct password is rejected as well while the account is locked
+	result, err = postRequestWithSessionForTest(testServer.URL, "/auth/user/password/change", getPasswordChangeBody("validpass123", "newvalidpass123"), cookies["sAccessToken"], cookies["antiCsrf"])
+	assert.NoError(t, err)
+	assert.Equal(t, "ACCOUNT_LOCKED_ERROR", result["status"])
+}
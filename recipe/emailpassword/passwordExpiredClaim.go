@@ -0,0 +1,64 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package emailpassword
+
+import (
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/epclaims"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/passwordhistory"
+	"github.com/supertokens/supertokens-golang/recipe/session/claims"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func NewPasswordExpiredClaim() (*claims.TypeSessionClaim, claims.BooleanClaimValidators) {
+	fetchValue := func(userId string, tenantId string, userContext supertokens.UserContext) (interface{}, error) {
+		instance, err := GetRecipeInstanceOrThrowError()
+		if err != nil {
+			return nil, err
+		}
+		config := instance.Config.PasswordHistory
+		if config == nil || config.MaxAge == 0 {
+			return false, nil
+		}
+
+		history, err := config.Store.GetHistory(userId, userContext)
+		if err != nil {
+			return nil, err
+		}
+		if history != nil && history.LastChangedAt != 0 {
+			return passwordhistory.IsExpired(*config, history.LastChangedAt), nil
+		}
+
+		// Users who signed up before the history was enabled have no history, so the
+		// time they signed up at is used instead
+		user, err := (*instance.RecipeImpl.GetUserByID)(userId, userContext)
+		if err != nil {
+			return nil, err
+		}
+		if user == nil {
+			// This is not an emailpassword user, so there is no password to expire
+			return false, nil
+		}
+		return passwordhistory.IsExpired(*config, int64(user.TimeJoined)), nil
+	}
+
+	return claims.BooleanClaim("st-pwd-exp", fetchValue, nil)
+}
+
+func init() {
+	// this function is called automatically when the package is imported
+	epclaims.PasswordExpiredClaim, epclaims.PasswordExpiredClaimValidators = NewPasswordExpiredClaim()
+}
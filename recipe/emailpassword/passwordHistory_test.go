@@ -0,0 +1,137 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package emailpassword
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/epclaims"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/epmodels"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/passwordhistory"
+	"github.com/supertokens/supertokens-golang/recipe/session"
+	"github.com/supertokens/supertokens-golang/supertokens"
+	"github.com/supertokens/supertokens-golang/test/unittesting"
+)
+
+func initPasswordHistoryForTest(t *testing.T, config passwordhistory.TypeInput) {
+	// The functions tested here do not call the core, so it does not need to be running
+	err := supertokens.Init(supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: "http://localhost:8080",
+		},
+		AppInfo: supertokens.AppInfo{
+			APIDomain:     "api.supertokens.io",
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{
+			Init(&epmodels.TypeInput{
+				PasswordHistory: &config,
+			}),
+			session.Init(nil),
+		},
+	})
+	assert.NoError(t, err)
+}
+
+func TestPasswordExpiredClaim(t *testing.T) {
+	resetAll()
+	defer resetAll()
+
+	store := passwordhistory.MakeInMemoryStore()
+	maxAge := 90 * 24 * time.Hour
+	initPasswordHistoryForTest(t, passwordhistory.TypeInput{
+		MaxAge:        &maxAge,
+		EnforceExpiry: true,
+		Store:         &store,
+	})
+	userContext := &map[string]interface{}{}
+
+	sessionRecipe, err := session.GetRecipeInstanceOrThrowError()
+	assert.NoError(t, err)
+	assert.Contains(t, sessionRecipe.GetClaimsAddedByOtherRecipes(), epclaims.PasswordExpiredClaim)
+
+	now := time.Now().UnixNano() / 1000000
+	assert.NoError(t, store.SetHistory("recentUser", passwordhistory.History{LastChangedAt: now - 24*60*60*1000}, userContext))
+	assert.NoError(t, store.SetHistory("oldUser", passwordhistory.History{LastChangedAt: now - 100*24*60*60*1000}, userContext))
+
+	value, err := epclaims.PasswordExpiredClaim.FetchValue("recentUser", "public", userContext)
+	assert.NoError(t, err)
+	assert.Equal(t, false, value)
+
+	value, err = epclaims.PasswordExpiredClaim.FetchValue("oldUser", "public", userContext)
+	assert.NoError(t, err)
+	assert.Equal(t, true, value)
+}
+
+func TestPasswordExpiredClaimIsNotAddedWithoutMaxAge(t *testing.T) {
+	resetAll()
+	defer resetAll()
+
+	store := passwordhistory.MakeInMemoryStore()
+	initPasswordHistoryForTest(t, passwordhistory.TypeInput{
+		RememberCount: 5,
+		Store:         &store,
+	})
+
+	sessionRecipe, err := session.GetRecipeInstanceOrThrowError()
+	assert.NoError(t, err)
+	assert.NotContains(t, sessionRecipe.GetClaimsAddedByOtherRecipes(), epclaims.PasswordExpiredClaim)
+
+	value, err := epclaims.PasswordExpiredClaim.FetchValue("userId", "public", &map[string]interface{}{})
+	assert.NoError(t, err)
+	assert.Equal(t, false, value)
+}
+
+func TestPasswordResetWithAReusedPasswordKeepsTheToken(t *testing.T) {
+	BeforeEach()
+	connectionURI := unittesting.StartUpST("localhost", "8080")
+	defer AfterEach()
+
+	store := passwordhistory.MakeInMemoryStore()
+	testServer := supertokensInitForTest(t, connectionURI,
+		Init(&epmodels.TypeInput{
+			PasswordHistory: &passwordhistory.TypeInput{
+				RememberCount: 5,
+				Store:         &store,
+			},
+		}),
+		session.Init(nil),
+	)
+	defer testServer.Close()
+
+	signUpResponse, err := SignUp("public", "test@example.com", "validpass123")
+	assert.NoError(t, err)
+	userId := signUpResponse.OK.User.ID
+
+	tokenResponse, err := CreateResetPasswordToken("public", userId)
+	assert.NoError(t, err)
+	resetResponse, err := ResetPasswordUsingToken("public", tokenResponse.OK.Token, "validpass123")
+	assert.NoError(t, err)
+	assert.NotNil(t, resetResponse.PasswordPolicyViolatedError)
+
+	// the token was not used, so it can be used with another password
+	resetResponse, err = ResetPasswordUsingToken("public", tokenResponse.OK.Token, "newpass123")
+	assert.NoError(t, err)
+	assert.NotNil(t, resetResponse.OK)
+
+	resetResponse, err = ResetPasswordUsingToken("public", tokenResponse.OK.Token, "otherpass123")
+	assert.NoError(t, err)
+	assert.NotNil(t, resetResponse.ResetPasswordInvalidTokenError)
+}
@@ -0,0 +1,125 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package emailpassword
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/epmodels"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/passwordmigration"
+	"github.com/supertokens/supertokens-golang/recipe/session"
+	"github.com/supertokens/supertokens-golang/test/unittesting"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestPasswordMigrationIsDisabledByDefault(t *testing.T) {
+	resetAll()
+	defer resetAll()
+	testServer := initWithoutCoreForTest(t, nil)
+	defer testServer.Close()
+
+	err := ImportLegacyPasswordHash("public", "test@example.com", passwordmigration.AlgorithmBcrypt, "hash")
+	assert.Error(t, err)
+	_, err = GetPasswordMigrationProgress()
+	assert.Error(t, err)
+}
+
+func TestSignInMigratesLegacyPassword(t *testing.T) {
+	BeforeEach()
+	connectionURI := unittesting.StartUpST("localhost", "8080")
+	defer AfterEach()
+
+	testServer := supertokensInitForTest(t, connectionURI,
+		Init(&epmodels.TypeInput{
+			PasswordMigration: &passwordmigration.TypeInput{},
+		}),
+		session.Init(nil),
+	)
+	defer testServer.Close()
+
+	legacyHash, err := bcrypt.GenerateFromPassword([]byte("legacypass123"), bcrypt.MinCost)
+	assert.NoError(t, err)
+
+	// a user that was imported with a random password
+	signUpResponse, err := SignUp("public", "imported@example.com", "randompass123")
+	assert.NoError(t, err)
+	assert.NotNil(t, signUpResponse.OK)
+	assert.NoError(t, ImportLegacyPasswordHash("public", "imported@example.com", passwordmigration.AlgorithmBcrypt, string(legacyHash)))
+	// a user that is only created on the first sign in
+	assert.NoError(t, ImportLegacyPasswordHash("public", "new@example.com", passwordmigration.AlgorithmBcrypt, string(legacyHash)))
+
+	signInResponse, err := SignIn("public", "imported@example.com", "wrongpass123")
+	assert.NoError(t, err)
+	assert.NotNil(t, signInResponse.WrongCredentialsError)
+
+	signInResponse, err = SignIn("public", "imported@example.com", "legacypass123")
+	assert.NoError(t, err)
+	assert.NotNil(t, signInResponse.OK)
+	assert.Equal(t, signUpResponse.OK.User.ID, signInResponse.OK.User.ID)
+
+	resp, err := unittesting.SignInRequest("new@example.com", "legacypass123", testServer.URL)
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	user, err := GetUserByEmail("public", "new@example.com")
+	assert.NoError(t, err)
+	assert.NotNil(t, user)
+
+	progress, err := GetPasswordMigrationProgress()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, progress.Migrated)
+	assert.Equal(t, 0, progress.Remaining[passwordmigration.AlgorithmBcrypt])
+
+	// the password is now verified by the core
+	signInResponse, err = SignIn("public", "imported@example.com", "legacypass123")
+	assert.NoError(t, err)
+	assert.NotNil(t, signInResponse.OK)
+}
+
+func TestPasswordChangeRemovesLegacyPasswordHash(t *testing.T) {
+	BeforeEach()
+	connectionURI := unittesting.StartUpST("localhost", "8080")
+	defer AfterEach()
+
+	testServer := supertokensInitForTest(t, connectionURI,
+		Init(&epmodels.TypeInput{
+			PasswordMigration: &passwordmigration.TypeInput{},
+		}),
+		session.Init(nil),
+	)
+	defer testServer.Close()
+
+	legacyHash, err := bcrypt.GenerateFromPassword([]byte("legacypass123"), bcrypt.MinCost)
+	assert.NoError(t, err)
+
+	signUpResponse, err := SignUp("public", "imported@example.com", "randompass123")
+	assert.NoError(t, err)
+	assert.NoError(t, ImportLegacyPasswordHash("public", "imported@example.com", passwordmigration.AlgorithmBcrypt, string(legacyHash)))
+
+	newPassword := "newpass123"
+	updateResponse, err := UpdateEmailOrPassword(signUpResponse.OK.User.ID, nil, &newPassword, nil, nil)
+	assert.NoError(t, err)
+	assert.NotNil(t, updateResponse.OK)
+
+	signInResponse, err := SignIn("public", "imported@example.com", "legacypass123")
+	assert.NoError(t, err)
+	assert.NotNil(t, signInResponse.WrongCredentialsError)
+
+	progress, err := GetPasswordMigrationProgress()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, progress.Migrated)
+}
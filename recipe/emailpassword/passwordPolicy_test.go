@@ -0,0 +1,196 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package emailpassword
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/epmodels"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/passwordpolicy"
+	"github.com/supertokens/supertokens-golang/recipe/session"
+	"github.com/supertokens/supertokens-golang/supertokens"
+	"github.com/supertokens/supertokens-golang/test/unittesting"
+)
+
+// The APIs tested here do not call the core, so it does not need to be running
+func initWithoutCoreForTest(t *testing.T, config *epmodels.TypeInput) *httptest.Server {
+	err := supertokens.Init(supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: "http://localhost:8080",
+		},
+		AppInfo: supertokens.AppInfo{
+			APIDomain:     "api.supertokens.io",
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{
+			Init(config),
+		},
+	})
+	assert.NoError(t, err)
+
+	mux := http.NewServeMux()
+	return httptest.NewServer(supertokens.Middleware(mux))
+}
+
+func getTestPasswordPolicy() *passwordpolicy.TypeInput {
+	trueValue := true
+	minLength := 12
+	return &passwordpolicy.TypeInput{
+		Default: passwordpolicy.Policy{
+			RequireSymbol:    &trueValue,
+			DisallowUserInfo: &trueValue,
+		},
+		TenantPolicies: map[string]passwordpolicy.Policy{
+			"strict": {MinLength: &minLength},
+		},
+	}
+}
+
+func TestPasswordPolicyReplacesDefaultPasswordValidator(t *testing.T) {
+	resetAll()
+	defer resetAll()
+	testServer := initWithoutCoreForTest(t, &epmodels.TypeInput{
+		PasswordPolicy: getTestPasswordPolicy(),
+	})
+	defer testServer.Close()
+
+	instance, err := GetRecipeInstanceOrThrowError()
+	assert.NoError(t, err)
+	for _, formField := range instance.Config.SignUpFeature.FormFields {
+		if formField.ID == "password" {
+			// The policy is applied separately, so the default validator accepts everything
+			assert.Nil(t, formField.Validate("a", "public"))
+		}
+	}
+}
+
+func TestPasswordPolicyIsAppliedOnSignUp(t *testing.T) {
+	resetAll()
+	defer resetAll()
+	testServer := initWithoutCoreForTest(t, &epmodels.TypeInput{
+		PasswordPolicy: getTestPasswordPolicy(),
+	})
+	defer testServer.Close()
+
+	formFields := map[string][]map[string]string{
+		"formFields": {
+			{"id": "email", "value": "johnny@example.com"},
+			{"id": "password", "value": "johnny123"},
+		},
+	}
+	postBody, err := json.Marshal(formFields)
+	assert.NoError(t, err)
+
+	resp, err := http.Post(testServer.URL+"/auth/signup", "application/json", bytes.NewBuffer(postBody))
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	dataInBytes, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	resp.Body.Close()
+
+	var result map[string]interface{}
+	assert.NoError(t, json.Unmarshal(dataInBytes, &result))
+	assert.Equal(t, "FIELD_ERROR", result["status"])
+	formFieldError := result["formFields"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "password", formFieldError["id"])
+	assert.Equal(t, "Password must contain at least one special character. Password must not contain your email", formFieldError["error"])
+	violations := formFieldError["violations"].([]interface{})
+	assert.Len(t, violations, 2)
+	assert.Equal(t, passwordpolicy.MissingSymbolCode, violations[0].(map[string]interface{})["code"])
+	assert.Equal(t, passwordpolicy.ContainsUserInfoCode, violations[1].(map[string]interface{})["code"])
+}
+
+func TestPasswordPolicyAPI(t *testing.T) {
+	resetAll()
+	defer resetAll()
+	testServer := initWithoutCoreForTest(t, &epmodels.TypeInput{
+		PasswordPolicy: getTestPasswordPolicy(),
+	})
+	defer testServer.Close()
+
+	resp, err := http.Get(testServer.URL + "/auth/password/policy")
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	dataInBytes, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	resp.Body.Close()
+
+	var result map[string]interface{}
+	assert.NoError(t, json.Unmarshal(dataInBytes, &result))
+	assert.Equal(t, "OK", result["status"])
+	policy := result["policy"].(map[string]interface{})
+	assert.Equal(t, float64(8), policy["minLength"])
+	assert.Equal(t, true, policy["requireSymbol"])
+
+	resp, err = http.Get(testServer.URL + "/auth/strict/password/policy")
+	assert.NoError(t, err)
+	dataInBytes, err = io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	resp.Body.Close()
+
+	assert.NoError(t, json.Unmarshal(dataInBytes, &result))
+	assert.Equal(t, float64(12), result["policy"].(map[string]interface{})["minLength"])
+}
+
+func TestPasswordPolicyAPIIsDisabledWithoutPolicy(t *testing.T) {
+	resetAll()
+	defer resetAll()
+	testServer := initWithoutCoreForTest(t, nil)
+	defer testServer.Close()
+
+	resp, err := http.Get(testServer.URL + "/auth/password/policy")
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, 404, resp.StatusCode)
+}
+
+func TestPasswordPolicyIsAppliedWithTheEmailOfTheUserOnPasswordReset(t *testing.T) {
+	BeforeEach()
+	connectionURI := unittesting.StartUpST("localhost", "8080")
+	defer AfterEach()
+
+	testServer := supertokensInitForTest(t, connectionURI,
+		Init(&epmodels.TypeInput{
+			PasswordPolicy: getTestPasswordPolicy(),
+		}),
+		session.Init(nil),
+	)
+	defer testServer.Close()
+
+	signUpResponse, err := SignUp("public", "johnny@example.com", "validpass123!")
+	assert.NoError(t, err)
+	userId := signUpResponse.OK.User.ID
+
+	tokenResponse, err := CreateResetPasswordToken("public", userId)
+	assert.NoError(t, err)
+	resetResponse, err := ResetPasswordUsingToken("public", tokenResponse.OK.Token, "johnny123!")
+	assert.NoError(t, err)
+	assert.NotNil(t, resetResponse.PasswordPolicyViolatedError)
+	assert.Len(t, resetResponse.PasswordPolicyViolatedError.Violations, 1)
+	assert.Equal(t, passwordpolicy.ContainsUserInfoCode, resetResponse.PasswordPolicyViolatedError.Violations[0].Code)
+
+	resetResponse, err = ResetPasswordUsingToken("public", tokenResponse.OK.Token, "newpass123!")
+	assert.NoError(t, err)
+	assert.NotNil(t, resetResponse.OK)
+}
@@ -0,0 +1,148 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package passwordhistory
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/passwordpolicy"
+	"github.com/supertokens/supertokens-golang/supertokens"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func NormaliseTypeInput(input TypeInput) (TypeNormalisedInput, error) {
+	if input.RememberCount < 0 {
+		return TypeNormalisedInput{}, errors.New("RememberCount must not be negative")
+	}
+	result := TypeNormalisedInput{
+		RememberCount:   input.RememberCount,
+		EnforceExpiry:   input.EnforceExpiry,
+		Store:           MakeUserMetadataStore(),
+		GetErrorMessage: input.GetErrorMessage,
+	}
+	if input.MaxAge != nil {
+		if *input.MaxAge <= 0 {
+			return TypeNormalisedInput{}, errors.New("MaxAge must be greater than 0")
+		}
+		result.MaxAge = *input.MaxAge
+	}
+	if result.EnforceExpiry && result.MaxAge == 0 {
+		return TypeNormalisedInput{}, errors.New("MaxAge must be set to enforce password expiry")
+	}
+	if input.Store != nil {
+		result.Store = *input.Store
+	}
+	return result, nil
+}
+
+// IsReused returns true if password is one of the last RememberCount passwords of the user
+func IsReused(config TypeNormalisedInput, userId string, password string, userContext supertokens.UserContext) (bool, error) {
+	if config.RememberCount == 0 {
+		return false, nil
+	}
+	history, err := config.Store.GetHistory(userId, userContext)
+	if err != nil || history == nil {
+		return false, err
+	}
+	preHashed := preHash(password)
+	for i, hash := range history.PasswordHashes {
+		if i >= config.RememberCount {
+			break
+		}
+		if bcrypt.CompareHashAndPassword([]byte(hash), preHashed) == nil {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Check returns a violation if password is one of the last RememberCount passwords of the user
+func Check(config TypeNormalisedInput, userId string, password string, tenantId string, userContext supertokens.UserContext) (*passwordpolicy.Violation, error) {
+	reused, err := IsReused(config, userId, password, userContext)
+	if err != nil || !reused {
+		return nil, err
+	}
+	message := "Password must be different from your last " + pluralisePasswords(config.RememberCount)
+	if config.GetErrorMessage != nil {
+		if customMessage := config.GetErrorMessage(ReusedCode, tenantId, userContext); customMessage != nil {
+			message = *customMessage
+		}
+	}
+	return &passwordpolicy.Violation{
+		Code:    ReusedCode,
+		Params:  map[string]interface{}{"rememberCount": config.RememberCount},
+		Message: message,
+	}, nil
+}
+
+// RecordPasswordChange adds password to the history of the user and sets the time at which
+// the password was last changed to now
+func RecordPasswordChange(config TypeNormalisedInput, userId string, password string, userContext supertokens.UserContext) error {
+	history, err := config.Store.GetHistory(userId, userContext)
+	if err != nil {
+		return err
+	}
+	if history == nil {
+		history = &History{}
+	}
+
+	hashes := []string{}
+	if config.RememberCount > 0 {
+		hash, err := bcrypt.GenerateFromPassword(preHash(password), bcrypt.DefaultCost)
+		if err != nil {
+			return err
+		}
+		hashes = append(hashes, string(hash))
+		for _, previousHash := range history.PasswordHashes {
+			if len(hashes) >= config.RememberCount {
+				break
+			}
+			hashes = append(hashes, previousHash)
+		}
+	}
+
+	return config.Store.SetHistory(userId, History{
+		PasswordHashes: hashes,
+		LastChangedAt:  time.Now().UnixNano() / 1000000,
+	}, userContext)
+}
+
+// IsExpired returns true if the password was last changed more than MaxAge ago.
+// lastChangedAt is in milliseconds.
+func IsExpired(config TypeNormalisedInput, lastChangedAt int64) bool {
+	if config.MaxAge == 0 {
+		return false
+	}
+	return time.Now().UnixNano()/1000000-lastChangedAt > config.MaxAge.Milliseconds()
+}
+
+// preHash makes passwords longer than the 72 bytes used by bcrypt count in full
+func preHash(password string) []byte {
+	sum := sha256.Sum256([]byte(password))
+	return []byte(base64.StdEncoding.EncodeToString(sum[:]))
+}
+
+func pluralisePasswords(count int) string {
+	if count == 1 {
+		return "password"
+	}
+	return strconv.Itoa(count) + " passwords"
+}
@@ -0,0 +1,146 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package passwordhistory
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func makeTestConfig(t *testing.T, rememberCount int) TypeNormalisedInput {
+	store := MakeInMemoryStore()
+	config, err := NormaliseTypeInput(TypeInput{
+		RememberCount: rememberCount,
+		Store:         &store,
+	})
+	assert.NoError(t, err)
+	return config
+}
+
+func TestReuseOfRecentPasswordsIsRejected(t *testing.T) {
+	config := makeTestConfig(t, 3)
+	userContext := &map[string]interface{}{}
+
+	for _, password := range []string{"first123", "second123", "third123", "fourth123"} {
+		assert.NoError(t, RecordPasswordChange(config, "userId", password, userContext))
+	}
+
+	history, err := config.Store.GetHistory("userId", userContext)
+	assert.NoError(t, err)
+	assert.Len(t, history.PasswordHashes, 3)
+	for _, hash := range history.PasswordHashes {
+		assert.False(t, strings.Contains(hash, "123"))
+	}
+
+	for _, password := range []string{"second123", "third123", "fourth123"} {
+		reused, err := IsReused(config, "userId", password, userContext)
+		assert.NoError(t, err)
+		assert.True(t, reused, password)
+	}
+
+	// the oldest password is no longer remembered
+	reused, err := IsReused(config, "userId", "first123", userContext)
+	assert.NoError(t, err)
+	assert.False(t, reused)
+
+	// the history is per user
+	reused, err = IsReused(config, "otherUserId", "fourth123", userContext)
+	assert.NoError(t, err)
+	assert.False(t, reused)
+}
+
+func TestLongPasswordsAreComparedInFull(t *testing.T) {
+	config := makeTestConfig(t, 1)
+	userContext := &map[string]interface{}{}
+
+	prefix := strings.Repeat("a", 80)
+	assert.NoError(t, RecordPasswordChange(config, "userId", prefix+"1", userContext))
+
+	reused, err := IsReused(config, "userId", prefix+"2", userContext)
+	assert.NoError(t, err)
+	assert.False(t, reused)
+}
+
+func TestCheckReturnsViolation(t *testing.T) {
+	config := makeTestConfig(t, 5)
+	userContext := &map[string]interface{}{}
+	assert.NoError(t, RecordPasswordChange(config, "userId", "password123", userContext))
+
+	violation, err := Check(config, "userId", "password123", "public", userContext)
+	assert.NoError(t, err)
+	assert.Equal(t, ReusedCode, violation.Code)
+	assert.Equal(t, "Password must be different from your last 5 passwords", violation.Message)
+	assert.Equal(t, 5, violation.Params["rememberCount"])
+
+	violation, err = Check(config, "userId", "different123", "public", userContext)
+	assert.NoError(t, err)
+	assert.Nil(t, violation)
+
+	config.GetErrorMessage = func(code string, tenantId string, userContext supertokens.UserContext) *string {
+		message := "Vous avez déjà utilisé ce mot de passe"
+		return &message
+	}
+	violation, err = Check(config, "userId", "password123", "public", userContext)
+	assert.NoError(t, err)
+	assert.Equal(t, "Vous avez déjà utilisé ce mot de passe", violation.Message)
+}
+
+func TestRecordPasswordChangeWithoutReuseCheck(t *testing.T) {
+	config := makeTestConfig(t, 0)
+	userContext := &map[string]interface{}{}
+
+	before := time.Now().UnixNano() / 1000000
+	assert.NoError(t, RecordPasswordChange(config, "userId", "password123", userContext))
+
+	history, err := config.Store.GetHistory("userId", userContext)
+	assert.NoError(t, err)
+	assert.Empty(t, history.PasswordHashes)
+	assert.GreaterOrEqual(t, history.LastChangedAt, before)
+
+	reused, err := IsReused(config, "userId", "password123", userContext)
+	assert.NoError(t, err)
+	assert.False(t, reused)
+}
+
+func TestIsExpired(t *testing.T) {
+	maxAge := 90 * 24 * time.Hour
+	config, err := NormaliseTypeInput(TypeInput{MaxAge: &maxAge})
+	assert.NoError(t, err)
+
+	now := time.Now().UnixNano() / 1000000
+	assert.False(t, IsExpired(config, now-89*24*60*60*1000))
+	assert.True(t, IsExpired(config, now-91*24*60*60*1000))
+
+	config.MaxAge = 0
+	assert.False(t, IsExpired(config, 0))
+}
+
+func TestInvalidConfig(t *testing.T) {
+	_, err := NormaliseTypeInput(TypeInput{RememberCount: -1})
+	assert.EqualError(t, err, "RememberCount must not be negative")
+
+	_, err = NormaliseTypeInput(TypeInput{EnforceExpiry: true})
+	assert.EqualError(t, err, "MaxAge must be set to enforce password expiry")
+
+	maxAge := time.Duration(0)
+	_, err = NormaliseTypeInput(TypeInput{MaxAge: &maxAge})
+	assert.EqualError(t, err, "MaxAge must be greater than 0")
+}
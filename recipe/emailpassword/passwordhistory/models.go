@@ -0,0 +1,69 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package passwordhistory
+
+import (
+	"time"
+
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+const (
+	ReusedCode = "PASSWORD_REUSED"
+
+	// UserMetadataKey is the key under which the default store keeps the history in the user metadata
+	UserMetadataKey = "st-password-history"
+)
+
+type History struct {
+	// PasswordHashes are bcrypt hashes of the previous passwords of the user, most recent first
+	PasswordHashes []string `json:"passwordHashes"`
+	// LastChangedAt is the time (in milliseconds) at which the password was last set
+	LastChangedAt int64 `json:"lastChangedAt"`
+}
+
+// Store keeps the password history of users
+type Store struct {
+	// GetHistory returns nil if there is no history for the user
+	GetHistory func(userId string, userContext supertokens.UserContext) (*History, error)
+	SetHistory func(userId string, history History, userContext supertokens.UserContext) error
+}
+
+type TypeInput struct {
+	// RememberCount is the number of previous passwords (including the current one) that
+	// cannot be reused. 0 disables the reuse check.
+	RememberCount int
+	// MaxAge is the time after which the password expires and PasswordExpiredClaim becomes
+	// true. Expiry is disabled if it is nil.
+	MaxAge *time.Duration
+	// EnforceExpiry adds a validator for PasswordExpiredClaim to all sessions, so that users
+	// with an expired password can only access APIs that remove the validator.
+	EnforceExpiry bool
+	// Store defaults to MakeUserMetadataStore, which requires the usermetadata recipe
+	Store *Store
+	// GetErrorMessage can be used to localise the error message. Returning nil uses the default message.
+	GetErrorMessage func(code string, tenantId string, userContext supertokens.UserContext) *string
+}
+
+type TypeNormalisedInput struct {
+	RememberCount int
+	// MaxAge is 0 if expiry is disabled
+	MaxAge          time.Duration
+	EnforceExpiry   bool
+	Store           Store
+	GetErrorMessage func(code string, tenantId string, userContext supertokens.UserContext) *string
+}
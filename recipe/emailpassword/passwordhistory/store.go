@@ -0,0 +1,85 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package passwordhistory
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/supertokens/supertokens-golang/recipe/usermetadata"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+// MakeUserMetadataStore keeps the history in the metadata of the user (under UserMetadataKey),
+// so the usermetadata recipe must be initialised
+func MakeUserMetadataStore() Store {
+	return Store{
+		GetHistory: func(userId string, userContext supertokens.UserContext) (*History, error) {
+			metadata, err := usermetadata.GetUserMetadata(userId, userContext)
+			if err != nil {
+				return nil, err
+			}
+			value, ok := metadata[UserMetadataKey]
+			if !ok || value == nil {
+				return nil, nil
+			}
+			// the metadata is returned as decoded JSON
+			valueJSON, err := json.Marshal(value)
+			if err != nil {
+				return nil, err
+			}
+			var history History
+			err = json.Unmarshal(valueJSON, &history)
+			if err != nil {
+				return nil, err
+			}
+			return &history, nil
+		},
+		SetHistory: func(userId string, history History, userContext supertokens.UserContext) error {
+			_, err := usermetadata.UpdateUserMetadata(userId, map[string]interface{}{
+				UserMetadataKey: history,
+			}, userContext)
+			return err
+		},
+	}
+}
+
+// MakeInMemoryStore keeps the history in memory. It is meant for tests and development, since
+// the history is lost when the process restarts and is not shared between instances.
+func MakeInMemoryStore() Store {
+	var mutex sync.RWMutex
+	histories := map[string]History{}
+
+	return Store{
+		GetHistory: func(userId string, userContext supertokens.UserContext) (*History, error) {
+			mutex.RLock()
+			defer mutex.RUnlock()
+			history, ok := histories[userId]
+			if !ok {
+				return nil, nil
+			}
+			history.PasswordHashes = append([]string{}, history.PasswordHashes...)
+			return &history, nil
+		},
+		SetHistory: func(userId string, history History, userContext supertokens.UserContext) error {
+			mutex.Lock()
+			defer mutex.Unlock()
+			histories[userId] = history
+			return nil
+		},
+	}
+}
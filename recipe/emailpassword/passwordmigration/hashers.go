@@ -0,0 +1,198 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package passwordmigration
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/crypto/scrypt"
+)
+
+// DefaultHashers returns the built-in hashers. Each of them expects the hashes in the format
+// described on its constructor.
+func DefaultHashers() map[string]PasswordHasher {
+	return map[string]PasswordHasher{
+		AlgorithmBcrypt:       MakeBcryptHasher(),
+		AlgorithmArgon2id:     MakeArgon2idHasher(),
+		AlgorithmScrypt:       MakeScryptHasher(),
+		AlgorithmPBKDF2:       MakePBKDF2Hasher(),
+		AlgorithmSaltedSHA256: MakeSaltedSHA256Hasher(true),
+	}
+}
+
+// MakeBcryptHasher verifies bcrypt hashes in the modular crypt format ($2a$, $2b$ or $2y$)
+func MakeBcryptHasher() PasswordHasher {
+	return PasswordHasher{
+		Verify: func(password string, hash string) (bool, error) {
+			err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+			if err == bcrypt.ErrMismatchedHashAndPassword {
+				return false, nil
+			}
+			if err != nil {
+				return false, err
+			}
+			return true, nil
+		},
+	}
+}
+
+// MakeArgon2idHasher verifies argon2id hashes in the PHC string format, as produced by the
+// reference implementation: $argon2id$v=19$m=<memory>,t=<iterations>,p=<parallelism>$<salt>$<hash>
+func MakeArgon2idHasher() PasswordHasher {
+	return PasswordHasher{
+		Verify: func(password string, hash string) (bool, error) {
+			parts := strings.Split(hash, "$")
+			if len(parts) != 6 || parts[1] != "argon2id" {
+				return false, errors.New("invalid argon2id hash")
+			}
+			var version int
+			_, err := fmt.Sscanf(parts[2], "v=%d", &version)
+			if err != nil || version != argon2.Version {
+				return false, errors.New("unsupported argon2id version")
+			}
+			var memory, iterations uint32
+			var parallelism uint8
+			_, err = fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &memory, &iterations, &parallelism)
+			if err != nil {
+				return false, errors.New("invalid argon2id parameters")
+			}
+			salt, err := decodeBase64(parts[4])
+			if err != nil {
+				return false, err
+			}
+			expected, err := decodeBase64(parts[5])
+			if err != nil {
+				return false, err
+			}
+			actual := argon2.IDKey([]byte(password), salt, iterations, memory, parallelism, uint32(len(expected)))
+			return subtle.ConstantTimeCompare(actual, expected) == 1, nil
+		},
+	}
+}
+
+// MakeScryptHasher verifies scrypt hashes in the format
+// $scrypt$ln=<log2 of N>,r=<block size>,p=<parallelism>$<base64 salt>$<base64 hash>
+func MakeScryptHasher() PasswordHasher {
+	return PasswordHasher{
+		Verify: func(password string, hash string) (bool, error) {
+			parts := strings.Split(hash, "$")
+			if len(parts) != 5 || parts[1] != "scrypt" {
+				return false, errors.New("invalid scrypt hash")
+			}
+			var logN, r, p int
+			_, err := fmt.Sscanf(parts[2], "ln=%d,r=%d,p=%d", &logN, &r, &p)
+			if err != nil || logN <= 0 || logN >= 32 {
+				return false, errors.New("invalid scrypt parameters")
+			}
+			salt, err := decodeBase64(parts[3])
+			if err != nil {
+				return false, err
+			}
+			expected, err := decodeBase64(parts[4])
+			if err != nil {
+				return false, err
+			}
+			actual, err := scrypt.Key([]byte(password), salt, 1<<logN, r, p, len(expected))
+			if err != nil {
+				return false, err
+			}
+			return subtle.ConstantTimeCompare(actual, expected) == 1, nil
+		},
+	}
+}
+
+// MakePBKDF2Hasher verifies PBKDF2 hashes in the format used by Django:
+// pbkdf2_<sha1|sha256|sha512>$<iterations>$<salt>$<base64 hash>
+func MakePBKDF2Hasher() PasswordHasher {
+	return PasswordHasher{
+		Verify: func(password string, encodedHash string) (bool, error) {
+			parts := strings.Split(encodedHash, "$")
+			if len(parts) != 4 {
+				return false, errors.New("invalid pbkdf2 hash")
+			}
+			var hashFunc func() hash.Hash
+			switch parts[0] {
+			case "pbkdf2_sha1":
+				hashFunc = sha1.New
+			case "pbkdf2_sha256":
+				hashFunc = sha256.New
+			case "pbkdf2_sha512":
+				hashFunc = sha512.New
+			default:
+				return false, errors.New("unsupported pbkdf2 digest: " + parts[0])
+			}
+			iterations, err := strconv.Atoi(parts[1])
+			if err != nil || iterations <= 0 {
+				return false, errors.New("invalid pbkdf2 iterations")
+			}
+			expected, err := decodeBase64(parts[3])
+			if err != nil {
+				return false, err
+			}
+			actual := pbkdf2.Key([]byte(password), []byte(parts[2]), iterations, len(expected), hashFunc)
+			return subtle.ConstantTimeCompare(actual, expected) == 1, nil
+		},
+	}
+}
+
+// MakeSaltedSHA256Hasher verifies hashes in the format <salt>$<hex digest>, where the digest is
+// the SHA-256 of the salt followed by the password, or of the password followed by the salt if
+// saltFirst is false
+func MakeSaltedSHA256Hasher(saltFirst bool) PasswordHasher {
+	return PasswordHasher{
+		Verify: func(password string, hash string) (bool, error) {
+			separatorIndex := strings.LastIndex(hash, "$")
+			if separatorIndex == -1 {
+				return false, errors.New("invalid salted sha256 hash")
+			}
+			salt := hash[:separatorIndex]
+			expected, err := hex.DecodeString(hash[separatorIndex+1:])
+			if err != nil {
+				return false, errors.New("invalid salted sha256 hash")
+			}
+			var actual [sha256.Size]byte
+			if saltFirst {
+				actual = sha256.Sum256([]byte(salt + password))
+			} else {
+				actual = sha256.Sum256([]byte(password + salt))
+			}
+			return subtle.ConstantTimeCompare(actual[:], expected) == 1, nil
+		},
+	}
+}
+
+// decodeBase64 accepts standard base64, with or without padding
+func decodeBase64(value string) ([]byte, error) {
+	decoded, err := base64.RawStdEncoding.DecodeString(strings.TrimRight(value, "="))
+	if err != nil {
+		return nil, errors.New("invalid base64 value in hash")
+	}
+	return decoded, nil
+}
@@ -0,0 +1,96 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package passwordmigration
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func NormaliseTypeInput(input TypeInput) (TypeNormalisedInput, error) {
+	result := TypeNormalisedInput{
+		Hashers: DefaultHashers(),
+		Store:   MakeInMemoryStore(),
+	}
+	for algorithm, hasher := range input.Hashers {
+		if hasher.Verify == nil {
+			return TypeNormalisedInput{}, errors.New("Verify of the " + algorithm + " hasher must not be nil")
+		}
+		result.Hashers[algorithm] = hasher
+	}
+	if input.Store != nil {
+		result.Store = *input.Store
+	}
+	return result, nil
+}
+
+// ImportLegacyHash saves the hash so that the user can sign in with their old password. The
+// algorithm must be one of the registered hashers.
+func ImportLegacyHash(config TypeNormalisedInput, hash LegacyPasswordHash, userContext supertokens.UserContext) error {
+	if _, ok := config.Hashers[hash.Algorithm]; !ok {
+		return errors.New("no password hasher is registered for the algorithm " + hash.Algorithm)
+	}
+	if hash.Hash == "" {
+		return errors.New("hash must not be empty")
+	}
+	hash.Email = normaliseEmail(hash.Email)
+	return config.Store.Save(hash, userContext)
+}
+
+// VerifyLegacyPassword returns true if there is a legacy hash for the email in the tenant and the
+// password matches it
+func VerifyLegacyPassword(config TypeNormalisedInput, tenantId string, email string, password string, userContext supertokens.UserContext) (bool, error) {
+	legacyHash, err := config.Store.Get(tenantId, normaliseEmail(email), userContext)
+	if err != nil {
+		return false, err
+	}
+	if legacyHash == nil {
+		return false, nil
+	}
+	hasher, ok := config.Hashers[legacyHash.Algorithm]
+	if !ok {
+		// The hasher was removed, so the user has to reset their password
+		supertokens.LogDebugMessage(fmt.Sprintf("passwordmigration: no hasher is registered for the algorithm %s", legacyHash.Algorithm))
+		return false, nil
+	}
+	return hasher.Verify(password, legacyHash.Hash)
+}
+
+// HasLegacyHash returns true if the password of the email in the tenant has not been migrated yet
+func HasLegacyHash(config TypeNormalisedInput, tenantId string, email string, userContext supertokens.UserContext) (bool, error) {
+	legacyHash, err := config.Store.Get(tenantId, normaliseEmail(email), userContext)
+	if err != nil {
+		return false, err
+	}
+	return legacyHash != nil, nil
+}
+
+// MarkMigrated removes the legacy hash once the password is stored by the core
+func MarkMigrated(config TypeNormalisedInput, tenantId string, email string, userContext supertokens.UserContext) error {
+	return config.Store.MarkMigrated(tenantId, normaliseEmail(email), userContext)
+}
+
+func GetProgress(config TypeNormalisedInput, userContext supertokens.UserContext) (Progress, error) {
+	return config.Store.GetProgress(userContext)
+}
+
+func normaliseEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
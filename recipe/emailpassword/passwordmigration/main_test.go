@@ -0,0 +1,191 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package passwordmigration
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/crypto/scrypt"
+)
+
+func makeTestConfig(t *testing.T, hashers map[string]PasswordHasher) TypeNormalisedInput {
+	config, err := NormaliseTypeInput(TypeInput{
+		Hashers: hashers,
+	})
+	assert.NoError(t, err)
+	return config
+}
+
+func getTestHashes(t *testing.T, password string) map[string]string {
+	bcryptHash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	assert.NoError(t, err)
+
+	salt := []byte("somesalt")
+	argon2Hash := argon2.IDKey([]byte(password), salt, 2, 1024, 1, 32)
+	scryptHash, err := scrypt.Key([]byte(password), salt, 1<<10, 8, 1, 32)
+	assert.NoError(t, err)
+	pbkdf2Hash := pbkdf2.Key([]byte(password), salt, 1000, 32, sha256.New)
+	sha256Hash := sha256.Sum256(append(salt, []byte(password)...))
+
+	return map[string]string{
+		AlgorithmBcrypt:       string(bcryptHash),
+		AlgorithmArgon2id:     fmt.Sprintf("$argon2id$v=19$m=1024,t=2,p=1$%s$%s", base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(argon2Hash)),
+		AlgorithmScrypt:       fmt.Sprintf("$scrypt$ln=10,r=8,p=1$%s$%s", base64.StdEncoding.EncodeToString(salt), base64.StdEncoding.EncodeToString(scryptHash)),
+		AlgorithmPBKDF2:       fmt.Sprintf("pbkdf2_sha256$1000$%s$%s", salt, base64.StdEncoding.EncodeToString(pbkdf2Hash)),
+		AlgorithmSaltedSHA256: fmt.Sprintf("%s$%s", salt, hex.EncodeToString(sha256Hash[:])),
+	}
+}
+
+func TestDefaultHashersVerifyTheirFormats(t *testing.T) {
+	hashers := DefaultHashers()
+	for algorithm, hash := range getTestHashes(t, "legacypass123") {
+		matches, err := hashers[algorithm].Verify("legacypass123", hash)
+		assert.NoError(t, err, algorithm)
+		assert.True(t, matches, algorithm)
+
+		matches, err = hashers[algorithm].Verify("wrongpass123", hash)
+		assert.NoError(t, err, algorithm)
+		assert.False(t, matches, algorithm)
+	}
+}
+
+func TestPBKDF2HasherSupportsOtherDigests(t *testing.T) {
+	hash := pbkdf2.Key([]byte("legacypass123"), []byte("salt"), 10, 64, sha512.New)
+	matches, err := MakePBKDF2Hasher().Verify("legacypass123", "pbkdf2_sha512$10$salt$"+base64.StdEncoding.EncodeToString(hash))
+	assert.NoError(t, err)
+	assert.True(t, matches)
+
+	_, err = MakePBKDF2Hasher().Verify("legacypass123", "pbkdf2_md5$10$salt$"+base64.StdEncoding.EncodeToString(hash))
+	assert.Error(t, err)
+}
+
+func TestSaltedSHA256HasherWithSaltAfterPassword(t *testing.T) {
+	hash := sha256.Sum256([]byte("legacypass123" + "salt"))
+	encodedHash := "salt$" + hex.EncodeToString(hash[:])
+
+	matches, err := MakeSaltedSHA256Hasher(false).Verify("legacypass123", encodedHash)
+	assert.NoError(t, err)
+	assert.True(t, matches)
+
+	matches, err = MakeSaltedSHA256Hasher(true).Verify("legacypass123", encodedHash)
+	assert.NoError(t, err)
+	assert.False(t, matches)
+}
+
+func TestMalformedHashesReturnAnError(t *testing.T) {
+	hashers := DefaultHashers()
+	for _, algorithm := range []string{AlgorithmBcrypt, AlgorithmArgon2id, AlgorithmScrypt, AlgorithmPBKDF2, AlgorithmSaltedSHA256} {
+		_, err := hashers[algorithm].Verify("legacypass123", "notahash")
+		assert.Error(t, err, algorithm)
+	}
+}
+
+func TestNormaliseTypeInputRejectsHashersWithoutVerify(t *testing.T) {
+	_, err := NormaliseTypeInput(TypeInput{
+		Hashers: map[string]PasswordHasher{"md5": {}},
+	})
+	assert.Error(t, err)
+	assert.Equal(t, "Verify of the md5 hasher must not be nil", err.Error())
+}
+
+func TestCustomHashersAreMergedWithDefaultHashers(t *testing.T) {
+	config := makeTestConfig(t, map[string]PasswordHasher{
+		"plain": {
+			Verify: func(password string, hash string) (bool, error) {
+				return password == hash, nil
+			},
+		},
+	})
+	assert.Len(t, config.Hashers, 6)
+	userContext := &map[string]interface{}{}
+
+	assert.NoError(t, ImportLegacyHash(config, LegacyPasswordHash{
+		TenantId:  "public",
+		Email:     "Test@Example.com ",
+		Algorithm: "plain",
+		Hash:      "legacypass123",
+	}, userContext))
+
+	matches, err := VerifyLegacyPassword(config, "public", "test@example.com", "legacypass123", userContext)
+	assert.NoError(t, err)
+	assert.True(t, matches)
+
+	// the hash belongs to a single tenant
+	matches, err = VerifyLegacyPassword(config, "other", "test@example.com", "legacypass123", userContext)
+	assert.NoError(t, err)
+	assert.False(t, matches)
+}
+
+func TestImportLegacyHashRejectsUnknownAlgorithms(t *testing.T) {
+	config := makeTestConfig(t, nil)
+	err := ImportLegacyHash(config, LegacyPasswordHash{
+		TenantId:  "public",
+		Email:     "test@example.com",
+		Algorithm: "md5",
+		Hash:      "5f4dcc3b5aa765d61d8327deb882cf99",
+	}, &map[string]interface{}{})
+	assert.Error(t, err)
+	assert.Equal(t, "no password hasher is registered for the algorithm md5", err.Error())
+}
+
+func TestProgressIsUpdatedWhenUsersAreMigrated(t *testing.T) {
+	config := makeTestConfig(t, nil)
+	userContext := &map[string]interface{}{}
+	hashes := getTestHashes(t, "legacypass123")
+
+	for _, email := range []string{"a@example.com", "b@example.com"} {
+		assert.NoError(t, ImportLegacyHash(config, LegacyPasswordHash{
+			TenantId:  "public",
+			Email:     email,
+			Algorithm: AlgorithmBcrypt,
+			Hash:      hashes[AlgorithmBcrypt],
+		}, userContext))
+	}
+	assert.NoError(t, ImportLegacyHash(config, LegacyPasswordHash{
+		TenantId:  "public",
+		Email:     "c@example.com",
+		Algorithm: AlgorithmPBKDF2,
+		Hash:      hashes[AlgorithmPBKDF2],
+	}, userContext))
+
+	progress, err := GetProgress(config, userContext)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{AlgorithmBcrypt: 2, AlgorithmPBKDF2: 1}, progress.Remaining)
+	assert.Equal(t, 0, progress.Migrated)
+
+	assert.NoError(t, MarkMigrated(config, "public", "C@example.com", userContext))
+	// marking a user without a legacy hash does not count
+	assert.NoError(t, MarkMigrated(config, "public", "d@example.com", userContext))
+
+	progress, err = GetProgress(config, userContext)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{AlgorithmBcrypt: 2}, progress.Remaining)
+	assert.Equal(t, 1, progress.Migrated)
+
+	hasLegacyHash, err := HasLegacyHash(config, "public", "c@example.com", userContext)
+	assert.NoError(t, err)
+	assert.False(t, hasLegacyHash)
+}
@@ -0,0 +1,75 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package passwordmigration
+
+import (
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+const (
+	AlgorithmBcrypt       = "bcrypt"
+	AlgorithmArgon2id     = "argon2id"
+	AlgorithmScrypt       = "scrypt"
+	AlgorithmPBKDF2       = "pbkdf2"
+	AlgorithmSaltedSHA256 = "salted-sha256"
+)
+
+// LegacyPasswordHash is the password hash of a user from the previous system
+type LegacyPasswordHash struct {
+	TenantId string `json:"tenantId"`
+	Email    string `json:"email"`
+	// Algorithm is the name of the PasswordHasher that verifies the hash
+	Algorithm string `json:"algorithm"`
+	Hash      string `json:"hash"`
+}
+
+// PasswordHasher verifies passwords against the hashes of a legacy format
+type PasswordHasher struct {
+	Verify func(password string, hash string) (bool, error)
+}
+
+type Progress struct {
+	// Remaining is the number of legacy hashes that are left, by algorithm. A hasher can be
+	// removed once there are none left for its algorithm.
+	Remaining map[string]int
+	// Migrated is the number of users whose password was moved to the core
+	Migrated int
+}
+
+// Store keeps the legacy password hashes until the users sign in
+type Store struct {
+	// Save inserts the hash, or replaces the hash of the same tenant and email
+	Save func(hash LegacyPasswordHash, userContext supertokens.UserContext) error
+	// Get returns nil if there is no legacy hash for the email in the tenant
+	Get func(tenantId string, email string, userContext supertokens.UserContext) (*LegacyPasswordHash, error)
+	// MarkMigrated removes the legacy hash and counts the user as migrated
+	MarkMigrated func(tenantId string, email string, userContext supertokens.UserContext) error
+	GetProgress  func(userContext supertokens.UserContext) (Progress, error)
+}
+
+type TypeInput struct {
+	// Hashers are merged with the built-in hashers (see DefaultHashers), by algorithm name
+	Hashers map[string]PasswordHasher
+	// Store defaults to an in-memory store, which is only useful for testing since the legacy
+	// hashes have to be imported every time the process starts
+	Store *Store
+}
+
+type TypeNormalisedInput struct {
+	Hashers map[string]PasswordHasher
+	Store   Store
+}
@@ -0,0 +1,73 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package passwordmigration
+
+import (
+	"sync"
+
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func MakeInMemoryStore() Store {
+	var lock sync.Mutex
+	hashes := map[string]LegacyPasswordHash{}
+	migrated := 0
+
+	getKey := func(tenantId string, email string) string {
+		return tenantId + "|" + email
+	}
+
+	return Store{
+		Save: func(hash LegacyPasswordHash, userContext supertokens.UserContext) error {
+			lock.Lock()
+			defer lock.Unlock()
+			hashes[getKey(hash.TenantId, hash.Email)] = hash
+			return nil
+		},
+		Get: func(tenantId string, email string, userContext supertokens.UserContext) (*LegacyPasswordHash, error) {
+			lock.Lock()
+			defer lock.Unlock()
+			hash, ok := hashes[getKey(tenantId, email)]
+			if !ok {
+				return nil, nil
+			}
+			return &hash, nil
+		},
+		MarkMigrated: func(tenantId string, email string, userContext supertokens.UserContext) error {
+			lock.Lock()
+			defer lock.Unlock()
+			key := getKey(tenantId, email)
+			if _, ok := hashes[key]; ok {
+				delete(hashes, key)
+				migrated++
+			}
+			return nil
+		},
+		GetProgress: func(userContext supertokens.UserContext) (Progress, error) {
+			lock.Lock()
+			defer lock.Unlock()
+			progress := Progress{
+				Remaining: map[string]int{},
+				Migrated:  migrated,
+			}
+			for _, hash := range hashes {
+				progress.Remaining[hash.Algorithm]++
+			}
+			return progress, nil
+		},
+	}
+}
@@ -0,0 +1,229 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package passwordpolicy
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func NormaliseTypeInput(input TypeInput) (TypeNormalisedInput, error) {
+	defaultPolicy, err := normalisePolicy(input.Default, Policy{})
+	if err != nil {
+		return TypeNormalisedInput{}, err
+	}
+
+	tenantPolicies := map[string]NormalisedPolicy{}
+	for tenantId, policy := range input.TenantPolicies {
+		tenantPolicy, err := normalisePolicy(policy, input.Default)
+		if err != nil {
+			return TypeNormalisedInput{}, fmt.Errorf("invalid password policy for tenant %s: %s", tenantId, err.Error())
+		}
+		tenantPolicies[tenantId] = tenantPolicy
+	}
+
+	return TypeNormalisedInput{
+		Default:         defaultPolicy,
+		TenantPolicies:  tenantPolicies,
+		GetErrorMessage: input.GetErrorMessage,
+	}, nil
+}
+
+// normalisePolicy applies the defaults to the fields that are not set in policy or fallback
+func normalisePolicy(policy Policy, fallback Policy) (NormalisedPolicy, error) {
+	result := NormalisedPolicy{
+		MinLength:        getIntOrDefault(policy.MinLength, fallback.MinLength, 8),
+		MaxLength:        getIntOrDefault(policy.MaxLength, fallback.MaxLength, 100),
+		RequireLowercase: getBoolOrDefault(policy.RequireLowercase, fallback.RequireLowercase, false),
+		RequireUppercase: getBoolOrDefault(policy.RequireUppercase, fallback.RequireUppercase, false),
+		RequireLetter:    getBoolOrDefault(policy.RequireLetter, fallback.RequireLetter, true),
+		RequireNumber:    getBoolOrDefault(policy.RequireNumber, fallback.RequireNumber, true),
+		RequireSymbol:    getBoolOrDefault(policy.RequireSymbol, fallback.RequireSymbol, false),
+		DisallowUserInfo: getBoolOrDefault(policy.DisallowUserInfo, fallback.DisallowUserInfo, false),
+		MinStrengthScore: getIntOrDefault(policy.MinStrengthScore, fallback.MinStrengthScore, 0),
+	}
+	if result.MinLength < 1 {
+		return NormalisedPolicy{}, errors.New("MinLength must be at least 1")
+	}
+	if result.MaxLength < result.MinLength {
+		return NormalisedPolicy{}, errors.New("MaxLength must be greater than or equal to MinLength")
+	}
+	if result.MinStrengthScore < 0 || result.MinStrengthScore > 4 {
+		return NormalisedPolicy{}, errors.New("MinStrengthScore must be between 0 and 4")
+	}
+	return result, nil
+}
+
+// GetPolicy returns the policy that applies to tenantId
+func GetPolicy(config TypeNormalisedInput, tenantId string) NormalisedPolicy {
+	if policy, ok := config.TenantPolicies[tenantId]; ok {
+		return policy
+	}
+	return config.Default
+}
+
+// Validate returns the rules of the policy of tenantId that password does not satisfy. userInputs
+// are values like the email of the user, which are checked if DisallowUserInfo is set and are
+// penalised when estimating the strength of the password.
+func Validate(config TypeNormalisedInput, password string, userInputs []string, tenantId string, userContext supertokens.UserContext) []Violation {
+	policy := GetPolicy(config, tenantId)
+	violations := []Violation{}
+
+	length := utf8.RuneCountInString(password)
+	if length < policy.MinLength {
+		violations = append(violations, Violation{Code: TooShortCode, Params: map[string]interface{}{"minLength": policy.MinLength}})
+	}
+	if length > policy.MaxLength {
+		violations = append(violations, Violation{Code: TooLongCode, Params: map[string]interface{}{"maxLength": policy.MaxLength}})
+	}
+
+	hasLower, hasUpper, hasLetter, hasNumber, hasSymbol := false, false, false, false, false
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			hasLower = true
+			hasLetter = true
+		case unicode.IsUpper(r):
+			hasUpper = true
+			hasLetter = true
+		case unicode.IsLetter(r):
+			hasLetter = true
+		case unicode.IsDigit(r):
+			hasNumber = true
+		case !unicode.IsSpace(r):
+			hasSymbol = true
+		}
+	}
+	if policy.RequireLowercase && !hasLower {
+		violations = append(violations, Violation{Code: MissingLowercaseCode})
+	}
+	if policy.RequireUppercase && !hasUpper {
+		violations = append(violations, Violation{Code: MissingUppercaseCode})
+	}
+	if policy.RequireLetter && !hasLetter {
+		violations = append(violations, Violation{Code: MissingLetterCode})
+	}
+	if policy.RequireNumber && !hasNumber {
+		violations = append(violations, Violation{Code: MissingNumberCode})
+	}
+	if policy.RequireSymbol && !hasSymbol {
+		violations = append(violations, Violation{Code: MissingSymbolCode})
+	}
+
+	userInfoTokens := getUserInfoTokens(userInputs)
+	if policy.DisallowUserInfo {
+		lowerPassword := strings.ToLower(password)
+		for _, token := range userInfoTokens {
+			if strings.Contains(lowerPassword, token) {
+				violations = append(violations, Violation{Code: ContainsUserInfoCode})
+				break
+			}
+		}
+	}
+
+	if policy.MinStrengthScore > 0 {
+		score := EstimateStrength(password, userInfoTokens)
+		if score < policy.MinStrengthScore {
+			violations = append(violations, Violation{Code: TooWeakCode, Params: map[string]interface{}{"minScore": policy.MinStrengthScore, "score": score}})
+		}
+	}
+
+	for i := range violations {
+		violations[i].Message = getErrorMessage(config, violations[i], tenantId, userContext)
+	}
+	return violations
+}
+
+// GetFailureReason joins the messages of the violations, for APIs that return a single error message
+func GetFailureReason(violations []Violation) string {
+	messages := []string{}
+	for _, violation := range violations {
+		messages = append(messages, violation.Message)
+	}
+	return strings.Join(messages, ". ")
+}
+
+// getUserInfoTokens returns the lowercase email and the part of it before the @. Parts
+// shorter than 4 characters are ignored, since they would reject too many passwords.
+func getUserInfoTokens(userInputs []string) []string {
+	tokens := []string{}
+	for _, input := range userInputs {
+		input = strings.ToLower(strings.TrimSpace(input))
+		if utf8.RuneCountInString(input) >= 4 {
+			tokens = append(tokens, input)
+		}
+		if at := strings.Index(input, "@"); at >= 4 {
+			tokens = append(tokens, input[:at])
+		}
+	}
+	return tokens
+}
+
+func getErrorMessage(config TypeNormalisedInput, violation Violation, tenantId string, userContext supertokens.UserContext) string {
+	if config.GetErrorMessage != nil {
+		message := config.GetErrorMessage(violation, tenantId, userContext)
+		if message != nil {
+			return *message
+		}
+	}
+	switch violation.Code {
+	case TooShortCode:
+		return fmt.Sprintf("Password must contain at least %v characters", violation.Params["minLength"])
+	case TooLongCode:
+		return fmt.Sprintf("Password must contain at most %v characters", violation.Params["maxLength"])
+	case MissingLowercaseCode:
+		return "Password must contain at least one lowercase letter"
+	case MissingUppercaseCode:
+		return "Password must contain at least one uppercase letter"
+	case MissingLetterCode:
+		return "Password must contain at least one alphabet"
+	case MissingNumberCode:
+		return "Password must contain at least one number"
+	case MissingSymbolCode:
+		return "Password must contain at least one special character"
+	case ContainsUserInfoCode:
+		return "Password must not contain your email"
+	case TooWeakCode:
+		return "Password is too easy to guess"
+	}
+	return "Password does not satisfy the password policy"
+}
+
+func getIntOrDefault(value *int, fallback *int, defaultValue int) int {
+	if value != nil {
+		return *value
+	}
+	if fallback != nil {
+		return *fallback
+	}
+	return defaultValue
+}
+
+func getBoolOrDefault(value *bool, fallback *bool, defaultValue bool) bool {
+	if value != nil {
+		return *value
+	}
+	if fallback != nil {
+		return *fallback
+	}
+	return defaultValue
+}
@@ -0,0 +1,155 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package passwordpolicy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func getViolationCodes(violations []Violation) []string {
+	codes := []string{}
+	for _, violation := range violations {
+		codes = append(codes, violation.Code)
+	}
+	return codes
+}
+
+func TestDefaultPolicyMatchesDefaultValidator(t *testing.T) {
+	config, err := NormaliseTypeInput(TypeInput{})
+	assert.NoError(t, err)
+
+	assert.Empty(t, Validate(config, "validPass123", nil, "public", &map[string]interface{}{}))
+
+	violations := Validate(config, "abc", nil, "public", &map[string]interface{}{})
+	assert.Equal(t, []string{TooShortCode, MissingNumberCode}, getViolationCodes(violations))
+	assert.Equal(t, "Password must contain at least 8 characters. Password must contain at least one number", GetFailureReason(violations))
+
+	violations = Validate(config, "12345678", nil, "public", &map[string]interface{}{})
+	assert.Equal(t, []string{MissingLetterCode}, getViolationCodes(violations))
+}
+
+func TestCharacterClassRules(t *testing.T) {
+	trueValue := true
+	minLength := 4
+	maxLength := 10
+	config, err := NormaliseTypeInput(TypeInput{
+		Default: Policy{
+			MinLength:        &minLength,
+			MaxLength:        &maxLength,
+			RequireLowercase: &trueValue,
+			RequireUppercase: &trueValue,
+			RequireSymbol:    &trueValue,
+		},
+	})
+	assert.NoError(t, err)
+
+	assert.Empty(t, Validate(config, "Ab1!", nil, "public", &map[string]interface{}{}))
+	assert.Equal(t, []string{MissingUppercaseCode, MissingSymbolCode}, getViolationCodes(Validate(config, "abc1", nil, "public", &map[string]interface{}{})))
+	assert.Equal(t, []string{TooLongCode}, getViolationCodes(Validate(config, "Abcdefghij1!", nil, "public", &map[string]interface{}{})))
+}
+
+func TestDisallowUserInfo(t *testing.T) {
+	trueValue := true
+	config, err := NormaliseTypeInput(TypeInput{
+		Default: Policy{DisallowUserInfo: &trueValue},
+	})
+	assert.NoError(t, err)
+
+	userInputs := []string{"Johnny@example.com"}
+	assert.Equal(t, []string{ContainsUserInfoCode}, getViolationCodes(Validate(config, "myJOHNNY123", userInputs, "public", &map[string]interface{}{})))
+	assert.Empty(t, Validate(config, "unrelated123", userInputs, "public", &map[string]interface{}{}))
+
+	// the local part is ignored if it is too short
+	assert.Empty(t, Validate(config, "joe12345", []string{"joe@example.com"}, "public", &map[string]interface{}{}))
+}
+
+func TestTenantPolicyOverridesDefault(t *testing.T) {
+	minLength := 12
+	trueValue := true
+	config, err := NormaliseTypeInput(TypeInput{
+		Default: Policy{RequireSymbol: &trueValue},
+		TenantPolicies: map[string]Policy{
+			"strict": {MinLength: &minLength},
+		},
+	})
+	assert.NoError(t, err)
+
+	policy := GetPolicy(config, "strict")
+	assert.Equal(t, 12, policy.MinLength)
+	assert.True(t, policy.RequireSymbol)
+	assert.Equal(t, 8, GetPolicy(config, "public").MinLength)
+
+	assert.Empty(t, Validate(config, "short12!", nil, "public", &map[string]interface{}{}))
+	assert.Equal(t, []string{TooShortCode}, getViolationCodes(Validate(config, "short12!", nil, "strict", &map[string]interface{}{})))
+}
+
+func TestInvalidPolicy(t *testing.T) {
+	minLength := 10
+	maxLength := 5
+	_, err := NormaliseTypeInput(TypeInput{
+		Default: Policy{MinLength: &minLength, MaxLength: &maxLength},
+	})
+	assert.EqualError(t, err, "MaxLength must be greater than or equal to MinLength")
+
+	score := 5
+	_, err = NormaliseTypeInput(TypeInput{
+		TenantPolicies: map[string]Policy{"t1": {MinStrengthScore: &score}},
+	})
+	assert.EqualError(t, err, "invalid password policy for tenant t1: MinStrengthScore must be between 0 and 4")
+}
+
+func TestCustomErrorMessages(t *testing.T) {
+	config, err := NormaliseTypeInput(TypeInput{
+		GetErrorMessage: func(violation Violation, tenantId string, userContext supertokens.UserContext) *string {
+			if violation.Code == TooShortCode {
+				message := "Le mot de passe est trop court"
+				return &message
+			}
+			return nil
+		},
+	})
+	assert.NoError(t, err)
+
+	violations := Validate(config, "abc", nil, "public", &map[string]interface{}{})
+	assert.Equal(t, "Le mot de passe est trop court", violations[0].Message)
+	assert.Equal(t, 8, violations[0].Params["minLength"])
+	assert.Equal(t, "Password must contain at least one number", violations[1].Message)
+}
+
+func TestMinStrengthScore(t *testing.T) {
+	score := 3
+	config, err := NormaliseTypeInput(TypeInput{
+		Default: Policy{MinStrengthScore: &score},
+	})
+	assert.NoError(t, err)
+
+	violations := Validate(config, "password123", nil, "public", &map[string]interface{}{})
+	assert.Equal(t, []string{TooWeakCode}, getViolationCodes(violations))
+	assert.Empty(t, Validate(config, "c0rrect-h0rse-battery", nil, "public", &map[string]interface{}{}))
+}
+
+func TestEstimateStrength(t *testing.T) {
+	assert.Equal(t, 0, EstimateStrength("password", nil))
+	assert.Equal(t, 0, EstimateStrength("P@ssw0rd", nil))
+	assert.Equal(t, 0, EstimateStrength("aaaaaaaaaaaa", nil))
+	assert.Less(t, EstimateStrength("abcdefgh12345678", nil), 2)
+	assert.Less(t, EstimateStrength("johnny2024", []string{"johnny"}), EstimateStrength("johnny2024", nil))
+	assert.Equal(t, 4, EstimateStrength("vG7#qLz!9wRt2$kP", nil))
+}
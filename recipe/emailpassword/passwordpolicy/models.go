@@ -0,0 +1,83 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package passwordpolicy
+
+import "github.com/supertokens/supertokens-golang/supertokens"
+
+const (
+	TooShortCode         = "PASSWORD_TOO_SHORT"
+	TooLongCode          = "PASSWORD_TOO_LONG"
+	MissingLowercaseCode = "PASSWORD_MISSING_LOWERCASE"
+	MissingUppercaseCode = "PASSWORD_MISSING_UPPERCASE"
+	MissingLetterCode    = "PASSWORD_MISSING_LETTER"
+	MissingNumberCode    = "PASSWORD_MISSING_NUMBER"
+	MissingSymbolCode    = "PASSWORD_MISSING_SYMBOL"
+	ContainsUserInfoCode = "PASSWORD_CONTAINS_USER_INFO"
+	TooWeakCode          = "PASSWORD_TOO_WEAK"
+)
+
+// Policy is a declarative password policy. Unset fields use the defaults, which match the
+// default password validator: between 8 and 100 characters with at least one letter and one number.
+type Policy struct {
+	MinLength        *int
+	MaxLength        *int
+	RequireLowercase *bool
+	RequireUppercase *bool
+	RequireLetter    *bool
+	RequireNumber    *bool
+	RequireSymbol    *bool
+	// DisallowUserInfo rejects passwords that contain the email of the user (or the part
+	// before the @ if it has at least 4 characters)
+	DisallowUserInfo *bool
+	// MinStrengthScore is the minimum score (from 0 to 4) returned by EstimateStrength
+	MinStrengthScore *int
+}
+
+type TypeInput struct {
+	Default Policy
+	// TenantPolicies overrides fields of the default policy for specific tenants
+	TenantPolicies map[string]Policy
+	// GetErrorMessage can be used to localise the error messages. Returning nil uses the default message.
+	GetErrorMessage func(violation Violation, tenantId string, userContext supertokens.UserContext) *string
+}
+
+type NormalisedPolicy struct {
+	MinLength        int  `json:"minLength"`
+	MaxLength        int  `json:"maxLength"`
+	RequireLowercase bool `json:"requireLowercase"`
+	RequireUppercase bool `json:"requireUppercase"`
+	RequireLetter    bool `json:"requireLetter"`
+	RequireNumber    bool `json:"requireNumber"`
+	RequireSymbol    bool `json:"requireSymbol"`
+	DisallowUserInfo bool `json:"disallowUserInfo"`
+	MinStrengthScore int  `json:"minStrengthScore"`
+}
+
+type TypeNormalisedInput struct {
+	Default         NormalisedPolicy
+	TenantPolicies  map[string]NormalisedPolicy
+	GetErrorMessage func(violation Violation, tenantId string, userContext supertokens.UserContext) *string
+}
+
+// Violation is a rule of the policy that the password does not satisfy. Code is one of the
+// constants above and Params contains the values needed to build a localised message
+// (for example minLength for PASSWORD_TOO_SHORT).
+type Violation struct {
+	Code    string                 `json:"code"`
+	Params  map[string]interface{} `json:"params,omitempty"`
+	Message string                 `json:"message"`
+}
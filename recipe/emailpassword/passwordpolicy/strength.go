@@ -0,0 +1,207 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package passwordpolicy
+
+import (
+	"math"
+	"strings"
+	"unicode"
+)
+
+// commonPasswords is a short list of the most common passwords (and base words of common
+// passwords), in order of frequency. It is used to estimate how guessable a password is.
+var commonPasswords = []string{
+	"password", "123456", "12345678", "qwerty", "123456789", "12345", "1234", "111111",
+	"1234567", "dragon", "123123", "baseball", "abc123", "football", "monkey", "letmein",
+	"696969", "shadow", "master", "666666", "qwertyuiop", "123321", "mustang", "1234567890",
+	"michael", "654321", "superman", "1qaz2wsx", "7777777", "121212", "000000", "qazwsx",
+	"123qwe", "killer", "trustno1", "jordan", "jennifer", "zxcvbnm", "asdfgh", "hunter",
+	"buster", "soccer", "harley", "batman", "andrew", "tigger", "sunshine", "iloveyou",
+	"charlie", "robert", "thomas", "hockey", "ranger", "daniel", "starwars",
+	"klaster", "112233", "george", "computer", "michelle", "jessica", "pepper", "1111",
+	"zxcvbn", "555555", "11111111", "131313", "freedom", "777777", "pass", "maggie",
+	"159753", "aaaaaa", "ginger", "princess", "joshua", "cheese", "amanda", "summer",
+	"love", "ashley", "nicole", "chelsea", "biteme", "matthew", "access", "yankees",
+	"987654321", "dallas", "austin", "thunder", "taylor", "matrix", "welcome", "admin",
+	"login", "passw0rd", "secret", "qwerty123", "changeme", "default", "guest", "test",
+	"hello", "whatever", "flower", "lovely", "solo",
+}
+
+var keyboardRows = []string{
+	"1234567890", "qwertyuiop", "asdfghjkl", "zxcvbnm", "abcdefghijklmnopqrstuvwxyz",
+}
+
+var leetReplacer = strings.NewReplacer(
+	"0", "o", "1", "i", "3", "e", "4", "a", "5", "s", "7", "t", "@", "a", "$", "s", "!", "i",
+)
+
+// EstimateStrength returns a zxcvbn-style score from 0 (too guessable) to 4 (very unguessable).
+// It estimates the number of guesses needed to find the password, taking into account common
+// passwords, the user inputs (like the email), repeated characters and sequences, and maps it
+// to a score using the same thresholds as zxcvbn.
+func EstimateStrength(password string, userInputs []string) int {
+	guesses := estimateGuesses(password, userInputs)
+	switch {
+	case guesses < 1e3:
+		return 0
+	case guesses < 1e6:
+		return 1
+	case guesses < 1e8:
+		return 2
+	case guesses < 1e10:
+		return 3
+	}
+	return 4
+}
+
+func estimateGuesses(password string, userInputs []string) float64 {
+	if password == "" {
+		return 0
+	}
+	dictionary := append(append([]string{}, userInputs...), commonPasswords...)
+	return estimateGuessesWithDictionary(password, dictionary, 3)
+}
+
+// estimateGuessesWithDictionary returns the smallest number of guesses for password, where a
+// word from the dictionary (a user input or a common password) only costs its rank, and the
+// rest of the password is estimated recursively (up to depth words).
+func estimateGuessesWithDictionary(password string, dictionary []string, depth int) float64 {
+	guesses := bruteforceGuesses(password)
+	if depth == 0 {
+		return guesses
+	}
+
+	lower := strings.ToLower(password)
+	for _, candidate := range []string{lower, leetReplacer.Replace(lower)} {
+		for rank, word := range dictionary {
+			if len(word) < 4 || !strings.Contains(candidate, word) {
+				continue
+			}
+			wordGuesses := float64(rank + 1)
+			if candidate != lower {
+				// l33t substitutions
+				wordGuesses *= 4
+			}
+			if lower != password {
+				// capitalisation
+				wordGuesses *= 2
+			}
+			rest := strings.Replace(candidate, word, "", 1)
+			if rest != "" {
+				wordGuesses *= estimateGuessesWithDictionary(rest, dictionary, depth-1)
+			}
+			guesses = math.Min(guesses, wordGuesses)
+		}
+	}
+	return guesses
+}
+
+// bruteforceGuesses returns charset^length, where runs of repeated characters and sequences
+// (like "aaaa", "1234" or "qwerty") only count as a couple of characters
+func bruteforceGuesses(password string) float64 {
+	if password == "" {
+		return 1
+	}
+	runes := []rune(password)
+	charsetSize := 0
+	hasLower, hasUpper, hasNumber, hasSymbol, hasOther := false, false, false, false, false
+	for _, r := range runes {
+		switch {
+		case r >= 'a' && r <= 'z':
+			hasLower = true
+		case r >= 'A' && r <= 'Z':
+			hasUpper = true
+		case r >= '0' && r <= '9':
+			hasNumber = true
+		case r < 128:
+			hasSymbol = true
+		case unicode.IsLetter(r):
+			hasOther = true
+		default:
+			hasSymbol = true
+		}
+	}
+	if hasLower {
+		charsetSize += 26
+	}
+	if hasUpper {
+		charsetSize += 26
+	}
+	if hasNumber {
+		charsetSize += 10
+	}
+	if hasSymbol {
+		charsetSize += 33
+	}
+	if hasOther {
+		charsetSize += 100
+	}
+
+	effectiveLength := 0.0
+	lower := []rune(strings.ToLower(password))
+	for i := 0; i < len(lower); {
+		runLength := getPatternLength(lower, i)
+		if runLength >= 3 {
+			effectiveLength += 2
+		} else {
+			runLength = 1
+			effectiveLength++
+		}
+		i += runLength
+	}
+
+	return math.Pow(float64(charsetSize), effectiveLength)
+}
+
+// getPatternLength returns the length of the run of repeated characters or the sequence
+// (forwards or backwards on a keyboard row or the alphabet) that starts at start
+func getPatternLength(runes []rune, start int) int {
+	repeat := 1
+	for start+repeat < len(runes) && runes[start+repeat] == runes[start] {
+		repeat++
+	}
+
+	longestSequence := 1
+	for _, row := range keyboardRows {
+		for _, sequence := range []string{row, reverse(row)} {
+			index := strings.IndexRune(sequence, runes[start])
+			if index < 0 {
+				continue
+			}
+			length := 1
+			for start+length < len(runes) && index+length < len(sequence) && rune(sequence[index+length]) == runes[start+length] {
+				length++
+			}
+			if length > longestSequence {
+				longestSequence = length
+			}
+		}
+	}
+
+	if repeat > longestSequence {
+		return repeat
+	}
+	return longestSequence
+}
+
+func reverse(value string) string {
+	runes := []rune(value)
+	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+		runes[i], runes[j] = runes[j], runes[i]
+	}
+	return string(runes)
+}
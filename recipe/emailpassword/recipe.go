@@ -20,12 +20,15 @@ import (
 	"net/http"
 
 	"github.com/supertokens/supertokens-golang/ingredients/emaildelivery"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/accountdeletion"
 	"github.com/supertokens/supertokens-golang/recipe/emailpassword/api"
 	"github.com/supertokens/supertokens-golang/recipe/emailpassword/constants"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/epclaims"
 	"github.com/supertokens/supertokens-golang/recipe/emailpassword/epmodels"
 	"github.com/supertokens/supertokens-golang/recipe/emailpassword/errors"
 	"github.com/supertokens/supertokens-golang/recipe/emailverification"
 	"github.com/supertokens/supertokens-golang/recipe/emailverification/evmodels"
+	"github.com/supertokens/supertokens-golang/recipe/session"
 
 	"github.com/supertokens/supertokens-golang/supertokens"
 )
@@ -38,6 +41,8 @@ type Recipe struct {
 	RecipeImpl    epmodels.RecipeInterface
 	APIImpl       epmodels.APIInterface
 	EmailDelivery emaildelivery.Ingredient
+
+	accountDeletionScheduler *accountdeletion.Scheduler
 }
 
 var singletonInstance *Recipe
@@ -50,7 +55,10 @@ func MakeRecipe(recipeId string, appInfo supertokens.NormalisedAppinfo, config *
 	if err != nil {
 		return Recipe{}, err
 	}
-	verifiedConfig := validateAndNormaliseUserInput(r, appInfo, config)
+	verifiedConfig, err := validateAndNormaliseUserInput(r, appInfo, config)
+	if err != nil {
+		return Recipe{}, err
+	}
 	r.Config = verifiedConfig
 	r.APIImpl = verifiedConfig.Override.APIs(api.MakeAPIImplementation())
 	var getEmailPasswordConfig = func() epmodels.TypeNormalisedInput {
@@ -70,10 +78,27 @@ func MakeRecipe(recipeId string, appInfo supertokens.NormalisedAppinfo, config *
 			emailVerificationRecipe.AddGetEmailForUserIdFunc(r.getEmailForUserId)
 		}
 
+		if verifiedConfig.PasswordHistory != nil && verifiedConfig.PasswordHistory.MaxAge != 0 {
+			sessionRecipe, err := session.GetRecipeInstanceOrThrowError()
+			if err != nil {
+				return err
+			}
+			sessionRecipe.AddClaimFromOtherRecipe(epclaims.PasswordExpiredClaim)
+			if verifiedConfig.PasswordHistory.EnforceExpiry {
+				sessionRecipe.AddClaimValidatorFromOtherRecipe(epclaims.PasswordExpiredClaimValidators.IsFalse(nil, nil))
+			}
+		}
+
+		if verifiedConfig.AccountDeletion != nil {
+			r.accountDeletionScheduler = accountdeletion.StartScheduler(*verifiedConfig.AccountDeletion, r.deleteUserForAccountDeletion)
+		}
+
 		return nil
 	})
 
 	r.RecipeModule.ResetForTest = resetForTest
+	r.RecipeModule.GetTenantIdsForUser = r.getTenantIdsForUser
+	r.RecipeModule.ExportUserData = r.exportUserData
 
 	return *r, nil
 }
@@ -130,6 +155,30 @@ func (r *Recipe) getAPIsHandled() ([]supertokens.APIHandled, error) {
 	if err != nil {
 		return nil, err
 	}
+	passwordPolicyAPI, err := supertokens.NewNormalisedURLPath(constants.PasswordPolicyAPI)
+	if err != nil {
+		return nil, err
+	}
+	emailChangeAPI, err := supertokens.NewNormalisedURLPath(constants.EmailChangeAPI)
+	if err != nil {
+		return nil, err
+	}
+	emailChangeConfirmAPI, err := supertokens.NewNormalisedURLPath(constants.EmailChangeConfirmAPI)
+	if err != nil {
+		return nil, err
+	}
+	passwordChangeAPI, err := supertokens.NewNormalisedURLPath(constants.PasswordChangeAPI)
+	if err != nil {
+		return nil, err
+	}
+	accountDeletionAPI, err := supertokens.NewNormalisedURLPath(constants.AccountDeletionAPI)
+	if err != nil {
+		return nil, err
+	}
+	accountDeletionCancelAPI, err := supertokens.NewNormalisedURLPath(constants.AccountDeletionCancelAPI)
+	if err != nil {
+		return nil, err
+	}
 	return []supertokens.APIHandled{{
 		Method:                 http.MethodPost,
 		PathWithoutAPIBasePath: signUpAPI,
@@ -160,6 +209,36 @@ func (r *Recipe) getAPIsHandled() ([]supertokens.APIHandled, error) {
 		PathWithoutAPIBasePath: signupEmailExistsAPI,
 		ID:                     constants.SignupEmailExistsAPI,
 		Disabled:               r.APIImpl.EmailExistsGET == nil,
+	}, {
+		Method:                 http.MethodGet,
+		PathWithoutAPIBasePath: passwordPolicyAPI,
+		ID:                     constants.PasswordPolicyAPI,
+		Disabled:               r.APIImpl.PasswordPolicyGET == nil || r.Config.PasswordPolicy == nil,
+	}, {
+		Method:                 http.MethodPost,
+		PathWithoutAPIBasePath: emailChangeAPI,
+		ID:                     constants.EmailChangeAPI,
+		Disabled:               r.APIImpl.EmailChangePOST == nil || r.Config.EmailChangeFeature == nil,
+	}, {
+		Method:                 http.MethodPost,
+		PathWithoutAPIBasePath: emailChangeConfirmAPI,
+		ID:                     constants.EmailChangeConfirmAPI,
+		Disabled:               r.APIImpl.EmailChangeConfirmPOST == nil || r.Config.EmailChangeFeature == nil,
+	}, {
+		Method:                 http.MethodPost,
+		PathWithoutAPIBasePath: passwordChangeAPI,
+		ID:                     constants.PasswordChangeAPI,
+		Disabled:               r.APIImpl.PasswordChangePOST == nil || r.Config.PasswordChangeFeature == nil,
+	}, {
+		Method:                 http.MethodPost,
+		PathWithoutAPIBasePath: accountDeletionAPI,
+		ID:                     constants.AccountDeletionAPI,
+		Disabled:               r.APIImpl.AccountDeletionPOST == nil || r.Config.AccountDeletion == nil,
+	}, {
+		Method:                 http.MethodPost,
+		PathWithoutAPIBasePath: accountDeletionCancelAPI,
+		ID:                     constants.AccountDeletionCancelAPI,
+		Disabled:               r.APIImpl.AccountDeletionCancelPOST == nil || r.Config.AccountDeletion == nil,
 	}}, nil
 }
 
@@ -184,6 +263,18 @@ func (r *Recipe) handleAPIRequest(id string, tenantId string, req *http.Request,
 		return api.PasswordReset(r.APIImpl, tenantId, options, userContext)
 	} else if id == constants.SignupEmailExistsAPIOld || id == constants.SignupEmailExistsAPI {
 		return api.EmailExists(r.APIImpl, tenantId, options, userContext)
+	} else if id == constants.PasswordPolicyAPI {
+		return api.PasswordPolicy(r.APIImpl, tenantId, options, userContext)
+	} else if id == constants.EmailChangeAPI {
+		return api.EmailChange(r.APIImpl, tenantId, options, userContext)
+	} else if id == constants.EmailChangeConfirmAPI {
+		return api.EmailChangeConfirm(r.APIImpl, tenantId, options, userContext)
+	} else if id == constants.PasswordChangeAPI {
+		return api.PasswordChange(r.APIImpl, tenantId, options, userContext)
+	} else if id == constants.AccountDeletionAPI {
+		return api.AccountDeletion(r.APIImpl, tenantId, options, userContext)
+	} else if id == constants.AccountDeletionCancelAPI {
+		return api.AccountDeletionCancel(r.APIImpl, tenantId, options, userContext)
 	}
 	return defaultErrors.New("should never come here")
 }
@@ -220,7 +311,23 @@ func (r *Recipe) getEmailForUserId(userID string, userContext supertokens.UserCo
 	}, nil
 }
 
+// deleteUserForAccountDeletion is called by the account deletion scheduler once the grace period is over
+func (r *Recipe) deleteUserForAccountDeletion(job accountdeletion.Job, userContext supertokens.UserContext) error {
+	tenantIds := []string{job.TenantId}
+	user, err := (*r.RecipeImpl.GetUserByID)(job.UserId, userContext)
+	if err != nil {
+		return err
+	}
+	if user != nil {
+		tenantIds = user.TenantIds
+	}
+	return accountdeletion.DeleteUser(*r.Config.AccountDeletion, job.UserId, tenantIds, userContext)
+}
+
 func resetForTest() {
+	if singletonInstance != nil && singletonInstance.accountDeletionScheduler != nil {
+		singletonInstance.accountDeletionScheduler.Stop()
+	}
 	singletonInstance = nil
 	PasswordResetEmailSentForTest = false
 	PasswordResetDataForTest = struct {
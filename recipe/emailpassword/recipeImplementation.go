@@ -16,12 +16,100 @@
 package emailpassword
 
 import (
+	"errors"
+
+	"github.com/supertokens/supertokens-golang/ingredients/emailpolicy"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/accountdeletion"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/accountlockout"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/api"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/emailchange"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/epclaims"
 	"github.com/supertokens/supertokens-golang/recipe/emailpassword/epmodels"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/passwordhistory"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/passwordmigration"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/passwordpolicy"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/resettoken"
+	"github.com/supertokens/supertokens-golang/recipe/emailverification"
+	"github.com/supertokens/supertokens-golang/recipe/emailverification/evclaims"
+	"github.com/supertokens/supertokens-golang/recipe/session"
 	"github.com/supertokens/supertokens-golang/supertokens"
 )
 
 func MakeRecipeImplementation(querier supertokens.Querier, getEmailPasswordConfig func() epmodels.TypeNormalisedInput) epmodels.RecipeInterface {
+	// recordPasswordChange is called after the password is changed in the core, so failures are
+	// only logged. The change is then missing from the password history, and PasswordExpiredClaim
+	// uses the previous change until the password changes again.
+	recordPasswordChange := func(userId string, password string, userContext supertokens.UserContext) {
+		config := getEmailPasswordConfig().PasswordHistory
+		if config == nil {
+			return
+		}
+		err := passwordhistory.RecordPasswordChange(*config, userId, password, userContext)
+		if err != nil {
+			supertokens.LogDebugMessage("could not record the password change of user " + userId + ": " + err.Error())
+			return
+		}
+		if config.MaxAge != 0 {
+			// so that existing sessions of the user refetch the claim
+			err = session.MarkClaimsAsStale(userId, []string{epclaims.PasswordExpiredClaim.Key}, userContext)
+			if err != nil {
+				supertokens.LogDebugMessage("could not mark the password expired claim as stale: " + err.Error())
+			}
+		}
+	}
+
+	checkPasswordReuse := func(userId string, password string, tenantId string, userContext supertokens.UserContext) (*epmodels.PasswordPolicyViolatedError, error) {
+		config := getEmailPasswordConfig().PasswordHistory
+		if config == nil {
+			return nil, nil
+		}
+		violation, err := passwordhistory.Check(*config, userId, password, tenantId, userContext)
+		if err != nil || violation == nil {
+			return nil, err
+		}
+		return &epmodels.PasswordPolicyViolatedError{
+			FailureReason: violation.Message,
+			Violations:    []passwordpolicy.Violation{*violation},
+		}, nil
+	}
+
+	normaliseEmail := func(email string, tenantId string) string {
+		return emailpolicy.Normalise(getEmailPasswordConfig().EmailPolicy, email, tenantId)
+	}
+
+	getUserByEmailInCore := func(email string, tenantId string, userContext supertokens.UserContext) (*epmodels.User, error) {
+		response, err := querier.SendGetRequest(tenantId+"/recipe/user", map[string]string{
+			"email": email,
+		}, userContext)
+		if err != nil {
+			return nil, err
+		}
+		status, ok := response["status"]
+		if ok && status.(string) == "OK" {
+			user, err := parseUser(response["user"])
+			if err != nil {
+				return nil, err
+			}
+			return user, nil
+		}
+		return nil, nil
+	}
+
 	signUp := func(email, password string, tenantId string, userContext supertokens.UserContext) (epmodels.SignUpResponse, error) {
+		if normalisedEmail := normaliseEmail(email, tenantId); normalisedEmail != email {
+			// users that signed up before the email policy was set are stored with the email as
+			// it was given, so they must not get a second account with the normalised email
+			existingUser, err := getUserByEmailInCore(email, tenantId, userContext)
+			if err != nil {
+				return epmodels.SignUpResponse{}, err
+			}
+			if existingUser != nil {
+				return epmodels.SignUpResponse{
+					EmailAlreadyExistsError: &struct{}{},
+				}, nil
+			}
+			email = normalisedEmail
+		}
 		response, err := querier.SendPostRequest(tenantId+"/recipe/signup", map[string]interface{}{
 			"email":    email,
 			"password": password,
@@ -35,6 +123,7 @@ func MakeRecipeImplementation(querier supertokens.Querier, getEmailPasswordConfi
 			if err != nil {
 				return epmodels.SignUpResponse{}, err
 			}
+			recordPasswordChange(user.ID, password, userContext)
 			return epmodels.SignUpResponse{
 				OK: &struct{ User epmodels.User }{User: *user},
 			}, nil
@@ -44,23 +133,46 @@ func MakeRecipeImplementation(querier supertokens.Querier, getEmailPasswordConfi
 		}, nil
 	}
 
+	var migrateLegacyPassword func(email, password string, tenantId string, userContext supertokens.UserContext) (*epmodels.User, error)
+
 	signIn := func(email, password string, tenantId string, userContext supertokens.UserContext) (epmodels.SignInResponse, error) {
-		response, err := querier.SendPostRequest(tenantId+"/recipe/signin", map[string]interface{}{
-			"email":    email,
-			"password": password,
-		}, userContext)
-		if err != nil {
-			return epmodels.SignInResponse{}, err
+		// users that signed up before the email policy was set are stored with the email as it was
+		// given, so that email is tried if the normalised one does not match
+		emails := []string{normaliseEmail(email, tenantId)}
+		if emails[0] != email {
+			emails = append(emails, email)
 		}
-		status, ok := response["status"]
-		if ok && status.(string) == "OK" {
-			user, err := parseUser(response["user"])
+		for _, emailInCore := range emails {
+			response, err := querier.SendPostRequest(tenantId+"/recipe/signin", map[string]interface{}{
+				"email":    emailInCore,
+				"password": password,
+			}, userContext)
 			if err != nil {
 				return epmodels.SignInResponse{}, err
 			}
-			return epmodels.SignInResponse{
-				OK: &struct{ User epmodels.User }{User: *user},
-			}, nil
+			status, ok := response["status"]
+			if ok && status.(string) == "OK" {
+				user, err := parseUser(response["user"])
+				if err != nil {
+					return epmodels.SignInResponse{}, err
+				}
+				return epmodels.SignInResponse{
+					OK: &struct{ User epmodels.User }{User: *user},
+				}, nil
+			}
+		}
+		email = emails[0]
+
+		if getEmailPasswordConfig().PasswordMigration != nil {
+			user, err := migrateLegacyPassword(email, password, tenantId, userContext)
+			if err != nil {
+				return epmodels.SignInResponse{}, err
+			}
+			if user != nil {
+				return epmodels.SignInResponse{
+					OK: &struct{ User epmodels.User }{User: *user},
+				}, nil
+			}
 		}
 		return epmodels.SignInResponse{
 			WrongCredentialsError: &struct{}{},
@@ -86,21 +198,93 @@ func MakeRecipeImplementation(querier supertokens.Querier, getEmailPasswordConfi
 	}
 
 	getUserByEmail := func(email string, tenantId string, userContext supertokens.UserContext) (*epmodels.User, error) {
-		response, err := querier.SendGetRequest(tenantId+"/recipe/user", map[string]string{
-			"email": email,
-		}, userContext)
+		normalisedEmail := normaliseEmail(email, tenantId)
+		user, err := getUserByEmailInCore(normalisedEmail, tenantId, userContext)
+		if err != nil || user != nil || normalisedEmail == email {
+			return user, err
+		}
+		// the user may have signed up before the email policy was set
+		return getUserByEmailInCore(email, tenantId, userContext)
+	}
+
+	// migrateLegacyPassword is called when the core rejects the password. If the password matches
+	// the legacy hash of the email, it is stored by the core (creating the user if it was not
+	// imported) and the legacy hash is removed. It returns nil if the password does not match.
+	migrateLegacyPassword = func(email, password string, tenantId string, userContext supertokens.UserContext) (*epmodels.User, error) {
+		config := *getEmailPasswordConfig().PasswordMigration
+		matches, err := passwordmigration.VerifyLegacyPassword(config, tenantId, email, password, userContext)
+		if err != nil || !matches {
+			return nil, err
+		}
+
+		user, err := getUserByEmail(email, tenantId, userContext)
 		if err != nil {
 			return nil, err
 		}
-		status, ok := response["status"]
-		if ok && status.(string) == "OK" {
-			user, err := parseUser(response["user"])
+		if user == nil {
+			signUpResponse, err := signUp(email, password, tenantId, userContext)
 			if err != nil {
 				return nil, err
 			}
-			return user, nil
+			if signUpResponse.OK == nil {
+				return nil, nil
+			}
+			user = &signUpResponse.OK.User
+		} else {
+			// the password policy is not applied, since the user must be able to keep signing in
+			// with the password they had in the legacy system
+			response, err := querier.SendPutRequest("/recipe/user", map[string]interface{}{
+				"userId":   user.ID,
+				"password": password,
+			}, userContext)
+			if err != nil {
+				return nil, err
+			}
+			if response["status"].(string) != "OK" {
+				return nil, nil
+			}
+			recordPasswordChange(user.ID, password, userContext)
 		}
-		return nil, nil
+
+		err = passwordmigration.MarkMigrated(config, tenantId, email, userContext)
+		if err != nil {
+			return nil, err
+		}
+		supertokens.LogDebugMessage("passwordmigration: migrated the password of user " + user.ID)
+		return user, nil
+	}
+
+	// removeLegacyPasswordHashes removes the legacy hashes of the user in all its tenants once the
+	// password or the email is changed, so that the old password can no longer be used
+	removeLegacyPasswordHashes := func(user epmodels.User, userContext supertokens.UserContext) error {
+		config := getEmailPasswordConfig().PasswordMigration
+		if config == nil {
+			return nil
+		}
+		for _, tenantId := range user.TenantIds {
+			hasLegacyHash, err := passwordmigration.HasLegacyHash(*config, tenantId, user.Email, userContext)
+			if err != nil {
+				return err
+			}
+			if hasLegacyHash {
+				err = passwordmigration.MarkMigrated(*config, tenantId, user.Email, userContext)
+				if err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+
+	removeLegacyPasswordHashesOfUser := func(userId string, userContext supertokens.UserContext) error {
+		if getEmailPasswordConfig().PasswordMigration == nil {
+			return nil
+		}
+		user, err := getUserByID(userId, userContext)
+		if err != nil || user == nil {
+			return err
+		}
+		return removeLegacyPasswordHashes(*user, userContext)
 	}
 
 	createResetPasswordToken := func(userID string, tenantId string, userContext supertokens.UserContext) (epmodels.CreateResetPasswordTokenResponse, error) {
@@ -112,8 +296,16 @@ func MakeRecipeImplementation(querier supertokens.Querier, getEmailPasswordConfi
 		}
 		status, ok := response["status"]
 		if ok && status.(string) == "OK" {
+			token := response["token"].(string)
+			config := getEmailPasswordConfig()
+			if config.PasswordHistory != nil || config.PasswordPolicy != nil {
+				err = resettoken.SaveToken(config.ResetPasswordTokens, token, userID, tenantId, userContext)
+				if err != nil {
+					return epmodels.CreateResetPasswordTokenResponse{}, err
+				}
+			}
 			return epmodels.CreateResetPasswordTokenResponse{
-				OK: &struct{ Token string }{Token: response["token"].(string)},
+				OK: &struct{ Token string }{Token: token},
 			}, nil
 		}
 		return epmodels.CreateResetPasswordTokenResponse{
@@ -121,7 +313,88 @@ func MakeRecipeImplementation(querier supertokens.Querier, getEmailPasswordConfi
 		}, nil
 	}
 
+	unlockAccount := func(email string, tenantId string, userContext supertokens.UserContext) error {
+		config := getEmailPasswordConfig().AccountLockout
+		if config == nil {
+			return nil
+		}
+		return accountlockout.Reset(*config, tenantId, normaliseEmail(email, tenantId), userContext)
+	}
+
+	// unlockAccountsOfUser unlocks the account of the user in all its tenants
+	unlockAccountsOfUser := func(userId string, userContext supertokens.UserContext) error {
+		if getEmailPasswordConfig().AccountLockout == nil {
+			return nil
+		}
+		user, err := getUserByID(userId, userContext)
+		if err != nil || user == nil {
+			return err
+		}
+		for _, tenantId := range user.TenantIds {
+			err = unlockAccount(user.Email, tenantId, userContext)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	// checkPasswordPolicyForUser checks the password against the password policy with the email of
+	// the user, for the callers that could not pass it to the validation of the form fields
+	checkPasswordPolicyForUser := func(userId string, password string, tenantId string, userContext supertokens.UserContext) (*epmodels.PasswordPolicyViolatedError, error) {
+		config := getEmailPasswordConfig().PasswordPolicy
+		if config == nil {
+			return nil, nil
+		}
+		user, err := getUserByID(userId, userContext)
+		if err != nil || user == nil {
+			return nil, err
+		}
+		violations := passwordpolicy.Validate(*config, password, []string{user.Email}, tenantId, userContext)
+		if len(violations) == 0 {
+			return nil, nil
+		}
+		return &epmodels.PasswordPolicyViolatedError{
+			FailureReason: passwordpolicy.GetFailureReason(violations),
+			Violations:    violations,
+		}, nil
+	}
+
+	// checkPasswordForResetToken checks the new password against the password history and the user
+	// info rules of the password policy before the core uses the token, so that the user can try
+	// again with the same link. The user of the token is only known if the token was created by
+	// this backend and kept in the ResetPasswordTokens store.
+	checkPasswordForResetToken := func(token, newPassword string, tenantId string, userContext supertokens.UserContext) (*epmodels.PasswordPolicyViolatedError, error) {
+		config := getEmailPasswordConfig()
+		if config.PasswordHistory == nil && config.PasswordPolicy == nil {
+			return nil, nil
+		}
+		userId, err := resettoken.GetUserId(config.ResetPasswordTokens, tenantId, token, userContext)
+		if err != nil {
+			return nil, err
+		}
+		if userId == nil {
+			supertokens.LogDebugMessage("resetPasswordUsingToken: the user of the token is not known, so the password is not checked against the password history and the user info")
+			return nil, nil
+		}
+		violatedError, err := checkPasswordPolicyForUser(*userId, newPassword, tenantId, userContext)
+		if err != nil || violatedError != nil {
+			return violatedError, err
+		}
+		return checkPasswordReuse(*userId, newPassword, tenantId, userContext)
+	}
+
 	resetPasswordUsingToken := func(token, newPassword string, tenantId string, userContext supertokens.UserContext) (epmodels.ResetPasswordUsingTokenResponse, error) {
+		violatedError, err := checkPasswordForResetToken(token, newPassword, tenantId, userContext)
+		if err != nil {
+			return epmodels.ResetPasswordUsingTokenResponse{}, err
+		}
+		if violatedError != nil {
+			return epmodels.ResetPasswordUsingTokenResponse{
+				PasswordPolicyViolatedError: violatedError,
+			}, nil
+		}
+
 		response, err := querier.SendPostRequest(tenantId+"/recipe/user/password/reset", map[string]interface{}{
 			"method":      "token",
 			"token":       token,
@@ -136,6 +409,15 @@ func MakeRecipeImplementation(querier supertokens.Querier, getEmailPasswordConfi
 			if ok {
 				// using CDI >= 2.12
 				userIdStr := userId.(string)
+				recordPasswordChange(userIdStr, newPassword, userContext)
+				err = unlockAccountsOfUser(userIdStr, userContext)
+				if err != nil {
+					return epmodels.ResetPasswordUsingTokenResponse{}, err
+				}
+				err = removeLegacyPasswordHashesOfUser(userIdStr, userContext)
+				if err != nil {
+					return epmodels.ResetPasswordUsingTokenResponse{}, err
+				}
 				return epmodels.ResetPasswordUsingTokenResponse{
 					OK: &struct {
 						UserId *string
@@ -163,7 +445,7 @@ func MakeRecipeImplementation(querier supertokens.Querier, getEmailPasswordConfi
 			"userId": userId,
 		}
 		if email != nil {
-			requestBody["email"] = email
+			requestBody["email"] = normaliseEmail(*email, tenantIdForPasswordPolicy)
 		}
 		if password != nil {
 			if applyPasswordPolicy == nil || *applyPasswordPolicy {
@@ -179,15 +461,67 @@ func MakeRecipeImplementation(querier supertokens.Querier, getEmailPasswordConfi
 						}
 					}
 				}
+
+				config := getEmailPasswordConfig()
+				if config.PasswordPolicy != nil || config.BreachedPasswordCheck != nil {
+					userInputs := []string{}
+					if email != nil {
+						userInputs = append(userInputs, *email)
+					} else {
+						user, err := getUserByID(userId, userContext)
+						if err != nil {
+							return epmodels.UpdateEmailOrPasswordResponse{}, err
+						}
+						if user != nil {
+							userInputs = append(userInputs, user.Email)
+						}
+					}
+					violations := api.ValidatePassword(config, *password, userInputs, tenantIdForPasswordPolicy, userContext)
+					if len(violations) > 0 {
+						errResponse := epmodels.PasswordPolicyViolatedError{
+							FailureReason: passwordpolicy.GetFailureReason(violations),
+							Violations:    violations,
+						}
+						return epmodels.UpdateEmailOrPasswordResponse{PasswordPolicyViolatedError: &errResponse}, nil
+					}
+				}
+
+				violatedError, err := checkPasswordReuse(userId, *password, tenantIdForPasswordPolicy, userContext)
+				if err != nil {
+					return epmodels.UpdateEmailOrPasswordResponse{}, err
+				}
+				if violatedError != nil {
+					return epmodels.UpdateEmailOrPasswordResponse{PasswordPolicyViolatedError: violatedError}, nil
+				}
 			}
 			requestBody["password"] = password
 		}
+
+		// the legacy hashes are keyed by the email, so the user is fetched before it changes
+		var userBeforeUpdate *epmodels.User
+		if getEmailPasswordConfig().PasswordMigration != nil {
+			user, err := getUserByID(userId, userContext)
+			if err != nil {
+				return epmodels.UpdateEmailOrPasswordResponse{}, err
+			}
+			userBeforeUpdate = user
+		}
+
 		response, err := querier.SendPutRequest("/recipe/user", requestBody, userContext)
 		if err != nil {
 			return epmodels.UpdateEmailOrPasswordResponse{}, nil
 		}
 
 		if response["status"].(string) == "OK" {
+			if password != nil {
+				recordPasswordChange(userId, *password, userContext)
+			}
+			if userBeforeUpdate != nil {
+				err = removeLegacyPasswordHashes(*userBeforeUpdate, userContext)
+				if err != nil {
+					return epmodels.UpdateEmailOrPasswordResponse{}, err
+				}
+			}
 			return epmodels.UpdateEmailOrPasswordResponse{
 				OK: &struct{}{},
 			}, nil
@@ -201,6 +535,164 @@ func MakeRecipeImplementation(querier supertokens.Querier, getEmailPasswordConfi
 			}, nil
 		}
 	}
+
+	// The pending email change is kept in the store of the email change feature, so that its token
+	// can only be used by ConsumeEmailChangeToken
+	createEmailChangeToken := func(userId string, newEmail string, tenantId string, userContext supertokens.UserContext) (epmodels.CreateEmailChangeTokenResponse, error) {
+		config := getEmailPasswordConfig().EmailChangeFeature
+		if config == nil {
+			return epmodels.CreateEmailChangeTokenResponse{}, errors.New("email change is not enabled. Please set EmailChangeFeature in the emailpassword config")
+		}
+		newEmail = normaliseEmail(newEmail, tenantId)
+		user, err := getUserByID(userId, userContext)
+		if err != nil {
+			return epmodels.CreateEmailChangeTokenResponse{}, err
+		}
+		if user == nil {
+			return epmodels.CreateEmailChangeTokenResponse{
+				UnknownUserIdError: &struct{}{},
+			}, nil
+		}
+
+		token, err := emailchange.CreateToken(config.PendingChanges, userId, tenantId, newEmail, userContext)
+		if err != nil {
+			return epmodels.CreateEmailChangeTokenResponse{}, err
+		}
+		return epmodels.CreateEmailChangeTokenResponse{
+			OK: &struct{ Token string }{
+				Token: token,
+			},
+		}, nil
+	}
+
+	// markEmailAsVerified verifies the new email once the user opened the link sent to it
+	markEmailAsVerified := func(userId string, email string, tenantId string, userContext supertokens.UserContext) error {
+		if emailverification.GetRecipeInstance() == nil {
+			return nil
+		}
+		tokenResponse, err := emailverification.CreateEmailVerificationToken(tenantId, userId, &email, userContext)
+		if err != nil {
+			return err
+		}
+		if tokenResponse.EmailAlreadyVerifiedError != nil {
+			return nil
+		}
+		_, err = emailverification.VerifyEmailUsingToken(tenantId, tokenResponse.OK.Token, userContext)
+		return err
+	}
+
+	consumeEmailChangeToken := func(token string, tenantId string, userContext supertokens.UserContext) (epmodels.ConsumeEmailChangeTokenResponse, error) {
+		config := getEmailPasswordConfig().EmailChangeFeature
+		if config == nil {
+			return epmodels.ConsumeEmailChangeTokenResponse{}, errors.New("email change is not enabled. Please set EmailChangeFeature in the emailpassword config")
+		}
+		change, err := emailchange.ConsumeToken(config.PendingChanges, tenantId, token, userContext)
+		if err != nil {
+			return epmodels.ConsumeEmailChangeTokenResponse{}, err
+		}
+		if change == nil {
+			return epmodels.ConsumeEmailChangeTokenResponse{
+				InvalidTokenError: &struct{}{},
+			}, nil
+		}
+		userId := change.UserId
+		newEmail := change.NewEmail
+
+		user, err := getUserByID(userId, userContext)
+		if err != nil {
+			return epmodels.ConsumeEmailChangeTokenResponse{}, err
+		}
+		if user == nil {
+			return epmodels.ConsumeEmailChangeTokenResponse{
+				InvalidTokenError: &struct{}{},
+			}, nil
+		}
+		previousEmail := user.Email
+
+		if previousEmail != newEmail {
+			updateResponse, err := updateEmailOrPassword(userId, &newEmail, nil, nil, tenantId, userContext)
+			if err != nil {
+				return epmodels.ConsumeEmailChangeTokenResponse{}, err
+			}
+			if updateResponse.EmailAlreadyExistsError != nil {
+				return epmodels.ConsumeEmailChangeTokenResponse{
+					EmailAlreadyExistsError: &struct{}{},
+				}, nil
+			} else if updateResponse.OK == nil {
+				return epmodels.ConsumeEmailChangeTokenResponse{
+					InvalidTokenError: &struct{}{},
+				}, nil
+			}
+			user.Email = newEmail
+		}
+
+		// the email is already changed in the core, so the call must not fail from here on
+		err = markEmailAsVerified(userId, newEmail, tenantId, userContext)
+		if err != nil {
+			supertokens.LogDebugMessage("could not mark the new email as verified: " + err.Error())
+		}
+		if previousEmail != newEmail && emailverification.GetRecipeInstance() != nil {
+			// so that existing sessions of the user refetch the claim for the new email
+			err = session.MarkClaimsAsStale(userId, []string{evclaims.EmailVerificationClaim.Key}, userContext)
+			if err != nil {
+				supertokens.LogDebugMessage("could not mark the email verification claim as stale: " + err.Error())
+			}
+		}
+
+		return epmodels.ConsumeEmailChangeTokenResponse{
+			OK: &struct {
+				User          epmodels.User
+				PreviousEmail string
+			}{
+				User:          *user,
+				PreviousEmail: previousEmail,
+			},
+		}, nil
+	}
+
+	scheduleAccountDeletion := func(userId string, tenantId string, userContext supertokens.UserContext) (epmodels.ScheduleAccountDeletionResponse, error) {
+		config := getEmailPasswordConfig().AccountDeletion
+		if config == nil {
+			return epmodels.ScheduleAccountDeletionResponse{}, errors.New("account deletion is not enabled. Please set AccountDeletion in the emailpassword config")
+		}
+		user, err := getUserByID(userId, userContext)
+		if err != nil {
+			return epmodels.ScheduleAccountDeletionResponse{}, err
+		}
+		if user == nil {
+			return epmodels.ScheduleAccountDeletionResponse{
+				UnknownUserIdError: &struct{}{},
+			}, nil
+		}
+
+		job, err := accountdeletion.Schedule(*config, userId, tenantId, userContext)
+		if err != nil {
+			return epmodels.ScheduleAccountDeletionResponse{}, err
+		}
+
+		// the user stays signed out until the deletion is cancelled
+		_, err = session.RevokeAllSessionsForUser(userId, nil, userContext)
+		if err != nil {
+			return epmodels.ScheduleAccountDeletionResponse{}, err
+		}
+
+		return epmodels.ScheduleAccountDeletionResponse{
+			OK: &struct {
+				DeleteAt int64
+			}{
+				DeleteAt: job.DeleteAt,
+			},
+		}, nil
+	}
+
+	cancelAccountDeletion := func(userId string, userContext supertokens.UserContext) error {
+		config := getEmailPasswordConfig().AccountDeletion
+		if config == nil {
+			return nil
+		}
+		return accountdeletion.Cancel(*config, userId, userContext)
+	}
+
 	return epmodels.RecipeInterface{
 		SignUp:                   &signUp,
 		SignIn:                   &signIn,
@@ -209,5 +701,10 @@ func MakeRecipeImplementation(querier supertokens.Querier, getEmailPasswordConfi
 		CreateResetPasswordToken: &createResetPasswordToken,
 		ResetPasswordUsingToken:  &resetPasswordUsingToken,
 		UpdateEmailOrPassword:    &updateEmailOrPassword,
+		UnlockAccount:            &unlockAccount,
+		CreateEmailChangeToken:   &createEmailChangeToken,
+		ConsumeEmailChangeToken:  &consumeEmailChangeToken,
+		ScheduleAccountDeletion:  &scheduleAccountDeletion,
+		CancelAccountDeletion:    &cancelAccountDeletion,
 	}
 }
@@ -0,0 +1,76 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package resettoken
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func NormaliseTypeInput(input TypeInput) (TypeNormalisedInput, error) {
+	result := TypeNormalisedInput{
+		TokenLifetime: time.Hour,
+		Store:         MakeInMemoryStore(),
+	}
+	if input.TokenLifetime != nil {
+		if *input.TokenLifetime <= 0 {
+			return TypeNormalisedInput{}, errors.New("TokenLifetime must be greater than 0")
+		}
+		result.TokenLifetime = *input.TokenLifetime
+	}
+	if input.Store != nil {
+		result.Store = *input.Store
+	}
+	return result, nil
+}
+
+// SaveToken remembers the user of a password reset token created by the core, so that the new
+// password can be checked before the token is used
+func SaveToken(config TypeNormalisedInput, token string, userId string, tenantId string, userContext supertokens.UserContext) error {
+	return config.Store.Save(hashToken(token), ResetToken{
+		UserId:    userId,
+		TenantId:  tenantId,
+		ExpiresAt: getCurrentTimeInMS() + config.TokenLifetime.Milliseconds(),
+	}, userContext)
+}
+
+// GetUserId returns the user of a password reset token. It returns nil if the token is unknown,
+// has expired or was created in another tenant. The token is still checked by the core when it is
+// used, so nil does not mean that the token is invalid.
+func GetUserId(config TypeNormalisedInput, tenantId string, token string, userContext supertokens.UserContext) (*string, error) {
+	resetToken, err := config.Store.Get(hashToken(token), userContext)
+	if err != nil || resetToken == nil {
+		return nil, err
+	}
+	if resetToken.TenantId != tenantId || resetToken.ExpiresAt < getCurrentTimeInMS() {
+		return nil, nil
+	}
+	return &resetToken.UserId, nil
+}
+
+func hashToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+func getCurrentTimeInMS() int64 {
+	return time.Now().UnixNano() / int64(time.Millisecond)
+}
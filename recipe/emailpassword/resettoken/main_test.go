@@ -0,0 +1,65 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package resettoken
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSaveAndGetToken(t *testing.T) {
+	config, err := NormaliseTypeInput(TypeInput{})
+	assert.NoError(t, err)
+	userContext := &map[string]interface{}{}
+
+	assert.NoError(t, SaveToken(config, "token", "userId", "public", userContext))
+
+	userId, err := GetUserId(config, "public", "unknown", userContext)
+	assert.NoError(t, err)
+	assert.Nil(t, userId)
+
+	userId, err = GetUserId(config, "public", "token", userContext)
+	assert.NoError(t, err)
+	assert.NotNil(t, userId)
+	assert.Equal(t, "userId", *userId)
+
+	userId, err = GetUserId(config, "tenant1", "token", userContext)
+	assert.NoError(t, err)
+	assert.Nil(t, userId)
+}
+
+func TestExpiredTokensAreIgnored(t *testing.T) {
+	tokenLifetime := time.Millisecond
+	config, err := NormaliseTypeInput(TypeInput{TokenLifetime: &tokenLifetime})
+	assert.NoError(t, err)
+	userContext := &map[string]interface{}{}
+
+	assert.NoError(t, SaveToken(config, "token", "userId", "public", userContext))
+	time.Sleep(5 * time.Millisecond)
+
+	userId, err := GetUserId(config, "public", "token", userContext)
+	assert.NoError(t, err)
+	assert.Nil(t, userId)
+}
+
+func TestConfigValidation(t *testing.T) {
+	tokenLifetime := time.Duration(0)
+	_, err := NormaliseTypeInput(TypeInput{TokenLifetime: &tokenLifetime})
+	assert.Error(t, err)
+}
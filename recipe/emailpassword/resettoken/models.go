@@ -0,0 +1,52 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package resettoken
+
+import (
+	"time"
+
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+// ResetToken is a password reset token created by the core for a user
+type ResetToken struct {
+	UserId   string `json:"userId"`
+	TenantId string `json:"tenantId"`
+	// ExpiresAt is the time (in milliseconds) after which the core no longer accepts the token
+	ExpiresAt int64 `json:"expiresAt"`
+}
+
+// Store keeps the password reset tokens by the SHA-256 hash of the token, so that the tokens
+// cannot be used by someone who can read the store.
+type Store struct {
+	Save func(tokenHash string, token ResetToken, userContext supertokens.UserContext) error
+	// Get returns nil if there is no token for the hash
+	Get func(tokenHash string, userContext supertokens.UserContext) (*ResetToken, error)
+}
+
+type TypeInput struct {
+	// TokenLifetime defaults to 1 hour and should be the password reset token lifetime of the core
+	TokenLifetime *time.Duration
+	// Store defaults to an in-memory store, which is not shared between instances of the
+	// backend and loses the tokens when the process exits
+	Store *Store
+}
+
+type TypeNormalisedInput struct {
+	TokenLifetime time.Duration
+	Store         Store
+}
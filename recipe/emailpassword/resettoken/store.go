@@ -0,0 +1,56 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package resettoken
+
+import (
+	"sync"
+
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+// MakeInMemoryStore keeps the password reset tokens in memory. They are lost when the process
+// restarts and are not shared between instances, so a shared store should be used in production
+// if the backend runs on more than one instance.
+func MakeInMemoryStore() Store {
+	var mutex sync.Mutex
+	tokens := map[string]ResetToken{}
+
+	return Store{
+		Save: func(tokenHash string, token ResetToken, userContext supertokens.UserContext) error {
+			mutex.Lock()
+			defer mutex.Unlock()
+			// expired tokens are removed here, since nothing else would remove unused tokens
+			now := getCurrentTimeInMS()
+			for hash, existing := range tokens {
+				if existing.ExpiresAt < now {
+					delete(tokens, hash)
+				}
+			}
+			tokens[tokenHash] = token
+			return nil
+		},
+		Get: func(tokenHash string, userContext supertokens.UserContext) (*ResetToken, error) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			token, ok := tokens[tokenHash]
+			if !ok {
+				return nil, nil
+			}
+			return &token, nil
+		},
+	}
+}
@@ -0,0 +1,50 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package emailpassword
+
+import (
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/accountdeletion"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func (r *Recipe) getTenantIdsForUser(userId string, userContext supertokens.UserContext) ([]string, error) {
+	user, err := (*r.RecipeImpl.GetUserByID)(userId, userContext)
+	if err != nil || user == nil {
+		return nil, err
+	}
+	return user.TenantIds, nil
+}
+
+func (r *Recipe) exportUserData(userId string, tenantIds []string, userContext supertokens.UserContext) (interface{}, error) {
+	user, err := (*r.RecipeImpl.GetUserByID)(userId, userContext)
+	if err != nil || user == nil {
+		return nil, err
+	}
+	data := map[string]interface{}{
+		"user": user,
+	}
+	if r.Config.AccountDeletion != nil {
+		job, err := accountdeletion.GetScheduledDeletion(*r.Config.AccountDeletion, userId, userContext)
+		if err != nil {
+			return nil, err
+		}
+		if job != nil {
+			data["scheduledDeletion"] = job
+		}
+	}
+	return data, nil
+}
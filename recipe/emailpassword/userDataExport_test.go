@@ -0,0 +1,79 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package emailpassword
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/emailverification"
+	"github.com/supertokens/supertokens-golang/recipe/emailverification/evmodels"
+	"github.com/supertokens/supertokens-golang/recipe/session"
+	"github.com/supertokens/supertokens-golang/recipe/usermetadata"
+	"github.com/supertokens/supertokens-golang/recipe/userroles"
+	"github.com/supertokens/supertokens-golang/supertokens"
+	"github.com/supertokens/supertokens-golang/test/unittesting"
+)
+
+func TestExportUserData(t *testing.T) {
+	BeforeEach()
+	connectionURI := unittesting.StartUpST("localhost", "8080")
+	defer AfterEach()
+
+	testServer := supertokensInitForTest(t, connectionURI,
+		Init(nil),
+		emailverification.Init(evmodels.TypeInput{Mode: evmodels.ModeOptional}),
+		usermetadata.Init(nil),
+		userroles.Init(nil),
+		session.Init(nil),
+	)
+	defer testServer.Close()
+
+	resp, err := unittesting.SignupRequest("test@example.com", "validpass123", testServer.URL)
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	user, err := GetUserByEmail("public", "test@example.com")
+	assert.NoError(t, err)
+
+	_, err = usermetadata.UpdateUserMetadata(user.ID, map[string]interface{}{"theme": "dark"})
+	assert.NoError(t, err)
+	_, err = userroles.CreateNewRoleOrAddPermissions("admin", []string{"read", "write"})
+	assert.NoError(t, err)
+	_, err = userroles.AddRoleToUser("public", user.ID, "admin")
+	assert.NoError(t, err)
+
+	result, err := supertokens.ExportUserData(user.ID)
+	assert.NoError(t, err)
+	assert.Nil(t, result.UserIdMapping)
+	assert.Equal(t, []string{"public"}, result.TenantIds)
+
+	jsonResult, err := json.Marshal(result)
+	assert.NoError(t, err)
+	var document map[string]interface{}
+	assert.NoError(t, json.Unmarshal(jsonResult, &document))
+	recipes := document["recipes"].(map[string]interface{})
+
+	assert.Equal(t, "test@example.com", recipes["emailpassword"].(map[string]interface{})["user"].(map[string]interface{})["email"])
+	assert.Equal(t, false, recipes["emailverification"].(map[string]interface{})["isVerified"])
+	assert.Equal(t, map[string]interface{}{"theme": "dark"}, recipes["usermetadata"].(map[string]interface{})["metadata"])
+	roles := recipes["userroles"].(map[string]interface{})["roles"].(map[string]interface{})["public"].([]interface{})
+	assert.Equal(t, "admin", roles[0].(map[string]interface{})["role"])
+	assert.ElementsMatch(t, []interface{}{"read", "write"}, roles[0].(map[string]interface{})["permissions"])
+	sessions := recipes["session"].(map[string]interface{})["sessions"].([]interface{})
+	assert.Len(t, sessions, 1)
+}
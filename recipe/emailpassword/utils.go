@@ -21,12 +21,21 @@ import (
 	"regexp"
 
 	"github.com/supertokens/supertokens-golang/ingredients/emaildelivery"
+	"github.com/supertokens/supertokens-golang/ingredients/emailpolicy"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/accountdeletion"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/accountlockout"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/breachedpassword"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/emailchange"
 	"github.com/supertokens/supertokens-golang/recipe/emailpassword/emaildelivery/backwardCompatibilityService"
 	"github.com/supertokens/supertokens-golang/recipe/emailpassword/epmodels"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/passwordhistory"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/passwordmigration"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/passwordpolicy"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/resettoken"
 	"github.com/supertokens/supertokens-golang/supertokens"
 )
 
-func validateAndNormaliseUserInput(recipeInstance *Recipe, appInfo supertokens.NormalisedAppinfo, config *epmodels.TypeInput) epmodels.TypeNormalisedInput {
+func validateAndNormaliseUserInput(recipeInstance *Recipe, appInfo supertokens.NormalisedAppinfo, config *epmodels.TypeInput) (epmodels.TypeNormalisedInput, error) {
 
 	typeNormalisedInput := makeTypeNormalisedInput(recipeInstance)
 
@@ -35,6 +44,113 @@ func validateAndNormaliseUserInput(recipeInstance *Recipe, appInfo supertokens.N
 		typeNormalisedInput.ResetPasswordUsingTokenFeature = validateAndNormaliseResetPasswordUsingTokenConfig(typeNormalisedInput.SignUpFeature)
 	}
 
+	if config != nil && config.PasswordPolicy != nil {
+		passwordPolicy, err := passwordpolicy.NormaliseTypeInput(*config.PasswordPolicy)
+		if err != nil {
+			return epmodels.TypeNormalisedInput{}, err
+		}
+		typeNormalisedInput.PasswordPolicy = &passwordPolicy
+
+		// The policy is checked separately (with the email of the user), so the default
+		// validator must not reject passwords that the policy allows
+		if !hasCustomPasswordValidator(config.SignUpFeature) {
+			for i := range typeNormalisedInput.SignUpFeature.FormFields {
+				if typeNormalisedInput.SignUpFeature.FormFields[i].ID == "password" {
+					typeNormalisedInput.SignUpFeature.FormFields[i].Validate = defaultValidator
+				}
+			}
+			typeNormalisedInput.ResetPasswordUsingTokenFeature = validateAndNormaliseResetPasswordUsingTokenConfig(typeNormalisedInput.SignUpFeature)
+		}
+	}
+
+	if config != nil && config.BreachedPasswordCheck != nil {
+		breachedPasswordCheck, err := breachedpassword.NormaliseTypeInput(*config.BreachedPasswordCheck)
+		if err != nil {
+			return epmodels.TypeNormalisedInput{}, err
+		}
+		typeNormalisedInput.BreachedPasswordCheck = &breachedPasswordCheck
+	}
+
+	if config != nil && config.PasswordHistory != nil {
+		passwordHistory, err := passwordhistory.NormaliseTypeInput(*config.PasswordHistory)
+		if err != nil {
+			return epmodels.TypeNormalisedInput{}, err
+		}
+		typeNormalisedInput.PasswordHistory = &passwordHistory
+	}
+
+	if config != nil && config.AccountLockout != nil {
+		accountLockout, err := accountlockout.NormaliseTypeInput(*config.AccountLockout)
+		if err != nil {
+			return epmodels.TypeNormalisedInput{}, err
+		}
+		typeNormalisedInput.AccountLockout = &accountLockout
+	}
+
+	if config != nil && config.EmailChangeFeature != nil {
+		pendingChangesInput := emailchange.TypeInput{}
+		if config.EmailChangeFeature.PendingChanges != nil {
+			pendingChangesInput = *config.EmailChangeFeature.PendingChanges
+		}
+		pendingChanges, err := emailchange.NormaliseTypeInput(pendingChangesInput)
+		if err != nil {
+			return epmodels.TypeNormalisedInput{}, err
+		}
+		typeNormalisedInput.EmailChangeFeature = &epmodels.TypeNormalisedInputEmailChangeFeature{
+			NotifyPreviousEmail: config.EmailChangeFeature.NotifyPreviousEmail,
+			PendingChanges:      pendingChanges,
+		}
+	}
+
+	if config != nil && config.PasswordChangeFeature != nil {
+		typeNormalisedInput.PasswordChangeFeature = &epmodels.TypeNormalisedInputPasswordChangeFeature{
+			FormFields: []epmodels.NormalisedFormField{{
+				ID:       "oldPassword",
+				Validate: defaultValidator,
+				Optional: false,
+			}, {
+				ID:       "newPassword",
+				Validate: defaultValidator,
+				Optional: false,
+			}},
+			RevokeOtherSessions: config.PasswordChangeFeature.RevokeOtherSessions,
+		}
+	}
+
+	if config != nil && config.AccountDeletion != nil {
+		accountDeletion, err := accountdeletion.NormaliseTypeInput(*config.AccountDeletion)
+		if err != nil {
+			return epmodels.TypeNormalisedInput{}, err
+		}
+		typeNormalisedInput.AccountDeletion = &accountDeletion
+	}
+
+	if config != nil && config.PasswordMigration != nil {
+		passwordMigration, err := passwordmigration.NormaliseTypeInput(*config.PasswordMigration)
+		if err != nil {
+			return epmodels.TypeNormalisedInput{}, err
+		}
+		typeNormalisedInput.PasswordMigration = &passwordMigration
+	}
+
+	if config != nil && config.EmailPolicy != nil {
+		emailPolicy, err := emailpolicy.NormaliseTypeInput(*config.EmailPolicy)
+		if err != nil {
+			return epmodels.TypeNormalisedInput{}, err
+		}
+		typeNormalisedInput.EmailPolicy = &emailPolicy
+	}
+
+	resetPasswordTokensInput := resettoken.TypeInput{}
+	if config != nil && config.ResetPasswordTokens != nil {
+		resetPasswordTokensInput = *config.ResetPasswordTokens
+	}
+	resetPasswordTokens, err := resettoken.NormaliseTypeInput(resetPasswordTokensInput)
+	if err != nil {
+		return epmodels.TypeNormalisedInput{}, err
+	}
+	typeNormalisedInput.ResetPasswordTokens = resetPasswordTokens
+
 	// we must call this after validateAndNormaliseSignupConfig
 	typeNormalisedInput.SignInFeature = validateAndNormaliseSignInConfig(typeNormalisedInput.SignUpFeature)
 
@@ -66,7 +182,19 @@ func validateAndNormaliseUserInput(recipeInstance *Recipe, appInfo supertokens.N
 		}
 	}
 
-	return typeNormalisedInput
+	return typeNormalisedInput, nil
+}
+
+func hasCustomPasswordValidator(config *epmodels.TypeInputSignUp) bool {
+	if config == nil {
+		return false
+	}
+	for _, formField := range config.FormFields {
+		if formField.ID == "password" && formField.Validate != nil {
+			return true
+		}
+	}
+	return false
 }
 
 func makeTypeNormalisedInput(recipeInstance *Recipe) epmodels.TypeNormalisedInput {
@@ -26,6 +26,14 @@ import (
 func MakeSMTPService(config emaildelivery.SMTPServiceConfig) *emaildelivery.EmailDeliveryInterface {
 	serviceImpl := MakeServiceImplementation(config.Settings)
 
+	if config.Templates != nil {
+		templates := *config.Templates
+		getContent := func(input emaildelivery.EmailType, userContext supertokens.UserContext) (emaildelivery.EmailContent, error) {
+			return emaildelivery.GetContentFromTemplates(templates, input, userContext)
+		}
+		serviceImpl.GetContent = &getContent
+	}
+
 	if config.Override != nil {
 		serviceImpl = config.Override(serviceImpl)
 	}
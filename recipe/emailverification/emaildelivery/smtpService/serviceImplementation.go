@@ -28,16 +28,20 @@ func MakeServiceImplementation(settings emaildelivery.SMTPSettings) emaildeliver
 		return emaildelivery.SendSMTPEmail(settings, input)
 	}
 
-	getContent := func(input emaildelivery.EmailType, userContext supertokens.UserContext) (emaildelivery.EmailContent, error) {
-		if input.EmailVerification != nil {
-			return getEmailVerifyEmailContent(*input.EmailVerification)
-		} else {
-			return emaildelivery.EmailContent{}, errors.New("should never come here")
-		}
-	}
+	getContent := GetDefaultContent
 
 	return emaildelivery.SMTPInterface{
 		SendRawEmail: &sendRawEmail,
 		GetContent:   &getContent,
 	}
 }
+
+// GetDefaultContent returns the built-in content of the emails sent by this recipe. It is
+// also used by the services that send emails using an HTTP API.
+func GetDefaultContent(input emaildelivery.EmailType, userContext supertokens.UserContext) (emaildelivery.EmailContent, error) {
+	if input.EmailVerification != nil {
+		return getEmailVerifyEmailContent(*input.EmailVerification)
+	} else {
+		return emaildelivery.EmailContent{}, errors.New("should never come here")
+	}
+}
@@ -18,6 +18,7 @@ package emailverification
 import (
 	"errors"
 
+	"github.com/supertokens/supertokens-golang/ingredients/deliverycapture"
 	"github.com/supertokens/supertokens-golang/ingredients/emaildelivery"
 	"github.com/supertokens/supertokens-golang/recipe/emailverification/api"
 	"github.com/supertokens/supertokens-golang/recipe/emailverification/emaildelivery/smtpService"
@@ -244,3 +245,25 @@ func SendEmailVerificationEmail(tenantId string, userID string, email *string, u
 func MakeSMTPService(config emaildelivery.SMTPServiceConfig) *emaildelivery.EmailDeliveryInterface {
 	return smtpService.MakeSMTPService(config)
 }
+
+func MakeSESService(config emaildelivery.SESServiceConfig) (*emaildelivery.EmailDeliveryInterface, error) {
+	return emaildelivery.MakeSESService(config, smtpService.GetDefaultContent)
+}
+
+func MakeSendGridService(config emaildelivery.SendGridServiceConfig) (*emaildelivery.EmailDeliveryInterface, error) {
+	return emaildelivery.MakeSendGridService(config, smtpService.GetDefaultContent)
+}
+
+func MakePostmarkService(config emaildelivery.PostmarkServiceConfig) (*emaildelivery.EmailDeliveryInterface, error) {
+	return emaildelivery.MakePostmarkService(config, smtpService.GetDefaultContent)
+}
+
+func MakeMailgunService(config emaildelivery.MailgunServiceConfig) (*emaildelivery.EmailDeliveryInterface, error) {
+	return emaildelivery.MakeMailgunService(config, smtpService.GetDefaultContent)
+}
+
+// MakeCaptureEmailService returns a service that stores the emails in inbox instead of sending
+// them. It should only be used in development and tests.
+func MakeCaptureEmailService(inbox *deliverycapture.Inbox) *emaildelivery.EmailDeliveryInterface {
+	return emaildelivery.MakeCaptureService(inbox, smtpService.GetDefaultContent)
+}
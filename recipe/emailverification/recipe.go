@@ -102,6 +102,7 @@ func MakeRecipe(recipeId string, appInfo supertokens.NormalisedAppinfo, config e
 	}
 
 	r.RecipeModule.ResetForTest = resetForTest
+	r.RecipeModule.ExportUserData = r.exportUserData
 
 	return *r, nil
 }
@@ -16,7 +16,9 @@
 package emailverification
 
 import (
+	"github.com/supertokens/supertokens-golang/recipe/emailverification/evclaims"
 	"github.com/supertokens/supertokens-golang/recipe/emailverification/evmodels"
+	"github.com/supertokens/supertokens-golang/recipe/session"
 	"github.com/supertokens/supertokens-golang/supertokens"
 )
 
@@ -95,6 +97,12 @@ func makeRecipeImplementation(querier supertokens.Querier) evmodels.RecipeInterf
 		if err != nil {
 			return evmodels.UnverifyEmailResponse{}, err
 		}
+		// Sessions of the user should not keep claiming that the email is verified until the claim expires
+		// The email is already unverified in the core, so the call must not fail
+		err = session.MarkClaimsAsStale(userId, []string{evclaims.EmailVerificationClaim.Key}, userContext)
+		if err != nil {
+			supertokens.LogDebugMessage("UnverifyEmail: could not mark the email verification claim as stale: " + err.Error())
+		}
 		return evmodels.UnverifyEmailResponse{
 			OK: &struct{}{},
 		}, nil
@@ -0,0 +1,39 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package emailverification
+
+import (
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func (r *Recipe) exportUserData(userId string, tenantIds []string, userContext supertokens.UserContext) (interface{}, error) {
+	emailInfo, err := r.GetEmailForUserID(userId, userContext)
+	if err != nil {
+		return nil, err
+	}
+	if emailInfo.OK == nil {
+		return nil, nil
+	}
+	isVerified, err := (*r.RecipeImpl.IsEmailVerified)(userId, emailInfo.OK.Email, userContext)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"email":      emailInfo.OK.Email,
+		"isVerified": isVerified,
+	}, nil
+}
@@ -0,0 +1,49 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package api
+
+import (
+	"github.com/supertokens/supertokens-golang/recipe/invitations/invitationsmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func Invite(apiImplementation invitationsmodels.APIInterface, tenantId string, options invitationsmodels.APIOptions, userContext supertokens.UserContext) error {
+	if apiImplementation.InviteGET == nil || (*apiImplementation.InviteGET) == nil {
+		options.OtherHandler(options.Res, options.Req)
+		return nil
+	}
+	token := options.Req.URL.Query().Get("token")
+	if token == "" {
+		return supertokens.BadInputError{Msg: "Please provide the token as a GET param"}
+	}
+	result, err := (*apiImplementation.InviteGET)(token, tenantId, options, userContext)
+	if err != nil {
+		return err
+	}
+	if result.OK != nil {
+		return supertokens.Send200Response(options.Res, map[string]interface{}{
+			"status": "OK",
+			"email":  result.OK.Email,
+		})
+	} else if result.InvalidInviteTokenError != nil {
+		return supertokens.Send200Response(options.Res, map[string]interface{}{
+			"status": "INVALID_INVITE_TOKEN_ERROR",
+		})
+	}
+
+	return supertokens.ErrorIfNoResponse(options.Res)
+}
@@ -0,0 +1,348 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package invitations
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/ingredients/deliverycapture"
+	"github.com/supertokens/supertokens-golang/ingredients/emaildelivery"
+	"github.com/supertokens/supertokens-golang/ingredients/emailpolicy"
+	"github.com/supertokens/supertokens-golang/recipe/invitations/invitationsmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func initInvitationsForTest(t *testing.T, inbox *deliverycapture.Inbox, config invitationsmodels.TypeInput) *httptest.Server {
+	if inbox != nil {
+		config.EmailDelivery = &emaildelivery.TypeInput{
+			Service: MakeCaptureEmailService(inbox),
+		}
+	}
+	err := supertokens.Init(supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: "http://localhost:8080",
+		},
+		AppInfo: supertokens.AppInfo{
+			APIDomain:     "api.supertokens.io",
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{
+			Init(&config),
+		},
+	})
+	assert.NoError(t, err)
+
+	mux := http.NewServeMux()
+	return httptest.NewServer(supertokens.Middleware(mux))
+}
+
+func getInviteTokenFromInbox(t *testing.T, inbox *deliverycapture.Inbox, email string) string {
+	message := inbox.GetLatestMessage(email)
+	if !assert.NotNil(t, message) || !assert.Len(t, message.Links, 1) {
+		return ""
+	}
+	link, err := url.Parse(message.Links[0])
+	assert.NoError(t, err)
+	return link.Query().Get("token")
+}
+
+func getInviteForTest(t *testing.T, url string) map[string]interface{} {
+	res, err := http.Get(url)
+	assert.NoError(t, err)
+	defer res.Body.Close()
+	assert.Equal(t, 200, res.StatusCode)
+
+	body, err := io.ReadAll(res.Body)
+	assert.NoError(t, err)
+	result := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal(body, &result))
+	return result
+}
+
+func TestCreateInviteSendsTheInvitationEmail(t *testing.T) {
+	BeforeEach()
+	defer AfterEach()
+	inbox := deliverycapture.MakeInbox(0)
+	server := initInvitationsForTest(t, inbox, invitationsmodels.TypeInput{})
+	defer server.Close()
+
+	response, err := CreateInvite("public", " Invited@Example.com", nil)
+	assert.NoError(t, err)
+	assert.NotNil(t, response.OK)
+	assert.Equal(t, "invited@example.com", response.OK.Invite.Email)
+	assert.Equal(t, []string{}, response.OK.Invite.Roles)
+	assert.Equal(t, int64(7*24*60*60*1000), response.OK.Invite.ExpiresAt-response.OK.Invite.CreatedAt)
+
+	message := inbox.GetLatestMessage("invited@example.com")
+	assert.NotNil(t, message)
+	assert.Equal(t, "You have been invited to SuperTokens", message.Subject)
+	assert.Equal(t, "public", message.TenantId)
+	assert.Equal(t, []string{"https://supertokens.io/auth/accept-invite?token=" + response.OK.Token + "&tenantId=public"}, message.Links)
+
+	// only the hash of the token is stored
+	assert.NotEqual(t, response.OK.Token, response.OK.Invite.TokenHash)
+	assert.Equal(t, hashInviteToken(response.OK.Token), response.OK.Invite.TokenHash)
+}
+
+func TestInviteAPIReturnsTheInvitedEmail(t *testing.T) {
+	BeforeEach()
+	defer AfterEach()
+	inbox := deliverycapture.MakeInbox(0)
+	server := initInvitationsForTest(t, inbox, invitationsmodels.TypeInput{})
+	defer server.Close()
+
+	_, err := CreateInvite("public", "invited@example.com", nil)
+	assert.NoError(t, err)
+	token := getInviteTokenFromInbox(t, inbox, "invited@example.com")
+
+	result := getInviteForTest(t, server.URL+"/auth/invite?token="+token)
+	assert.Equal(t, "OK", result["status"])
+	assert.Equal(t, "invited@example.com", result["email"])
+
+	result = getInviteForTest(t, server.URL+"/auth/invite?token=invalid")
+	assert.Equal(t, "INVALID_INVITE_TOKEN_ERROR", result["status"])
+
+	// the invite is only valid in its tenant
+	invite, err := GetInviteByToken("tenant1", token)
+	assert.NoError(t, err)
+	assert.Nil(t, invite)
+
+	res, err := http.Get(server.URL + "/auth/invite")
+	assert.NoError(t, err)
+	assert.Equal(t, 400, res.StatusCode)
+	res.Body.Close()
+}
+
+func TestExpiredInvitesAreNotReturned(t *testing.T) {
+	BeforeEach()
+	defer AfterEach()
+	inviteLifetime := 50 * time.Millisecond
+	inbox := deliverycapture.MakeInbox(0)
+	server := initInvitationsForTest(t, inbox, invitationsmodels.TypeInput{
+		InviteLifetime: &inviteLifetime,
+	})
+	defer server.Close()
+
+	response, err := CreateInvite("public", "invited@example.com", nil)
+	assert.NoError(t, err)
+
+	invites, err := ListInvites("public")
+	assert.NoError(t, err)
+	assert.Len(t, invites, 1)
+
+	time.Sleep(2 * inviteLifetime)
+
+	invite, err := GetInviteByToken("public", response.OK.Token)
+	assert.NoError(t, err)
+	assert.Nil(t, invite)
+
+	invites, err = ListInvites("public")
+	assert.NoError(t, err)
+	assert.Len(t, invites, 0)
+}
+
+func TestRevokeInvite(t *testing.T) {
+	BeforeEach()
+	defer AfterEach()
+	inbox := deliverycapture.MakeInbox(0)
+	server := initInvitationsForTest(t, inbox, invitationsmodels.TypeInput{})
+	defer server.Close()
+
+	first, err := CreateInvite("public", "first@example.com", nil)
+	assert.NoError(t, err)
+	second, err := CreateInvite("public", "second@example.com", nil)
+	assert.NoError(t, err)
+	_, err = CreateInvite("tenant1", "third@example.com", nil)
+	assert.NoError(t, err)
+
+	invites, err := ListInvites("public")
+	assert.NoError(t, err)
+	assert.Len(t, invites, 2)
+
+	revokeResponse, err := RevokeInvite(first.OK.Invite.InviteId)
+	assert.NoError(t, err)
+	assert.True(t, revokeResponse.OK.DidInviteExist)
+
+	revokeResponse, err = RevokeInvite(first.OK.Invite.InviteId)
+	assert.NoError(t, err)
+	assert.False(t, revokeResponse.OK.DidInviteExist)
+
+	invite, err := GetInviteByToken("public", first.OK.Token)
+	assert.NoError(t, err)
+	assert.Nil(t, invite)
+
+	invites, err = ListInvites("public")
+	assert.NoError(t, err)
+	assert.Len(t, invites, 1)
+	assert.Equal(t, second.OK.Invite.InviteId, invites[0].InviteId)
+}
+
+func TestCreateInviteFailsWithoutAnEmailDeliveryService(t *testing.T) {
+	BeforeEach()
+	defer AfterEach()
+	server := initInvitationsForTest(t, nil, invitationsmodels.TypeInput{})
+	defer server.Close()
+
+	_, err := CreateInvite("public", "invited@example.com", nil)
+	assert.Error(t, err)
+
+	// the invite cannot be used since its token was not sent
+	invites, err := ListInvites("public")
+	assert.NoError(t, err)
+	assert.Len(t, invites, 0)
+}
+
+func TestGetInviteForSignUp(t *testing.T) {
+	BeforeEach()
+	defer AfterEach()
+	inbox := deliverycapture.MakeInbox(0)
+	server := initInvitationsForTest(t, inbox, invitationsmodels.TypeInput{
+		InviteOnlyTenantIds: []string{"public"},
+	})
+	defer server.Close()
+
+	response, err := CreateInvite("public", "invited@example.com", nil)
+	assert.NoError(t, err)
+	userContext := &map[string]interface{}{}
+	instance := GetRecipeInstance()
+
+	req, err := http.NewRequest("POST", "/auth/signup", nil)
+	assert.NoError(t, err)
+	invite, allowed, err := instance.GetInviteForSignUp("public", "invited@example.com", nil, req, userContext)
+	assert.NoError(t, err)
+	assert.False(t, allowed)
+	assert.Nil(t, invite)
+
+	req.Header.Set(invitationsmodels.InviteTokenHeaderKey, response.OK.Token)
+	invite, allowed, err = instance.GetInviteForSignUp("public", "someone@example.com", nil, req, userContext)
+	assert.NoError(t, err)
+	assert.False(t, allowed)
+	assert.Nil(t, invite)
+
+	invite, allowed, err = instance.GetInviteForSignUp("public", "Invited@Example.com", nil, req, userContext)
+	assert.NoError(t, err)
+	assert.True(t, allowed)
+	assert.Equal(t, response.OK.Invite.InviteId, invite.InviteId)
+
+	// tenants that are not invite only do not need an invite
+	tenantInvite, allowed, err := instance.GetInviteForSignUp("tenant1", "someone@example.com", nil, req, userContext)
+	assert.NoError(t, err)
+	assert.True(t, allowed)
+	assert.Nil(t, tenantInvite)
+	assert.NoError(t, instance.CompleteSignUp(tenantInvite, "userId", userContext))
+	assert.NoError(t, instance.CancelSignUp(tenantInvite, userContext))
+
+	// the invite is reserved until the sign up is cancelled
+	otherInvite, allowed, err := instance.GetInviteForSignUp("public", "invited@example.com", nil, req, userContext)
+	assert.NoError(t, err)
+	assert.False(t, allowed)
+	assert.Nil(t, otherInvite)
+
+	assert.NoError(t, instance.CancelSignUp(invite, userContext))
+	invite, allowed, err = instance.GetInviteForSignUp("public", "invited@example.com", nil, req, userContext)
+	assert.NoError(t, err)
+	assert.True(t, allowed)
+	assert.NotNil(t, invite)
+
+	assert.NoError(t, instance.CompleteSignUp(invite, "userId", userContext))
+	invite, err = GetInviteByToken("public", response.OK.Token)
+	assert.NoError(t, err)
+	assert.Nil(t, invite)
+}
+
+func TestAnInviteCanOnlyBeUsedByOneSignUpAtATime(t *testing.T) {
+	BeforeEach()
+	defer AfterEach()
+	server := initInvitationsForTest(t, deliverycapture.MakeInbox(0), invitationsmodels.TypeInput{
+		InviteOnlyTenantIds: []string{"public"},
+	})
+	defer server.Close()
+
+	response, err := CreateInvite("public", "invited@example.com", nil)
+	assert.NoError(t, err)
+	instance := GetRecipeInstance()
+
+	var wg sync.WaitGroup
+	var allowedCount int32
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req, err := http.NewRequest("POST", "/auth/signup", nil)
+			assert.NoError(t, err)
+			req.Header.Set(invitationsmodels.InviteTokenHeaderKey, response.OK.Token)
+			_, allowed, err := instance.GetInviteForSignUp("public", "invited@example.com", nil, req, &map[string]interface{}{})
+			assert.NoError(t, err)
+			if allowed {
+				atomic.AddInt32(&allowedCount, 1)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), allowedCount)
+}
+
+func TestInvalidInviteLifetimeReturnsAnError(t *testing.T) {
+	inviteLifetime := time.Duration(0)
+	_, err := validateAndNormaliseUserInput(&invitationsmodels.TypeInput{
+		InviteLifetime: &inviteLifetime,
+	})
+	assert.EqualError(t, err, "InviteLifetime must be greater than 0")
+}
+
+func TestInviteForAnAliasCanBeUsedWithTheNormalisedEmail(t *testing.T) {
+	BeforeEach()
+	defer AfterEach()
+	server := initInvitationsForTest(t, deliverycapture.MakeInbox(0), invitationsmodels.TypeInput{
+		InviteOnlyTenantIds: []string{"public"},
+	})
+	defer server.Close()
+
+	emailPolicy, err := emailpolicy.NormaliseTypeInput(emailpolicy.TypeInput{
+		NormaliseEmail: emailpolicy.NormaliseEmailWithProviderAliases,
+	})
+	assert.NoError(t, err)
+
+	response, err := CreateInvite("public", "John+Team@gmail.com", nil)
+	assert.NoError(t, err)
+
+	req, err := http.NewRequest("POST", "/auth/signup", nil)
+	assert.NoError(t, err)
+	req.Header.Set(invitationsmodels.InviteTokenHeaderKey, response.OK.Token)
+
+	// without the policy of the recipe, the alias does not match
+	invite, allowed, err := GetRecipeInstance().GetInviteForSignUp("public", "john@gmail.com", nil, req, &map[string]interface{}{})
+	assert.NoError(t, err)
+	assert.False(t, allowed)
+	assert.Nil(t, invite)
+
+	// the sign up APIs give the email normalised with their policy
+	invite, allowed, err = GetRecipeInstance().GetInviteForSignUp("public", "john@gmail.com", &emailPolicy, req, &map[string]interface{}{})
+	assert.NoError(t, err)
+	assert.True(t, allowed)
+	assert.Equal(t, response.OK.Invite.InviteId, invite.InviteId)
+}
@@ -0,0 +1,91 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package invitationsmodels
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/supertokens/supertokens-golang/ingredients/emaildelivery"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+// InviteTokenHeaderKey is the header in which the frontend sends the invite token to the sign up
+// APIs of the emailpassword, thirdparty and passwordless recipes
+const InviteTokenHeaderKey = "st-invite-token"
+
+type Invite struct {
+	InviteId string   `json:"inviteId"`
+	Email    string   `json:"email"`
+	TenantId string   `json:"tenantId"`
+	Roles    []string `json:"roles"`
+	// TokenHash is the SHA-256 of the token sent in the invitation email. The token itself is
+	// never stored.
+	TokenHash string `json:"-"`
+	// CreatedAt and ExpiresAt are in milliseconds
+	CreatedAt int64 `json:"createdAt"`
+	ExpiresAt int64 `json:"expiresAt"`
+}
+
+// Store keeps the invites. Expired invites can be removed by the store.
+type Store struct {
+	// Save inserts the invite, or replaces the invite with the same InviteId
+	Save func(invite Invite, userContext supertokens.UserContext) error
+	// Get returns nil if there is no invite with the inviteId
+	Get func(inviteId string, userContext supertokens.UserContext) (*Invite, error)
+	// GetByTokenHash returns nil if there is no invite with the tokenHash
+	GetByTokenHash func(tokenHash string, userContext supertokens.UserContext) (*Invite, error)
+	// List returns the invites of the tenant, oldest first
+	List func(tenantId string, userContext supertokens.UserContext) ([]Invite, error)
+	// Delete returns false if there was no invite with the inviteId
+	Delete func(inviteId string, userContext supertokens.UserContext) (bool, error)
+}
+
+type TypeInput struct {
+	// InviteOnlyTenantIds are the tenants in which new users can only sign up with an invite.
+	// Invites can be created for any tenant, but are only required in these ones.
+	InviteOnlyTenantIds []string
+	// InviteLifetime defaults to 7 days
+	InviteLifetime *time.Duration
+	// Store defaults to an in-memory store, which is not shared between instances of the backend
+	// and is cleared when the backend restarts
+	Store         *Store
+	EmailDelivery *emaildelivery.TypeInput
+	Override      *OverrideStruct
+}
+
+type TypeNormalisedInput struct {
+	InviteOnlyTenantIds    map[string]bool
+	InviteLifetime         time.Duration
+	Store                  Store
+	GetEmailDeliveryConfig func() emaildelivery.TypeInputWithService
+	Override               OverrideStruct
+}
+
+type OverrideStruct struct {
+	Functions func(originalImplementation RecipeInterface) RecipeInterface
+	APIs      func(originalImplementation APIInterface) APIInterface
+}
+
+type APIOptions struct {
+	RecipeImplementation RecipeInterface
+	Config               TypeNormalisedInput
+	RecipeID             string
+	Req                  *http.Request
+	Res                  http.ResponseWriter
+	OtherHandler         http.HandlerFunc
+}
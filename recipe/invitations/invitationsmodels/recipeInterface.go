@@ -0,0 +1,54 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package invitationsmodels
+
+import "github.com/supertokens/supertokens-golang/supertokens"
+
+type CreateInviteResponse struct {
+	OK *struct {
+		Invite Invite
+		// Token is only returned when the invite is created, since only its hash is stored
+		Token string
+	}
+	UnknownRoleError *struct {
+		Role string
+	}
+}
+
+type RevokeInviteResponse struct {
+	OK *struct {
+		DidInviteExist bool
+	}
+}
+
+type RecipeInterface struct {
+	CreateInvite *func(email string, roles []string, tenantId string, userContext supertokens.UserContext) (CreateInviteResponse, error)
+	// GetInviteByToken returns nil if there is no invite for the token in the tenant, or if it has expired
+	GetInviteByToken *func(token string, tenantId string, userContext supertokens.UserContext) (*Invite, error)
+	// ListInvites returns the invites of the tenant that have not expired
+	ListInvites  *func(tenantId string, userContext supertokens.UserContext) ([]Invite, error)
+	RevokeInvite *func(inviteId string, userContext supertokens.UserContext) (RevokeInviteResponse, error)
+	// ReserveInvite is called before the invited user is created. It removes the invite from the
+	// store so that it cannot be used by another sign up at the same time, and returns false if the
+	// invite was already used or revoked.
+	ReserveInvite *func(invite Invite, userContext supertokens.UserContext) (bool, error)
+	// ReleaseInvite puts back an invite reserved by ReserveInvite if the user could not be created
+	ReleaseInvite *func(invite Invite, userContext supertokens.UserContext) error
+	// ConsumeInvite is called once the invited user has signed up. It gives the roles of the
+	// invite to the user, using the userroles recipe.
+	ConsumeInvite *func(invite Invite, userId string, userContext supertokens.UserContext) error
+}
@@ -0,0 +1,141 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package invitations
+
+import (
+	"github.com/supertokens/supertokens-golang/ingredients/deliverycapture"
+	"github.com/supertokens/supertokens-golang/ingredients/emaildelivery"
+	"github.com/supertokens/supertokens-golang/recipe/invitations/api"
+	"github.com/supertokens/supertokens-golang/recipe/invitations/emaildelivery/smtpService"
+	"github.com/supertokens/supertokens-golang/recipe/invitations/invitationsmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+// Init makes the tenants in InviteOnlyTenantIds invite only: new users can only sign up using
+// the emailpassword, thirdparty and passwordless recipes if the frontend sends the token of an
+// invite for their email in the st-invite-token header. The roles of the invite are given to
+// the user once they have signed up. The recipe also exposes GET /invite?token=<token>, which
+// returns the invited email.
+func Init(config *invitationsmodels.TypeInput) supertokens.Recipe {
+	return recipeInit(config)
+}
+
+// CreateInvite creates an invite and sends the invitation email with the link to accept it.
+// The roles must exist (see userroles.CreateNewRoleOrAddPermissions).
+func CreateInvite(tenantId string, email string, roles []string, userContext ...supertokens.UserContext) (invitationsmodels.CreateInviteResponse, error) {
+	instance, err := getRecipeInstanceOrThrowError()
+	if err != nil {
+		return invitationsmodels.CreateInviteResponse{}, err
+	}
+	if len(userContext) == 0 {
+		userContext = append(userContext, &map[string]interface{}{})
+	}
+	response, err := (*instance.RecipeImpl.CreateInvite)(email, roles, tenantId, userContext[0])
+	if err != nil || response.OK == nil {
+		return response, err
+	}
+
+	inviteLink, err := api.GetInviteLink(
+		instance.RecipeModule.GetAppInfo(),
+		response.OK.Token,
+		tenantId,
+		supertokens.GetRequestFromUserContext(userContext[0]),
+		userContext[0],
+	)
+	if err != nil {
+		return invitationsmodels.CreateInviteResponse{}, err
+	}
+
+	invite := response.OK.Invite
+	supertokens.LogDebugMessage("invitations: sending the invitation email to " + invite.Email)
+	err = (*instance.EmailDelivery.IngredientInterfaceImpl.SendEmail)(emaildelivery.EmailType{
+		Invitation: &emaildelivery.InvitationType{
+			InviteId:       invite.InviteId,
+			Email:          invite.Email,
+			InviteLink:     inviteLink,
+			InviteLifetime: uint64(invite.ExpiresAt - invite.CreatedAt),
+			TenantId:       tenantId,
+		},
+	}, userContext[0])
+	if err != nil {
+		// the token cannot be sent again, so the invite is useless
+		_, _ = (*instance.RecipeImpl.RevokeInvite)(invite.InviteId, userContext[0])
+		return invitationsmodels.CreateInviteResponse{}, err
+	}
+	return response, nil
+}
+
+// GetInviteByToken returns nil if there is no invite for the token in the tenant, or if it has expired
+func GetInviteByToken(tenantId string, token string, userContext ...supertokens.UserContext) (*invitationsmodels.Invite, error) {
+	instance, err := getRecipeInstanceOrThrowError()
+	if err != nil {
+		return nil, err
+	}
+	if len(userContext) == 0 {
+		userContext = append(userContext, &map[string]interface{}{})
+	}
+	return (*instance.RecipeImpl.GetInviteByToken)(token, tenantId, userContext[0])
+}
+
+// ListInvites returns the invites of the tenant that have not expired, oldest first
+func ListInvites(tenantId string, userContext ...supertokens.UserContext) ([]invitationsmodels.Invite, error) {
+	instance, err := getRecipeInstanceOrThrowError()
+	if err != nil {
+		return nil, err
+	}
+	if len(userContext) == 0 {
+		userContext = append(userContext, &map[string]interface{}{})
+	}
+	return (*instance.RecipeImpl.ListInvites)(tenantId, userContext[0])
+}
+
+func RevokeInvite(inviteId string, userContext ...supertokens.UserContext) (invitationsmodels.RevokeInviteResponse, error) {
+	instance, err := getRecipeInstanceOrThrowError()
+	if err != nil {
+		return invitationsmodels.RevokeInviteResponse{}, err
+	}
+	if len(userContext) == 0 {
+		userContext = append(userContext, &map[string]interface{}{})
+	}
+	return (*instance.RecipeImpl.RevokeInvite)(inviteId, userContext[0])
+}
+
+func MakeSMTPService(config emaildelivery.SMTPServiceConfig) *emaildelivery.EmailDeliveryInterface {
+	return smtpService.MakeSMTPService(config)
+}
+
+func MakeSESService(config emaildelivery.SESServiceConfig) (*emaildelivery.EmailDeliveryInterface, error) {
+	return emaildelivery.MakeSESService(config, smtpService.GetDefaultContent)
+}
+
+func MakeSendGridService(config emaildelivery.SendGridServiceConfig) (*emaildelivery.EmailDeliveryInterface, error) {
+	return emaildelivery.MakeSendGridService(config, smtpService.GetDefaultContent)
+}
+
+func MakePostmarkService(config emaildelivery.PostmarkServiceConfig) (*emaildelivery.EmailDeliveryInterface, error) {
+	return emaildelivery.MakePostmarkService(config, smtpService.GetDefaultContent)
+}
+
+func MakeMailgunService(config emaildelivery.MailgunServiceConfig) (*emaildelivery.EmailDeliveryInterface, error) {
+	return emaildelivery.MakeMailgunService(config, smtpService.GetDefaultContent)
+}
+
+// MakeCaptureEmailService returns a service that stores the emails in inbox instead of sending
+// them. It should only be used in development and tests.
+func MakeCaptureEmailService(inbox *deliverycapture.Inbox) *emaildelivery.EmailDeliveryInterface {
+	return emaildelivery.MakeCaptureService(inbox, smtpService.GetDefaultContent)
+}
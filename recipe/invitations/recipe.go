@@ -0,0 +1,193 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package invitations
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/supertokens/supertokens-golang/ingredients/emaildelivery"
+	"github.com/supertokens/supertokens-golang/ingredients/emailpolicy"
+	"github.com/supertokens/supertokens-golang/recipe/invitations/api"
+	"github.com/supertokens/supertokens-golang/recipe/invitations/invitationsmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+const RECIPE_ID = "invitations"
+
+type Recipe struct {
+	RecipeModule  supertokens.RecipeModule
+	Config        invitationsmodels.TypeNormalisedInput
+	RecipeImpl    invitationsmodels.RecipeInterface
+	APIImpl       invitationsmodels.APIInterface
+	EmailDelivery emaildelivery.Ingredient
+}
+
+var singletonInstance *Recipe
+
+func MakeRecipe(recipeId string, appInfo supertokens.NormalisedAppinfo, config *invitationsmodels.TypeInput, emailDeliveryIngredient *emaildelivery.Ingredient, onSuperTokensAPIError func(err error, req *http.Request, res http.ResponseWriter)) (Recipe, error) {
+	r := &Recipe{}
+	verifiedConfig, err := validateAndNormaliseUserInput(config)
+	if err != nil {
+		return Recipe{}, err
+	}
+	r.Config = verifiedConfig
+	r.APIImpl = verifiedConfig.Override.APIs(api.MakeAPIImplementation())
+
+	r.RecipeImpl = verifiedConfig.Override.Functions(makeRecipeImplementation(verifiedConfig))
+
+	recipeModuleInstance := supertokens.MakeRecipeModule(recipeId, appInfo, r.handleAPIRequest, r.getAllCORSHeaders, r.getAPIsHandled, nil, r.handleError, onSuperTokensAPIError)
+	r.RecipeModule = recipeModuleInstance
+
+	if emailDeliveryIngredient != nil {
+		r.EmailDelivery = *emailDeliveryIngredient
+	} else {
+		r.EmailDelivery = emaildelivery.MakeIngredient(verifiedConfig.GetEmailDeliveryConfig())
+	}
+
+	r.RecipeModule.ResetForTest = ResetForTest
+
+	return *r, nil
+}
+
+func getRecipeInstanceOrThrowError() (*Recipe, error) {
+	if singletonInstance != nil {
+		return singletonInstance, nil
+	}
+	return nil, errors.New("Initialisation not done. Did you forget to call the init function?")
+}
+
+// GetRecipeInstance returns nil if the recipe was not initialised, in which case no tenant is
+// invite only
+func GetRecipeInstance() *Recipe {
+	return singletonInstance
+}
+
+func recipeInit(config *invitationsmodels.TypeInput) supertokens.Recipe {
+	return func(appInfo supertokens.NormalisedAppinfo, onSuperTokensAPIError func(err error, req *http.Request, res http.ResponseWriter)) (*supertokens.RecipeModule, error) {
+		if singletonInstance == nil {
+			recipe, err := MakeRecipe(RECIPE_ID, appInfo, config, nil, onSuperTokensAPIError)
+			if err != nil {
+				return nil, err
+			}
+			singletonInstance = &recipe
+			return &singletonInstance.RecipeModule, nil
+		}
+		return nil, errors.New("Invitations recipe has already been initialised. Please check your code for bugs.")
+	}
+}
+
+// IsInviteOnlyTenant returns true if new users can only sign up in the tenant with an invite
+func (r *Recipe) IsInviteOnlyTenant(tenantId string) bool {
+	return r.Config.InviteOnlyTenantIds[tenantId]
+}
+
+// GetInviteForSignUp is used by the sign up APIs before creating a new user. If the tenant is
+// invite only, the invite token must be in the request headers and the invite must be for the
+// email. Both emails are normalised with emailPolicy, the EmailPolicy of the recipe that signs up
+// the user, so that an invite for an alias of the email can be used. allowed is false if the user
+// cannot sign up, and invite is nil if no invite is needed.
+// The returned invite is reserved, so it cannot be used by another sign up: the caller must call
+// CompleteSignUp once the user is created, or CancelSignUp if no user was created.
+func (r *Recipe) GetInviteForSignUp(tenantId string, email string, emailPolicy *emailpolicy.TypeNormalisedInput, req *http.Request, userContext supertokens.UserContext) (invite *invitationsmodels.Invite, allowed bool, err error) {
+	if !r.IsInviteOnlyTenant(tenantId) {
+		return nil, true, nil
+	}
+	token := req.Header.Get(invitationsmodels.InviteTokenHeaderKey)
+	if token == "" {
+		supertokens.LogDebugMessage("invitations: sign up rejected because there is no invite token in the request")
+		return nil, false, nil
+	}
+	invite, err = (*r.RecipeImpl.GetInviteByToken)(token, tenantId, userContext)
+	if err != nil {
+		return nil, false, err
+	}
+	if invite == nil || !strings.EqualFold(emailpolicy.Normalise(emailPolicy, invite.Email, tenantId), emailpolicy.Normalise(emailPolicy, strings.TrimSpace(email), tenantId)) {
+		supertokens.LogDebugMessage("invitations: sign up rejected because the invite token is invalid or for another email")
+		return nil, false, nil
+	}
+	reserved, err := (*r.RecipeImpl.ReserveInvite)(*invite, userContext)
+	if err != nil {
+		return nil, false, err
+	}
+	if !reserved {
+		supertokens.LogDebugMessage("invitations: sign up rejected because the invite was used by another sign up")
+		return nil, false, nil
+	}
+	return invite, true, nil
+}
+
+// CancelSignUp releases the invite returned by GetInviteForSignUp if no user was created, so that
+// it can be used again. It does nothing if invite is nil.
+func (r *Recipe) CancelSignUp(invite *invitationsmodels.Invite, userContext supertokens.UserContext) error {
+	if invite == nil {
+		return nil
+	}
+	return (*r.RecipeImpl.ReleaseInvite)(*invite, userContext)
+}
+
+// CompleteSignUp consumes the invite once the user was created. It does nothing if invite is nil.
+func (r *Recipe) CompleteSignUp(invite *invitationsmodels.Invite, userId string, userContext supertokens.UserContext) error {
+	if invite == nil {
+		return nil
+	}
+	return (*r.RecipeImpl.ConsumeInvite)(*invite, userId, userContext)
+}
+
+// implement RecipeModule
+
+func (r *Recipe) getAPIsHandled() ([]supertokens.APIHandled, error) {
+	inviteAPINormalised, err := supertokens.NewNormalisedURLPath(InviteAPI)
+	if err != nil {
+		return nil, err
+	}
+
+	return []supertokens.APIHandled{{
+		Method:                 http.MethodGet,
+		PathWithoutAPIBasePath: inviteAPINormalised,
+		ID:                     inviteAPIID,
+		Disabled:               r.APIImpl.InviteGET == nil,
+	}}, nil
+}
+
+func (r *Recipe) handleAPIRequest(id string, tenantId string, req *http.Request, res http.ResponseWriter, theirHandler http.HandlerFunc, _ supertokens.NormalisedURLPath, _ string, userContext supertokens.UserContext) error {
+	options := invitationsmodels.APIOptions{
+		RecipeImplementation: r.RecipeImpl,
+		Config:               r.Config,
+		RecipeID:             r.RecipeModule.GetRecipeID(),
+		Req:                  req,
+		Res:                  res,
+		OtherHandler:         theirHandler,
+	}
+	if id == inviteAPIID {
+		return api.Invite(r.APIImpl, tenantId, options, userContext)
+	}
+	return errors.New("should never come here")
+}
+
+func (r *Recipe) getAllCORSHeaders() []string {
+	return []string{invitationsmodels.InviteTokenHeaderKey}
+}
+
+func (r *Recipe) handleError(err error, req *http.Request, res http.ResponseWriter, userContext supertokens.UserContext) (bool, error) {
+	return false, nil
+}
+
+func ResetForTest() {
+	singletonInstance = nil
+}
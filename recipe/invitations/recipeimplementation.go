@@ -0,0 +1,164 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package invitations
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/supertokens/supertokens-golang/recipe/invitations/invitationsmodels"
+	"github.com/supertokens/supertokens-golang/recipe/userroles"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func makeRecipeImplementation(config invitationsmodels.TypeNormalisedInput) invitationsmodels.RecipeInterface {
+
+	createInvite := func(email string, roles []string, tenantId string, userContext supertokens.UserContext) (invitationsmodels.CreateInviteResponse, error) {
+		for _, role := range roles {
+			response, err := userroles.GetPermissionsForRole(role, userContext)
+			if err != nil {
+				return invitationsmodels.CreateInviteResponse{}, err
+			}
+			if response.UnknownRoleError != nil {
+				return invitationsmodels.CreateInviteResponse{
+					UnknownRoleError: &struct{ Role string }{
+						Role: role,
+					},
+				}, nil
+			}
+		}
+
+		token, err := generateInviteToken()
+		if err != nil {
+			return invitationsmodels.CreateInviteResponse{}, err
+		}
+
+		if roles == nil {
+			roles = []string{}
+		}
+		now := getCurrentTimeInMS()
+		invite := invitationsmodels.Invite{
+			InviteId:  uuid.New().String(),
+			Email:     strings.ToLower(strings.TrimSpace(email)),
+			TenantId:  tenantId,
+			Roles:     roles,
+			TokenHash: hashInviteToken(token),
+			CreatedAt: now,
+			ExpiresAt: now + config.InviteLifetime.Milliseconds(),
+		}
+		err = config.Store.Save(invite, userContext)
+		if err != nil {
+			return invitationsmodels.CreateInviteResponse{}, err
+		}
+		supertokens.LogDebugMessage("invitations: created the invite " + invite.InviteId + " in the tenant " + tenantId)
+
+		return invitationsmodels.CreateInviteResponse{
+			OK: &struct {
+				Invite invitationsmodels.Invite
+				Token  string
+			}{
+				Invite: invite,
+				Token:  token,
+			},
+		}, nil
+	}
+
+	getInviteByToken := func(token string, tenantId string, userContext supertokens.UserContext) (*invitationsmodels.Invite, error) {
+		if token == "" {
+			return nil, nil
+		}
+		invite, err := config.Store.GetByTokenHash(hashInviteToken(token), userContext)
+		if err != nil || invite == nil {
+			return nil, err
+		}
+		if invite.TenantId != tenantId || invite.ExpiresAt <= getCurrentTimeInMS() {
+			return nil, nil
+		}
+		return invite, nil
+	}
+
+	listInvites := func(tenantId string, userContext supertokens.UserContext) ([]invitationsmodels.Invite, error) {
+		invites, err := config.Store.List(tenantId, userContext)
+		if err != nil {
+			return nil, err
+		}
+		now := getCurrentTimeInMS()
+		result := []invitationsmodels.Invite{}
+		for _, invite := range invites {
+			if invite.ExpiresAt > now {
+				result = append(result, invite)
+			}
+		}
+		return result, nil
+	}
+
+	revokeInvite := func(inviteId string, userContext supertokens.UserContext) (invitationsmodels.RevokeInviteResponse, error) {
+		didInviteExist, err := config.Store.Delete(inviteId, userContext)
+		if err != nil {
+			return invitationsmodels.RevokeInviteResponse{}, err
+		}
+		return invitationsmodels.RevokeInviteResponse{
+			OK: &struct{ DidInviteExist bool }{
+				DidInviteExist: didInviteExist,
+			},
+		}, nil
+	}
+
+	reserveInvite := func(invite invitationsmodels.Invite, userContext supertokens.UserContext) (bool, error) {
+		didInviteExist, err := config.Store.Delete(invite.InviteId, userContext)
+		if err != nil {
+			return false, err
+		}
+		if didInviteExist {
+			supertokens.LogDebugMessage("invitations: reserved the invite " + invite.InviteId)
+		}
+		return didInviteExist, nil
+	}
+
+	releaseInvite := func(invite invitationsmodels.Invite, userContext supertokens.UserContext) error {
+		if invite.ExpiresAt <= getCurrentTimeInMS() {
+			return nil
+		}
+		supertokens.LogDebugMessage("invitations: released the invite " + invite.InviteId)
+		return config.Store.Save(invite, userContext)
+	}
+
+	consumeInvite := func(invite invitationsmodels.Invite, userId string, userContext supertokens.UserContext) error {
+		for _, role := range invite.Roles {
+			response, err := userroles.AddRoleToUser(invite.TenantId, userId, role, userContext)
+			if err != nil {
+				return err
+			}
+			if response.UnknownRoleError != nil {
+				// the role was deleted after the invite was created
+				supertokens.LogDebugMessage("invitations: the role " + role + " of the invite " + invite.InviteId + " does not exist anymore")
+			}
+		}
+		supertokens.LogDebugMessage("invitations: the invite " + invite.InviteId + " was used by the user " + userId)
+		return nil
+	}
+
+	return invitationsmodels.RecipeInterface{
+		CreateInvite:     &createInvite,
+		GetInviteByToken: &getInviteByToken,
+		ListInvites:      &listInvites,
+		RevokeInvite:     &revokeInvite,
+		ReserveInvite:    &reserveInvite,
+		ReleaseInvite:    &releaseInvite,
+		ConsumeInvite:    &consumeInvite,
+	}
+}
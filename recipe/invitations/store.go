@@ -0,0 +1,88 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package invitations
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/supertokens/supertokens-golang/recipe/invitations/invitationsmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func MakeInMemoryStore() invitationsmodels.Store {
+	var lock sync.Mutex
+	invites := map[string]invitationsmodels.Invite{}
+
+	removeExpiredInvites := func(now int64) {
+		for inviteId, invite := range invites {
+			if invite.ExpiresAt <= now {
+				delete(invites, inviteId)
+			}
+		}
+	}
+
+	return invitationsmodels.Store{
+		Save: func(invite invitationsmodels.Invite, userContext supertokens.UserContext) error {
+			lock.Lock()
+			defer lock.Unlock()
+			removeExpiredInvites(getCurrentTimeInMS())
+			invites[invite.InviteId] = invite
+			return nil
+		},
+		Get: func(inviteId string, userContext supertokens.UserContext) (*invitationsmodels.Invite, error) {
+			lock.Lock()
+			defer lock.Unlock()
+			invite, ok := invites[inviteId]
+			if !ok {
+				return nil, nil
+			}
+			return &invite, nil
+		},
+		GetByTokenHash: func(tokenHash string, userContext supertokens.UserContext) (*invitationsmodels.Invite, error) {
+			lock.Lock()
+			defer lock.Unlock()
+			for _, invite := range invites {
+				if invite.TokenHash == tokenHash {
+					return &invite, nil
+				}
+			}
+			return nil, nil
+		},
+		List: func(tenantId string, userContext supertokens.UserContext) ([]invitationsmodels.Invite, error) {
+			lock.Lock()
+			defer lock.Unlock()
+			result := []invitationsmodels.Invite{}
+			for _, invite := range invites {
+				if invite.TenantId == tenantId {
+					result = append(result, invite)
+				}
+			}
+			sort.Slice(result, func(i, j int) bool {
+				return result[i].CreatedAt < result[j].CreatedAt
+			})
+			return result, nil
+		},
+		Delete: func(inviteId string, userContext supertokens.UserContext) (bool, error) {
+			lock.Lock()
+			defer lock.Unlock()
+			_, ok := invites[inviteId]
+			delete(invites, inviteId)
+			return ok, nil
+		},
+	}
+}
@@ -0,0 +1,121 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package invitations
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/supertokens/supertokens-golang/ingredients/emaildelivery"
+	"github.com/supertokens/supertokens-golang/recipe/invitations/invitationsmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func validateAndNormaliseUserInput(config *invitationsmodels.TypeInput) (invitationsmodels.TypeNormalisedInput, error) {
+	typeNormalisedInput := makeTypeNormalisedInput()
+
+	if config == nil {
+		config = &invitationsmodels.TypeInput{}
+	}
+
+	for _, tenantId := range config.InviteOnlyTenantIds {
+		typeNormalisedInput.InviteOnlyTenantIds[tenantId] = true
+	}
+
+	if config.InviteLifetime != nil {
+		if *config.InviteLifetime <= 0 {
+			return invitationsmodels.TypeNormalisedInput{}, errors.New("InviteLifetime must be greater than 0")
+		}
+		typeNormalisedInput.InviteLifetime = *config.InviteLifetime
+	}
+
+	if config.Store != nil {
+		typeNormalisedInput.Store = *config.Store
+	}
+
+	typeNormalisedInput.GetEmailDeliveryConfig = func() emaildelivery.TypeInputWithService {
+		emailService := makeDefaultEmailService()
+		if config.EmailDelivery != nil && config.EmailDelivery.Service != nil {
+			emailService = *config.EmailDelivery.Service
+		}
+		result := emaildelivery.TypeInputWithService{
+			Service: emailService,
+		}
+		if config.EmailDelivery != nil && config.EmailDelivery.Override != nil {
+			result.Override = config.EmailDelivery.Override
+		}
+		return result
+	}
+
+	if config.Override != nil {
+		if config.Override.Functions != nil {
+			typeNormalisedInput.Override.Functions = config.Override.Functions
+		}
+		if config.Override.APIs != nil {
+			typeNormalisedInput.Override.APIs = config.Override.APIs
+		}
+	}
+
+	return typeNormalisedInput, nil
+}
+
+func makeTypeNormalisedInput() invitationsmodels.TypeNormalisedInput {
+	return invitationsmodels.TypeNormalisedInput{
+		InviteOnlyTenantIds: map[string]bool{},
+		InviteLifetime:      7 * 24 * time.Hour,
+		Store:               MakeInMemoryStore(),
+		Override: invitationsmodels.OverrideStruct{
+			Functions: func(originalImplementation invitationsmodels.RecipeInterface) invitationsmodels.RecipeInterface {
+				return originalImplementation
+			},
+			APIs: func(originalImplementation invitationsmodels.APIInterface) invitationsmodels.APIInterface {
+				return originalImplementation
+			},
+		},
+	}
+}
+
+// makeDefaultEmailService is used if no email delivery service is configured. There is no
+// default way of sending invitations, so creating an invite fails until one is configured.
+func makeDefaultEmailService() emaildelivery.EmailDeliveryInterface {
+	sendEmail := func(input emaildelivery.EmailType, userContext supertokens.UserContext) error {
+		return errors.New("no email delivery service is configured for the invitations recipe. Please set EmailDelivery.Service (for example using invitations.MakeSMTPService)")
+	}
+	return emaildelivery.EmailDeliveryInterface{
+		SendEmail: &sendEmail,
+	}
+}
+
+func generateInviteToken() (string, error) {
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(token), nil
+}
+
+func hashInviteToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+func getCurrentTimeInMS() int64 {
+	return time.Now().UnixNano() / int64(time.Millisecond)
+}
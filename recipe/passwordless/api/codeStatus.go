@@ -0,0 +1,71 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package api
+
+import (
+	"github.com/supertokens/supertokens-golang/recipe/passwordless/crossdevice"
+	"github.com/supertokens/supertokens-golang/recipe/passwordless/plessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+// CodeStatus is polled by the device that requested a magic link, to get its session once the
+// link is opened on another device
+func CodeStatus(apiImplementation plessmodels.APIInterface, tenantId string, options plessmodels.APIOptions, userContext supertokens.UserContext) error {
+	if apiImplementation.CodeStatusGET == nil || (*apiImplementation.CodeStatusGET) == nil {
+		options.OtherHandler(options.Res, options.Req)
+		return nil
+	}
+	preAuthSessionID := options.Req.URL.Query().Get("preAuthSessionId")
+	if preAuthSessionID == "" {
+		return supertokens.BadInputError{Msg: "Please provide the preAuthSessionId as a GET param"}
+	}
+	// the device ID proves that the request comes from the device that requested the link, so it
+	// is sent in a header to keep it out of the logs
+	deviceID := options.Req.Header.Get(crossdevice.DeviceIdHeaderKey)
+	if deviceID == "" {
+		return supertokens.BadInputError{Msg: "Please provide the deviceId in the " + crossdevice.DeviceIdHeaderKey + " header"}
+	}
+
+	response, err := (*apiImplementation.CodeStatusGET)(deviceID, preAuthSessionID, tenantId, options, userContext)
+	if err != nil {
+		return err
+	}
+
+	var result map[string]interface{}
+
+	if response.OK != nil {
+		result = map[string]interface{}{
+			"status":         "OK",
+			"createdNewUser": response.OK.CreatedNewUser,
+			"user":           response.OK.User,
+		}
+	} else if response.Pending != nil {
+		result = map[string]interface{}{
+			"status": "PENDING",
+		}
+	} else if response.RestartFlowError != nil {
+		result = map[string]interface{}{
+			"status": "RESTART_FLOW_ERROR",
+		}
+	} else if response.GeneralError != nil {
+		result = supertokens.ConvertGeneralErrorToJsonResponse(*response.GeneralError)
+	} else {
+		return supertokens.ErrorIfNoResponse(options.Res)
+	}
+
+	return supertokens.Send200Response(options.Res, result)
+}
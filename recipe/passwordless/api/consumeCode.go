@@ -118,6 +118,18 @@ func ConsumeCode(apiImplementation plessmodels.APIInterface, tenantId string, op
 		result = map[string]interface{}{
 			"status": "RESTART_FLOW_ERROR",
 		}
+	} else if response.CrossDeviceLoginApproved != nil {
+		result = map[string]interface{}{
+			"status": "CROSS_DEVICE_LOGIN_APPROVED",
+		}
+	} else if response.InviteRequiredError != nil {
+		result = map[string]interface{}{
+			"status": "INVITE_REQUIRED_ERROR",
+		}
+	} else if response.PhoneNumberNotInvitableError != nil {
+		result = map[string]interface{}{
+			"status": "PHONE_NUMBER_NOT_INVITABLE_ERROR",
+		}
 	} else if response.GeneralError != nil {
 		result = supertokens.ConvertGeneralErrorToJsonResponse(*response.GeneralError)
 	} else {
@@ -21,6 +21,7 @@ import (
 	"strings"
 
 	"github.com/nyaruka/phonenumbers"
+	"github.com/supertokens/supertokens-golang/recipe/passwordless/phonenumber"
 	"github.com/supertokens/supertokens-golang/recipe/passwordless/plessmodels"
 	"github.com/supertokens/supertokens-golang/supertokens"
 )
@@ -80,6 +81,24 @@ func CreateCode(apiImplementation plessmodels.APIInterface, tenantId string, opt
 		}
 	}
 
+	if okPhoneNumber && options.Config.PhoneNumber != nil {
+		normalisedPhoneNumber, validationError := phonenumber.Normalise(options.Config.PhoneNumber, phoneNumber.(string), tenantId)
+		if validationError != nil {
+			result := map[string]interface{}{
+				"status":  "INVALID_PHONE_NUMBER_ERROR",
+				"reason":  validationError.Reason,
+				"message": validationError.Message,
+			}
+			if validationError.NumberType != nil {
+				result["numberType"] = *validationError.NumberType
+			}
+			return supertokens.Send200Response(options.Res, result)
+		}
+		// the number is validated by ValidatePhoneNumber in the E.164 format, so that numbers
+		// without a country code are accepted for the default region
+		phoneNumber = normalisedPhoneNumber
+	}
+
 	if okPhoneNumber {
 		var validateErr *string
 		if options.Config.ContactMethodPhone.Enabled {
@@ -128,6 +147,11 @@ func CreateCode(apiImplementation plessmodels.APIInterface, tenantId string, opt
 			"preAuthSessionId": response.OK.PreAuthSessionID,
 			"flowType":         response.OK.FlowType,
 		}
+	} else if response.EmailNotAllowedError != nil {
+		result = map[string]interface{}{
+			"status": "EMAIL_NOT_ALLOWED_ERROR",
+			"reason": response.EmailNotAllowedError.Reason,
+		}
 	} else if response.GeneralError != nil {
 		result = supertokens.ConvertGeneralErrorToJsonResponse(*response.GeneralError)
 	} else {
@@ -17,10 +17,16 @@ package api
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/supertokens/supertokens-golang/ingredients/emaildelivery"
+	"github.com/supertokens/supertokens-golang/ingredients/emailpolicy"
 	"github.com/supertokens/supertokens-golang/ingredients/smsdelivery"
 	"github.com/supertokens/supertokens-golang/recipe/emailverification"
+	"github.com/supertokens/supertokens-golang/recipe/invitations"
+	"github.com/supertokens/supertokens-golang/recipe/invitations/invitationsmodels"
+	"github.com/supertokens/supertokens-golang/recipe/passwordless/codepolicy"
+	"github.com/supertokens/supertokens-golang/recipe/passwordless/crossdevice"
 	"github.com/supertokens/supertokens-golang/recipe/passwordless/plessmodels"
 	"github.com/supertokens/supertokens-golang/recipe/session"
 	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
@@ -30,7 +36,40 @@ import (
 func MakeAPIImplementation() plessmodels.APIInterface {
 
 	consumeCodePOST := func(userInput *plessmodels.UserInputCodeWithDeviceID, linkCode *string, preAuthSessionID string, tenantId string, options plessmodels.APIOptions, userContext supertokens.UserContext) (plessmodels.ConsumeCodePOSTResponse, error) {
+		// a magic link opened on another device than the one that requested it only approves the
+		// login attempt, and the session is created for the device that requested it
+		var crossDeviceAttempt *crossdevice.LoginAttempt
+		if options.Config.CrossDeviceLink != nil && linkCode != nil {
+			attempt, err := crossdevice.GetLoginAttempt(*options.Config.CrossDeviceLink, preAuthSessionID, userContext)
+			if err != nil {
+				return plessmodels.ConsumeCodePOSTResponse{}, err
+			}
+			if attempt != nil && !crossdevice.IsSameDevice(*attempt, options.Req.Header.Get(crossdevice.DeviceIdHeaderKey)) {
+				crossDeviceAttempt = attempt
+			}
+		}
+
+		var invite *invitationsmodels.Invite
+		invitationsInstance := invitations.GetRecipeInstance()
+		if invitationsInstance != nil && invitationsInstance.IsInviteOnlyTenant(tenantId) {
+			inviteForSignUp, rejectedResponse, err := getInviteForSignUp(invitationsInstance, preAuthSessionID, tenantId, options, userContext)
+			if err != nil {
+				return plessmodels.ConsumeCodePOSTResponse{}, err
+			}
+			if rejectedResponse != nil {
+				return *rejectedResponse, nil
+			}
+			invite = inviteForSignUp
+		}
+
 		response, err := (*options.RecipeImplementation.ConsumeCode)(userInput, linkCode, preAuthSessionID, tenantId, userContext)
+		if invitationsInstance != nil && (err != nil || response.OK == nil || !response.OK.CreatedNewUser) {
+			// no user was created, so the invite can be used again
+			cancelErr := invitationsInstance.CancelSignUp(invite, userContext)
+			if err == nil {
+				err = cancelErr
+			}
+		}
 		if err != nil {
 			return plessmodels.ConsumeCodePOSTResponse{}, err
 		}
@@ -45,6 +84,13 @@ func MakeAPIImplementation() plessmodels.APIInterface {
 
 		user := response.OK.User
 
+		if invitationsInstance != nil && response.OK.CreatedNewUser {
+			err = invitationsInstance.CompleteSignUp(invite, user.ID, userContext)
+			if err != nil {
+				return plessmodels.ConsumeCodePOSTResponse{}, err
+			}
+		}
+
 		if user.Email != nil {
 			evInstance := emailverification.GetRecipeInstance()
 			if evInstance != nil {
@@ -61,6 +107,22 @@ func MakeAPIImplementation() plessmodels.APIInterface {
 			}
 		}
 
+		if crossDeviceAttempt != nil {
+			err = crossdevice.Approve(*options.Config.CrossDeviceLink, *crossDeviceAttempt, user.ID, response.OK.CreatedNewUser, userContext)
+			if err != nil {
+				return plessmodels.ConsumeCodePOSTResponse{}, err
+			}
+			return plessmodels.ConsumeCodePOSTResponse{
+				CrossDeviceLoginApproved: &struct{}{},
+			}, nil
+		}
+		if options.Config.CrossDeviceLink != nil {
+			err = crossdevice.Complete(*options.Config.CrossDeviceLink, preAuthSessionID, userContext)
+			if err != nil {
+				return plessmodels.ConsumeCodePOSTResponse{}, err
+			}
+		}
+
 		session, err := session.CreateNewSession(options.Req, options.Res, tenantId, user.ID, map[string]interface{}{}, map[string]interface{}{}, userContext)
 		if err != nil {
 			return plessmodels.ConsumeCodePOSTResponse{}, err
@@ -80,6 +142,27 @@ func MakeAPIImplementation() plessmodels.APIInterface {
 	}
 
 	createCodePOST := func(email *string, phoneNumber *string, tenantId string, options plessmodels.APIOptions, userContext supertokens.UserContext) (plessmodels.CreateCodePOSTResponse, error) {
+		if email != nil && options.Config.EmailPolicy != nil {
+			// existing users can always sign in, the policy only applies to new users
+			user, err := (*options.RecipeImplementation.GetUserByEmail)(*email, tenantId, userContext)
+			if err != nil {
+				return plessmodels.CreateCodePOSTResponse{}, err
+			}
+			if user == nil {
+				normalisedEmail := emailpolicy.Normalise(options.Config.EmailPolicy, *email, tenantId)
+				reason := emailpolicy.CheckSignUpAllowed(options.Config.EmailPolicy, normalisedEmail, tenantId)
+				if reason != nil {
+					return plessmodels.CreateCodePOSTResponse{
+						EmailNotAllowedError: &struct {
+							Reason emailpolicy.Reason
+						}{
+							Reason: *reason,
+						},
+					}, nil
+				}
+			}
+		}
+
 		var userInputCodeInput *string
 		if options.Config.GetCustomUserInputCode != nil {
 			c, err := options.Config.GetCustomUserInputCode(tenantId, userContext)
@@ -94,6 +177,11 @@ func MakeAPIImplementation() plessmodels.APIInterface {
 			return plessmodels.CreateCodePOSTResponse{}, err
 		}
 
+		err = startCrossDeviceLoginAttempt(options, *response.OK, tenantId, userContext)
+		if err != nil {
+			return plessmodels.CreateCodePOSTResponse{}, err
+		}
+
 		// now we will send an email / text message
 		var magicLink *string
 		var userInputCode *string
@@ -252,6 +340,40 @@ func MakeAPIImplementation() plessmodels.APIInterface {
 			}, nil
 		}
 
+		if options.Config.CodePolicy != nil {
+			policy := codepolicy.GetPolicy(*options.Config.CodePolicy, tenantId)
+			if policy.ResendCooldown > 0 {
+				lastCodeCreatedAt := uint64(0)
+				for _, code := range deviceInfo.Codes {
+					if code.TimeCreated > lastCodeCreatedAt {
+						lastCodeCreatedAt = code.TimeCreated
+					}
+				}
+				resendAllowedAt := codepolicy.GetResendAllowedAt(policy, lastCodeCreatedAt)
+				if uint64(time.Now().UnixNano()/int64(time.Millisecond)) < resendAllowedAt {
+					return plessmodels.ResendCodePOSTResponse{
+						ResendCooldownError: &struct {
+							ResendAllowedAt uint64
+						}{
+							ResendAllowedAt: resendAllowedAt,
+						},
+					}, nil
+				}
+			}
+
+			// the count is incremented before the code is sent, so that concurrent requests
+			// cannot all pass the check. A resend that fails after this still counts.
+			resendCount, err := options.Config.CodePolicy.Store.IncrementResendCount(deviceInfo.PreAuthSessionID, userContext)
+			if err != nil {
+				return plessmodels.ResendCodePOSTResponse{}, err
+			}
+			if policy.MaxResends >= 0 && resendCount > policy.MaxResends {
+				return plessmodels.ResendCodePOSTResponse{
+					MaxResendsReachedError: &struct{}{},
+				}, nil
+			}
+		}
+
 		for numberOfTriesToCreateNewCode := 0; numberOfTriesToCreateNewCode < 3; numberOfTriesToCreateNewCode++ {
 			var userInputCodeInput *string
 			if options.Config.GetCustomUserInputCode != nil {
@@ -276,6 +398,11 @@ func MakeAPIImplementation() plessmodels.APIInterface {
 				}, nil
 			}
 
+			err = startCrossDeviceLoginAttempt(options, *response.OK, tenantId, userContext)
+			if err != nil {
+				return plessmodels.ResendCodePOSTResponse{}, err
+			}
+
 			var magicLink *string
 			var userInputCode *string
 			flowType := options.Config.FlowType
@@ -390,11 +517,127 @@ func MakeAPIImplementation() plessmodels.APIInterface {
 		}, nil
 	}
 
+	codeStatusGET := func(deviceID string, preAuthSessionID string, tenantId string, options plessmodels.APIOptions, userContext supertokens.UserContext) (plessmodels.CodeStatusGETResponse, error) {
+		config := options.Config.CrossDeviceLink
+		if config == nil {
+			return plessmodels.CodeStatusGETResponse{
+				RestartFlowError: &struct{}{},
+			}, nil
+		}
+
+		attempt, err := crossdevice.WaitForApproval(*config, preAuthSessionID, deviceID, userContext)
+		if err != nil {
+			return plessmodels.CodeStatusGETResponse{}, err
+		}
+		if attempt == nil || attempt.TenantId != tenantId {
+			return plessmodels.CodeStatusGETResponse{
+				RestartFlowError: &struct{}{},
+			}, nil
+		}
+		if !attempt.Approved {
+			return plessmodels.CodeStatusGETResponse{
+				Pending: &struct{}{},
+			}, nil
+		}
+
+		// another poll of the same device may have got the session already
+		attempt, err = crossdevice.ConsumeApproval(*config, preAuthSessionID, userContext)
+		if err != nil {
+			return plessmodels.CodeStatusGETResponse{}, err
+		}
+		if attempt == nil {
+			return plessmodels.CodeStatusGETResponse{
+				RestartFlowError: &struct{}{},
+			}, nil
+		}
+		user, err := (*options.RecipeImplementation.GetUserByID)(attempt.UserId, userContext)
+		if err != nil {
+			return plessmodels.CodeStatusGETResponse{}, err
+		}
+		if user == nil {
+			// the user was deleted after the link was opened
+			return plessmodels.CodeStatusGETResponse{
+				RestartFlowError: &struct{}{},
+			}, nil
+		}
+
+		session, err := session.CreateNewSession(options.Req, options.Res, tenantId, user.ID, map[string]interface{}{}, map[string]interface{}{}, userContext)
+		if err != nil {
+			return plessmodels.CodeStatusGETResponse{}, err
+		}
+
+		return plessmodels.CodeStatusGETResponse{
+			OK: &struct {
+				CreatedNewUser bool
+				User           plessmodels.User
+				Session        sessmodels.SessionContainer
+			}{
+				CreatedNewUser: attempt.CreatedNewUser,
+				User:           *user,
+				Session:        session,
+			},
+		}, nil
+	}
+
 	return plessmodels.APIInterface{
 		ConsumeCodePOST:      &consumeCodePOST,
 		CreateCodePOST:       &createCodePOST,
 		EmailExistsGET:       &emailExistsGET,
 		PhoneNumberExistsGET: &phoneNumberExistsGET,
 		ResendCodePOST:       &resendCodePOST,
+		CodeStatusGET:        &codeStatusGET,
+	}
+}
+
+// startCrossDeviceLoginAttempt saves the login attempt until the code expires, so that the link
+// can be opened on another device
+func startCrossDeviceLoginAttempt(options plessmodels.APIOptions, code plessmodels.NewCode, tenantId string, userContext supertokens.UserContext) error {
+	if options.Config.CrossDeviceLink == nil {
+		return nil
+	}
+	return crossdevice.StartLoginAttempt(*options.Config.CrossDeviceLink, code.PreAuthSessionID, code.DeviceID, tenantId, int64(code.TimeCreated+code.CodeLifetime), userContext)
+}
+
+// getInviteForSignUp returns the reserved invite of the user signing up in an invite only tenant,
+// or nil if the user already exists. If the user cannot sign up, the response to send is returned
+// instead. Users can only be invited by email, so new users signing up with a phone number get a
+// PhoneNumberNotInvitableError.
+func getInviteForSignUp(invitationsInstance *invitations.Recipe, preAuthSessionID string, tenantId string, options plessmodels.APIOptions, userContext supertokens.UserContext) (*invitationsmodels.Invite, *plessmodels.ConsumeCodePOSTResponse, error) {
+	device, err := (*options.RecipeImplementation.ListCodesByPreAuthSessionID)(preAuthSessionID, tenantId, userContext)
+	if err != nil {
+		return nil, nil, err
+	}
+	if device == nil {
+		// consuming the code returns a RESTART_FLOW_ERROR
+		return nil, nil, nil
+	}
+
+	var user *plessmodels.User
+	if device.Email != nil {
+		user, err = (*options.RecipeImplementation.GetUserByEmail)(*device.Email, tenantId, userContext)
+	} else if device.PhoneNumber != nil {
+		user, err = (*options.RecipeImplementation.GetUserByPhoneNumber)(*device.PhoneNumber, tenantId, userContext)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if user != nil {
+		return nil, nil, nil
+	}
+	if device.Email == nil {
+		supertokens.LogDebugMessage("invitations: sign up rejected because only emails can be invited")
+		return nil, &plessmodels.ConsumeCodePOSTResponse{
+			PhoneNumberNotInvitableError: &struct{}{},
+		}, nil
+	}
+	invite, allowed, err := invitationsInstance.GetInviteForSignUp(tenantId, *device.Email, options.Config.EmailPolicy, options.Req, userContext)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !allowed {
+		return nil, &plessmodels.ConsumeCodePOSTResponse{
+			InviteRequiredError: &struct{}{},
+		}, nil
 	}
+	return invite, nil, nil
 }
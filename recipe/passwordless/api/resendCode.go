@@ -73,6 +73,15 @@ func ResendCode(apiImplementation plessmodels.APIInterface, tenantId string, opt
 		result = map[string]interface{}{
 			"status": "RESTART_FLOW_ERROR",
 		}
+	} else if response.ResendCooldownError != nil {
+		result = map[string]interface{}{
+			"status":          "RESEND_COOLDOWN_ERROR",
+			"resendAllowedAt": response.ResendCooldownError.ResendAllowedAt,
+		}
+	} else if response.MaxResendsReachedError != nil {
+		result = map[string]interface{}{
+			"status": "MAX_RESENDS_REACHED_ERROR",
+		}
 	} else if response.GeneralError != nil {
 		result = map[string]interface{}{
 			"status":  "GENERAL_ERROR",
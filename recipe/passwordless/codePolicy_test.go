@@ -0,0 +1,216 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package passwordless
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/ingredients/deliverycapture"
+	"github.com/supertokens/supertokens-golang/ingredients/emaildelivery"
+	"github.com/supertokens/supertokens-golang/recipe/passwordless/codepolicy"
+	"github.com/supertokens/supertokens-golang/recipe/passwordless/plessmodels"
+	"github.com/supertokens/supertokens-golang/recipe/session"
+	"github.com/supertokens/supertokens-golang/supertokens"
+	"github.com/supertokens/supertokens-golang/test/unittesting"
+)
+
+func resendCodeForTest(t *testing.T, url string, deviceID string, preAuthSessionID string) map[string]interface{} {
+	body, err := json.Marshal(map[string]interface{}{
+		"deviceId":         deviceID,
+		"preAuthSessionId": preAuthSessionID,
+	})
+	assert.NoError(t, err)
+	resp, err := http.Post(url+"/auth/signinup/code/resend", "application/json", bytes.NewBuffer(body))
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	dataInBytes, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	resp.Body.Close()
+
+	var result map[string]interface{}
+	assert.NoError(t, json.Unmarshal(dataInBytes, &result))
+	return result
+}
+
+func TestCodeFormatCannotBeUsedWithGetCustomUserInputCode(t *testing.T) {
+	resetAll()
+	defer resetAll()
+	codeLength := 8
+
+	assert.PanicsWithValue(t, "CodeLength and Charset of the CodePolicy cannot be used with GetCustomUserInputCode", func() {
+		supertokens.Init(supertokens.TypeInput{
+			Supertokens: &supertokens.ConnectionInfo{
+				ConnectionURI: "http://localhost:8080",
+			},
+			AppInfo: supertokens.AppInfo{
+				APIDomain:     "api.supertokens.io",
+				AppName:       "SuperTokens",
+				WebsiteDomain: "supertokens.io",
+			},
+			RecipeList: []supertokens.Recipe{
+				Init(plessmodels.TypeInput{
+					FlowType: "USER_INPUT_CODE",
+					ContactMethodEmail: plessmodels.ContactMethodEmailConfig{
+						Enabled: true,
+					},
+					GetCustomUserInputCode: func(tenantId string, userContext supertokens.UserContext) (string, error) {
+						return "123456", nil
+					},
+					CodePolicy: &codepolicy.TypeInput{
+						Default: codepolicy.Policy{CodeLength: &codeLength},
+					},
+				}),
+			},
+		})
+	})
+}
+
+func TestCodePolicyFormatAndLifetime(t *testing.T) {
+	BeforeEach()
+	connectionURI := unittesting.StartUpST("localhost", "8080")
+	defer AfterEach()
+
+	codeLength := 8
+	charset := "ABCDEFGH"
+	lifetime := time.Minute
+	inbox := deliverycapture.MakeInbox(10)
+	testServer := supertokensInitForTest(t, connectionURI,
+		Init(plessmodels.TypeInput{
+			FlowType: "USER_INPUT_CODE",
+			ContactMethodEmail: plessmodels.ContactMethodEmailConfig{
+				Enabled: true,
+			},
+			EmailDelivery: &emaildelivery.TypeInput{
+				Service: MakeCaptureEmailService(inbox),
+			},
+			CodePolicy: &codepolicy.TypeInput{
+				Default: codepolicy.Policy{
+					CodeLength:   &codeLength,
+					Charset:      &charset,
+					CodeLifetime: &lifetime,
+				},
+			},
+		}),
+		session.Init(nil),
+	)
+	defer testServer.Close()
+
+	codeInfo, err := CreateCodeWithEmail("public", "test@example.com", nil)
+	assert.NoError(t, err)
+	assert.Len(t, codeInfo.OK.UserInputCode, 8)
+	assert.Equal(t, uint64(60000), codeInfo.OK.CodeLifetime)
+
+	result := resendCodeForTest(t, testServer.URL, codeInfo.OK.DeviceID, codeInfo.OK.PreAuthSessionID)
+	assert.Equal(t, "OK", result["status"])
+	message := inbox.GetLatestMessage("test@example.com")
+	assert.NotNil(t, message)
+	assert.Len(t, message.Codes, 1)
+	assert.Len(t, message.Codes[0], 8)
+
+	resp, err := ConsumeCodeWithUserInputCode("public", codeInfo.OK.DeviceID, message.Codes[0], codeInfo.OK.PreAuthSessionID)
+	assert.NoError(t, err)
+	assert.NotNil(t, resp.OK)
+}
+
+func TestCodesAreRevokedAfterMaxFailedAttempts(t *testing.T) {
+	BeforeEach()
+	connectionURI := unittesting.StartUpST("localhost", "8080")
+	defer AfterEach()
+
+	maxFailedAttempts := 2
+	testServer := supertokensInitForTest(t, connectionURI,
+		Init(plessmodels.TypeInput{
+			FlowType: "USER_INPUT_CODE",
+			ContactMethodEmail: plessmodels.ContactMethodEmailConfig{
+				Enabled: true,
+			},
+			CodePolicy: &codepolicy.TypeInput{
+				Default: codepolicy.Policy{MaxFailedAttempts: &maxFailedAttempts},
+			},
+		}),
+		session.Init(nil),
+	)
+	defer testServer.Close()
+
+	codeInfo, err := CreateCodeWithEmail("public", "test@example.com", nil)
+	assert.NoError(t, err)
+
+	resp, err := ConsumeCodeWithUserInputCode("public", codeInfo.OK.DeviceID, "wrongcode", codeInfo.OK.PreAuthSessionID)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, resp.IncorrectUserInputCodeError.FailedCodeInputAttemptCount)
+	assert.Equal(t, 2, resp.IncorrectUserInputCodeError.MaximumCodeInputAttempts)
+
+	resp, err = ConsumeCodeWithUserInputCode("public", codeInfo.OK.DeviceID, "wrongcode", codeInfo.OK.PreAuthSessionID)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, resp.IncorrectUserInputCodeError.FailedCodeInputAttemptCount)
+
+	// the right code can no longer be used
+	resp, err = ConsumeCodeWithUserInputCode("public", codeInfo.OK.DeviceID, codeInfo.OK.UserInputCode, codeInfo.OK.PreAuthSessionID)
+	assert.NoError(t, err)
+	assert.NotNil(t, resp.RestartFlowError)
+}
+
+func TestResendCooldownAndMaxResends(t *testing.T) {
+	BeforeEach()
+	connectionURI := unittesting.StartUpST("localhost", "8080")
+	defer AfterEach()
+
+	cooldown := time.Second
+	maxResends := 1
+	inbox := deliverycapture.MakeInbox(10)
+	testServer := supertokensInitForTest(t, connectionURI,
+		Init(plessmodels.TypeInput{
+			FlowType: "USER_INPUT_CODE",
+			ContactMethodEmail: plessmodels.ContactMethodEmailConfig{
+				Enabled: true,
+			},
+			EmailDelivery: &emaildelivery.TypeInput{
+				Service: MakeCaptureEmailService(inbox),
+			},
+			CodePolicy: &codepolicy.TypeInput{
+				Default: codepolicy.Policy{
+					ResendCooldown: &cooldown,
+					MaxResends:     &maxResends,
+				},
+			},
+		}),
+		session.Init(nil),
+	)
+	defer testServer.Close()
+
+	codeInfo, err := CreateCodeWithEmail("public", "test@example.com", nil)
+	assert.NoError(t, err)
+
+	result := resendCodeForTest(t, testServer.URL, codeInfo.OK.DeviceID, codeInfo.OK.PreAuthSessionID)
+	assert.Equal(t, "RESEND_COOLDOWN_ERROR", result["status"])
+	assert.Equal(t, float64(codeInfo.OK.TimeCreated+1000), result["resendAllowedAt"])
+
+	time.Sleep(cooldown)
+	result = resendCodeForTest(t, testServer.URL, codeInfo.OK.DeviceID, codeInfo.OK.PreAuthSessionID)
+	assert.Equal(t, "OK", result["status"])
+
+	time.Sleep(cooldown)
+	result = resendCodeForTest(t, testServer.URL, codeInfo.OK.DeviceID, codeInfo.OK.PreAuthSessionID)
+	assert.Equal(t, "MAX_RESENDS_REACHED_ERROR", result["status"])
+	assert.Len(t, inbox.GetMessages(nil), 1)
+}
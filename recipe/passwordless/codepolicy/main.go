@@ -0,0 +1,174 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package codepolicy
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+	"unicode/utf8"
+)
+
+const defaultCharset = "0123456789"
+
+func NormaliseTypeInput(input TypeInput) (TypeNormalisedInput, error) {
+	defaultPolicy, err := normalisePolicy(input.Default, Policy{})
+	if err != nil {
+		return TypeNormalisedInput{}, err
+	}
+
+	tenantPolicies := map[string]NormalisedPolicy{}
+	for tenantId, policy := range input.TenantPolicies {
+		tenantPolicy, err := normalisePolicy(policy, input.Default)
+		if err != nil {
+			return TypeNormalisedInput{}, fmt.Errorf("invalid code policy for tenant %s: %s", tenantId, err.Error())
+		}
+		tenantPolicies[tenantId] = tenantPolicy
+	}
+
+	result := TypeNormalisedInput{
+		Default:        defaultPolicy,
+		TenantPolicies: tenantPolicies,
+		Store:          MakeInMemoryStore(),
+	}
+	if input.Store != nil {
+		result.Store = *input.Store
+	}
+	return result, nil
+}
+
+// normalisePolicy applies the defaults to the fields that are not set in policy or fallback
+func normalisePolicy(policy Policy, fallback Policy) (NormalisedPolicy, error) {
+	result := NormalisedPolicy{
+		CodeLength:        getIntOrDefault(policy.CodeLength, fallback.CodeLength, 6),
+		Charset:           getStringOrDefault(policy.Charset, fallback.Charset, defaultCharset),
+		CodeLifetime:      getDurationOrDefault(policy.CodeLifetime, fallback.CodeLifetime, 0),
+		MaxFailedAttempts: getIntOrDefault(policy.MaxFailedAttempts, fallback.MaxFailedAttempts, 0),
+		ResendCooldown:    getDurationOrDefault(policy.ResendCooldown, fallback.ResendCooldown, 0),
+		MaxResends:        getIntOrDefault(policy.MaxResends, fallback.MaxResends, -1),
+	}
+	if result.CodeLength < 4 {
+		return NormalisedPolicy{}, errors.New("CodeLength must be at least 4")
+	}
+	if utf8.RuneCountInString(result.Charset) < 2 {
+		return NormalisedPolicy{}, errors.New("Charset must contain at least 2 characters")
+	}
+	if (policy.CodeLifetime != nil || fallback.CodeLifetime != nil) && result.CodeLifetime <= 0 {
+		return NormalisedPolicy{}, errors.New("CodeLifetime must be greater than 0")
+	}
+	if (policy.MaxFailedAttempts != nil || fallback.MaxFailedAttempts != nil) && result.MaxFailedAttempts < 1 {
+		return NormalisedPolicy{}, errors.New("MaxFailedAttempts must be at least 1")
+	}
+	if result.ResendCooldown < 0 {
+		return NormalisedPolicy{}, errors.New("ResendCooldown must not be negative")
+	}
+	if (policy.MaxResends != nil || fallback.MaxResends != nil) && result.MaxResends < 0 {
+		return NormalisedPolicy{}, errors.New("MaxResends must not be negative")
+	}
+	return result, nil
+}
+
+// GetPolicy returns the policy that applies to tenantId
+func GetPolicy(config TypeNormalisedInput, tenantId string) NormalisedPolicy {
+	if policy, ok := config.TenantPolicies[tenantId]; ok {
+		return policy
+	}
+	return config.Default
+}
+
+// GenerateUserInputCode returns a random code of CodeLength characters from Charset
+func GenerateUserInputCode(policy NormalisedPolicy) (string, error) {
+	charset := []rune(policy.Charset)
+	code := make([]rune, policy.CodeLength)
+	for i := range code {
+		index, err := rand.Int(rand.Reader, big.NewInt(int64(len(charset))))
+		if err != nil {
+			return "", err
+		}
+		code[i] = charset[index.Int64()]
+	}
+	return string(code), nil
+}
+
+// GetCodeLifetime returns the lifetime (in milliseconds) of the codes, given the lifetime
+// configured in the core
+func GetCodeLifetime(policy NormalisedPolicy, coreCodeLifetime uint64) uint64 {
+	if policy.CodeLifetime == 0 {
+		return coreCodeLifetime
+	}
+	lifetime := uint64(policy.CodeLifetime.Milliseconds())
+	if lifetime < coreCodeLifetime {
+		return lifetime
+	}
+	return coreCodeLifetime
+}
+
+// IsCodeExpired returns true if the code created at timeCreated (in milliseconds) has outlived
+// the lifetime of the policy. Codes that outlive the lifetime of the core are removed by the core.
+func IsCodeExpired(policy NormalisedPolicy, timeCreated uint64, now time.Time) bool {
+	if policy.CodeLifetime == 0 {
+		return false
+	}
+	return uint64(now.UnixNano()/int64(time.Millisecond)) >= timeCreated+uint64(policy.CodeLifetime.Milliseconds())
+}
+
+// GetMaximumCodeInputAttempts returns the number of attempts allowed, given the maximum
+// configured in the core
+func GetMaximumCodeInputAttempts(policy NormalisedPolicy, coreMaximum int) int {
+	if policy.MaxFailedAttempts != 0 && policy.MaxFailedAttempts < coreMaximum {
+		return policy.MaxFailedAttempts
+	}
+	return coreMaximum
+}
+
+// GetResendAllowedAt returns the time (in milliseconds) after which a new code can be sent for a
+// device whose last code was created at lastCodeCreatedAt
+func GetResendAllowedAt(policy NormalisedPolicy, lastCodeCreatedAt uint64) uint64 {
+	return lastCodeCreatedAt + uint64(policy.ResendCooldown.Milliseconds())
+}
+
+func getIntOrDefault(value *int, fallback *int, defaultValue int) int {
+	if value != nil {
+		return *value
+	}
+	if fallback != nil {
+		return *fallback
+	}
+	return defaultValue
+}
+
+func getStringOrDefault(value *string, fallback *string, defaultValue string) string {
+	if value != nil {
+		return *value
+	}
+	if fallback != nil {
+		return *fallback
+	}
+	return defaultValue
+}
+
+func getDurationOrDefault(value *time.Duration, fallback *time.Duration, defaultValue time.Duration) time.Duration {
+	if value != nil {
+		return *value
+	}
+	if fallback != nil {
+		return *fallback
+	}
+	return defaultValue
+}
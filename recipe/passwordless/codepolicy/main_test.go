@@ -0,0 +1,171 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package codepolicy
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultPolicy(t *testing.T) {
+	config, err := NormaliseTypeInput(TypeInput{})
+	assert.NoError(t, err)
+	assert.Equal(t, NormalisedPolicy{
+		CodeLength: 6,
+		Charset:    "0123456789",
+		MaxResends: -1,
+	}, config.Default)
+	assert.Equal(t, uint64(900000), GetCodeLifetime(config.Default, 900000))
+	assert.Equal(t, 5, GetMaximumCodeInputAttempts(config.Default, 5))
+	assert.False(t, IsCodeExpired(config.Default, 0, time.Now()))
+}
+
+func TestTenantPoliciesOverrideTheDefaultPolicy(t *testing.T) {
+	codeLength := 8
+	lifetime := 5 * time.Minute
+	maxFailedAttempts := 3
+	charset := "ABCDEF"
+	config, err := NormaliseTypeInput(TypeInput{
+		Default: Policy{
+			CodeLifetime:      &lifetime,
+			MaxFailedAttempts: &maxFailedAttempts,
+		},
+		TenantPolicies: map[string]Policy{
+			"strict": {CodeLength: &codeLength, Charset: &charset},
+		},
+	})
+	assert.NoError(t, err)
+
+	policy := GetPolicy(config, "strict")
+	assert.Equal(t, 8, policy.CodeLength)
+	assert.Equal(t, "ABCDEF", policy.Charset)
+	// the fields that are not overridden come from the default policy
+	assert.Equal(t, lifetime, policy.CodeLifetime)
+	assert.Equal(t, 3, policy.MaxFailedAttempts)
+
+	assert.Equal(t, 6, GetPolicy(config, "public").CodeLength)
+}
+
+func TestInvalidPolicies(t *testing.T) {
+	zero := 0
+	zeroDuration := time.Duration(0)
+	negativeDuration := -time.Second
+	negative := -1
+	shortCharset := "1"
+
+	for _, testCase := range []struct {
+		policy  Policy
+		message string
+	}{
+		{Policy{CodeLength: &zero}, "CodeLength must be at least 4"},
+		{Policy{Charset: &shortCharset}, "Charset must contain at least 2 characters"},
+		{Policy{CodeLifetime: &zeroDuration}, "CodeLifetime must be greater than 0"},
+		{Policy{MaxFailedAttempts: &zero}, "MaxFailedAttempts must be at least 1"},
+		{Policy{ResendCooldown: &negativeDuration}, "ResendCooldown must not be negative"},
+		{Policy{MaxResends: &negative}, "MaxResends must not be negative"},
+	} {
+		_, err := NormaliseTypeInput(TypeInput{Default: testCase.policy})
+		assert.Error(t, err)
+		assert.Equal(t, testCase.message, err.Error())
+	}
+
+	_, err := NormaliseTypeInput(TypeInput{
+		TenantPolicies: map[string]Policy{"tenant1": {CodeLength: &zero}},
+	})
+	assert.Error(t, err)
+	assert.Equal(t, "invalid code policy for tenant tenant1: CodeLength must be at least 4", err.Error())
+}
+
+func TestGenerateUserInputCode(t *testing.T) {
+	codeLength := 10
+	charset := "AB"
+	config, err := NormaliseTypeInput(TypeInput{
+		Default: Policy{CodeLength: &codeLength, Charset: &charset},
+	})
+	assert.NoError(t, err)
+
+	code, err := GenerateUserInputCode(config.Default)
+	assert.NoError(t, err)
+	assert.Len(t, code, 10)
+	assert.Empty(t, strings.Trim(code, "AB"))
+}
+
+func TestPolicyCanOnlyShortenTheLimitsOfTheCore(t *testing.T) {
+	lifetime := time.Minute
+	maxFailedAttempts := 10
+	config, err := NormaliseTypeInput(TypeInput{
+		Default: Policy{CodeLifetime: &lifetime, MaxFailedAttempts: &maxFailedAttempts},
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, uint64(60000), GetCodeLifetime(config.Default, 900000))
+	assert.Equal(t, uint64(30000), GetCodeLifetime(config.Default, 30000))
+	assert.Equal(t, 5, GetMaximumCodeInputAttempts(config.Default, 5))
+
+	now := time.Now()
+	createdAt := uint64(now.UnixNano() / int64(time.Millisecond))
+	assert.False(t, IsCodeExpired(config.Default, createdAt, now))
+	assert.True(t, IsCodeExpired(config.Default, createdAt, now.Add(time.Minute)))
+}
+
+func TestInMemoryStoreCountsResends(t *testing.T) {
+	store := MakeInMemoryStore()
+	userContext := &map[string]interface{}{}
+
+	count, err := store.GetResendCount("preAuthSessionId", userContext)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, count)
+
+	for i := 1; i <= 3; i++ {
+		count, err = store.IncrementResendCount("preAuthSessionId", userContext)
+		assert.NoError(t, err)
+		assert.Equal(t, i, count)
+	}
+
+	assert.NoError(t, store.Delete("preAuthSessionId", userContext))
+	count, err = store.GetResendCount("preAuthSessionId", userContext)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, count)
+}
+
+func TestInMemoryStoreReturnsEachCountOnceForConcurrentResends(t *testing.T) {
+	store := MakeInMemoryStore()
+	userContext := &map[string]interface{}{}
+
+	var mutex sync.Mutex
+	var waitGroup sync.WaitGroup
+	counts := map[int]bool{}
+	for i := 0; i < 20; i++ {
+		waitGroup.Add(1)
+		go func() {
+			defer waitGroup.Done()
+			count, err := store.IncrementResendCount("preAuthSessionId", userContext)
+			assert.NoError(t, err)
+			mutex.Lock()
+			defer mutex.Unlock()
+			counts[count] = true
+		}()
+	}
+	waitGroup.Wait()
+
+	// so at most MaxResends of the requests get a count within the limit
+	assert.Len(t, counts, 20)
+}
@@ -0,0 +1,78 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package codepolicy
+
+import (
+	"time"
+
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+// Policy configures the codes sent by the passwordless recipe. Unset fields use the defaults.
+// The core has its own code lifetime and maximum number of attempts, so the ones set here can
+// only make them stricter.
+type Policy struct {
+	// CodeLength is the number of characters of the user input code. Defaults to 6.
+	CodeLength *int
+	// Charset contains the characters that the user input code is made of. Defaults to digits.
+	Charset *string
+	// CodeLifetime defaults to the lifetime configured in the core
+	CodeLifetime *time.Duration
+	// MaxFailedAttempts is the number of wrong user input codes after which all codes of the
+	// device are revoked. Defaults to the maximum configured in the core.
+	MaxFailedAttempts *int
+	// ResendCooldown is the time between two codes sent for the same device. Defaults to 0.
+	ResendCooldown *time.Duration
+	// MaxResends is the number of times a new code can be sent for a device. Defaults to no limit.
+	MaxResends *int
+}
+
+type TypeInput struct {
+	Default Policy
+	// TenantPolicies overrides fields of the default policy for specific tenants
+	TenantPolicies map[string]Policy
+	// Store defaults to an in-memory store, which is not shared between instances of the backend
+	Store *Store
+}
+
+type NormalisedPolicy struct {
+	CodeLength int
+	Charset    string
+	// CodeLifetime is 0 if the lifetime configured in the core is used
+	CodeLifetime time.Duration
+	// MaxFailedAttempts is 0 if the maximum configured in the core is used
+	MaxFailedAttempts int
+	ResendCooldown    time.Duration
+	// MaxResends is -1 if there is no limit
+	MaxResends int
+}
+
+type TypeNormalisedInput struct {
+	Default        NormalisedPolicy
+	TenantPolicies map[string]NormalisedPolicy
+	Store          Store
+}
+
+// Store counts the codes that were resent per login attempt (identified by its preAuthSessionId)
+type Store struct {
+	GetResendCount func(preAuthSessionId string, userContext supertokens.UserContext) (int, error)
+	// IncrementResendCount returns the count after the increment. It must increment and read the
+	// count in one step, since the resend API compares the returned count to MaxResends.
+	IncrementResendCount func(preAuthSessionId string, userContext supertokens.UserContext) (int, error)
+	// Delete is called once the login attempt is over
+	Delete func(preAuthSessionId string, userContext supertokens.UserContext) error
+}
@@ -0,0 +1,71 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package codepolicy
+
+import (
+	"sync"
+	"time"
+
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+// entries of login attempts that are not resent for this long are removed, since their codes
+// have expired in the meantime
+const inMemoryStoreEntryLifetime = 24 * time.Hour
+
+type resendCount struct {
+	count        int
+	lastResentAt time.Time
+}
+
+func MakeInMemoryStore() Store {
+	var lock sync.Mutex
+	counts := map[string]resendCount{}
+
+	removeOldEntries := func(now time.Time) {
+		for preAuthSessionId, entry := range counts {
+			if now.Sub(entry.lastResentAt) > inMemoryStoreEntryLifetime {
+				delete(counts, preAuthSessionId)
+			}
+		}
+	}
+
+	return Store{
+		GetResendCount: func(preAuthSessionId string, userContext supertokens.UserContext) (int, error) {
+			lock.Lock()
+			defer lock.Unlock()
+			return counts[preAuthSessionId].count, nil
+		},
+		IncrementResendCount: func(preAuthSessionId string, userContext supertokens.UserContext) (int, error) {
+			lock.Lock()
+			defer lock.Unlock()
+			now := time.Now()
+			removeOldEntries(now)
+			entry := counts[preAuthSessionId]
+			entry.count++
+			entry.lastResentAt = now
+			counts[preAuthSessionId] = entry
+			return entry.count, nil
+		},
+		Delete: func(preAuthSessionId string, userContext supertokens.UserContext) error {
+			lock.Lock()
+			defer lock.Unlock()
+			delete(counts, preAuthSessionId)
+			return nil
+		},
+	}
+}
@@ -19,6 +19,7 @@ const (
 	createCodeAPI              = "/signinup/code"
 	resendCodeAPI              = "/signinup/code/resend"
 	consumeCodeAPI             = "/signinup/code/consume"
+	codeStatusAPI              = "/signinup/code/status"
 	doesEmailExistAPIOld       = "/signup/email/exists"
 	doesPhoneNumberExistAPIOld = "/signup/phonenumber/exists"
 	doesEmailExistAPI          = "/passwordless/email/exists"
@@ -0,0 +1,237 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package passwordless
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/ingredients/deliverycapture"
+	"github.com/supertokens/supertokens-golang/ingredients/emaildelivery"
+	"github.com/supertokens/supertokens-golang/recipe/passwordless/crossdevice"
+	"github.com/supertokens/supertokens-golang/recipe/passwordless/plessmodels"
+	"github.com/supertokens/supertokens-golang/recipe/session"
+	"github.com/supertokens/supertokens-golang/supertokens"
+	"github.com/supertokens/supertokens-golang/test/unittesting"
+)
+
+func sendCrossDeviceRequestForTest(t *testing.T, method string, url string, body map[string]interface{}, deviceID string) (*http.Response, map[string]interface{}) {
+	var bodyReader io.Reader
+	if body != nil {
+		bodyInBytes, err := json.Marshal(body)
+		assert.NoError(t, err)
+		bodyReader = bytes.NewBuffer(bodyInBytes)
+	}
+	req, err := http.NewRequest(method, url, bodyReader)
+	assert.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	if deviceID != "" {
+		req.Header.Set(crossdevice.DeviceIdHeaderKey, deviceID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	dataInBytes, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	resp.Body.Close()
+
+	var result map[string]interface{}
+	if resp.StatusCode == 200 {
+		assert.NoError(t, json.Unmarshal(dataInBytes, &result))
+	}
+	return resp, result
+}
+
+func initCrossDeviceLinkWithoutCoreForTest(t *testing.T, crossDeviceLink *crossdevice.TypeInput) *httptest.Server {
+	err := supertokens.Init(supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: "http://localhost:8080",
+		},
+		AppInfo: supertokens.AppInfo{
+			APIDomain:     "api.supertokens.io",
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{
+			Init(plessmodels.TypeInput{
+				FlowType: "MAGIC_LINK",
+				ContactMethodEmail: plessmodels.ContactMethodEmailConfig{
+					Enabled: true,
+				},
+				CrossDeviceLink: crossDeviceLink,
+			}),
+		},
+	})
+	assert.NoError(t, err)
+	return httptest.NewServer(supertokens.Middleware(http.NewServeMux()))
+}
+
+func TestCodeStatusAPIIsDisabledByDefault(t *testing.T) {
+	resetAll()
+	defer resetAll()
+	testServer := initCrossDeviceLinkWithoutCoreForTest(t, nil)
+	defer testServer.Close()
+
+	resp, _ := sendCrossDeviceRequestForTest(t, http.MethodGet, testServer.URL+"/auth/signinup/code/status?preAuthSessionId=abc", nil, "deviceId")
+	assert.Equal(t, 404, resp.StatusCode)
+}
+
+func TestCodeStatusAPIRequiresTheDeviceId(t *testing.T) {
+	resetAll()
+	defer resetAll()
+	testServer := initCrossDeviceLinkWithoutCoreForTest(t, &crossdevice.TypeInput{})
+	defer testServer.Close()
+
+	resp, _ := sendCrossDeviceRequestForTest(t, http.MethodGet, testServer.URL+"/auth/signinup/code/status?preAuthSessionId=abc", nil, "")
+	assert.Equal(t, 400, resp.StatusCode)
+
+	// there is no login attempt for this preAuthSessionId
+	resp, result := sendCrossDeviceRequestForTest(t, http.MethodGet, testServer.URL+"/auth/signinup/code/status?preAuthSessionId=abc", nil, "deviceId")
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.Equal(t, "RESTART_FLOW_ERROR", result["status"])
+}
+
+func TestCrossDeviceLinkCannotBeUsedWithUserInputCodeFlow(t *testing.T) {
+	resetAll()
+	defer resetAll()
+
+	assert.PanicsWithValue(t, "CrossDeviceLink can only be used with the MAGIC_LINK and USER_INPUT_CODE_AND_MAGIC_LINK flow types", func() {
+		supertokens.Init(supertokens.TypeInput{
+			Supertokens: &supertokens.ConnectionInfo{
+				ConnectionURI: "http://localhost:8080",
+			},
+			AppInfo: supertokens.AppInfo{
+				APIDomain:     "api.supertokens.io",
+				AppName:       "SuperTokens",
+				WebsiteDomain: "supertokens.io",
+			},
+			RecipeList: []supertokens.Recipe{
+				Init(plessmodels.TypeInput{
+					FlowType: "USER_INPUT_CODE",
+					ContactMethodEmail: plessmodels.ContactMethodEmailConfig{
+						Enabled: true,
+					},
+					CrossDeviceLink: &crossdevice.TypeInput{},
+				}),
+			},
+		})
+	})
+}
+
+func TestMagicLinkOpenedOnAnotherDevice(t *testing.T) {
+	BeforeEach()
+	connectionURI := unittesting.StartUpST("localhost", "8080")
+	defer AfterEach()
+
+	inbox := deliverycapture.MakeInbox(10)
+	testServer := supertokensInitForTest(t, connectionURI,
+		Init(plessmodels.TypeInput{
+			FlowType: "MAGIC_LINK",
+			ContactMethodEmail: plessmodels.ContactMethodEmailConfig{
+				Enabled: true,
+			},
+			EmailDelivery: &emaildelivery.TypeInput{
+				Service: MakeCaptureEmailService(inbox),
+			},
+			CrossDeviceLink: &crossdevice.TypeInput{},
+		}),
+		session.Init(nil),
+	)
+	defer testServer.Close()
+
+	_, result := sendCrossDeviceRequestForTest(t, http.MethodPost, testServer.URL+"/auth/signinup/code", map[string]interface{}{
+		"email": "test@example.com",
+	}, "")
+	assert.Equal(t, "OK", result["status"])
+	deviceID := result["deviceId"].(string)
+	preAuthSessionID := result["preAuthSessionId"].(string)
+	statusURL := testServer.URL + "/auth/signinup/code/status?preAuthSessionId=" + url.QueryEscape(preAuthSessionID)
+
+	_, result = sendCrossDeviceRequestForTest(t, http.MethodGet, statusURL, nil, deviceID)
+	assert.Equal(t, "PENDING", result["status"])
+
+	message := inbox.GetLatestMessage("test@example.com")
+	assert.NotNil(t, message)
+	link, err := url.Parse(message.Links[0])
+	assert.NoError(t, err)
+
+	// the link is opened on another device, which does not know the device ID
+	resp, result := sendCrossDeviceRequestForTest(t, http.MethodPost, testServer.URL+"/auth/signinup/code/consume", map[string]interface{}{
+		"preAuthSessionId": preAuthSessionID,
+		"linkCode":         link.Fragment,
+	}, "")
+	assert.Equal(t, "CROSS_DEVICE_LOGIN_APPROVED", result["status"])
+	assert.Empty(t, resp.Header.Get("front-token"))
+
+	// a device that did not request the link cannot get the session
+	_, result = sendCrossDeviceRequestForTest(t, http.MethodGet, statusURL, nil, "otherDeviceId")
+	assert.Equal(t, "RESTART_FLOW_ERROR", result["status"])
+
+	resp, result = sendCrossDeviceRequestForTest(t, http.MethodGet, statusURL, nil, deviceID)
+	assert.Equal(t, "OK", result["status"])
+	assert.Equal(t, true, result["createdNewUser"])
+	assert.NotEmpty(t, resp.Header.Get("front-token"))
+
+	// the session can only be taken once
+	_, result = sendCrossDeviceRequestForTest(t, http.MethodGet, statusURL, nil, deviceID)
+	assert.Equal(t, "RESTART_FLOW_ERROR", result["status"])
+}
+
+func TestMagicLinkOpenedOnTheSameDevice(t *testing.T) {
+	BeforeEach()
+	connectionURI := unittesting.StartUpST("localhost", "8080")
+	defer AfterEach()
+
+	inbox := deliverycapture.MakeInbox(10)
+	testServer := supertokensInitForTest(t, connectionURI,
+		Init(plessmodels.TypeInput{
+			FlowType: "MAGIC_LINK",
+			ContactMethodEmail: plessmodels.ContactMethodEmailConfig{
+				Enabled: true,
+			},
+			EmailDelivery: &emaildelivery.TypeInput{
+				Service: MakeCaptureEmailService(inbox),
+			},
+			CrossDeviceLink: &crossdevice.TypeInput{},
+		}),
+		session.Init(nil),
+	)
+	defer testServer.Close()
+
+	_, result := sendCrossDeviceRequestForTest(t, http.MethodPost, testServer.URL+"/auth/signinup/code", map[string]interface{}{
+		"email": "test@example.com",
+	}, "")
+	deviceID := result["deviceId"].(string)
+	preAuthSessionID := result["preAuthSessionId"].(string)
+
+	message := inbox.GetLatestMessage("test@example.com")
+	assert.NotNil(t, message)
+	link, err := url.Parse(message.Links[0])
+	assert.NoError(t, err)
+
+	resp, result := sendCrossDeviceRequestForTest(t, http.MethodPost, testServer.URL+"/auth/signinup/code/consume", map[string]interface{}{
+		"preAuthSessionId": preAuthSessionID,
+		"linkCode":         link.Fragment,
+	}, deviceID)
+	assert.Equal(t, "OK", result["status"])
+	assert.NotEmpty(t, resp.Header.Get("front-token"))
+}
@@ -0,0 +1,155 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package crossdevice
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func NormaliseTypeInput(input TypeInput) (TypeNormalisedInput, error) {
+	result := TypeNormalisedInput{
+		ApprovalLifetime: 5 * time.Minute,
+		LongPollTimeout:  0,
+		PollInterval:     time.Second,
+		Store:            MakeInMemoryStore(),
+	}
+	if input.ApprovalLifetime != nil {
+		if *input.ApprovalLifetime <= 0 {
+			return TypeNormalisedInput{}, errors.New("ApprovalLifetime must be greater than 0")
+		}
+		result.ApprovalLifetime = *input.ApprovalLifetime
+	}
+	if input.LongPollTimeout != nil {
+		if *input.LongPollTimeout < 0 {
+			return TypeNormalisedInput{}, errors.New("LongPollTimeout must not be negative")
+		}
+		result.LongPollTimeout = *input.LongPollTimeout
+	}
+	if input.PollInterval != nil {
+		if *input.PollInterval <= 0 {
+			return TypeNormalisedInput{}, errors.New("PollInterval must be greater than 0")
+		}
+		result.PollInterval = *input.PollInterval
+	}
+	if input.Store != nil {
+		result.Store = *input.Store
+	}
+	return result, nil
+}
+
+// StartLoginAttempt saves the login attempt once a code is sent, or extends it when a new code
+// is sent for the same device. codeExpiresAt is in milliseconds.
+func StartLoginAttempt(config TypeNormalisedInput, preAuthSessionId string, deviceId string, tenantId string, codeExpiresAt int64, userContext supertokens.UserContext) error {
+	return config.Store.Save(LoginAttempt{
+		PreAuthSessionID: preAuthSessionId,
+		DeviceIDHash:     hashDeviceId(deviceId),
+		TenantId:         tenantId,
+		ExpiresAt:        codeExpiresAt,
+	}, userContext)
+}
+
+// GetLoginAttempt returns nil if there is no attempt for the preAuthSessionId or it has expired
+func GetLoginAttempt(config TypeNormalisedInput, preAuthSessionId string, userContext supertokens.UserContext) (*LoginAttempt, error) {
+	attempt, err := config.Store.Get(preAuthSessionId, userContext)
+	if err != nil || attempt == nil {
+		return nil, err
+	}
+	if attempt.ExpiresAt <= getCurrentTimeInMS() {
+		return nil, config.Store.Delete(preAuthSessionId, userContext)
+	}
+	return attempt, nil
+}
+
+// IsSameDevice returns true if deviceId is the device that started the attempt
+func IsSameDevice(attempt LoginAttempt, deviceId string) bool {
+	if deviceId == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(attempt.DeviceIDHash), []byte(hashDeviceId(deviceId))) == 1
+}
+
+// Approve marks the attempt as approved for the user that consumed the link on another device
+func Approve(config TypeNormalisedInput, attempt LoginAttempt, userId string, createdNewUser bool, userContext supertokens.UserContext) error {
+	attempt.Approved = true
+	attempt.UserId = userId
+	attempt.CreatedNewUser = createdNewUser
+	attempt.ExpiresAt = getCurrentTimeInMS() + config.ApprovalLifetime.Milliseconds()
+	supertokens.LogDebugMessage("crossdevice: approved the login attempt " + attempt.PreAuthSessionID)
+	return config.Store.Save(attempt, userContext)
+}
+
+// WaitForApproval returns the attempt once it is approved, or after LongPollTimeout if it is
+// still pending. It returns nil if the attempt does not exist, has expired or was started by
+// another device. It stops waiting with an error if the context of the user context is done,
+// for example because the client disconnected.
+func WaitForApproval(config TypeNormalisedInput, preAuthSessionId string, deviceId string, userContext supertokens.UserContext) (*LoginAttempt, error) {
+	ctx := supertokens.GetContextFromUserContext(userContext)
+	deadline := time.Now().Add(config.LongPollTimeout)
+	for {
+		attempt, err := GetLoginAttempt(config, preAuthSessionId, userContext)
+		if err != nil || attempt == nil {
+			return nil, err
+		}
+		if !IsSameDevice(*attempt, deviceId) {
+			return nil, nil
+		}
+		if attempt.Approved || !time.Now().Add(config.PollInterval).Before(deadline) {
+			return attempt, nil
+		}
+		timer := time.NewTimer(config.PollInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// ConsumeApproval removes the approved attempt before the device gets its session, so that it can
+// only be used once. It returns nil if the attempt is not approved, has expired or was already
+// consumed by another request.
+func ConsumeApproval(config TypeNormalisedInput, preAuthSessionId string, userContext supertokens.UserContext) (*LoginAttempt, error) {
+	attempt, err := config.Store.ConsumeApproved(preAuthSessionId, userContext)
+	if err != nil || attempt == nil {
+		return nil, err
+	}
+	if attempt.ExpiresAt <= getCurrentTimeInMS() {
+		return nil, nil
+	}
+	return attempt, nil
+}
+
+// Complete removes the attempt once the link was consumed on the device that requested it
+func Complete(config TypeNormalisedInput, preAuthSessionId string, userContext supertokens.UserContext) error {
+	return config.Store.Delete(preAuthSessionId, userContext)
+}
+
+func hashDeviceId(deviceId string) string {
+	hash := sha256.Sum256([]byte(deviceId))
+	return hex.EncodeToString(hash[:])
+}
+
+func getCurrentTimeInMS() int64 {
+	return time.Now().UnixNano() / int64(time.Millisecond)
+}
@@ -0,0 +1,179 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package crossdevice
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func makeTestConfig(t *testing.T, input TypeInput) TypeNormalisedInput {
+	config, err := NormaliseTypeInput(input)
+	assert.NoError(t, err)
+	return config
+}
+
+func TestInvalidConfig(t *testing.T) {
+	zero := time.Duration(0)
+	negative := -time.Second
+
+	_, err := NormaliseTypeInput(TypeInput{ApprovalLifetime: &zero})
+	assert.Equal(t, "ApprovalLifetime must be greater than 0", err.Error())
+	_, err = NormaliseTypeInput(TypeInput{LongPollTimeout: &negative})
+	assert.Equal(t, "LongPollTimeout must not be negative", err.Error())
+	_, err = NormaliseTypeInput(TypeInput{PollInterval: &zero})
+	assert.Equal(t, "PollInterval must be greater than 0", err.Error())
+}
+
+func TestLoginAttemptIsApprovedForTheDeviceThatStartedIt(t *testing.T) {
+	config := makeTestConfig(t, TypeInput{})
+	userContext := &map[string]interface{}{}
+
+	assert.NoError(t, StartLoginAttempt(config, "preAuthSessionId", "deviceId", "public", getCurrentTimeInMS()+60000, userContext))
+
+	attempt, err := GetLoginAttempt(config, "preAuthSessionId", userContext)
+	assert.NoError(t, err)
+	assert.NotNil(t, attempt)
+	assert.NotEqual(t, "deviceId", attempt.DeviceIDHash)
+	assert.True(t, IsSameDevice(*attempt, "deviceId"))
+	assert.False(t, IsSameDevice(*attempt, "otherDeviceId"))
+	assert.False(t, IsSameDevice(*attempt, ""))
+
+	attempt, err = WaitForApproval(config, "preAuthSessionId", "deviceId", userContext)
+	assert.NoError(t, err)
+	assert.False(t, attempt.Approved)
+
+	assert.NoError(t, Approve(config, *attempt, "userId", true, userContext))
+
+	// another device cannot get the approval
+	attempt, err = WaitForApproval(config, "preAuthSessionId", "otherDeviceId", userContext)
+	assert.NoError(t, err)
+	assert.Nil(t, attempt)
+
+	attempt, err = WaitForApproval(config, "preAuthSessionId", "deviceId", userContext)
+	assert.NoError(t, err)
+	assert.True(t, attempt.Approved)
+	assert.Equal(t, "userId", attempt.UserId)
+	assert.True(t, attempt.CreatedNewUser)
+
+	attempt, err = ConsumeApproval(config, "preAuthSessionId", userContext)
+	assert.NoError(t, err)
+	assert.Equal(t, "userId", attempt.UserId)
+	attempt, err = WaitForApproval(config, "preAuthSessionId", "deviceId", userContext)
+	assert.NoError(t, err)
+	assert.Nil(t, attempt)
+}
+
+func TestApprovalCanOnlyBeConsumedOnce(t *testing.T) {
+	config := makeTestConfig(t, TypeInput{})
+	userContext := &map[string]interface{}{}
+
+	assert.NoError(t, StartLoginAttempt(config, "preAuthSessionId", "deviceId", "public", getCurrentTimeInMS()+60000, userContext))
+	attempt, err := GetLoginAttempt(config, "preAuthSessionId", userContext)
+	assert.NoError(t, err)
+
+	// pending attempts cannot be consumed
+	consumedAttempt, err := ConsumeApproval(config, "preAuthSessionId", userContext)
+	assert.NoError(t, err)
+	assert.Nil(t, consumedAttempt)
+
+	assert.NoError(t, Approve(config, *attempt, "userId", false, userContext))
+
+	var wg sync.WaitGroup
+	var consumedCount int32
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			consumedAttempt, err := ConsumeApproval(config, "preAuthSessionId", &map[string]interface{}{})
+			assert.NoError(t, err)
+			if consumedAttempt != nil {
+				atomic.AddInt32(&consumedCount, 1)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), consumedCount)
+}
+
+func TestLongPollStopsWhenTheContextIsDone(t *testing.T) {
+	timeout := 5 * time.Second
+	interval := 10 * time.Millisecond
+	config := makeTestConfig(t, TypeInput{LongPollTimeout: &timeout, PollInterval: &interval})
+
+	assert.NoError(t, StartLoginAttempt(config, "preAuthSessionId", "deviceId", "public", getCurrentTimeInMS()+60000, &map[string]interface{}{}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	attempt, err := WaitForApproval(config, "preAuthSessionId", "deviceId", supertokens.MakeUserContextFromContext(ctx))
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Nil(t, attempt)
+	assert.Less(t, time.Since(start), timeout)
+}
+
+func TestExpiredLoginAttemptsAreIgnored(t *testing.T) {
+	config := makeTestConfig(t, TypeInput{})
+	userContext := &map[string]interface{}{}
+
+	assert.NoError(t, StartLoginAttempt(config, "preAuthSessionId", "deviceId", "public", getCurrentTimeInMS()-1, userContext))
+
+	attempt, err := GetLoginAttempt(config, "preAuthSessionId", userContext)
+	assert.NoError(t, err)
+	assert.Nil(t, attempt)
+}
+
+func TestLongPollReturnsOnceApproved(t *testing.T) {
+	timeout := 5 * time.Second
+	interval := 10 * time.Millisecond
+	config := makeTestConfig(t, TypeInput{LongPollTimeout: &timeout, PollInterval: &interval})
+	userContext := &map[string]interface{}{}
+
+	assert.NoError(t, StartLoginAttempt(config, "preAuthSessionId", "deviceId", "public", getCurrentTimeInMS()+60000, userContext))
+	attempt, err := GetLoginAttempt(config, "preAuthSessionId", userContext)
+	assert.NoError(t, err)
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		Approve(config, *attempt, "userId", false, &map[string]interface{}{})
+	}()
+
+	start := time.Now()
+	approvedAttempt, err := WaitForApproval(config, "preAuthSessionId", "deviceId", userContext)
+	assert.NoError(t, err)
+	assert.True(t, approvedAttempt.Approved)
+	assert.Less(t, time.Since(start), timeout)
+}
+
+func TestLongPollReturnsPendingAfterTimeout(t *testing.T) {
+	timeout := 50 * time.Millisecond
+	interval := 10 * time.Millisecond
+	config := makeTestConfig(t, TypeInput{LongPollTimeout: &timeout, PollInterval: &interval})
+	userContext := &map[string]interface{}{}
+
+	assert.NoError(t, StartLoginAttempt(config, "preAuthSessionId", "deviceId", "public", getCurrentTimeInMS()+60000, userContext))
+
+	attempt, err := WaitForApproval(config, "preAuthSessionId", "deviceId", userContext)
+	assert.NoError(t, err)
+	assert.False(t, attempt.Approved)
+}
@@ -0,0 +1,77 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package crossdevice
+
+import (
+	"time"
+
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+// DeviceIdHeaderKey is the header in which the frontend sends the device ID of the login attempt
+// when it consumes a magic link. A link consumed without it (or with another device ID) was
+// opened on another device.
+const DeviceIdHeaderKey = "st-passwordless-device-id"
+
+// LoginAttempt is a login attempt started by the create code API
+type LoginAttempt struct {
+	PreAuthSessionID string `json:"preAuthSessionId"`
+	// DeviceIDHash is the SHA-256 of the device ID, which is only known by the device that
+	// started the login attempt
+	DeviceIDHash string `json:"deviceIdHash"`
+	TenantId     string `json:"tenantId"`
+	// ExpiresAt is in milliseconds. Until the link is consumed, it is the expiry of the last
+	// code sent. Once approved, the device has ApprovalLifetime to get its session.
+	ExpiresAt int64 `json:"expiresAt"`
+	// Approved is true once the link was consumed on another device
+	Approved       bool   `json:"approved"`
+	UserId         string `json:"userId,omitempty"`
+	CreatedNewUser bool   `json:"createdNewUser"`
+}
+
+// Store keeps the login attempts. Expired attempts can be removed by the store.
+type Store struct {
+	// Save inserts the attempt, or replaces the attempt with the same PreAuthSessionID
+	Save func(attempt LoginAttempt, userContext supertokens.UserContext) error
+	// Get returns nil if there is no attempt for the preAuthSessionId
+	Get    func(preAuthSessionId string, userContext supertokens.UserContext) (*LoginAttempt, error)
+	Delete func(preAuthSessionId string, userContext supertokens.UserContext) error
+	// ConsumeApproved removes the attempt and returns it if it is approved, in a single atomic
+	// step. It returns nil if there is no approved attempt for the preAuthSessionId, so that an
+	// approval can only be used by one request.
+	ConsumeApproved func(preAuthSessionId string, userContext supertokens.UserContext) (*LoginAttempt, error)
+}
+
+type TypeInput struct {
+	// ApprovalLifetime is the time the device has to get its session once the link was consumed
+	// on another device. Defaults to 5 minutes.
+	ApprovalLifetime *time.Duration
+	// LongPollTimeout is how long the status API waits for the approval before answering that
+	// the attempt is pending. Defaults to 0, which answers immediately.
+	LongPollTimeout *time.Duration
+	// PollInterval is how often the store is checked during a long poll. Defaults to 1 second.
+	PollInterval *time.Duration
+	// Store defaults to an in-memory store, which is not shared between instances of the backend
+	Store *Store
+}
+
+type TypeNormalisedInput struct {
+	ApprovalLifetime time.Duration
+	LongPollTimeout  time.Duration
+	PollInterval     time.Duration
+	Store            Store
+}
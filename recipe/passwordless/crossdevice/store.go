@@ -0,0 +1,71 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package crossdevice
+
+import (
+	"sync"
+
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func MakeInMemoryStore() Store {
+	var lock sync.Mutex
+	attempts := map[string]LoginAttempt{}
+
+	removeExpiredAttempts := func(now int64) {
+		for preAuthSessionId, attempt := range attempts {
+			if attempt.ExpiresAt <= now {
+				delete(attempts, preAuthSessionId)
+			}
+		}
+	}
+
+	return Store{
+		Save: func(attempt LoginAttempt, userContext supertokens.UserContext) error {
+			lock.Lock()
+			defer lock.Unlock()
+			removeExpiredAttempts(getCurrentTimeInMS())
+			attempts[attempt.PreAuthSessionID] = attempt
+			return nil
+		},
+		Get: func(preAuthSessionId string, userContext supertokens.UserContext) (*LoginAttempt, error) {
+			lock.Lock()
+			defer lock.Unlock()
+			attempt, ok := attempts[preAuthSessionId]
+			if !ok {
+				return nil, nil
+			}
+			return &attempt, nil
+		},
+		Delete: func(preAuthSessionId string, userContext supertokens.UserContext) error {
+			lock.Lock()
+			defer lock.Unlock()
+			delete(attempts, preAuthSessionId)
+			return nil
+		},
+		ConsumeApproved: func(preAuthSessionId string, userContext supertokens.UserContext) (*LoginAttempt, error) {
+			lock.Lock()
+			defer lock.Unlock()
+			attempt, ok := attempts[preAuthSessionId]
+			if !ok || !attempt.Approved {
+				return nil, nil
+			}
+			delete(attempts, preAuthSessionId)
+			return &attempt, nil
+		},
+	}
+}
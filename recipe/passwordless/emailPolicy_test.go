@@ -0,0 +1,122 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package passwordless
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/ingredients/emailpolicy"
+	"github.com/supertokens/supertokens-golang/recipe/passwordless/plessmodels"
+	"github.com/supertokens/supertokens-golang/recipe/session"
+	"github.com/supertokens/supertokens-golang/test/unittesting"
+)
+
+func createCodeWithEmailForTest(t *testing.T, url string, email string) map[string]interface{} {
+	body, err := json.Marshal(map[string]interface{}{
+		"email": email,
+	})
+	assert.NoError(t, err)
+	resp, err := http.Post(url+"/auth/signinup/code", "application/json", bytes.NewBuffer(body))
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	dataInBytes, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	resp.Body.Close()
+
+	var result map[string]interface{}
+	assert.NoError(t, json.Unmarshal(dataInBytes, &result))
+	return result
+}
+
+func TestEmailPolicyOnlyAppliesToNewPasswordlessUsers(t *testing.T) {
+	BeforeEach()
+	connectionURI := unittesting.StartUpST("localhost", "8080")
+	defer AfterEach()
+
+	testServer := supertokensInitForTest(t, connectionURI,
+		Init(plessmodels.TypeInput{
+			FlowType: "USER_INPUT_CODE",
+			ContactMethodEmail: plessmodels.ContactMethodEmailConfig{
+				Enabled: true,
+			},
+			EmailPolicy: &emailpolicy.TypeInput{
+				TenantAllowedDomains: map[string][]string{
+					"public": {"example.com"},
+				},
+			},
+		}),
+		session.Init(nil),
+	)
+	defer testServer.Close()
+
+	result := createCodeWithEmailForTest(t, testServer.URL, "john@other.com")
+	assert.Equal(t, "EMAIL_NOT_ALLOWED_ERROR", result["status"])
+	assert.Equal(t, "DOMAIN_NOT_ALLOWED", result["reason"])
+
+	result = createCodeWithEmailForTest(t, testServer.URL, "John@Example.com")
+	assert.Equal(t, "OK", result["status"])
+	codes, err := ListCodesByEmail("public", "john@example.com")
+	assert.NoError(t, err)
+	assert.Len(t, codes, 1)
+
+	// the policy is enforced by the API, so existing users outside the allowed domains can still sign in
+	response, err := SignInUpByEmail("public", "jane@other.com")
+	assert.NoError(t, err)
+	assert.True(t, response.CreatedNewUser)
+	result = createCodeWithEmailForTest(t, testServer.URL, "Jane@Other.com")
+	assert.Equal(t, "OK", result["status"])
+}
+
+func TestUsersThatSignedUpBeforeTheEmailPolicyAreNotDuplicated(t *testing.T) {
+	BeforeEach()
+	connectionURI := unittesting.StartUpST("localhost", "8080")
+	defer AfterEach()
+
+	config := plessmodels.TypeInput{
+		FlowType: "USER_INPUT_CODE",
+		ContactMethodEmail: plessmodels.ContactMethodEmailConfig{
+			Enabled: true,
+		},
+	}
+	testServer := supertokensInitForTest(t, connectionURI, Init(config), session.Init(nil))
+	signInUpResponse, err := SignInUpByEmail("public", "Jane@Example.com")
+	assert.NoError(t, err)
+	testServer.Close()
+
+	resetAll()
+	config.EmailPolicy = &emailpolicy.TypeInput{}
+	testServer = supertokensInitForTest(t, connectionURI, Init(config), session.Init(nil))
+	defer testServer.Close()
+
+	user, err := GetUserByEmail("public", "Jane@Example.com")
+	assert.NoError(t, err)
+	assert.NotNil(t, user)
+	assert.Equal(t, signInUpResponse.User.ID, user.ID)
+
+	// the code is created for the email of the existing user, so consuming it signs them in
+	result := createCodeWithEmailForTest(t, testServer.URL, "Jane@Example.com")
+	assert.Equal(t, "OK", result["status"])
+	codes, err := ListCodesByEmail("public", "Jane@Example.com")
+	assert.NoError(t, err)
+	assert.Len(t, codes, 1)
+	assert.Equal(t, "Jane@Example.com", *codes[0].Email)
+}
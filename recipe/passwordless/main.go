@@ -16,9 +16,11 @@
 package passwordless
 
 import (
+	"github.com/supertokens/supertokens-golang/ingredients/deliverycapture"
 	"github.com/supertokens/supertokens-golang/ingredients/emaildelivery"
 	"github.com/supertokens/supertokens-golang/ingredients/smsdelivery"
 	"github.com/supertokens/supertokens-golang/recipe/passwordless/emaildelivery/smtpService"
+	"github.com/supertokens/supertokens-golang/recipe/passwordless/phonenumber"
 	"github.com/supertokens/supertokens-golang/recipe/passwordless/plessmodels"
 	"github.com/supertokens/supertokens-golang/recipe/passwordless/smsdelivery/supertokensService"
 	"github.com/supertokens/supertokens-golang/recipe/passwordless/smsdelivery/twilioService"
@@ -290,6 +292,17 @@ func DeletePhoneNumberForUser(userID string, userContext ...supertokens.UserCont
 	return (*instance.RecipeImpl.DeletePhoneNumberForUser)(userID, userContext[0])
 }
 
+// NormalisePhoneNumber validates the phone number using the PhoneNumber config of the recipe and
+// returns it in the E.164 format
+func NormalisePhoneNumber(tenantId string, phoneNumber string) (string, *phonenumber.ValidationError, error) {
+	instance, err := GetRecipeInstanceOrThrowError()
+	if err != nil {
+		return "", nil, err
+	}
+	normalisedPhoneNumber, validationError := phonenumber.Normalise(instance.Config.PhoneNumber, phoneNumber, tenantId)
+	return normalisedPhoneNumber, validationError, nil
+}
+
 func SendEmail(input emaildelivery.EmailType, userContext ...supertokens.UserContext) error {
 	instance, err := GetRecipeInstanceOrThrowError()
 	if err != nil {
@@ -316,6 +329,28 @@ func MakeSMTPService(config emaildelivery.SMTPServiceConfig) *emaildelivery.Emai
 	return smtpService.MakeSMTPService(config)
 }
 
+func MakeSESService(config emaildelivery.SESServiceConfig) (*emaildelivery.EmailDeliveryInterface, error) {
+	return emaildelivery.MakeSESService(config, smtpService.GetDefaultContent)
+}
+
+func MakeSendGridService(config emaildelivery.SendGridServiceConfig) (*emaildelivery.EmailDeliveryInterface, error) {
+	return emaildelivery.MakeSendGridService(config, smtpService.GetDefaultContent)
+}
+
+func MakePostmarkService(config emaildelivery.PostmarkServiceConfig) (*emaildelivery.EmailDeliveryInterface, error) {
+	return emaildelivery.MakePostmarkService(config, smtpService.GetDefaultContent)
+}
+
+func MakeMailgunService(config emaildelivery.MailgunServiceConfig) (*emaildelivery.EmailDeliveryInterface, error) {
+	return emaildelivery.MakeMailgunService(config, smtpService.GetDefaultContent)
+}
+
+// MakeCaptureEmailService returns a service that stores the emails in inbox instead of sending
+// them. It should only be used in development and tests.
+func MakeCaptureEmailService(inbox *deliverycapture.Inbox) *emaildelivery.EmailDeliveryInterface {
+	return emaildelivery.MakeCaptureService(inbox, smtpService.GetDefaultContent)
+}
+
 func MakeTwilioService(config smsdelivery.TwilioServiceConfig) (*smsdelivery.SmsDeliveryInterface, error) {
 	return twilioService.MakeTwilioService(config)
 }
@@ -323,3 +358,27 @@ func MakeTwilioService(config smsdelivery.TwilioServiceConfig) (*smsdelivery.Sms
 func MakeSupertokensSMSService(apiKey string) *smsdelivery.SmsDeliveryInterface {
 	return supertokensService.MakeSupertokensSMSService(apiKey)
 }
+
+func MakeSNSService(config smsdelivery.SNSServiceConfig) (*smsdelivery.SmsDeliveryInterface, error) {
+	return smsdelivery.MakeSNSService(config, twilioService.GetDefaultContent)
+}
+
+func MakeVonageService(config smsdelivery.VonageServiceConfig) (*smsdelivery.SmsDeliveryInterface, error) {
+	return smsdelivery.MakeVonageService(config, twilioService.GetDefaultContent)
+}
+
+func MakeMessageBirdService(config smsdelivery.MessageBirdServiceConfig) (*smsdelivery.SmsDeliveryInterface, error) {
+	return smsdelivery.MakeMessageBirdService(config, twilioService.GetDefaultContent)
+}
+
+// MakeCaptureSMSService returns a service that stores the SMS in inbox instead of sending
+// them. It should only be used in development and tests.
+func MakeCaptureSMSService(inbox *deliverycapture.Inbox) *smsdelivery.SmsDeliveryInterface {
+	return smsdelivery.MakeCaptureService(inbox, twilioService.GetDefaultContent)
+}
+
+// MakeRoutingSMSService returns a service that sends SMS using the services configured for the
+// country prefix of the phone number, falling back to the next service if one fails
+func MakeRoutingSMSService(config smsdelivery.RoutingServiceConfig) (*smsdelivery.SmsDeliveryInterface, error) {
+	return smsdelivery.MakeRoutingService(config)
+}
@@ -25,10 +25,12 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/supertokens/supertokens-golang/ingredients/smsdelivery"
 	"github.com/supertokens/supertokens-golang/recipe/passwordless/plessmodels"
+	"github.com/supertokens/supertokens-golang/recipe/passwordless/smsdelivery/supertokensService"
 	"github.com/supertokens/supertokens-golang/recipe/session"
 	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
 	"github.com/supertokens/supertokens-golang/supertokens"
 	"github.com/supertokens/supertokens-golang/test/unittesting"
+	"gopkg.in/h2non/gock.v1"
 )
 
 func TestSmsDefaultBackwardCompatibilityPasswordlessLogin(t *testing.T) {
@@ -513,3 +515,48 @@ func TestSmsTwilioOverridePasswordlessLogin(t *testing.T) {
 // 		nil,
 // 	)
 // }
+
+func TestSupertokensSmsServiceReturnsErrorForNon2xxResponse(t *testing.T) {
+	BeforeEach()
+	defer AfterEach()
+	err := supertokens.Init(supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: "http://localhost:8080",
+		},
+		AppInfo: supertokens.AppInfo{
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+			APIDomain:     "api.supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{
+			session.Init(nil),
+		},
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	defer gock.OffAll()
+	gock.New(supertokensService.SUPERTOKENS_SMS_SERVICE_URL).
+		Post("/").
+		Reply(500).
+		JSON(map[string]interface{}{"error": "internal error"})
+	gock.New(supertokensService.SUPERTOKENS_SMS_SERVICE_URL).
+		Post("/").
+		Reply(200)
+
+	service := supertokensService.MakeSupertokensSMSService("apiKey")
+	input := smsdelivery.SmsType{
+		PasswordlessLogin: &smsdelivery.PasswordlessLoginType{
+			PhoneNumber:  "+919876543210",
+			CodeLifetime: 1000,
+		},
+	}
+
+	err = (*service.SendSms)(input, &map[string]interface{}{})
+	assert.EqualError(t, err, "Error sending SMS. API returned 500 status.")
+
+	err = (*service.SendSms)(input, &map[string]interface{}{})
+	assert.NoError(t, err)
+	assert.True(t, gock.IsDone())
+}
@@ -0,0 +1,169 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package passwordless
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/passwordless/phonenumber"
+	"github.com/supertokens/supertokens-golang/recipe/passwordless/plessmodels"
+	"github.com/supertokens/supertokens-golang/recipe/session"
+	"github.com/supertokens/supertokens-golang/supertokens"
+	"github.com/supertokens/supertokens-golang/test/unittesting"
+)
+
+func createCodeWithPhoneNumberForTest(t *testing.T, url string, phoneNumber string) map[string]interface{} {
+	body, err := json.Marshal(map[string]interface{}{
+		"phoneNumber": phoneNumber,
+	})
+	assert.NoError(t, err)
+	resp, err := http.Post(url+"/auth/signinup/code", "application/json", bytes.NewBuffer(body))
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	dataInBytes, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	resp.Body.Close()
+
+	var result map[string]interface{}
+	assert.NoError(t, json.Unmarshal(dataInBytes, &result))
+	return result
+}
+
+func TestCreateCodeAPIReturnsStructuredPhoneNumberErrors(t *testing.T) {
+	resetAll()
+	defer resetAll()
+	err := supertokens.Init(supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: "http://localhost:8080",
+		},
+		AppInfo: supertokens.AppInfo{
+			APIDomain:     "api.supertokens.io",
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{
+			Init(plessmodels.TypeInput{
+				FlowType: "USER_INPUT_CODE",
+				ContactMethodPhone: plessmodels.ContactMethodPhoneConfig{
+					Enabled: true,
+				},
+				PhoneNumber: &phonenumber.TypeInput{
+					DefaultRegion:      "GB",
+					AllowedNumberTypes: phonenumber.SMSNumberTypes,
+				},
+			}),
+		},
+	})
+	assert.NoError(t, err)
+	testServer := httptest.NewServer(supertokens.Middleware(http.NewServeMux()))
+	defer testServer.Close()
+
+	result := createCodeWithPhoneNumberForTest(t, testServer.URL, "020 7946 0958")
+	assert.Equal(t, "INVALID_PHONE_NUMBER_ERROR", result["status"])
+	assert.Equal(t, "NUMBER_TYPE_NOT_ALLOWED", result["reason"])
+	assert.Equal(t, "FIXED_LINE", result["numberType"])
+
+	result = createCodeWithPhoneNumberForTest(t, testServer.URL, "+44 74")
+	assert.Equal(t, "INVALID_PHONE_NUMBER_ERROR", result["status"])
+	assert.Equal(t, "TOO_SHORT", result["reason"])
+	assert.Nil(t, result["numberType"])
+}
+
+func TestPhoneNumberConfigWithUnknownRegion(t *testing.T) {
+	resetAll()
+	defer resetAll()
+
+	assert.PanicsWithValue(t, "unknown region ZZ in the phone number config", func() {
+		supertokens.Init(supertokens.TypeInput{
+			Supertokens: &supertokens.ConnectionInfo{
+				ConnectionURI: "http://localhost:8080",
+			},
+			AppInfo: supertokens.AppInfo{
+				APIDomain:     "api.supertokens.io",
+				AppName:       "SuperTokens",
+				WebsiteDomain: "supertokens.io",
+			},
+			RecipeList: []supertokens.Recipe{
+				Init(plessmodels.TypeInput{
+					FlowType: "USER_INPUT_CODE",
+					ContactMethodPhone: plessmodels.ContactMethodPhoneConfig{
+						Enabled: true,
+					},
+					PhoneNumber: &phonenumber.TypeInput{
+						DefaultRegion: "ZZ",
+					},
+				}),
+			},
+		})
+	})
+}
+
+func TestPhoneNumbersAreStoredAndLookedUpAsE164(t *testing.T) {
+	BeforeEach()
+	connectionURI := unittesting.StartUpST("localhost", "8080")
+	defer AfterEach()
+
+	testServer := supertokensInitForTest(t, connectionURI,
+		Init(plessmodels.TypeInput{
+			FlowType: "USER_INPUT_CODE",
+			ContactMethodPhone: plessmodels.ContactMethodPhoneConfig{
+				Enabled: true,
+			},
+			PhoneNumber: &phonenumber.TypeInput{
+				DefaultRegion: "US",
+			},
+		}),
+		session.Init(nil),
+	)
+	defer testServer.Close()
+
+	result := createCodeWithPhoneNumberForTest(t, testServer.URL, "(650) 253-0000")
+	assert.Equal(t, "OK", result["status"])
+
+	codes, err := ListCodesByPhoneNumber("public", "+1 650-253-0000")
+	assert.NoError(t, err)
+	assert.Len(t, codes, 1)
+	assert.Equal(t, "+16502530000", *codes[0].PhoneNumber)
+
+	response, err := SignInUpByPhoneNumber("public", "+1 (650) 253 0000")
+	assert.NoError(t, err)
+	assert.True(t, response.CreatedNewUser)
+	assert.Equal(t, "+16502530000", *response.User.PhoneNumber)
+
+	// the same number in another format signs in the same user
+	response, err = SignInUpByPhoneNumber("public", "650.253.0000")
+	assert.NoError(t, err)
+	assert.False(t, response.CreatedNewUser)
+
+	user, err := GetUserByPhoneNumber("public", "6502530000")
+	assert.NoError(t, err)
+	assert.Equal(t, response.User.ID, user.ID)
+
+	phoneNumber := "+1 (650) 253-0001"
+	updateResponse, err := UpdateUser(user.ID, nil, &phoneNumber)
+	assert.NoError(t, err)
+	assert.NotNil(t, updateResponse.OK)
+	user, err = GetUserByID(user.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "+16502530001", *user.PhoneNumber)
+}
@@ -0,0 +1,161 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package phonenumber
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/nyaruka/phonenumbers"
+)
+
+var numberTypes = map[phonenumbers.PhoneNumberType]NumberType{
+	phonenumbers.FIXED_LINE:           NumberTypeFixedLine,
+	phonenumbers.MOBILE:               NumberTypeMobile,
+	phonenumbers.FIXED_LINE_OR_MOBILE: NumberTypeFixedLineOrMobile,
+	phonenumbers.TOLL_FREE:            NumberTypeTollFree,
+	phonenumbers.PREMIUM_RATE:         NumberTypePremiumRate,
+	phonenumbers.SHARED_COST:          NumberTypeSharedCost,
+	phonenumbers.VOIP:                 NumberTypeVoIP,
+	phonenumbers.PERSONAL_NUMBER:      NumberTypePersonalNumber,
+	phonenumbers.PAGER:                NumberTypePager,
+	phonenumbers.UAN:                  NumberTypeUAN,
+	phonenumbers.VOICEMAIL:            NumberTypeVoicemail,
+	phonenumbers.UNKNOWN:              NumberTypeUnknown,
+}
+
+func NormaliseTypeInput(input TypeInput) (TypeNormalisedInput, error) {
+	defaultRegion, err := normaliseRegion(input.DefaultRegion)
+	if err != nil {
+		return TypeNormalisedInput{}, err
+	}
+	result := TypeNormalisedInput{
+		DefaultRegion:        defaultRegion,
+		TenantDefaultRegions: map[string]string{},
+	}
+	for tenantId, region := range input.TenantDefaultRegions {
+		normalisedRegion, err := normaliseRegion(region)
+		if err != nil {
+			return TypeNormalisedInput{}, err
+		}
+		result.TenantDefaultRegions[tenantId] = normalisedRegion
+	}
+	if len(input.AllowedNumberTypes) > 0 {
+		result.AllowedNumberTypes = map[NumberType]bool{}
+		for _, numberType := range input.AllowedNumberTypes {
+			if !isKnownNumberType(numberType) {
+				return TypeNormalisedInput{}, errors.New("unknown number type " + string(numberType) + " in AllowedNumberTypes")
+			}
+			result.AllowedNumberTypes[numberType] = true
+		}
+	}
+	return result, nil
+}
+
+// GetDefaultRegion returns the region used for numbers of the tenant given without a country code
+func GetDefaultRegion(config *TypeNormalisedInput, tenantId string) string {
+	if config == nil {
+		return ""
+	}
+	if region, ok := config.TenantDefaultRegions[tenantId]; ok {
+		return region
+	}
+	return config.DefaultRegion
+}
+
+// Normalise validates the phone number and returns it in the E.164 format. config can be nil,
+// in which case the number must have a country code and numbers of any type are allowed.
+func Normalise(config *TypeNormalisedInput, phoneNumber string, tenantId string) (string, *ValidationError) {
+	parsedPhoneNumber, err := phonenumbers.Parse(strings.TrimSpace(phoneNumber), GetDefaultRegion(config, tenantId))
+	if err != nil {
+		return "", getParseError(err)
+	}
+
+	switch phonenumbers.IsPossibleNumberWithReason(parsedPhoneNumber) {
+	case phonenumbers.INVALID_COUNTRY_CODE:
+		return "", makeValidationError(ReasonInvalidCountryCode, "Phone number has an invalid country code")
+	case phonenumbers.TOO_SHORT:
+		return "", makeValidationError(ReasonTooShort, "Phone number is too short")
+	case phonenumbers.TOO_LONG:
+		return "", makeValidationError(ReasonTooLong, "Phone number is too long")
+	}
+	if !phonenumbers.IsValidNumber(parsedPhoneNumber) {
+		return "", makeValidationError(ReasonInvalidNumber, "Phone number is invalid")
+	}
+
+	if config != nil && config.AllowedNumberTypes != nil {
+		numberType := numberTypes[phonenumbers.GetNumberType(parsedPhoneNumber)]
+		if !config.AllowedNumberTypes[numberType] {
+			validationError := makeValidationError(ReasonNumberTypeNotAllowed, "Phone numbers of this type cannot be used")
+			validationError.NumberType = &numberType
+			return "", validationError
+		}
+	}
+
+	return phonenumbers.Format(parsedPhoneNumber, phonenumbers.E164), nil
+}
+
+// NormaliseLeniently returns the phone number in the E.164 format if it can be parsed, and the
+// trimmed phone number otherwise. It is used for lookups, which must not fail for numbers that
+// were accepted by a custom ValidatePhoneNumber function.
+func NormaliseLeniently(config *TypeNormalisedInput, phoneNumber string, tenantId string) string {
+	phoneNumber = strings.TrimSpace(phoneNumber)
+	parsedPhoneNumber, err := phonenumbers.Parse(phoneNumber, GetDefaultRegion(config, tenantId))
+	if err != nil {
+		return phoneNumber
+	}
+	return phonenumbers.Format(parsedPhoneNumber, phonenumbers.E164)
+}
+
+func getParseError(err error) *ValidationError {
+	switch err {
+	case phonenumbers.ErrInvalidCountryCode:
+		return makeValidationError(ReasonInvalidCountryCode, "Phone number has an invalid country code")
+	case phonenumbers.ErrTooShortNSN, phonenumbers.ErrTooShortAfterIDD:
+		return makeValidationError(ReasonTooShort, "Phone number is too short")
+	case phonenumbers.ErrNumTooLong:
+		return makeValidationError(ReasonTooLong, "Phone number is too long")
+	}
+	return makeValidationError(ReasonNotANumber, "Phone number is invalid")
+}
+
+func makeValidationError(reason ValidationErrorReason, message string) *ValidationError {
+	return &ValidationError{
+		Reason:  reason,
+		Message: message,
+	}
+}
+
+func normaliseRegion(region string) (string, error) {
+	if region == "" {
+		return "", nil
+	}
+	region = strings.ToUpper(strings.TrimSpace(region))
+	if phonenumbers.GetCountryCodeForRegion(region) == 0 {
+		return "", errors.New("unknown region " + region + " in the phone number config")
+	}
+	return region, nil
+}
+
+func isKnownNumberType(numberType NumberType) bool {
+	for _, knownNumberType := range numberTypes {
+		if knownNumberType == numberType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,106 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package phonenumber
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormaliseFormatsNumbersAsE164(t *testing.T) {
+	for _, phoneNumber := range []string{"+1 (650) 253-0000", "+16502530000", " +1-650-253-0000 ", "+1.650.253.0000"} {
+		normalised, validationError := Normalise(nil, phoneNumber, "public")
+		assert.Nil(t, validationError)
+		assert.Equal(t, "+16502530000", normalised)
+	}
+}
+
+func TestNormaliseUsesTheDefaultRegionOfTheTenant(t *testing.T) {
+	config, err := NormaliseTypeInput(TypeInput{
+		DefaultRegion: "us",
+		TenantDefaultRegions: map[string]string{
+			"uk": "GB",
+		},
+	})
+	assert.NoError(t, err)
+
+	normalised, validationError := Normalise(&config, "(650) 253-0000", "public")
+	assert.Nil(t, validationError)
+	assert.Equal(t, "+16502530000", normalised)
+
+	normalised, validationError = Normalise(&config, "07400 123456", "uk")
+	assert.Nil(t, validationError)
+	assert.Equal(t, "+447400123456", normalised)
+
+	// numbers with a country code are not affected by the region
+	normalised, validationError = Normalise(&config, "+44 7400 123456", "public")
+	assert.Nil(t, validationError)
+	assert.Equal(t, "+447400123456", normalised)
+}
+
+func TestNormaliseRejectsNumbersWithoutCountryCodeIfThereIsNoRegion(t *testing.T) {
+	_, validationError := Normalise(nil, "(650) 253-0000", "public")
+	assert.NotNil(t, validationError)
+	assert.Equal(t, ReasonInvalidCountryCode, validationError.Reason)
+}
+
+func TestNormaliseReturnsTheReason(t *testing.T) {
+	_, validationError := Normalise(nil, "not a number", "public")
+	assert.Equal(t, ReasonNotANumber, validationError.Reason)
+
+	_, validationError = Normalise(nil, "+1 650 25", "public")
+	assert.Equal(t, ReasonTooShort, validationError.Reason)
+
+	_, validationError = Normalise(nil, "+1 650 253 0000 1234", "public")
+	assert.Equal(t, ReasonTooLong, validationError.Reason)
+
+	_, validationError = Normalise(nil, "+1 650 053 0000", "public")
+	assert.Equal(t, ReasonInvalidNumber, validationError.Reason)
+}
+
+func TestNormaliseFiltersNumberTypes(t *testing.T) {
+	config, err := NormaliseTypeInput(TypeInput{
+		AllowedNumberTypes: SMSNumberTypes,
+	})
+	assert.NoError(t, err)
+
+	normalised, validationError := Normalise(&config, "+44 7400 123456", "public")
+	assert.Nil(t, validationError)
+	assert.Equal(t, "+447400123456", normalised)
+
+	_, validationError = Normalise(&config, "+44 20 7946 0958", "public")
+	assert.Equal(t, ReasonNumberTypeNotAllowed, validationError.Reason)
+	assert.Equal(t, NumberTypeFixedLine, *validationError.NumberType)
+
+	_, validationError = Normalise(&config, "+44 56 1234 5678", "public")
+	assert.Equal(t, ReasonNumberTypeNotAllowed, validationError.Reason)
+	assert.Equal(t, NumberTypeVoIP, *validationError.NumberType)
+}
+
+func TestNormaliseLenientlyKeepsUnparsableNumbers(t *testing.T) {
+	assert.Equal(t, "+16502530000", NormaliseLeniently(nil, "+1 (650) 253-0000", "public"))
+	assert.Equal(t, "abc", NormaliseLeniently(nil, " abc ", "public"))
+}
+
+func TestNormaliseTypeInputRejectsUnknownValues(t *testing.T) {
+	_, err := NormaliseTypeInput(TypeInput{DefaultRegion: "XX"})
+	assert.EqualError(t, err, "unknown region XX in the phone number config")
+
+	_, err = NormaliseTypeInput(TypeInput{AllowedNumberTypes: []NumberType{"LANDLINE"}})
+	assert.EqualError(t, err, "unknown number type LANDLINE in AllowedNumberTypes")
+}
@@ -0,0 +1,76 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package phonenumber
+
+// NumberType is the type of a phone number according to the libphonenumber metadata
+type NumberType string
+
+const (
+	NumberTypeFixedLine         NumberType = "FIXED_LINE"
+	NumberTypeMobile            NumberType = "MOBILE"
+	NumberTypeFixedLineOrMobile NumberType = "FIXED_LINE_OR_MOBILE"
+	NumberTypeTollFree          NumberType = "TOLL_FREE"
+	NumberTypePremiumRate       NumberType = "PREMIUM_RATE"
+	NumberTypeSharedCost        NumberType = "SHARED_COST"
+	NumberTypeVoIP              NumberType = "VOIP"
+	NumberTypePersonalNumber    NumberType = "PERSONAL_NUMBER"
+	NumberTypePager             NumberType = "PAGER"
+	NumberTypeUAN               NumberType = "UAN"
+	NumberTypeVoicemail         NumberType = "VOICEMAIL"
+	NumberTypeUnknown           NumberType = "UNKNOWN"
+)
+
+// SMSNumberTypes are the number types that can receive text messages. Numbers of type
+// FIXED_LINE_OR_MOBILE are allowed since the metadata cannot tell them apart in some regions.
+var SMSNumberTypes = []NumberType{NumberTypeMobile, NumberTypeFixedLineOrMobile}
+
+// ValidationErrorReason tells why a phone number was rejected
+type ValidationErrorReason string
+
+const (
+	ReasonNotANumber           ValidationErrorReason = "NOT_A_NUMBER"
+	ReasonInvalidCountryCode   ValidationErrorReason = "INVALID_COUNTRY_CODE"
+	ReasonTooShort             ValidationErrorReason = "TOO_SHORT"
+	ReasonTooLong              ValidationErrorReason = "TOO_LONG"
+	ReasonInvalidNumber        ValidationErrorReason = "INVALID_NUMBER"
+	ReasonNumberTypeNotAllowed ValidationErrorReason = "NUMBER_TYPE_NOT_ALLOWED"
+)
+
+type ValidationError struct {
+	Reason  ValidationErrorReason
+	Message string
+	// NumberType is only set if Reason is NUMBER_TYPE_NOT_ALLOWED
+	NumberType *NumberType
+}
+
+type TypeInput struct {
+	// DefaultRegion is the ISO 3166-1 alpha-2 region used for numbers given without a country
+	// code, like "US". If it is empty, numbers must start with + and a country code.
+	DefaultRegion string
+	// TenantDefaultRegions overrides DefaultRegion per tenant
+	TenantDefaultRegions map[string]string
+	// AllowedNumberTypes restricts the numbers that can be used, for example to SMSNumberTypes.
+	// If it is empty, numbers of any type are allowed.
+	AllowedNumberTypes []NumberType
+}
+
+type TypeNormalisedInput struct {
+	DefaultRegion        string
+	TenantDefaultRegions map[string]string
+	// AllowedNumberTypes is nil if numbers of any type are allowed
+	AllowedNumberTypes map[NumberType]bool
+}
@@ -19,6 +19,7 @@ import (
 	"net/http"
 
 	"github.com/supertokens/supertokens-golang/ingredients/emaildelivery"
+	"github.com/supertokens/supertokens-golang/ingredients/emailpolicy"
 	"github.com/supertokens/supertokens-golang/ingredients/smsdelivery"
 	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
 	"github.com/supertokens/supertokens-golang/supertokens"
@@ -42,6 +43,7 @@ type APIInterface struct {
 	ConsumeCodePOST      *func(userInput *UserInputCodeWithDeviceID, linkCode *string, preAuthSessionID string, tenantId string, options APIOptions, userContext supertokens.UserContext) (ConsumeCodePOSTResponse, error)
 	EmailExistsGET       *func(email string, tenantId string, options APIOptions, userContext supertokens.UserContext) (EmailExistsGETResponse, error)
 	PhoneNumberExistsGET *func(phoneNumber string, tenantId string, options APIOptions, userContext supertokens.UserContext) (PhoneNumberExistsGETResponse, error)
+	CodeStatusGET        *func(deviceID string, preAuthSessionID string, tenantId string, options APIOptions, userContext supertokens.UserContext) (CodeStatusGETResponse, error)
 }
 
 type ConsumeCodePOSTResponse struct {
@@ -59,13 +61,44 @@ type ConsumeCodePOSTResponse struct {
 		MaximumCodeInputAttempts    int
 	}
 	RestartFlowError *struct{}
+	// CrossDeviceLoginApproved is returned if the magic link was opened on another device than
+	// the one that requested it. No session is created for the device that opened the link.
+	CrossDeviceLoginApproved *struct{}
+	// InviteRequiredError is returned if a new user signs up in an invite only tenant without a
+	// valid invite for their email. The invite token must be sent by the device that consumes
+	// the code.
+	InviteRequiredError *struct{}
+	// PhoneNumberNotInvitableError is returned if a new user signs up with a phone number in an
+	// invite only tenant. Invites are sent by email, so these users cannot sign up.
+	PhoneNumberNotInvitableError *struct{}
+	GeneralError                 *supertokens.GeneralErrorResponse
+}
+
+type CodeStatusGETResponse struct {
+	OK *struct {
+		CreatedNewUser bool
+		User           User
+		Session        sessmodels.SessionContainer
+	}
+	// Pending is returned until the magic link is opened on another device
+	Pending          *struct{}
+	RestartFlowError *struct{}
 	GeneralError     *supertokens.GeneralErrorResponse
 }
 
 type ResendCodePOSTResponse struct {
 	OK             *struct{}
 	ResetFlowError *struct{}
-	GeneralError   *supertokens.GeneralErrorResponse
+	// ResendCooldownError is returned if the previous code was sent less than the ResendCooldown
+	// of the code policy ago
+	ResendCooldownError *struct {
+		// ResendAllowedAt is the time (in milliseconds) after which a new code can be sent
+		ResendAllowedAt uint64
+	}
+	// MaxResendsReachedError is returned if MaxResends of the code policy were already sent for
+	// the device. The user has to restart the flow.
+	MaxResendsReachedError *struct{}
+	GeneralError           *supertokens.GeneralErrorResponse
 }
 
 type CreateCodePOSTResponse struct {
@@ -74,6 +107,11 @@ type CreateCodePOSTResponse struct {
 		PreAuthSessionID string
 		FlowType         string
 	}
+	// EmailNotAllowedError is returned if a new user signs up with an email rejected by the
+	// EmailPolicy
+	EmailNotAllowedError *struct {
+		Reason emailpolicy.Reason
+	}
 	GeneralError *supertokens.GeneralErrorResponse
 }
 
@@ -17,7 +17,11 @@ package plessmodels
 
 import (
 	"github.com/supertokens/supertokens-golang/ingredients/emaildelivery"
+	"github.com/supertokens/supertokens-golang/ingredients/emailpolicy"
 	"github.com/supertokens/supertokens-golang/ingredients/smsdelivery"
+	"github.com/supertokens/supertokens-golang/recipe/passwordless/codepolicy"
+	"github.com/supertokens/supertokens-golang/recipe/passwordless/crossdevice"
+	"github.com/supertokens/supertokens-golang/recipe/passwordless/phonenumber"
 	"github.com/supertokens/supertokens-golang/supertokens"
 )
 
@@ -38,6 +42,20 @@ type TypeInput struct {
 	Override                  *OverrideStruct
 	EmailDelivery             *emaildelivery.TypeInput
 	SmsDelivery               *smsdelivery.TypeInput
+	// CodePolicy configures the format and lifetime of the codes, the number of failed attempts
+	// and how often codes can be resent. The user input codes are generated using the policy
+	// unless one is given (for example by GetCustomUserInputCode).
+	CodePolicy *codepolicy.TypeInput
+	// CrossDeviceLink lets users open the magic link on another device than the one that
+	// requested it. The link then approves the login attempt instead of creating a session, and
+	// the device that requested it gets its session from the code status API.
+	CrossDeviceLink *crossdevice.TypeInput
+	// PhoneNumber configures the default region of numbers given without a country code and the
+	// number types that can be used. Numbers are always stored in the E.164 format.
+	PhoneNumber *phonenumber.TypeInput
+	// EmailPolicy normalises the emails before they are stored or looked up, and restricts the
+	// emails that can sign up
+	EmailPolicy *emailpolicy.TypeInput
 }
 
 type TypeNormalisedInput struct {
@@ -49,6 +67,14 @@ type TypeNormalisedInput struct {
 	Override                  OverrideStruct
 	GetEmailDeliveryConfig    func() emaildelivery.TypeInputWithService
 	GetSmsDeliveryConfig      func() smsdelivery.TypeInputWithService
+	// CodePolicy is nil if the limits of the core are used
+	CodePolicy *codepolicy.TypeNormalisedInput
+	// CrossDeviceLink is nil if magic links always create the session on the device that opens them
+	CrossDeviceLink *crossdevice.TypeNormalisedInput
+	// PhoneNumber is nil if numbers must have a country code and can be of any type
+	PhoneNumber *phonenumber.TypeNormalisedInput
+	// EmailPolicy is nil if emails are used as given and all emails can sign up
+	EmailPolicy *emailpolicy.TypeNormalisedInput
 }
 
 type OverrideStruct struct {
@@ -24,6 +24,7 @@ import (
 	"github.com/supertokens/supertokens-golang/recipe/emailverification"
 	"github.com/supertokens/supertokens-golang/recipe/emailverification/evmodels"
 	"github.com/supertokens/supertokens-golang/recipe/passwordless/api"
+	"github.com/supertokens/supertokens-golang/recipe/passwordless/crossdevice"
 	"github.com/supertokens/supertokens-golang/recipe/passwordless/plessmodels"
 	"github.com/supertokens/supertokens-golang/supertokens"
 )
@@ -52,7 +53,9 @@ func MakeRecipe(recipeId string, appInfo supertokens.NormalisedAppinfo, config p
 	if err != nil {
 		return Recipe{}, err
 	}
-	recipeImplementation := MakeRecipeImplementation(*querierInstance)
+	recipeImplementation := makeRecipeImplementation(*querierInstance, func() plessmodels.TypeNormalisedInput {
+		return r.Config
+	})
 	r.RecipeImpl = verifiedConfig.Override.Functions(recipeImplementation)
 
 	recipeModuleInstance := supertokens.MakeRecipeModule(recipeId, appInfo, r.handleAPIRequest, r.getAllCORSHeaders, r.getAPIsHandled, nil, r.handleError, onSuperTokensAPIError)
@@ -99,6 +102,8 @@ func MakeRecipe(recipeId string, appInfo supertokens.NormalisedAppinfo, config p
 		UserContext      supertokens.UserContext
 	}{}
 	r.RecipeModule.ResetForTest = resetForTest
+	r.RecipeModule.GetTenantIdsForUser = r.getTenantIdsForUser
+	r.RecipeModule.ExportUserData = r.exportUserData
 	// the code above is for testing related resets
 
 	return *r, nil
@@ -160,6 +165,10 @@ func (r *Recipe) getAPIsHandled() ([]supertokens.APIHandled, error) {
 	if err != nil {
 		return nil, err
 	}
+	codeStatusAPINormalised, err := supertokens.NewNormalisedURLPath(codeStatusAPI)
+	if err != nil {
+		return nil, err
+	}
 
 	return []supertokens.APIHandled{{
 		Method:                 http.MethodPost,
@@ -196,6 +205,11 @@ func (r *Recipe) getAPIsHandled() ([]supertokens.APIHandled, error) {
 		PathWithoutAPIBasePath: resendCodeAPINormalised,
 		ID:                     resendCodeAPI,
 		Disabled:               r.APIImpl.ResendCodePOST == nil,
+	}, {
+		Method:                 http.MethodGet,
+		PathWithoutAPIBasePath: codeStatusAPINormalised,
+		ID:                     codeStatusAPI,
+		Disabled:               r.APIImpl.CodeStatusGET == nil || r.Config.CrossDeviceLink == nil,
 	}}, nil
 }
 
@@ -219,12 +233,17 @@ func (r *Recipe) handleAPIRequest(id string, tenantId string, req *http.Request,
 		return api.DoesEmailExist(r.APIImpl, tenantId, options, userContext)
 	} else if id == doesPhoneNumberExistAPIOld || id == doesPhoneNumberExistAPI {
 		return api.DoesPhoneNumberExist(r.APIImpl, tenantId, options, userContext)
+	} else if id == codeStatusAPI {
+		return api.CodeStatus(r.APIImpl, tenantId, options, userContext)
 	} else {
 		return api.ResendCode(r.APIImpl, tenantId, options, userContext)
 	}
 }
 
 func (r *Recipe) getAllCORSHeaders() []string {
+	if r.Config.CrossDeviceLink != nil {
+		return []string{crossdevice.DeviceIdHeaderKey}
+	}
 	return []string{}
 }
 
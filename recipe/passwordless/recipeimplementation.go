@@ -17,18 +17,114 @@ package passwordless
 
 import (
 	"errors"
+	"time"
 
+	"github.com/supertokens/supertokens-golang/ingredients/emailpolicy"
+	"github.com/supertokens/supertokens-golang/recipe/passwordless/codepolicy"
+	"github.com/supertokens/supertokens-golang/recipe/passwordless/phonenumber"
 	"github.com/supertokens/supertokens-golang/recipe/passwordless/plessmodels"
 	"github.com/supertokens/supertokens-golang/supertokens"
 )
 
+// defaultMaximumCodeInputAttempts is the default of the core, used when the core is not called
+const defaultMaximumCodeInputAttempts = 5
+
+// MakeRecipeImplementation uses the code policy, phone number and email policy config of the
+// initialised passwordless recipe. If the recipe is not initialised, the limits of the core are
+// used and emails are not normalised.
 func MakeRecipeImplementation(querier supertokens.Querier) plessmodels.RecipeInterface {
+	return makeRecipeImplementation(querier, func() plessmodels.TypeNormalisedInput {
+		if singletonInstance == nil {
+			return plessmodels.TypeNormalisedInput{}
+		}
+		return singletonInstance.Config
+	})
+}
+
+func makeRecipeImplementation(querier supertokens.Querier, getPasswordlessConfig func() plessmodels.TypeNormalisedInput) plessmodels.RecipeInterface {
+	// getCodeLifetime shortens the lifetime returned by the core to the one of the code policy
+	getCodeLifetime := func(coreCodeLifetime uint64, tenantId string) uint64 {
+		config := getPasswordlessConfig().CodePolicy
+		if config == nil {
+			return coreCodeLifetime
+		}
+		return codepolicy.GetCodeLifetime(codepolicy.GetPolicy(*config, tenantId), coreCodeLifetime)
+	}
+
+	// generateUserInputCode returns nil if the core should generate the code
+	generateUserInputCode := func(tenantId string) (*string, error) {
+		config := getPasswordlessConfig().CodePolicy
+		if config == nil {
+			return nil, nil
+		}
+		code, err := codepolicy.GenerateUserInputCode(codepolicy.GetPolicy(*config, tenantId))
+		if err != nil {
+			return nil, err
+		}
+		return &code, nil
+	}
+
+	// normalisePhoneNumber formats the number as E.164 so that the same number is always stored
+	// and looked up the same way, whatever the format it was given in
+	normalisePhoneNumber := func(phoneNumber string, tenantId string) string {
+		return phonenumber.NormaliseLeniently(getPasswordlessConfig().PhoneNumber, phoneNumber, tenantId)
+	}
+
+	getUserByEmailInCore := func(email string, tenantId string, userContext supertokens.UserContext) (*plessmodels.User, error) {
+		response, err := querier.SendGetRequest(tenantId+"/recipe/user", map[string]string{
+			"email": email,
+		}, userContext)
+		if err != nil {
+			return nil, err
+		}
+		status := response["status"].(string)
+
+		if status == "OK" {
+			user := getUserFromJSONResponse(response["user"].(map[string]interface{}))
+			return &user, nil
+		}
+		return nil, nil
+	}
+
+	// getEmailInCore returns the normalised email, unless only a user that signed up before the
+	// email policy was set has the email as it was given. That user is then signed in instead
+	// of creating a new user with the normalised email.
+	getEmailInCore := func(email string, tenantId string, userContext supertokens.UserContext) (string, error) {
+		normalisedEmail := emailpolicy.Normalise(getPasswordlessConfig().EmailPolicy, email, tenantId)
+		if normalisedEmail == email {
+			return email, nil
+		}
+		user, err := getUserByEmailInCore(normalisedEmail, tenantId, userContext)
+		if err != nil || user != nil {
+			return normalisedEmail, err
+		}
+		user, err = getUserByEmailInCore(email, tenantId, userContext)
+		if err != nil {
+			return "", err
+		}
+		if user != nil {
+			return email, nil
+		}
+		return normalisedEmail, nil
+	}
+
 	createCode := func(email *string, phoneNumber *string, userInputCode *string, tenantId string, userContext supertokens.UserContext) (plessmodels.CreateCodeResponse, error) {
 		body := map[string]interface{}{}
 		if email != nil {
-			body["email"] = *email
+			emailInCore, err := getEmailInCore(*email, tenantId, userContext)
+			if err != nil {
+				return plessmodels.CreateCodeResponse{}, err
+			}
+			body["email"] = emailInCore
 		} else if phoneNumber != nil {
-			body["phoneNumber"] = *phoneNumber
+			body["phoneNumber"] = normalisePhoneNumber(*phoneNumber, tenantId)
+		}
+		if userInputCode == nil {
+			generatedCode, err := generateUserInputCode(tenantId)
+			if err != nil {
+				return plessmodels.CreateCodeResponse{}, err
+			}
+			userInputCode = generatedCode
 		}
 		if userInputCode != nil {
 			body["userInputCode"] = *userInputCode
@@ -44,7 +140,7 @@ func MakeRecipeImplementation(querier supertokens.Querier) plessmodels.RecipeInt
 				DeviceID:         response["deviceId"].(string),
 				UserInputCode:    response["userInputCode"].(string),
 				LinkCode:         response["linkCode"].(string),
-				CodeLifetime:     uint64(response["codeLifetime"].(float64)),
+				CodeLifetime:     getCodeLifetime(uint64(response["codeLifetime"].(float64)), tenantId),
 				TimeCreated:      uint64(response["timeCreated"].(float64)),
 			},
 		}, nil
@@ -108,6 +204,13 @@ func MakeRecipeImplementation(querier supertokens.Querier) plessmodels.RecipeInt
 			"deviceId": deviceID,
 		}
 
+		if userInputCode == nil {
+			generatedCode, err := generateUserInputCode(tenantId)
+			if err != nil {
+				return plessmodels.ResendCodeResponse{}, err
+			}
+			userInputCode = generatedCode
+		}
 		if userInputCode != nil {
 			body["userInputCode"] = *userInputCode
 		}
@@ -127,7 +230,7 @@ func MakeRecipeImplementation(querier supertokens.Querier) plessmodels.RecipeInt
 					DeviceID:         response["deviceId"].(string),
 					UserInputCode:    response["userInputCode"].(string),
 					LinkCode:         response["linkCode"].(string),
-					CodeLifetime:     uint64(response["codeLifetime"].(float64)),
+					CodeLifetime:     getCodeLifetime(uint64(response["codeLifetime"].(float64)), tenantId),
 					TimeCreated:      uint64(response["timeCreated"].(float64)),
 				},
 			}, nil
@@ -143,19 +246,11 @@ func MakeRecipeImplementation(querier supertokens.Querier) plessmodels.RecipeInt
 	}
 
 	getUserByEmail := func(email string, tenantId string, userContext supertokens.UserContext) (*plessmodels.User, error) {
-		response, err := querier.SendGetRequest(tenantId+"/recipe/user", map[string]string{
-			"email": email,
-		}, userContext)
+		emailInCore, err := getEmailInCore(email, tenantId, userContext)
 		if err != nil {
 			return nil, err
 		}
-		status := response["status"].(string)
-
-		if status == "OK" {
-			user := getUserFromJSONResponse(response["user"].(map[string]interface{}))
-			return &user, nil
-		}
-		return nil, nil
+		return getUserByEmailInCore(emailInCore, tenantId, userContext)
 	}
 
 	getUserByID := func(userID string, userContext supertokens.UserContext) (*plessmodels.User, error) {
@@ -176,7 +271,7 @@ func MakeRecipeImplementation(querier supertokens.Querier) plessmodels.RecipeInt
 
 	getUserByPhoneNumber := func(phoneNumber string, tenantId string, userContext supertokens.UserContext) (*plessmodels.User, error) {
 		response, err := querier.SendGetRequest(tenantId+"/recipe/user", map[string]string{
-			"phoneNumber": phoneNumber,
+			"phoneNumber": normalisePhoneNumber(phoneNumber, tenantId),
 		}, userContext)
 		if err != nil {
 			return nil, err
@@ -209,8 +304,12 @@ func MakeRecipeImplementation(querier supertokens.Querier) plessmodels.RecipeInt
 	}
 
 	listCodesByEmail := func(email string, tenantId string, userContext supertokens.UserContext) ([]plessmodels.DeviceType, error) {
+		emailInCore, err := getEmailInCore(email, tenantId, userContext)
+		if err != nil {
+			return nil, err
+		}
 		response, err := querier.SendGetRequest(tenantId+"/recipe/signinup/codes", map[string]string{
-			"email": email,
+			"email": emailInCore,
 		}, userContext)
 
 		if err != nil {
@@ -222,7 +321,7 @@ func MakeRecipeImplementation(querier supertokens.Querier) plessmodels.RecipeInt
 
 	listCodesByPhoneNumber := func(phoneNumber string, tenantId string, userContext supertokens.UserContext) ([]plessmodels.DeviceType, error) {
 		response, err := querier.SendGetRequest(tenantId+"/recipe/signinup/codes", map[string]string{
-			"phoneNumber": phoneNumber,
+			"phoneNumber": normalisePhoneNumber(phoneNumber, tenantId),
 		}, userContext)
 
 		if err != nil {
@@ -253,9 +352,13 @@ func MakeRecipeImplementation(querier supertokens.Querier) plessmodels.RecipeInt
 	revokeAllCodes := func(email *string, phoneNumber *string, tenantId string, userContext supertokens.UserContext) error {
 		body := map[string]interface{}{}
 		if email != nil {
-			body["email"] = *email
+			emailInCore, err := getEmailInCore(*email, tenantId, userContext)
+			if err != nil {
+				return err
+			}
+			body["email"] = emailInCore
 		} else if phoneNumber != nil {
-			body["phoneNumber"] = *phoneNumber
+			body["phoneNumber"] = normalisePhoneNumber(*phoneNumber, tenantId)
 		}
 		_, err := querier.SendPostRequest(tenantId+"/recipe/signinup/codes/remove", body, userContext)
 		if err != nil {
@@ -275,15 +378,120 @@ func MakeRecipeImplementation(querier supertokens.Querier) plessmodels.RecipeInt
 		return nil
 	}
 
+	revokeCodesOfDevice := func(device plessmodels.DeviceType, tenantId string, userContext supertokens.UserContext) error {
+		for _, code := range device.Codes {
+			err := revokeCode(code.CodeID, tenantId, userContext)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	// consumeCodeWithPolicy enforces the code lifetime and the maximum number of failed attempts of
+	// the code policy, which can be stricter than the ones of the core
+	consumeCodeWithPolicy := func(userInput *plessmodels.UserInputCodeWithDeviceID, linkCode *string, preAuthSessionID string, tenantId string, userContext supertokens.UserContext) (plessmodels.ConsumeCodeResponse, error) {
+		config := getPasswordlessConfig().CodePolicy
+		if config == nil {
+			return consumeCode(userInput, linkCode, preAuthSessionID, tenantId, userContext)
+		}
+		policy := codepolicy.GetPolicy(*config, tenantId)
+
+		if policy.CodeLifetime != 0 {
+			device, err := listCodesByPreAuthSessionID(preAuthSessionID, tenantId, userContext)
+			if err != nil {
+				return plessmodels.ConsumeCodeResponse{}, err
+			}
+			if device != nil {
+				expiredCodes := []plessmodels.Code{}
+				now := time.Now()
+				for _, code := range device.Codes {
+					if codepolicy.IsCodeExpired(policy, code.TimeCreated, now) {
+						expiredCodes = append(expiredCodes, code)
+					}
+				}
+				if userInput != nil && len(expiredCodes) > 0 && len(expiredCodes) == len(device.Codes) {
+					// the codes are kept, so that a new code can still be sent for the device
+					return plessmodels.ConsumeCodeResponse{
+						ExpiredUserInputCodeError: &struct {
+							FailedCodeInputAttemptCount int
+							MaximumCodeInputAttempts    int
+						}{
+							FailedCodeInputAttemptCount: device.FailedCodeInputAttemptCount,
+							MaximumCodeInputAttempts:    codepolicy.GetMaximumCodeInputAttempts(policy, defaultMaximumCodeInputAttempts),
+						},
+					}, nil
+				}
+				// a link code cannot be matched to its code, so the expired codes are revoked
+				// before the core checks the link code
+				err = revokeCodesOfDevice(plessmodels.DeviceType{Codes: expiredCodes}, tenantId, userContext)
+				if err != nil {
+					return plessmodels.ConsumeCodeResponse{}, err
+				}
+			}
+		}
+
+		response, err := consumeCode(userInput, linkCode, preAuthSessionID, tenantId, userContext)
+		if err != nil {
+			return plessmodels.ConsumeCodeResponse{}, err
+		}
+
+		if response.IncorrectUserInputCodeError != nil {
+			errResponse := response.IncorrectUserInputCodeError
+			errResponse.MaximumCodeInputAttempts = codepolicy.GetMaximumCodeInputAttempts(policy, errResponse.MaximumCodeInputAttempts)
+			if errResponse.FailedCodeInputAttemptCount >= errResponse.MaximumCodeInputAttempts {
+				device, err := listCodesByPreAuthSessionID(preAuthSessionID, tenantId, userContext)
+				if err != nil {
+					return plessmodels.ConsumeCodeResponse{}, err
+				}
+				if device != nil {
+					supertokens.LogDebugMessage("codepolicy: revoking the codes of a device after too many failed attempts")
+					err = revokeCodesOfDevice(*device, tenantId, userContext)
+					if err != nil {
+						return plessmodels.ConsumeCodeResponse{}, err
+					}
+				}
+				err = config.Store.Delete(preAuthSessionID, userContext)
+				if err != nil {
+					return plessmodels.ConsumeCodeResponse{}, err
+				}
+			}
+		} else if response.ExpiredUserInputCodeError != nil {
+			errResponse := response.ExpiredUserInputCodeError
+			errResponse.MaximumCodeInputAttempts = codepolicy.GetMaximumCodeInputAttempts(policy, errResponse.MaximumCodeInputAttempts)
+		} else {
+			// the login attempt is over
+			err = config.Store.Delete(preAuthSessionID, userContext)
+			if err != nil {
+				return plessmodels.ConsumeCodeResponse{}, err
+			}
+		}
+		return response, nil
+	}
+
 	updateUser := func(userID string, email *string, phoneNumber *string, userContext supertokens.UserContext) (plessmodels.UpdateUserResponse, error) {
 		body := map[string]interface{}{
 			"userId": userID,
 		}
+
+		// the normalisation of the email and of numbers without a country code can depend on
+		// the tenant of the user
+		tenantId := supertokens.DefaultTenantId
+		config := getPasswordlessConfig()
+		if (email != nil && config.EmailPolicy != nil) || (phoneNumber != nil && config.PhoneNumber != nil && len(config.PhoneNumber.TenantDefaultRegions) > 0) {
+			user, err := getUserByID(userID, userContext)
+			if err != nil {
+				return plessmodels.UpdateUserResponse{}, err
+			}
+			if user != nil && len(user.TenantIds) > 0 {
+				tenantId = user.TenantIds[0]
+			}
+		}
 		if email != nil {
-			body["email"] = *email
+			body["email"] = emailpolicy.Normalise(config.EmailPolicy, *email, tenantId)
 		}
 		if phoneNumber != nil {
-			body["phoneNumber"] = *phoneNumber
+			body["phoneNumber"] = normalisePhoneNumber(*phoneNumber, tenantId)
 		}
 
 		response, err := querier.SendPutRequest("/recipe/user", body, userContext)
@@ -366,7 +574,7 @@ func MakeRecipeImplementation(querier supertokens.Querier) plessmodels.RecipeInt
 
 	return plessmodels.RecipeInterface{
 		CreateCode:                  &createCode,
-		ConsumeCode:                 &consumeCode,
+		ConsumeCode:                 &consumeCodeWithPolicy,
 		CreateNewCodeForDevice:      &createNewCodeForDevice,
 		GetUserByEmail:              &getUserByEmail,
 		GetUserByID:                 &getUserByID,
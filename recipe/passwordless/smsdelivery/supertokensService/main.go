@@ -65,6 +65,9 @@ func MakeSupertokensSMSService(apiKey string) *smsdelivery.SmsDeliveryInterface
 		req.Header.Set("api-version", "0")
 		client := &http.Client{}
 		resp, err := client.Do(req)
+		if err == nil {
+			defer resp.Body.Close()
+		}
 
 		if err == nil && resp.StatusCode < 300 {
 			supertokens.LogDebugMessage(fmt.Sprintf("Passwordless login SMS sent to %s", input.PhoneNumber))
@@ -75,14 +78,14 @@ func MakeSupertokensSMSService(apiKey string) *smsdelivery.SmsDeliveryInterface
 			supertokens.LogDebugMessage(fmt.Sprintf("Error: %s", err.Error()))
 		} else {
 			supertokens.LogDebugMessage(fmt.Sprintf("Error status: %d", resp.StatusCode))
-			body, err := ioutil.ReadAll(resp.Body)
-			if err != nil {
-				supertokens.LogDebugMessage(fmt.Sprintf("Error: %s", err.Error()))
+			body, readErr := ioutil.ReadAll(resp.Body)
+			if readErr != nil {
+				supertokens.LogDebugMessage(fmt.Sprintf("Error: %s", readErr.Error()))
 			} else {
 				supertokens.LogDebugMessage(fmt.Sprintf("Error response: %s", string(body)))
 			}
 
-			_ = fmt.Errorf("Error sending SMS. API returned %d status.", resp.StatusCode)
+			err = fmt.Errorf("Error sending SMS. API returned %d status.", resp.StatusCode)
 		}
 
 		supertokens.LogDebugMessage("Logging the input below:")
@@ -26,13 +26,17 @@ func MakeServiceImplementation(config smsdelivery.TwilioSettings) smsdelivery.Tw
 		return smsdelivery.SendTwilioSms(config, input)
 	}
 
-	getContent := func(input smsdelivery.SmsType, userContext supertokens.UserContext) (smsdelivery.SMSContent, error) {
-		result := getPasswordlessLoginSmsContent(*input.PasswordlessLogin)
-		return result, nil
-	}
+	getContent := GetDefaultContent
 
 	return smsdelivery.TwilioInterface{
 		SendRawSms: &sendRawSms,
 		GetContent: &getContent,
 	}
 }
+
+// GetDefaultContent returns the built-in content of the SMS sent by this recipe. It is
+// also used by the services that send SMS using an HTTP API.
+func GetDefaultContent(input smsdelivery.SmsType, userContext supertokens.UserContext) (smsdelivery.SMSContent, error) {
+	result := getPasswordlessLoginSmsContent(*input.PasswordlessLogin)
+	return result, nil
+}
@@ -21,8 +21,12 @@ import (
 
 	"github.com/nyaruka/phonenumbers"
 	"github.com/supertokens/supertokens-golang/ingredients/emaildelivery"
+	"github.com/supertokens/supertokens-golang/ingredients/emailpolicy"
 	"github.com/supertokens/supertokens-golang/ingredients/smsdelivery"
+	"github.com/supertokens/supertokens-golang/recipe/passwordless/codepolicy"
+	"github.com/supertokens/supertokens-golang/recipe/passwordless/crossdevice"
 	"github.com/supertokens/supertokens-golang/recipe/passwordless/emaildelivery/backwardCompatibilityService"
+	"github.com/supertokens/supertokens-golang/recipe/passwordless/phonenumber"
 	"github.com/supertokens/supertokens-golang/recipe/passwordless/plessmodels"
 	smsBackwardCompatibilityService "github.com/supertokens/supertokens-golang/recipe/passwordless/smsdelivery/backwardCompatibilityService"
 	"github.com/supertokens/supertokens-golang/supertokens"
@@ -82,6 +86,44 @@ func validateAndNormaliseUserInput(appInfo supertokens.NormalisedAppinfo, config
 
 	// GetCustomUserInputCode is initialized correctly in makeTypeNormalisedInput
 
+	if config.CrossDeviceLink != nil {
+		if config.FlowType == "USER_INPUT_CODE" {
+			panic("CrossDeviceLink can only be used with the MAGIC_LINK and USER_INPUT_CODE_AND_MAGIC_LINK flow types")
+		}
+		crossDeviceLink, err := crossdevice.NormaliseTypeInput(*config.CrossDeviceLink)
+		if err != nil {
+			panic(err.Error())
+		}
+		typeNormalisedInput.CrossDeviceLink = &crossDeviceLink
+	}
+
+	if config.PhoneNumber != nil {
+		phoneNumber, err := phonenumber.NormaliseTypeInput(*config.PhoneNumber)
+		if err != nil {
+			panic(err.Error())
+		}
+		typeNormalisedInput.PhoneNumber = &phoneNumber
+	}
+
+	if config.EmailPolicy != nil {
+		emailPolicy, err := emailpolicy.NormaliseTypeInput(*config.EmailPolicy)
+		if err != nil {
+			panic(err.Error())
+		}
+		typeNormalisedInput.EmailPolicy = &emailPolicy
+	}
+
+	if config.CodePolicy != nil {
+		codePolicy, err := codepolicy.NormaliseTypeInput(*config.CodePolicy)
+		if err != nil {
+			panic(err.Error())
+		}
+		typeNormalisedInput.CodePolicy = &codePolicy
+		if config.GetCustomUserInputCode != nil && isCodeFormatSet(*config.CodePolicy) {
+			panic("CodeLength and Charset of the CodePolicy cannot be used with GetCustomUserInputCode")
+		}
+	}
+
 	typeNormalisedInput.GetEmailDeliveryConfig = func() emaildelivery.TypeInputWithService {
 		createAndSendCustomEmail := DefaultCreateAndSendCustomEmail(appInfo)
 		emailService := backwardCompatibilityService.MakeBackwardCompatibilityService(appInfo, createAndSendCustomEmail)
@@ -124,6 +166,19 @@ func validateAndNormaliseUserInput(appInfo supertokens.NormalisedAppinfo, config
 	return typeNormalisedInput
 }
 
+// isCodeFormatSet returns true if the code length or charset is set for any tenant
+func isCodeFormatSet(config codepolicy.TypeInput) bool {
+	if config.Default.CodeLength != nil || config.Default.Charset != nil {
+		return true
+	}
+	for _, policy := range config.TenantPolicies {
+		if policy.CodeLength != nil || policy.Charset != nil {
+			return true
+		}
+	}
+	return false
+}
+
 func makeTypeNormalisedInput(appInfo supertokens.NormalisedAppinfo, inputConfig plessmodels.TypeInput) plessmodels.TypeNormalisedInput {
 	return plessmodels.TypeNormalisedInput{
 		FlowType: inputConfig.FlowType,
@@ -0,0 +1,174 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package session
+
+import (
+	"sync"
+	"time"
+
+	"github.com/supertokens/supertokens-golang/recipe/session/claims"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+// maxUsersInClaimVersionStore is the number of users for which the in-memory store keeps the
+// versions of the claims
+const maxUsersInClaimVersionStore = 10000
+
+// makeInMemoryClaimVersionStore is the default ClaimVersionStore. The versions are not shared
+// between processes, so only the process that marked the claims as stale refetches them.
+func makeInMemoryClaimVersionStore() sessmodels.ClaimVersionStore {
+	return makeInMemoryClaimVersionStoreWithLimit(maxUsersInClaimVersionStore)
+}
+
+func makeInMemoryClaimVersionStoreWithLimit(maxUsers int) sessmodels.ClaimVersionStore {
+	var mutex sync.RWMutex
+	versionsForAllUsers := map[string]int64{}
+	versionsPerUser := map[string]map[string]int64{}
+	lastVersionPerUser := map[string]int64{}
+
+	// evictOldestUser removes the user whose claims were marked as stale the longest time ago. Its
+	// versions are kept for all users, so that its sessions still refetch the claims (along with
+	// the sessions of the other users whose claims were fetched before).
+	evictOldestUser := func() {
+		oldestUserId := ""
+		var oldestVersion int64
+		for userId, version := range lastVersionPerUser {
+			if oldestUserId == "" || version < oldestVersion {
+				oldestUserId = userId
+				oldestVersion = version
+			}
+		}
+		for key, version := range versionsPerUser[oldestUserId] {
+			if version > versionsForAllUsers[key] {
+				versionsForAllUsers[key] = version
+			}
+		}
+		delete(versionsPerUser, oldestUserId)
+		delete(lastVersionPerUser, oldestUserId)
+	}
+
+	return sessmodels.ClaimVersionStore{
+		SetClaimVersions: func(userId *string, claimKeys []string, version int64, userContext supertokens.UserContext) error {
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			versions := versionsForAllUsers
+			if userId != nil {
+				if _, ok := versionsPerUser[*userId]; !ok {
+					if len(versionsPerUser) >= maxUsers {
+						evictOldestUser()
+					}
+					versionsPerUser[*userId] = map[string]int64{}
+				}
+				versions = versionsPerUser[*userId]
+				if version > lastVersionPerUser[*userId] {
+					lastVersionPerUser[*userId] = version
+				}
+			}
+			for _, key := range claimKeys {
+				if version > versions[key] {
+					versions[key] = version
+				}
+			}
+			return nil
+		},
+		GetClaimVersions: func(userId string, userContext supertokens.UserContext) (map[string]int64, error) {
+			mutex.RLock()
+			defer mutex.RUnlock()
+
+			result := map[string]int64{}
+			for key, version := range versionsForAllUsers {
+				result[key] = version
+			}
+			for key, version := range versionsPerUser[userId] {
+				if version > result[key] {
+					result[key] = version
+				}
+			}
+			return result, nil
+		},
+	}
+}
+
+func markClaimsAsStale(userId *string, claimKeys []string, userContext supertokens.UserContext) error {
+	instance := singletonInstance
+	if instance == nil {
+		// there are no sessions to refetch the claims
+		supertokens.LogDebugMessage("markClaimsAsStale: the session recipe is not initialised, nothing to do")
+		return nil
+	}
+	return instance.Config.ClaimVersionStore.SetClaimVersions(userId, claimKeys, time.Now().UnixNano()/1000000, userContext)
+}
+
+// RefetchStaleClaims refetches the claims in the access token payload of the session that were marked
+// as stale after they were fetched. The access token is regenerated once for all refetched claims.
+// claimValidators are used to find the claims to check in addition to the claims added by other recipes.
+func RefetchStaleClaims(sessionContainer sessmodels.SessionContainer, claimValidators []claims.SessionClaimValidator, userContext supertokens.UserContext) error {
+	instance, err := getRecipeInstanceOrThrowError()
+	if err != nil {
+		return err
+	}
+
+	userId := sessionContainer.GetUserIDWithContext(userContext)
+	versions, err := instance.Config.ClaimVersionStore.GetClaimVersions(userId, userContext)
+	if err != nil {
+		return err
+	}
+	if len(versions) == 0 {
+		return nil
+	}
+
+	claimsToCheck := map[string]*claims.TypeSessionClaim{}
+	for _, claim := range instance.GetClaimsAddedByOtherRecipes() {
+		claimsToCheck[claim.Key] = claim
+	}
+	for _, validator := range claimValidators {
+		if validator.Claim != nil {
+			claimsToCheck[validator.Claim.Key] = validator.Claim
+		}
+	}
+
+	accessTokenPayload := sessionContainer.GetAccessTokenPayloadWithContext(userContext)
+	tenantId := sessionContainer.GetTenantIdWithContext(userContext)
+	var accessTokenPayloadUpdate map[string]interface{} = nil
+
+	for key, version := range versions {
+		claim, ok := claimsToCheck[key]
+		if !ok {
+			continue
+		}
+		lastRefetchTime := claim.GetLastRefetchTime(accessTokenPayload, userContext)
+		// Claims that are not in the payload are handled by the validators
+		if lastRefetchTime == nil || *lastRefetchTime >= version {
+			continue
+		}
+		supertokens.LogDebugMessage("RefetchStaleClaims: refetching stale claim " + key)
+		accessTokenPayloadUpdate, err = claim.Build(userId, tenantId, accessTokenPayloadUpdate, userContext)
+		if err != nil {
+			return err
+		}
+		if claim.GetValueFromPayload(accessTokenPayloadUpdate, userContext) == nil {
+			// The claim no longer has a value, so we remove it from the payload
+			accessTokenPayloadUpdate = claim.RemoveFromPayloadByMerge_internal(accessTokenPayloadUpdate, userContext)
+		}
+	}
+
+	if accessTokenPayloadUpdate == nil {
+		return nil
+	}
+	return sessionContainer.MergeIntoAccessTokenPayloadWithContext(accessTokenPayloadUpdate, userContext)
+}
@@ -0,0 +1,136 @@
+package session
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/session/claims"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func TestInMemoryClaimVersionStore(t *testing.T) {
+	store := makeInMemoryClaimVersionStore()
+
+	versions, err := store.GetClaimVersions("user1", nil)
+	assert.NoError(t, err)
+	assert.Empty(t, versions)
+
+	user1 := "user1"
+	assert.NoError(t, store.SetClaimVersions(&user1, []string{"a", "b"}, 100, nil))
+	assert.NoError(t, store.SetClaimVersions(nil, []string{"b", "c"}, 200, nil))
+	// Older versions do not overwrite newer ones
+	assert.NoError(t, store.SetClaimVersions(&user1, []string{"a"}, 50, nil))
+
+	versions, err = store.GetClaimVersions("user1", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int64{"a": 100, "b": 200, "c": 200}, versions)
+
+	versions, err = store.GetClaimVersions("user2", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int64{"b": 200, "c": 200}, versions)
+}
+
+func TestInMemoryClaimVersionStoreEvictsTheOldestUser(t *testing.T) {
+	store := makeInMemoryClaimVersionStoreWithLimit(2)
+
+	user1, user2, user3 := "user1", "user2", "user3"
+	assert.NoError(t, store.SetClaimVersions(&user1, []string{"a"}, 100, nil))
+	assert.NoError(t, store.SetClaimVersions(&user2, []string{"b"}, 200, nil))
+	assert.NoError(t, store.SetClaimVersions(&user3, []string{"c"}, 300, nil))
+
+	// the versions of the evicted user are kept for all users, so its sessions still refetch the claim
+	versions, err := store.GetClaimVersions("user1", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int64{"a": 100}, versions)
+
+	versions, err = store.GetClaimVersions("user3", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int64{"a": 100, "c": 300}, versions)
+}
+
+func TestMarkClaimsAsStaleWithoutSessionRecipe(t *testing.T) {
+	resetAll()
+	defer resetAll()
+
+	assert.NoError(t, MarkClaimsAsStale("userId", []string{"a"}))
+	assert.NoError(t, MarkClaimsAsStaleForAllUsers([]string{"a"}))
+}
+
+func TestRefetchStaleClaims(t *testing.T) {
+	resetAll()
+	defer resetAll()
+	err := supertokens.Init(supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: "http://localhost:8080",
+		},
+		AppInfo: supertokens.AppInfo{
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+			APIDomain:     "api.supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{
+			Init(nil),
+		},
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	numberOfFetches := 0
+	counterClaim, counterValidators := claims.PrimitiveClaim("st-counter", func(userId string, tenantId string, userContext supertokens.UserContext) (interface{}, error) {
+		numberOfFetches++
+		return numberOfFetches, nil
+	}, nil)
+
+	payload, err := counterClaim.Build("userId", "public", nil, nil)
+	assert.NoError(t, err)
+	// The value was fetched in the past
+	payload["st-counter"].(map[string]interface{})["t"] = int64(1000)
+
+	updates := []map[string]interface{}{}
+	sessionContainer := &sessmodels.TypeSessionContainer{}
+	sessionContainer.GetUserIDWithContext = func(userContext supertokens.UserContext) string {
+		return "userId"
+	}
+	sessionContainer.GetTenantIdWithContext = func(userContext supertokens.UserContext) string {
+		return "public"
+	}
+	sessionContainer.GetAccessTokenPayloadWithContext = func(userContext supertokens.UserContext) map[string]interface{} {
+		return payload
+	}
+	sessionContainer.MergeIntoAccessTokenPayloadWithContext = func(accessTokenPayloadUpdate map[string]interface{}, userContext supertokens.UserContext) error {
+		updates = append(updates, accessTokenPayloadUpdate)
+		for k, v := range accessTokenPayloadUpdate {
+			payload[k] = v
+		}
+		return nil
+	}
+	validators := []claims.SessionClaimValidator{counterValidators.HasValue(1, nil, nil)}
+
+	assert.NoError(t, RefetchStaleClaims(sessionContainer, validators, &map[string]interface{}{}))
+	assert.Len(t, updates, 0)
+
+	assert.NoError(t, MarkClaimsAsStale("otherUser", []string{"st-counter"}))
+	assert.NoError(t, RefetchStaleClaims(sessionContainer, validators, &map[string]interface{}{}))
+	assert.Len(t, updates, 0)
+
+	assert.NoError(t, MarkClaimsAsStale("userId", []string{"st-counter"}))
+	// Claims that are not used by the validators or other recipes are not refetched
+	assert.NoError(t, RefetchStaleClaims(sessionContainer, []claims.SessionClaimValidator{}, &map[string]interface{}{}))
+	assert.Len(t, updates, 0)
+
+	assert.NoError(t, RefetchStaleClaims(sessionContainer, validators, &map[string]interface{}{}))
+	assert.Len(t, updates, 1)
+	assert.Equal(t, 2, counterClaim.GetValueFromPayload(payload, nil))
+
+	// The claim was refetched after it was marked as stale
+	assert.NoError(t, RefetchStaleClaims(sessionContainer, validators, &map[string]interface{}{}))
+	assert.Len(t, updates, 1)
+
+	payload["st-counter"].(map[string]interface{})["t"] = int64(1000)
+	assert.NoError(t, MarkClaimsAsStaleForAllUsers([]string{"st-counter"}))
+	assert.NoError(t, RefetchStaleClaims(sessionContainer, validators, &map[string]interface{}{}))
+	assert.Len(t, updates, 2)
+	assert.Equal(t, 3, counterClaim.GetValueFromPayload(payload, nil))
+}
@@ -23,3 +23,28 @@ type BooleanClaimValidators struct {
 	IsTrue  func(maxAgeInSeconds *int64, id *string) SessionClaimValidator
 	IsFalse func(maxAgeInSeconds *int64, id *string) SessionClaimValidator
 }
+
+// NewBooleanClaim is the typed equivalent of BooleanClaim
+func NewBooleanClaim(key string, fetchValue TypedFetchValueFunc[bool], defaultMaxAgeInSeconds *int64) (*TypedSessionClaim[bool], TypedBooleanClaimValidators) {
+	claim, primitiveClaimValidators := NewPrimitiveClaim(key, fetchValue, defaultMaxAgeInSeconds)
+
+	validators := TypedBooleanClaimValidators{
+		TypedPrimitiveClaimValidators: primitiveClaimValidators,
+
+		IsTrue: func(maxAgeInSeconds *int64, id *string) SessionClaimValidator {
+			return primitiveClaimValidators.HasValue(true, maxAgeInSeconds, id)
+		},
+
+		IsFalse: func(maxAgeInSeconds *int64, id *string) SessionClaimValidator {
+			return primitiveClaimValidators.HasValue(false, maxAgeInSeconds, id)
+		},
+	}
+
+	return claim, validators
+}
+
+type TypedBooleanClaimValidators struct {
+	TypedPrimitiveClaimValidators[bool]
+	IsTrue  func(maxAgeInSeconds *int64, id *string) SessionClaimValidator
+	IsFalse func(maxAgeInSeconds *int64, id *string) SessionClaimValidator
+}
@@ -0,0 +1,60 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package claims
+
+// Number is the set of types that can be used as the value of a numeric claim
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 |
+		~float32 | ~float64
+}
+
+// NewNumericClaim creates a claim whose value is a number, for example a quota or a level
+func NewNumericClaim[T Number](key string, fetchValue TypedFetchValueFunc[T], defaultMaxAgeInSeconds *int64) (*TypedSessionClaim[T], NumericClaimValidators[T]) {
+	claim, primitiveClaimValidators := NewPrimitiveClaim(key, fetchValue, defaultMaxAgeInSeconds)
+
+	validators := NumericClaimValidators[T]{
+		TypedPrimitiveClaimValidators: primitiveClaimValidators,
+
+		GreaterThan: func(val T, maxAgeInSeconds *int64, id *string) SessionClaimValidator {
+			return newTypedClaimValidator(claim, defaultMaxAgeInSeconds, maxAgeInSeconds, id, "expectedToBeGreaterThan", val, func(claimVal T) bool {
+				return claimVal > val
+			})
+		},
+
+		LessThan: func(val T, maxAgeInSeconds *int64, id *string) SessionClaimValidator {
+			return newTypedClaimValidator(claim, defaultMaxAgeInSeconds, maxAgeInSeconds, id, "expectedToBeLessThan", val, func(claimVal T) bool {
+				return claimVal < val
+			})
+		},
+
+		Between: func(min T, max T, maxAgeInSeconds *int64, id *string) SessionClaimValidator {
+			return newTypedClaimValidator(claim, defaultMaxAgeInSeconds, maxAgeInSeconds, id, "expectedToBeBetween", []T{min, max}, func(claimVal T) bool {
+				return claimVal >= min && claimVal <= max
+			})
+		},
+	}
+
+	return claim, validators
+}
+
+type NumericClaimValidators[T Number] struct {
+	TypedPrimitiveClaimValidators[T]
+	GreaterThan func(val T, maxAgeInSeconds *int64, id *string) SessionClaimValidator
+	LessThan    func(val T, maxAgeInSeconds *int64, id *string) SessionClaimValidator
+	// Between checks that the value is in the [min, max] range (both inclusive)
+	Between func(min T, max T, maxAgeInSeconds *int64, id *string) SessionClaimValidator
+}
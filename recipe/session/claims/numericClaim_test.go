@@ -0,0 +1,67 @@
+package claims
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func TestNumericClaimValidators(t *testing.T) {
+	claim, validators := NewNumericClaim("test", func(userId string, tenantId string, userContext supertokens.UserContext) (*int, error) {
+		val := 10
+		return &val, nil
+	}, nil)
+
+	payload, err := claim.Build("userId", "public", nil, nil)
+	assert.NoError(t, err)
+
+	for _, p := range []map[string]interface{}{payload, jsonRoundTrip(t, payload)} {
+		assert.True(t, validators.GreaterThan(9, nil, nil).Validate(p, nil).IsValid)
+		assert.False(t, validators.GreaterThan(10, nil, nil).Validate(p, nil).IsValid)
+
+		assert.True(t, validators.LessThan(11, nil, nil).Validate(p, nil).IsValid)
+		assert.False(t, validators.LessThan(10, nil, nil).Validate(p, nil).IsValid)
+
+		assert.True(t, validators.Between(10, 20, nil, nil).Validate(p, nil).IsValid)
+		assert.True(t, validators.Between(0, 10, nil, nil).Validate(p, nil).IsValid)
+		assert.False(t, validators.Between(11, 20, nil, nil).Validate(p, nil).IsValid)
+
+		assert.True(t, validators.HasValue(10, nil, nil).Validate(p, nil).IsValid)
+	}
+
+	result := validators.Between(11, 20, nil, nil).Validate(payload, nil)
+	assert.Equal(t, []int{11, 20}, result.Reason.(map[string]interface{})["expectedToBeBetween"])
+	assert.Equal(t, 10, result.Reason.(map[string]interface{})["actualValue"])
+}
+
+func TestNumericClaimWithFloats(t *testing.T) {
+	claim, validators := NewNumericClaim("test", func(userId string, tenantId string, userContext supertokens.UserContext) (*float64, error) {
+		val := 0.5
+		return &val, nil
+	}, nil)
+
+	payload, err := claim.Build("userId", "public", nil, nil)
+	assert.NoError(t, err)
+	payload = jsonRoundTrip(t, payload)
+
+	assert.True(t, validators.Between(0, 1, nil, nil).Validate(payload, nil).IsValid)
+	assert.False(t, validators.GreaterThan(0.5, nil, nil).Validate(payload, nil).IsValid)
+}
+
+func TestNumericClaimMaxAge(t *testing.T) {
+	claim, validators := NewNumericClaim("test", func(userId string, tenantId string, userContext supertokens.UserContext) (*int, error) {
+		val := 10
+		return &val, nil
+	}, nil)
+
+	payload, err := claim.Build("userId", "public", nil, nil)
+	assert.NoError(t, err)
+
+	maxAge := int64(1)
+	assert.False(t, validators.GreaterThan(5, &maxAge, nil).ShouldRefetch(payload, nil))
+	time.Sleep(2 * time.Second)
+	assert.True(t, validators.GreaterThan(5, &maxAge, nil).ShouldRefetch(payload, nil))
+	assert.False(t, validators.GreaterThan(5, &maxAge, nil).Validate(payload, nil).IsValid)
+}
@@ -0,0 +1,106 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package claims
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// NewObjectClaim creates a claim whose value is an object (usually a struct or a map). Apart from
+// comparing the whole value, it can be validated using a predicate or by matching the value at a path.
+func NewObjectClaim[T any](key string, fetchValue TypedFetchValueFunc[T], defaultMaxAgeInSeconds *int64) (*TypedSessionClaim[T], ObjectClaimValidators[T]) {
+	claim, primitiveClaimValidators := NewPrimitiveClaim(key, fetchValue, defaultMaxAgeInSeconds)
+
+	validators := ObjectClaimValidators[T]{
+		TypedPrimitiveClaimValidators: primitiveClaimValidators,
+
+		Matches: func(predicate func(value T) bool, maxAgeInSeconds *int64, id *string) SessionClaimValidator {
+			return newTypedClaimValidator(claim, defaultMaxAgeInSeconds, maxAgeInSeconds, id, "", nil, predicate)
+		},
+
+		HasValueAtPath: func(path string, val interface{}, maxAgeInSeconds *int64, id *string) SessionClaimValidator {
+			expected := map[string]interface{}{
+				"path":  path,
+				"value": val,
+			}
+			return newTypedClaimValidator(claim, defaultMaxAgeInSeconds, maxAgeInSeconds, id, "expectedValueAtPath", expected, func(claimVal T) bool {
+				valueAtPath, ok := getValueAtPath(claimVal, path)
+				if !ok {
+					return false
+				}
+				normalisedVal, err := normaliseJSONValue(val)
+				if err != nil {
+					return false
+				}
+				return reflect.DeepEqual(valueAtPath, normalisedVal)
+			})
+		},
+	}
+
+	return claim, validators
+}
+
+type ObjectClaimValidators[T any] struct {
+	TypedPrimitiveClaimValidators[T]
+	// Matches checks the value of the claim using predicate. The validation reason only contains the
+	// actual value, so use a custom id if you need to tell validators of the same claim apart.
+	Matches func(predicate func(value T) bool, maxAgeInSeconds *int64, id *string) SessionClaimValidator
+	// HasValueAtPath checks that the JSON representation of the claim value has val at path. The path
+	// is a dot separated list of object keys and array indexes, for example "plan.features.0".
+	HasValueAtPath func(path string, val interface{}, maxAgeInSeconds *int64, id *string) SessionClaimValidator
+}
+
+func normaliseJSONValue(value interface{}) (interface{}, error) {
+	jsonValue, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var result interface{}
+	err = json.Unmarshal(jsonValue, &result)
+	return result, err
+}
+
+func getValueAtPath(value interface{}, path string) (interface{}, bool) {
+	current, err := normaliseJSONValue(value)
+	if err != nil {
+		return nil, false
+	}
+	if path == "" {
+		return current, true
+	}
+	for _, part := range strings.Split(path, ".") {
+		switch typedCurrent := current.(type) {
+		case map[string]interface{}:
+			next, ok := typedCurrent[part]
+			if !ok {
+				return nil, false
+			}
+			current = next
+		case []interface{}:
+			index, err := strconv.Atoi(part)
+			if err != nil || index < 0 || index >= len(typedCurrent) {
+				return nil, false
+			}
+			current = typedCurrent[index]
+		default:
+			return nil, false
+		}
+	}
+	return current, true
+}
@@ -0,0 +1,62 @@
+package claims
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+type testSubscription struct {
+	Tier     string   `json:"tier"`
+	Seats    int      `json:"seats"`
+	Features []string `json:"features"`
+}
+
+func TestObjectClaimValidators(t *testing.T) {
+	claim, validators := NewObjectClaim("test", func(userId string, tenantId string, userContext supertokens.UserContext) (*testSubscription, error) {
+		return &testSubscription{Tier: "pro", Seats: 5, Features: []string{"sso", "audit"}}, nil
+	}, nil)
+
+	payload, err := claim.Build("userId", "public", nil, nil)
+	assert.NoError(t, err)
+
+	for _, p := range []map[string]interface{}{payload, jsonRoundTrip(t, payload)} {
+		assert.True(t, validators.Matches(func(value testSubscription) bool {
+			return value.Tier == "pro" && value.Seats >= 5
+		}, nil, nil).Validate(p, nil).IsValid)
+		assert.False(t, validators.Matches(func(value testSubscription) bool {
+			return value.Seats > 5
+		}, nil, nil).Validate(p, nil).IsValid)
+
+		assert.True(t, validators.HasValueAtPath("tier", "pro", nil, nil).Validate(p, nil).IsValid)
+		assert.True(t, validators.HasValueAtPath("seats", 5, nil, nil).Validate(p, nil).IsValid)
+		assert.True(t, validators.HasValueAtPath("features.1", "audit", nil, nil).Validate(p, nil).IsValid)
+		assert.True(t, validators.HasValueAtPath("features", []string{"sso", "audit"}, nil, nil).Validate(p, nil).IsValid)
+
+		assert.False(t, validators.HasValueAtPath("tier", "free", nil, nil).Validate(p, nil).IsValid)
+		assert.False(t, validators.HasValueAtPath("features.2", "audit", nil, nil).Validate(p, nil).IsValid)
+		assert.False(t, validators.HasValueAtPath("tier.name", "pro", nil, nil).Validate(p, nil).IsValid)
+		assert.False(t, validators.HasValueAtPath("missing", nil, nil, nil).Validate(p, nil).IsValid)
+	}
+
+	result := validators.HasValueAtPath("tier", "free", nil, nil).Validate(payload, nil)
+	assert.Equal(t, map[string]interface{}{
+		"path":  "tier",
+		"value": "free",
+	}, result.Reason.(map[string]interface{})["expectedValueAtPath"])
+}
+
+func TestObjectClaimShouldRefetch(t *testing.T) {
+	claim, validators := NewObjectClaim("test", func(userId string, tenantId string, userContext supertokens.UserContext) (*map[string]interface{}, error) {
+		return nil, nil
+	}, nil)
+
+	payload, err := claim.Build("userId", "public", nil, nil)
+	assert.NoError(t, err)
+	assert.True(t, validators.HasValueAtPath("a", 1, nil, nil).ShouldRefetch(payload, nil))
+
+	payload = claim.AddToPayload_internal(payload, map[string]interface{}{"a": 1}, nil)
+	assert.False(t, validators.HasValueAtPath("a", 1, nil, nil).ShouldRefetch(payload, nil))
+	assert.True(t, validators.HasValueAtPath("a", 1, nil, nil).Validate(payload, nil).IsValid)
+}
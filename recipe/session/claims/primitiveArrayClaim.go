@@ -1,333 +1,87 @@
 package claims
 
 import (
-	"time"
-
 	"github.com/supertokens/supertokens-golang/supertokens"
 )
 
+// PrimitiveArrayClaim creates a claim whose value is an array stored as is in the access token
+// payload. Use NewArrayClaim to get typed values and validators.
 func PrimitiveArrayClaim(key string, fetchValue FetchValueFunc, defaultMaxAgeInSeconds *int64) (*TypeSessionClaim, PrimitiveArrayClaimValidators) {
-	// Claim functions are identical to primitive claim, only validators are different
-	sessionClaim, _ := PrimitiveClaim(key, fetchValue, defaultMaxAgeInSeconds)
+	typedClaim, typedValidators := NewArrayClaim(key, func(userId string, tenantId string, userContext supertokens.UserContext) (*[]interface{}, error) {
+		value, err := fetchValue(userId, tenantId, userContext)
+		if err != nil || value == nil {
+			return nil, err
+		}
+		arrayValue, err := ConvertClaimValue[[]interface{}](value)
+		if err != nil {
+			return nil, err
+		}
+		return arrayValue, nil
+	}, defaultMaxAgeInSeconds)
+	typedClaim.FetchValue = fetchValue
 
 	validators := PrimitiveArrayClaimValidators{
-		Includes: func(val interface{}, maxAgeInSeconds *int64, id *string) SessionClaimValidator {
-			if maxAgeInSeconds == nil {
-				maxAgeInSeconds = defaultMaxAgeInSeconds
-			}
-			claimId := sessionClaim.Key
-			if id != nil {
-				claimId = *id
-			}
-			return SessionClaimValidator{
-				ID:    claimId,
-				Claim: sessionClaim,
-				ShouldRefetch: func(payload map[string]interface{}, userContext supertokens.UserContext) bool {
-					claimVal, ok := sessionClaim.GetValueFromPayload(payload, userContext).([]interface{})
-					if !ok || claimVal == nil {
-						return true
-					}
-					if maxAgeInSeconds != nil {
-						return *sessionClaim.GetLastRefetchTime(payload, userContext) < time.Now().UnixNano()/1000000-*maxAgeInSeconds*1000
-					}
-					return false
-				},
-				Validate: func(payload map[string]interface{}, userContext supertokens.UserContext) ClaimValidationResult {
-					claimVal := sessionClaim.GetValueFromPayload(payload, userContext).([]interface{})
+		Includes:    typedValidators.Includes,
+		Excludes:    typedValidators.Excludes,
+		IncludesAll: typedValidators.IncludesAll,
+		IncludesAny: typedValidators.IncludesAny,
+		ExcludesAll: typedValidators.ExcludesAll,
+	}
 
-					if claimVal == nil {
-						return ClaimValidationResult{
-							IsValid: false,
-							Reason: map[string]interface{}{
-								"message":           "value does not exist",
-								"expectedToInclude": val,
-								"actualValue":       claimVal,
-							},
-						}
-					}
-					ageInSeconds := (time.Now().UnixNano()/1000000 - *sessionClaim.GetLastRefetchTime(payload, userContext)) / 1000
-					if maxAgeInSeconds != nil && ageInSeconds > *maxAgeInSeconds {
-						return ClaimValidationResult{
-							IsValid: false,
-							Reason: map[string]interface{}{
-								"message":         "expired",
-								"ageInSeconds":    ageInSeconds,
-								"maxAgeInSeconds": *maxAgeInSeconds,
-							},
-						}
-					}
-					if !includes(claimVal, val) {
-						return ClaimValidationResult{
-							IsValid: false,
-							Reason: map[string]interface{}{
-								"message":           "wrong value",
-								"expectedToInclude": val,
-								"actualValue":       claimVal,
-							},
-						}
-					}
-					return ClaimValidationResult{
-						IsValid: true,
-					}
-				},
-			}
-		},
-		Excludes: func(val interface{}, maxAgeInSeconds *int64, id *string) SessionClaimValidator {
-			if maxAgeInSeconds == nil {
-				maxAgeInSeconds = defaultMaxAgeInSeconds
-			}
-			claimId := sessionClaim.Key
-			if id != nil {
-				claimId = *id
-			}
-			return SessionClaimValidator{
-				ID:    claimId,
-				Claim: sessionClaim,
-				ShouldRefetch: func(payload map[string]interface{}, userContext supertokens.UserContext) bool {
-					val, ok := sessionClaim.GetValueFromPayload(payload, userContext).([]interface{})
-					if !ok || val == nil {
-						return true
-					}
-					if maxAgeInSeconds != nil {
-						return *sessionClaim.GetLastRefetchTime(payload, userContext) < time.Now().UnixNano()/1000000-*maxAgeInSeconds*1000
-					}
-					return false
-				},
-				Validate: func(payload map[string]interface{}, userContext supertokens.UserContext) ClaimValidationResult {
-					claimVal := sessionClaim.GetValueFromPayload(payload, userContext).([]interface{})
+	return typedClaim.TypeSessionClaim, validators
+}
 
-					if claimVal == nil {
-						return ClaimValidationResult{
-							IsValid: false,
-							Reason: map[string]interface{}{
-								"message":           "value does not exist",
-								"expectedToInclude": val,
-								"actualValue":       claimVal,
-							},
-						}
-					}
-					ageInSeconds := (time.Now().UnixNano()/1000000 - *sessionClaim.GetLastRefetchTime(payload, userContext)) / 1000
-					if maxAgeInSeconds != nil && ageInSeconds > *maxAgeInSeconds {
-						return ClaimValidationResult{
-							IsValid: false,
-							Reason: map[string]interface{}{
-								"message":         "expired",
-								"ageInSeconds":    ageInSeconds,
-								"maxAgeInSeconds": *maxAgeInSeconds,
-							},
-						}
-					}
-					if includes(claimVal, val) {
-						return ClaimValidationResult{
-							IsValid: false,
-							Reason: map[string]interface{}{
-								"message":           "wrong value",
-								"expectedToExclude": val,
-								"actualValue":       claimVal,
-							},
-						}
-					}
-					return ClaimValidationResult{
-						IsValid: true,
-					}
-				},
-			}
-		},
-		IncludesAll: func(vals []interface{}, maxAgeInSeconds *int64, id *string) SessionClaimValidator {
-			if maxAgeInSeconds == nil {
-				maxAgeInSeconds = defaultMaxAgeInSeconds
-			}
-			claimId := sessionClaim.Key
-			if id != nil {
-				claimId = *id
-			}
-			return SessionClaimValidator{
-				ID:    claimId,
-				Claim: sessionClaim,
-				ShouldRefetch: func(payload map[string]interface{}, userContext supertokens.UserContext) bool {
-					val, ok := sessionClaim.GetValueFromPayload(payload, userContext).([]interface{})
-					if !ok || val == nil {
-						return true
-					}
-					if maxAgeInSeconds != nil {
-						return *sessionClaim.GetLastRefetchTime(payload, userContext) < time.Now().UnixNano()/1000000-*maxAgeInSeconds*1000
-					}
-					return false
-				},
-				Validate: func(payload map[string]interface{}, userContext supertokens.UserContext) ClaimValidationResult {
-					claimVal := sessionClaim.GetValueFromPayload(payload, userContext).([]interface{})
+type PrimitiveArrayClaimValidators struct {
+	Includes    func(val interface{}, maxAgeInSeconds *int64, id *string) SessionClaimValidator
+	Excludes    func(val interface{}, maxAgeInSeconds *int64, id *string) SessionClaimValidator
+	IncludesAll func(vals []interface{}, maxAgeInSeconds *int64, id *string) SessionClaimValidator
+	IncludesAny func(vals []interface{}, maxAgeInSeconds *int64, id *string) SessionClaimValidator
+	ExcludesAll func(vals []interface{}, maxAgeInSeconds *int64, id *string) SessionClaimValidator
+}
 
-					if claimVal == nil {
-						return ClaimValidationResult{
-							IsValid: false,
-							Reason: map[string]interface{}{
-								"message":           "value does not exist",
-								"expectedToInclude": vals,
-								"actualValue":       claimVal,
-							},
-						}
-					}
-					ageInSeconds := (time.Now().UnixNano()/1000000 - *sessionClaim.GetLastRefetchTime(payload, userContext)) / 1000
-					if maxAgeInSeconds != nil && ageInSeconds > *maxAgeInSeconds {
-						return ClaimValidationResult{
-							IsValid: false,
-							Reason: map[string]interface{}{
-								"message":         "expired",
-								"ageInSeconds":    ageInSeconds,
-								"maxAgeInSeconds": *maxAgeInSeconds,
-							},
-						}
-					}
+// NewArrayClaim creates a claim whose value is a []T. Like NewPrimitiveClaim, the value is
+// converted back to []T when read from the access token payload.
+func NewArrayClaim[T any](key string, fetchValue TypedFetchValueFunc[[]T], defaultMaxAgeInSeconds *int64) (*TypedSessionClaim[[]T], TypedArrayClaimValidators[T]) {
+	// Claim functions are identical to primitive claim, only validators are different
+	claim := newTypedClaim(key, fetchValue)
 
-					if !includesAll(claimVal, vals) {
-						return ClaimValidationResult{
-							IsValid: false,
-							Reason: map[string]interface{}{
-								"message":           "wrong value",
-								"expectedToInclude": vals,
-								"actualValue":       claimVal,
-							},
-						}
-					}
-					return ClaimValidationResult{
-						IsValid: true,
-					}
-				},
-			}
+	// Excludes and IncludesAny use expectedToInclude in the reason if the value does not exist, as
+	// the validators of PrimitiveArrayClaim always did
+	validators := TypedArrayClaimValidators[T]{
+		Includes: func(val T, maxAgeInSeconds *int64, id *string) SessionClaimValidator {
+			return newTypedClaimValidator(claim, defaultMaxAgeInSeconds, maxAgeInSeconds, id, "expectedToInclude", val, func(claimVal []T) bool {
+				return includes(claimVal, val)
+			})
 		},
-		IncludesAny: func(vals []interface{}, maxAgeInSeconds *int64, id *string) SessionClaimValidator {
-			if maxAgeInSeconds == nil {
-				maxAgeInSeconds = defaultMaxAgeInSeconds
-			}
-			claimId := sessionClaim.Key
-			if id != nil {
-				claimId = *id
-			}
-			return SessionClaimValidator{
-				ID:    claimId,
-				Claim: sessionClaim,
-				ShouldRefetch: func(payload map[string]interface{}, userContext supertokens.UserContext) bool {
-					val, ok := sessionClaim.GetValueFromPayload(payload, userContext).([]interface{})
-					if !ok || val == nil {
-						return true
-					}
-					if maxAgeInSeconds != nil {
-						return *sessionClaim.GetLastRefetchTime(payload, userContext) < time.Now().UnixNano()/1000000-*maxAgeInSeconds*1000
-					}
-					return false
-				},
-				Validate: func(payload map[string]interface{}, userContext supertokens.UserContext) ClaimValidationResult {
-					claimVal := sessionClaim.GetValueFromPayload(payload, userContext).([]interface{})
-
-					if claimVal == nil {
-						return ClaimValidationResult{
-							IsValid: false,
-							Reason: map[string]interface{}{
-								"message":           "value does not exist",
-								"expectedToInclude": vals,
-								"actualValue":       claimVal,
-							},
-						}
-					}
-					ageInSeconds := (time.Now().UnixNano()/1000000 - *sessionClaim.GetLastRefetchTime(payload, userContext)) / 1000
-					if maxAgeInSeconds != nil && ageInSeconds > *maxAgeInSeconds {
-						return ClaimValidationResult{
-							IsValid: false,
-							Reason: map[string]interface{}{
-								"message":         "expired",
-								"ageInSeconds":    ageInSeconds,
-								"maxAgeInSeconds": *maxAgeInSeconds,
-							},
-						}
-					}
-
-					if excludesAll(claimVal, vals) {
-						return ClaimValidationResult{
-							IsValid: false,
-							Reason: map[string]interface{}{
-								"message":                       "wrong value",
-								"expectedToIncludeAtLeastOneOf": vals,
-								"actualValue":                   claimVal,
-							},
-						}
-					}
-					return ClaimValidationResult{
-						IsValid: true,
-					}
-				},
-			}
+		Excludes: func(val T, maxAgeInSeconds *int64, id *string) SessionClaimValidator {
+			return newTypedClaimValidatorWithMissingValueKey(claim, defaultMaxAgeInSeconds, maxAgeInSeconds, id, "expectedToInclude", "expectedToExclude", val, func(claimVal []T) bool {
+				return !includes(claimVal, val)
+			})
 		},
-		ExcludesAll: func(vals []interface{}, maxAgeInSeconds *int64, id *string) SessionClaimValidator {
-			if maxAgeInSeconds == nil {
-				maxAgeInSeconds = defaultMaxAgeInSeconds
-			}
-			claimId := sessionClaim.Key
-			if id != nil {
-				claimId = *id
-			}
-			return SessionClaimValidator{
-				ID:    claimId,
-				Claim: sessionClaim,
-				ShouldRefetch: func(payload map[string]interface{}, userContext supertokens.UserContext) bool {
-					val, ok := sessionClaim.GetValueFromPayload(payload, userContext).([]interface{})
-					if !ok || val == nil {
-						return true
-					}
-					if maxAgeInSeconds != nil {
-						return *sessionClaim.GetLastRefetchTime(payload, userContext) < time.Now().UnixNano()/1000000-*maxAgeInSeconds*1000
-					}
-					return false
-				},
-				Validate: func(payload map[string]interface{}, userContext supertokens.UserContext) ClaimValidationResult {
-					claimVal := sessionClaim.GetValueFromPayload(payload, userContext).([]interface{})
-
-					if claimVal == nil {
-						return ClaimValidationResult{
-							IsValid: false,
-							Reason: map[string]interface{}{
-								"message":              "value does not exist",
-								"expectedToNotInclude": vals,
-								"actualValue":          claimVal,
-							},
-						}
-					}
-					ageInSeconds := (time.Now().UnixNano()/1000000 - *sessionClaim.GetLastRefetchTime(payload, userContext)) / 1000
-					if maxAgeInSeconds != nil && ageInSeconds > *maxAgeInSeconds {
-						return ClaimValidationResult{
-							IsValid: false,
-							Reason: map[string]interface{}{
-								"message":         "expired",
-								"ageInSeconds":    ageInSeconds,
-								"maxAgeInSeconds": *maxAgeInSeconds,
-							},
-						}
-					}
-
-					if !excludesAll(claimVal, vals) {
-						return ClaimValidationResult{
-							IsValid: false,
-							Reason: map[string]interface{}{
-								"message":              "wrong value",
-								"expectedToNotInclude": vals,
-								"actualValue":          claimVal,
-							},
-						}
-					}
-					return ClaimValidationResult{
-						IsValid: true,
-					}
-				},
-			}
+		IncludesAll: func(vals []T, maxAgeInSeconds *int64, id *string) SessionClaimValidator {
+			return newTypedClaimValidator(claim, defaultMaxAgeInSeconds, maxAgeInSeconds, id, "expectedToInclude", vals, func(claimVal []T) bool {
+				return includesAll(claimVal, vals)
+			})
+		},
+		IncludesAny: func(vals []T, maxAgeInSeconds *int64, id *string) SessionClaimValidator {
+			return newTypedClaimValidatorWithMissingValueKey(claim, defaultMaxAgeInSeconds, maxAgeInSeconds, id, "expectedToInclude", "expectedToIncludeAtLeastOneOf", vals, func(claimVal []T) bool {
+				return !excludesAll(claimVal, vals)
+			})
+		},
+		ExcludesAll: func(vals []T, maxAgeInSeconds *int64, id *string) SessionClaimValidator {
+			return newTypedClaimValidator(claim, defaultMaxAgeInSeconds, maxAgeInSeconds, id, "expectedToNotInclude", vals, func(claimVal []T) bool {
+				return excludesAll(claimVal, vals)
+			})
 		},
 	}
 
-	return sessionClaim, validators
+	return claim, validators
 }
 
-type PrimitiveArrayClaimValidators struct {
-	Includes    func(val interface{}, maxAgeInSeconds *int64, id *string) SessionClaimValidator
-	Excludes    func(val interface{}, maxAgeInSeconds *int64, id *string) SessionClaimValidator
-	IncludesAll func(vals []interface{}, maxAgeInSeconds *int64, id *string) SessionClaimValidator
-	IncludesAny func(vals []interface{}, maxAgeInSeconds *int64, id *string) SessionClaimValidator
-	ExcludesAll func(vals []interface{}, maxAgeInSeconds *int64, id *string) SessionClaimValidator
+type TypedArrayClaimValidators[T any] struct {
+	Includes    func(val T, maxAgeInSeconds *int64, id *string) SessionClaimValidator
+	Excludes    func(val T, maxAgeInSeconds *int64, id *string) SessionClaimValidator
+	IncludesAll func(vals []T, maxAgeInSeconds *int64, id *string) SessionClaimValidator
+	IncludesAny func(vals []T, maxAgeInSeconds *int64, id *string) SessionClaimValidator
+	ExcludesAll func(vals []T, maxAgeInSeconds *int64, id *string) SessionClaimValidator
 }
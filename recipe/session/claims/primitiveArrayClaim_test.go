@@ -50,3 +50,30 @@ func TestPrimitiveArrayClaimValidators(t *testing.T) {
 	assert.False(t, validators.ExcludesAll([]interface{}{101, true}, nil, nil).Validate(payload, nil).IsValid)
 	assert.False(t, validators.ExcludesAll([]interface{}{false, "world"}, nil, nil).Validate(payload, nil).IsValid)
 }
+
+func TestPrimitiveArrayClaimValidatorsWithoutValue(t *testing.T) {
+	_, validators := PrimitiveArrayClaim(
+		"test",
+		func(userId string, tenantId string, userContext supertokens.UserContext) (interface{}, error) {
+			return nil, nil
+		},
+		nil,
+	)
+	payload := map[string]interface{}{}
+
+	assertMissingValueReason := func(validator SessionClaimValidator, expectedKey string, expected interface{}) {
+		result := validator.Validate(payload, nil)
+		assert.False(t, result.IsValid)
+		assert.Equal(t, map[string]interface{}{
+			"message":     "value does not exist",
+			expectedKey:   expected,
+			"actualValue": nil,
+		}, result.Reason)
+	}
+
+	assertMissingValueReason(validators.Includes(100, nil, nil), "expectedToInclude", 100)
+	assertMissingValueReason(validators.Excludes(100, nil, nil), "expectedToInclude", 100)
+	assertMissingValueReason(validators.IncludesAll([]interface{}{100}, nil, nil), "expectedToInclude", []interface{}{100})
+	assertMissingValueReason(validators.IncludesAny([]interface{}{100}, nil, nil), "expectedToInclude", []interface{}{100})
+	assertMissingValueReason(validators.ExcludesAll([]interface{}{100}, nil, nil), "expectedToNotInclude", []interface{}{100})
+}
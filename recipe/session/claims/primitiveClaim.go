@@ -6,8 +6,52 @@ import (
 	"github.com/supertokens/supertokens-golang/supertokens"
 )
 
+// PrimitiveClaim creates a claim whose value is stored as is in the access token payload.
+// Use NewPrimitiveClaim to get typed values and validators.
 func PrimitiveClaim(key string, fetchValue FetchValueFunc, defaultMaxAgeInSeconds *int64) (*TypeSessionClaim, PrimitiveClaimValidators) {
-	sessionClaim := SessionClaim(key, fetchValue)
+	typedClaim, typedValidators := NewPrimitiveClaim(key, func(userId string, tenantId string, userContext supertokens.UserContext) (*interface{}, error) {
+		value, err := fetchValue(userId, tenantId, userContext)
+		if err != nil || value == nil {
+			return nil, err
+		}
+		return &value, nil
+	}, defaultMaxAgeInSeconds)
+	typedClaim.FetchValue = fetchValue
+
+	validators := PrimitiveClaimValidators{
+		HasValue: typedValidators.HasValue,
+	}
+
+	return typedClaim.TypeSessionClaim, validators
+}
+
+type PrimitiveClaimValidators struct {
+	HasValue func(val interface{}, maxAgeInSeconds *int64, id *string) SessionClaimValidator
+}
+
+// NewPrimitiveClaim creates a claim with a value of type T. The value is stored in the
+// access token payload as JSON and converted back to T when read, so T can be any type
+// that can be (un)marshalled using encoding/json.
+func NewPrimitiveClaim[T any](key string, fetchValue TypedFetchValueFunc[T], defaultMaxAgeInSeconds *int64) (*TypedSessionClaim[T], TypedPrimitiveClaimValidators[T]) {
+	claim := newTypedClaim(key, fetchValue)
+
+	validators := TypedPrimitiveClaimValidators[T]{
+		HasValue: func(val T, maxAgeInSeconds *int64, id *string) SessionClaimValidator {
+			return newTypedClaimValidator(claim, defaultMaxAgeInSeconds, maxAgeInSeconds, id, "expectedValue", val, func(claimVal T) bool {
+				return isEqual(claimVal, val)
+			})
+		},
+	}
+
+	return claim, validators
+}
+
+type TypedPrimitiveClaimValidators[T any] struct {
+	HasValue func(val T, maxAgeInSeconds *int64, id *string) SessionClaimValidator
+}
+
+func newTypedClaim[T any](key string, fetchValue TypedFetchValueFunc[T]) *TypedSessionClaim[T] {
+	sessionClaim := SessionClaim(key, typedFetchValueToUntyped(fetchValue))
 
 	sessionClaim.AddToPayload_internal = func(payload map[string]interface{}, value interface{}, userContext supertokens.UserContext) map[string]interface{} {
 		payload[sessionClaim.Key] = map[string]interface{}{
@@ -48,70 +92,79 @@ func PrimitiveClaim(key string, fetchValue FetchValueFunc, defaultMaxAgeInSecond
 		return nil
 	}
 
-	validators := PrimitiveClaimValidators{
-		HasValue: func(val interface{}, maxAgeInSeconds *int64, id *string) SessionClaimValidator {
-			if maxAgeInSeconds == nil {
-				maxAgeInSeconds = defaultMaxAgeInSeconds
+	return &TypedSessionClaim[T]{TypeSessionClaim: sessionClaim}
+}
+
+// newTypedClaimValidator creates a validator that checks that the claim value exists, is not
+// older than maxAgeInSeconds and passes isValid. expectedKey and expected are added to the
+// reason if the validation fails, unless expectedKey is empty.
+func newTypedClaimValidator[T any](claim *TypedSessionClaim[T], defaultMaxAgeInSeconds *int64, maxAgeInSeconds *int64, id *string, expectedKey string, expected interface{}, isValid func(claimVal T) bool) SessionClaimValidator {
+	return newTypedClaimValidatorWithMissingValueKey(claim, defaultMaxAgeInSeconds, maxAgeInSeconds, id, expectedKey, expectedKey, expected, isValid)
+}
+
+// newTypedClaimValidatorWithMissingValueKey is the same as newTypedClaimValidator, but uses
+// missingValueKey instead of expectedKey in the reason if the value does not exist
+func newTypedClaimValidatorWithMissingValueKey[T any](claim *TypedSessionClaim[T], defaultMaxAgeInSeconds *int64, maxAgeInSeconds *int64, id *string, missingValueKey string, expectedKey string, expected interface{}, isValid func(claimVal T) bool) SessionClaimValidator {
+	if maxAgeInSeconds == nil {
+		maxAgeInSeconds = defaultMaxAgeInSeconds
+	}
+	validatorId := claim.Key
+	if id != nil {
+		validatorId = *id
+	}
+	return SessionClaimValidator{
+		ID:    validatorId,
+		Claim: claim.TypeSessionClaim,
+		ShouldRefetch: func(payload map[string]interface{}, userContext supertokens.UserContext) bool {
+			claimVal := claim.GetTypedValueFromPayload(payload, userContext)
+			if claimVal == nil {
+				return true
 			}
-			validatorId := sessionClaim.Key
-			if id != nil {
-				validatorId = *id
+			return maxAgeInSeconds != nil && *claim.GetLastRefetchTime(payload, userContext) < time.Now().UnixNano()/1000000-*maxAgeInSeconds*1000
+		},
+		Validate: func(payload map[string]interface{}, userContext supertokens.UserContext) ClaimValidationResult {
+			claimVal := claim.GetTypedValueFromPayload(payload, userContext)
+
+			if claimVal == nil {
+				reason := map[string]interface{}{
+					"message":     "value does not exist",
+					"actualValue": nil,
+				}
+				if missingValueKey != "" {
+					reason[missingValueKey] = expected
+				}
+				return ClaimValidationResult{
+					IsValid: false,
+					Reason:  reason,
+				}
+			}
+			ageInSeconds := (time.Now().UnixNano()/1000000 - *claim.GetLastRefetchTime(payload, userContext)) / 1000
+			if maxAgeInSeconds != nil && ageInSeconds > *maxAgeInSeconds {
+				return ClaimValidationResult{
+					IsValid: false,
+					Reason: map[string]interface{}{
+						"message":         "expired",
+						"ageInSeconds":    ageInSeconds,
+						"maxAgeInSeconds": *maxAgeInSeconds,
+					},
+				}
 			}
-			return SessionClaimValidator{
-				ID:    validatorId,
-				Claim: sessionClaim,
-				ShouldRefetch: func(payload map[string]interface{}, userContext supertokens.UserContext) bool {
-					val := sessionClaim.GetValueFromPayload(payload, userContext)
-					if val == nil {
-						return true
-					}
-					return maxAgeInSeconds != nil && *sessionClaim.GetLastRefetchTime(payload, userContext) < time.Now().UnixNano()/1000000-*maxAgeInSeconds*1000
-				},
-				Validate: func(payload map[string]interface{}, userContext supertokens.UserContext) ClaimValidationResult {
-					claimVal := sessionClaim.GetValueFromPayload(payload, userContext)
-
-					if claimVal == nil {
-						return ClaimValidationResult{
-							IsValid: false,
-							Reason: map[string]interface{}{
-								"message":       "value does not exist",
-								"expectedValue": val,
-								"actualValue":   claimVal,
-							},
-						}
-					}
-					ageInSeconds := (time.Now().UnixNano()/1000000 - *sessionClaim.GetLastRefetchTime(payload, userContext)) / 1000
-					if maxAgeInSeconds != nil && ageInSeconds > *maxAgeInSeconds {
-						return ClaimValidationResult{
-							IsValid: false,
-							Reason: map[string]interface{}{
-								"message":         "expired",
-								"ageInSeconds":    ageInSeconds,
-								"maxAgeInSeconds": *maxAgeInSeconds,
-							},
-						}
-					}
-					if claimVal != val {
-						return ClaimValidationResult{
-							IsValid: false,
-							Reason: map[string]interface{}{
-								"message":       "wrong value",
-								"expectedValue": val,
-								"actualValue":   claimVal,
-							},
-						}
-					}
-					return ClaimValidationResult{
-						IsValid: true,
-					}
-				},
+			if !isValid(*claimVal) {
+				reason := map[string]interface{}{
+					"message":     "wrong value",
+					"actualValue": *claimVal,
+				}
+				if expectedKey != "" {
+					reason[expectedKey] = expected
+				}
+				return ClaimValidationResult{
+					IsValid: false,
+					Reason:  reason,
+				}
+			}
+			return ClaimValidationResult{
+				IsValid: true,
 			}
 		},
 	}
-
-	return sessionClaim, validators
-}
-
-type PrimitiveClaimValidators struct {
-	HasValue func(val interface{}, maxAgeInSeconds *int64, id *string) SessionClaimValidator
 }
@@ -0,0 +1,57 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package claims
+
+import (
+	"time"
+)
+
+// NewTimestampClaim creates a claim whose value is a point in time, for example when a trial expires
+func NewTimestampClaim(key string, fetchValue TypedFetchValueFunc[time.Time], defaultMaxAgeInSeconds *int64) (*TypedSessionClaim[time.Time], TimestampClaimValidators) {
+	claim, primitiveClaimValidators := NewPrimitiveClaim(key, fetchValue, defaultMaxAgeInSeconds)
+
+	validators := TimestampClaimValidators{
+		TypedPrimitiveClaimValidators: primitiveClaimValidators,
+
+		IsBefore: func(val time.Time, maxAgeInSeconds *int64, id *string) SessionClaimValidator {
+			return newTypedClaimValidator(claim, defaultMaxAgeInSeconds, maxAgeInSeconds, id, "expectedToBeBefore", val, func(claimVal time.Time) bool {
+				return claimVal.Before(val)
+			})
+		},
+
+		IsAfter: func(val time.Time, maxAgeInSeconds *int64, id *string) SessionClaimValidator {
+			return newTypedClaimValidator(claim, defaultMaxAgeInSeconds, maxAgeInSeconds, id, "expectedToBeAfter", val, func(claimVal time.Time) bool {
+				return claimVal.After(val)
+			})
+		},
+
+		NotExpired: func(maxAgeInSeconds *int64, id *string) SessionClaimValidator {
+			return newTypedClaimValidator(claim, defaultMaxAgeInSeconds, maxAgeInSeconds, id, "", nil, func(claimVal time.Time) bool {
+				return claimVal.After(time.Now())
+			})
+		},
+	}
+
+	return claim, validators
+}
+
+type TimestampClaimValidators struct {
+	TypedPrimitiveClaimValidators[time.Time]
+	IsBefore func(val time.Time, maxAgeInSeconds *int64, id *string) SessionClaimValidator
+	IsAfter  func(val time.Time, maxAgeInSeconds *int64, id *string) SessionClaimValidator
+	// NotExpired checks that the value of the claim is in the future at the time of validation
+	NotExpired func(maxAgeInSeconds *int64, id *string) SessionClaimValidator
+}
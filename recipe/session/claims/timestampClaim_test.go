@@ -0,0 +1,44 @@
+package claims
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func TestTimestampClaimValidators(t *testing.T) {
+	trialEnd := time.Now().Add(time.Hour)
+	claim, validators := NewTimestampClaim("test", func(userId string, tenantId string, userContext supertokens.UserContext) (*time.Time, error) {
+		return &trialEnd, nil
+	}, nil)
+
+	payload, err := claim.Build("userId", "public", nil, nil)
+	assert.NoError(t, err)
+
+	for _, p := range []map[string]interface{}{payload, jsonRoundTrip(t, payload)} {
+		assert.True(t, validators.IsBefore(trialEnd.Add(time.Minute), nil, nil).Validate(p, nil).IsValid)
+		assert.False(t, validators.IsBefore(trialEnd.Add(-time.Minute), nil, nil).Validate(p, nil).IsValid)
+
+		assert.True(t, validators.IsAfter(trialEnd.Add(-time.Minute), nil, nil).Validate(p, nil).IsValid)
+		assert.False(t, validators.IsAfter(trialEnd.Add(time.Minute), nil, nil).Validate(p, nil).IsValid)
+
+		assert.True(t, validators.NotExpired(nil, nil).Validate(p, nil).IsValid)
+	}
+}
+
+func TestTimestampClaimNotExpired(t *testing.T) {
+	claim, validators := NewTimestampClaim("test", func(userId string, tenantId string, userContext supertokens.UserContext) (*time.Time, error) {
+		return nil, nil
+	}, nil)
+
+	payload := claim.AddToPayload_internal(map[string]interface{}{}, time.Now().Add(-time.Minute), nil)
+	result := validators.NotExpired(nil, nil).Validate(payload, nil)
+	assert.False(t, result.IsValid)
+	assert.Equal(t, "wrong value", result.Reason.(map[string]interface{})["message"])
+
+	payload = map[string]interface{}{}
+	assert.True(t, validators.NotExpired(nil, nil).ShouldRefetch(payload, nil))
+	assert.False(t, validators.NotExpired(nil, nil).Validate(payload, nil).IsValid)
+}
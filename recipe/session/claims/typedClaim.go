@@ -0,0 +1,87 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package claims
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+// TypedFetchValueFunc returns the value of a typed claim for a user. Returning a nil
+// pointer means that the claim has no value and it will not be added to the payload.
+type TypedFetchValueFunc[T any] func(userId string, tenantId string, userContext supertokens.UserContext) (*T, error)
+
+// TypedSessionClaim is a session claim whose value is of type T. It embeds the untyped
+// claim, so it can be used anywhere a *TypeSessionClaim is expected by passing
+// claim.TypeSessionClaim.
+type TypedSessionClaim[T any] struct {
+	*TypeSessionClaim
+}
+
+// GetTypedValueFromPayload returns the value of the claim in the payload converted to T,
+// or nil if the claim is not in the payload or its value cannot be converted to T.
+func (claim *TypedSessionClaim[T]) GetTypedValueFromPayload(payload map[string]interface{}, userContext supertokens.UserContext) *T {
+	value, err := ConvertClaimValue[T](claim.GetValueFromPayload(payload, userContext))
+	if err != nil {
+		supertokens.LogDebugMessage("GetTypedValueFromPayload: could not convert value of claim " + claim.Key + ": " + err.Error())
+		return nil
+	}
+	return value
+}
+
+// ConvertClaimValue converts a claim value to T. Values read from an access token payload
+// have gone through JSON, so numbers are float64, arrays are []interface{} and objects are
+// map[string]interface{}. These are converted by re-encoding them as JSON and decoding
+// the result into T, which also handles time.Time and structs with json tags.
+func ConvertClaimValue[T any](value interface{}) (*T, error) {
+	if value == nil {
+		return nil, nil
+	}
+	if typedValue, ok := value.(T); ok {
+		return &typedValue, nil
+	}
+	jsonValue, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var result T
+	err = json.Unmarshal(jsonValue, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func typedFetchValueToUntyped[T any](fetchValue TypedFetchValueFunc[T]) FetchValueFunc {
+	return func(userId string, tenantId string, userContext supertokens.UserContext) (interface{}, error) {
+		value, err := fetchValue(userId, tenantId, userContext)
+		if err != nil || value == nil {
+			return nil, err
+		}
+		return *value, nil
+	}
+}
+
+// isEqual compares two claim values. Types like time.Time define an Equal method
+// that should be used instead of comparing their fields.
+func isEqual[T any](a T, b T) bool {
+	if equaler, ok := interface{}(a).(interface{ Equal(T) bool }); ok {
+		return equaler.Equal(b)
+	}
+	return reflect.DeepEqual(a, b)
+}
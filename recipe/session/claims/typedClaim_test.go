@@ -0,0 +1,177 @@
+package claims
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+type testClaimStruct struct {
+	Name  string `json:"name"`
+	Level int    `json:"level"`
+}
+
+// jsonRoundTrip simulates the payload being sent to the core and read back from an access token
+func jsonRoundTrip(t *testing.T, payload map[string]interface{}) map[string]interface{} {
+	jsonPayload, err := json.Marshal(payload)
+	assert.NoError(t, err)
+	result := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal(jsonPayload, &result))
+	return result
+}
+
+func TestTypedPrimitiveClaimWithNumbers(t *testing.T) {
+	claim, validators := NewPrimitiveClaim("test", func(userId string, tenantId string, userContext supertokens.UserContext) (*int64, error) {
+		val := int64(42)
+		return &val, nil
+	}, nil)
+
+	payload, err := claim.Build("userId", "public", nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(42), *claim.GetTypedValueFromPayload(payload, nil))
+	assert.True(t, validators.HasValue(42, nil, nil).Validate(payload, nil).IsValid)
+
+	payload = jsonRoundTrip(t, payload)
+	assert.Equal(t, float64(42), claim.GetValueFromPayload(payload, nil))
+	assert.Equal(t, int64(42), *claim.GetTypedValueFromPayload(payload, nil))
+	assert.True(t, validators.HasValue(42, nil, nil).Validate(payload, nil).IsValid)
+	assert.False(t, validators.HasValue(43, nil, nil).Validate(payload, nil).IsValid)
+	assert.False(t, validators.HasValue(42, nil, nil).ShouldRefetch(payload, nil))
+}
+
+func TestTypedPrimitiveClaimWithTime(t *testing.T) {
+	now := time.Now()
+	claim, validators := NewPrimitiveClaim("test", func(userId string, tenantId string, userContext supertokens.UserContext) (*time.Time, error) {
+		return &now, nil
+	}, nil)
+
+	payload, err := claim.Build("userId", "public", nil, nil)
+	assert.NoError(t, err)
+	payload = jsonRoundTrip(t, payload)
+
+	assert.True(t, now.Equal(*claim.GetTypedValueFromPayload(payload, nil)))
+	assert.True(t, validators.HasValue(now.UTC(), nil, nil).Validate(payload, nil).IsValid)
+	assert.False(t, validators.HasValue(now.Add(time.Second), nil, nil).Validate(payload, nil).IsValid)
+}
+
+func TestTypedPrimitiveClaimWithStruct(t *testing.T) {
+	claim, validators := NewPrimitiveClaim("test", func(userId string, tenantId string, userContext supertokens.UserContext) (*testClaimStruct, error) {
+		return &testClaimStruct{Name: "gold", Level: 3}, nil
+	}, nil)
+
+	payload, err := claim.Build("userId", "public", nil, nil)
+	assert.NoError(t, err)
+	payload = jsonRoundTrip(t, payload)
+
+	assert.Equal(t, testClaimStruct{Name: "gold", Level: 3}, *claim.GetTypedValueFromPayload(payload, nil))
+	assert.True(t, validators.HasValue(testClaimStruct{Name: "gold", Level: 3}, nil, nil).Validate(payload, nil).IsValid)
+	assert.False(t, validators.HasValue(testClaimStruct{Name: "gold", Level: 2}, nil, nil).Validate(payload, nil).IsValid)
+}
+
+func TestTypedPrimitiveClaimWithoutValue(t *testing.T) {
+	claim, validators := NewPrimitiveClaim("test", func(userId string, tenantId string, userContext supertokens.UserContext) (*string, error) {
+		return nil, nil
+	}, nil)
+
+	payload, err := claim.Build("userId", "public", nil, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, claim.GetTypedValueFromPayload(payload, nil))
+	assert.True(t, validators.HasValue("hello", nil, nil).ShouldRefetch(payload, nil))
+
+	result := validators.HasValue("hello", nil, nil).Validate(payload, nil)
+	assert.False(t, result.IsValid)
+	assert.Equal(t, "value does not exist", result.Reason.(map[string]interface{})["message"])
+
+	// Values of the wrong type are treated as missing
+	payload = claim.AddToPayload_internal(map[string]interface{}{}, 100, nil)
+	assert.Nil(t, claim.GetTypedValueFromPayload(payload, nil))
+}
+
+func TestTypedPrimitiveClaimMaxAge(t *testing.T) {
+	claim, validators := NewPrimitiveClaim("test", func(userId string, tenantId string, userContext supertokens.UserContext) (*string, error) {
+		val := "hello"
+		return &val, nil
+	}, nil)
+
+	payload, err := claim.Build("userId", "public", nil, nil)
+	assert.NoError(t, err)
+
+	maxAge := int64(1)
+	assert.True(t, validators.HasValue("hello", &maxAge, nil).Validate(payload, nil).IsValid)
+	time.Sleep(2 * time.Second)
+	assert.True(t, validators.HasValue("hello", &maxAge, nil).ShouldRefetch(payload, nil))
+	result := validators.HasValue("hello", &maxAge, nil).Validate(payload, nil)
+	assert.False(t, result.IsValid)
+	assert.Equal(t, "expired", result.Reason.(map[string]interface{})["message"])
+}
+
+func TestTypedArrayClaimValidators(t *testing.T) {
+	claim, validators := NewArrayClaim("test", func(userId string, tenantId string, userContext supertokens.UserContext) (*[]int, error) {
+		return &[]int{1, 2, 3}, nil
+	}, nil)
+
+	payload, err := claim.Build("userId", "public", nil, nil)
+	assert.NoError(t, err)
+
+	for _, p := range []map[string]interface{}{payload, jsonRoundTrip(t, payload)} {
+		assert.Equal(t, []int{1, 2, 3}, *claim.GetTypedValueFromPayload(p, nil))
+
+		assert.True(t, validators.Includes(2, nil, nil).Validate(p, nil).IsValid)
+		assert.False(t, validators.Includes(4, nil, nil).Validate(p, nil).IsValid)
+
+		assert.True(t, validators.Excludes(4, nil, nil).Validate(p, nil).IsValid)
+		assert.False(t, validators.Excludes(1, nil, nil).Validate(p, nil).IsValid)
+
+		assert.True(t, validators.IncludesAll([]int{1, 3}, nil, nil).Validate(p, nil).IsValid)
+		assert.False(t, validators.IncludesAll([]int{1, 4}, nil, nil).Validate(p, nil).IsValid)
+
+		assert.True(t, validators.IncludesAny([]int{4, 3}, nil, nil).Validate(p, nil).IsValid)
+		assert.False(t, validators.IncludesAny([]int{4, 5}, nil, nil).Validate(p, nil).IsValid)
+
+		assert.True(t, validators.ExcludesAll([]int{4, 5}, nil, nil).Validate(p, nil).IsValid)
+		assert.False(t, validators.ExcludesAll([]int{4, 1}, nil, nil).Validate(p, nil).IsValid)
+	}
+}
+
+func TestTypedBooleanClaim(t *testing.T) {
+	claim, validators := NewBooleanClaim("test", func(userId string, tenantId string, userContext supertokens.UserContext) (*bool, error) {
+		val := true
+		return &val, nil
+	}, nil)
+
+	payload, err := claim.Build("userId", "public", nil, nil)
+	assert.NoError(t, err)
+	payload = jsonRoundTrip(t, payload)
+
+	assert.True(t, *claim.GetTypedValueFromPayload(payload, nil))
+	assert.True(t, validators.IsTrue(nil, nil).Validate(payload, nil).IsValid)
+	assert.False(t, validators.IsFalse(nil, nil).Validate(payload, nil).IsValid)
+}
+
+func TestUntypedArrayClaimAcceptsTypedSlices(t *testing.T) {
+	claim, validators := PrimitiveArrayClaim("test", func(userId string, tenantId string, userContext supertokens.UserContext) (interface{}, error) {
+		return []string{"admin"}, nil
+	}, nil)
+
+	payload, err := claim.Build("userId", "public", nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"admin"}, claim.GetValueFromPayload(payload, nil))
+	assert.True(t, validators.Includes("admin", nil, nil).Validate(payload, nil).IsValid)
+	assert.False(t, validators.Includes("user", nil, nil).Validate(payload, nil).IsValid)
+}
+
+func TestConvertClaimValue(t *testing.T) {
+	value, err := ConvertClaimValue[map[string]int](map[string]interface{}{"a": float64(1)})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"a": 1}, *value)
+
+	value, err = ConvertClaimValue[map[string]int](nil)
+	assert.NoError(t, err)
+	assert.Nil(t, value)
+
+	_, err = ConvertClaimValue[map[string]int]("not a map")
+	assert.Error(t, err)
+}
@@ -1,34 +1,26 @@
 package claims
 
-func includes(s []interface{}, e interface{}) bool {
+func includes[T any](s []T, e T) bool {
 	for _, a := range s {
-		if a == e {
+		if isEqual(a, e) {
 			return true
 		}
 	}
 	return false
 }
 
-func includesAll(s []interface{}, e []interface{}) bool {
-	valsMap := map[interface{}]bool{}
-	for _, v := range s {
-		valsMap[v] = true
-	}
+func includesAll[T any](s []T, e []T) bool {
 	for _, v := range e {
-		if !valsMap[v] {
+		if !includes(s, v) {
 			return false
 		}
 	}
 	return true
 }
 
-func excludesAll(s []interface{}, e []interface{}) bool {
-	valsMap := map[interface{}]bool{}
-	for _, v := range s {
-		valsMap[v] = true
-	}
+func excludesAll[T any](s []T, e []T) bool {
 	for _, v := range e {
-		if valsMap[v] {
+		if includes(s, v) {
 			return false
 		}
 	}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/supertokens/supertokens-golang/recipe/session/claims"
 	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
 	"github.com/supertokens/supertokens-golang/supertokens"
 	"github.com/supertokens/supertokens-golang/test/unittesting"
@@ -163,3 +164,74 @@ func TestGetClaimValueForNonExistantSessionhandle(t *testing.T) {
 	assert.Nil(t, getRes.OK)
 	assert.NotNil(t, getRes.SessionDoesNotExistError)
 }
+
+func TestGetTypedClaimValueReturnsRightValue(t *testing.T) {
+	type membership struct {
+		Plan  string `json:"plan"`
+		Seats int    `json:"seats"`
+	}
+	membershipClaim, _ := claims.NewPrimitiveClaim("st-membership", func(userId string, tenantId string, userContext supertokens.UserContext) (*membership, error) {
+		return &membership{Plan: "pro", Seats: 5}, nil
+	}, nil)
+
+	BeforeEach()
+	connectionURI := unittesting.StartUpST("localhost", "8080")
+	defer AfterEach()
+	configValue := supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: connectionURI,
+		},
+		AppInfo: supertokens.AppInfo{
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+			APIDomain:     "api.supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{
+			Init(&sessmodels.TypeInput{
+				Override: &sessmodels.OverrideStruct{
+					Functions: func(originalImplementation sessmodels.RecipeInterface) sessmodels.RecipeInterface {
+						oCreateNewSession := *originalImplementation.CreateNewSession
+						nCreateNewSession := func(userID string, accessTokenPayload map[string]interface{}, sessionDataInDatabase map[string]interface{}, disableAntiCsrf *bool, tenantId string, userContext supertokens.UserContext) (sessmodels.SessionContainer, error) {
+							accessTokenPayload, err := membershipClaim.Build(userID, "public", accessTokenPayload, userContext)
+							if err != nil {
+								return nil, err
+							}
+							return oCreateNewSession(userID, accessTokenPayload, sessionDataInDatabase, disableAntiCsrf, tenantId, userContext)
+						}
+						*originalImplementation.CreateNewSession = nCreateNewSession
+						return originalImplementation
+					},
+				},
+			}),
+		},
+	}
+	err := supertokens.Init(configValue)
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	sessionContainer, err := CreateNewSessionWithoutRequestResponse("public", "userId", map[string]interface{}{}, map[string]interface{}{}, nil)
+	assert.NoError(t, err)
+
+	value, err := GetTypedClaimValueFromSession(sessionContainer, membershipClaim)
+	assert.NoError(t, err)
+	assert.Equal(t, membership{Plan: "pro", Seats: 5}, *value)
+
+	getRes, err := GetTypedClaimValue(sessionContainer.GetHandle(), membershipClaim)
+	assert.NoError(t, err)
+	assert.NotNil(t, getRes.OK)
+	assert.Equal(t, membership{Plan: "pro", Seats: 5}, *getRes.OK.Value)
+
+	ok, err := SetTypedClaimValue(sessionContainer.GetHandle(), membershipClaim, membership{Plan: "team", Seats: 20})
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	getRes, err = GetTypedClaimValue(sessionContainer.GetHandle(), membershipClaim)
+	assert.NoError(t, err)
+	assert.Equal(t, membership{Plan: "team", Seats: 20}, *getRes.OK.Value)
+
+	getRes, err = GetTypedClaimValue("invalidSessionHandle", membershipClaim)
+	assert.NoError(t, err)
+	assert.Nil(t, getRes.OK)
+	assert.NotNil(t, getRes.SessionDoesNotExistError)
+}
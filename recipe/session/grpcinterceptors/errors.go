@@ -0,0 +1,99 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package grpcinterceptors
+
+import (
+	"encoding/json"
+	defaultErrors "errors"
+	"strconv"
+
+	"github.com/supertokens/supertokens-golang/recipe/session/errors"
+	"github.com/supertokens/supertokens-golang/supertokens"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrorDomain is set as the domain of the ErrorInfo detail attached to the
+// status returned for session errors. The reason is one of the error strings
+// in the session errors package (UNAUTHORISED, TRY_REFRESH_TOKEN, ...).
+const ErrorDomain = "supertokens.com"
+
+const (
+	clearTokensMetadataKey           = "clearTokens"
+	claimValidationErrorsMetadataKey = "claimValidationErrors"
+)
+
+// ToStatusError converts session errors into gRPC status errors:
+//   - TRY_REFRESH_TOKEN and UNAUTHORISED map to codes.Unauthenticated
+//   - INVALID_CLAIMS maps to codes.PermissionDenied, with the validation errors in the details
+//   - TOKEN_THEFT_DETECTED maps to codes.Unauthenticated
+//
+// Errors that already carry a gRPC status are returned as is and all other
+// errors are mapped to codes.Internal.
+func ToStatusError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	tryRefreshTokenErr := errors.TryRefreshTokenError{}
+	unauthorisedErr := errors.UnauthorizedError{}
+	tokenTheftErr := errors.TokenTheftDetectedError{}
+	invalidClaimErr := errors.InvalidClaimError{}
+
+	if defaultErrors.As(err, &tryRefreshTokenErr) {
+		supertokens.LogDebugMessage("grpcinterceptors: returning TRY_REFRESH_TOKEN")
+		return newStatusError(codes.Unauthenticated, tryRefreshTokenErr.Msg, errors.TryRefreshTokenErrorStr, nil)
+	} else if defaultErrors.As(err, &unauthorisedErr) {
+		supertokens.LogDebugMessage("grpcinterceptors: returning UNAUTHORISED")
+		clearTokens := unauthorisedErr.ClearTokens == nil || *unauthorisedErr.ClearTokens
+		return newStatusError(codes.Unauthenticated, unauthorisedErr.Msg, errors.UnauthorizedErrorStr, map[string]string{
+			clearTokensMetadataKey: strconv.FormatBool(clearTokens),
+		})
+	} else if defaultErrors.As(err, &tokenTheftErr) {
+		supertokens.LogDebugMessage("grpcinterceptors: returning TOKEN_THEFT_DETECTED")
+		return newStatusError(codes.Unauthenticated, tokenTheftErr.Msg, errors.TokenTheftDetectedErrorStr, map[string]string{
+			clearTokensMetadataKey: "true",
+		})
+	} else if defaultErrors.As(err, &invalidClaimErr) {
+		supertokens.LogDebugMessage("grpcinterceptors: returning INVALID_CLAIMS")
+		claimValidationErrors, jsonErr := json.Marshal(invalidClaimErr.InvalidClaims)
+		if jsonErr != nil {
+			return status.Error(codes.Internal, jsonErr.Error())
+		}
+		return newStatusError(codes.PermissionDenied, "invalid claim", errors.InvalidClaimsErrorStr, map[string]string{
+			claimValidationErrorsMetadataKey: string(claimValidationErrors),
+		})
+	}
+
+	return status.Error(codes.Internal, err.Error())
+}
+
+func newStatusError(code codes.Code, message string, reason string, metadata map[string]string) error {
+	st := status.New(code, message)
+	stWithDetails, err := st.WithDetails(&errdetails.ErrorInfo{
+		Reason:   reason,
+		Domain:   ErrorDomain,
+		Metadata: metadata,
+	})
+	if err != nil {
+		return st.Err()
+	}
+	return stWithDetails.Err()
+}
@@ -0,0 +1,176 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package grpcinterceptors
+
+import (
+	"context"
+	"strings"
+
+	"github.com/supertokens/supertokens-golang/recipe/session"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	authorizationMetadataKey = "authorization"
+	antiCsrfMetadataKey      = "anti-csrf"
+	accessTokenMetadataKey   = "st-access-token"
+	frontTokenMetadataKey    = "front-token"
+)
+
+type TypeInput struct {
+	VerifySessionOptions *sessmodels.VerifySessionOptions
+	// ShouldVerifySession can be used to skip session verification for some methods
+	// (for example health checks). If nil, every method is verified.
+	ShouldVerifySession func(fullMethod string) bool
+}
+
+// UnaryServerInterceptor verifies the session for every unary call, the same way
+// session.VerifySession does for HTTP handlers. The access token is read from the
+// "authorization" metadata ("Bearer <token>") and the verified session can be
+// fetched in the handler using session.GetSessionFromRequestContext.
+func UnaryServerInterceptor(config *TypeInput) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := verifySession(ctx, config, info.FullMethod, grpc.SetHeader)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor is the streaming equivalent of UnaryServerInterceptor.
+// The session is verified once, when the stream is opened.
+func StreamServerInterceptor(config *TypeInput) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		setHeader := func(_ context.Context, md metadata.MD) error {
+			return ss.SetHeader(md)
+		}
+		ctx, err := verifySession(ss.Context(), config, info.FullMethod, setHeader)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &serverStreamWithContext{ServerStream: ss, ctx: ctx})
+	}
+}
+
+type serverStreamWithContext struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStreamWithContext) Context() context.Context {
+	return s.ctx
+}
+
+func verifySession(ctx context.Context, config *TypeInput, fullMethod string, setHeader func(context.Context, metadata.MD) error) (context.Context, error) {
+	if config != nil && config.ShouldVerifySession != nil && !config.ShouldVerifySession(fullMethod) {
+		supertokens.LogDebugMessage("grpcinterceptors: skipping session verification for " + fullMethod)
+		return ctx, nil
+	}
+
+	instance, err := session.GetRecipeInstanceOrThrowError()
+	if err != nil {
+		return ctx, ToStatusError(err)
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	accessToken := getAccessTokenFromMetadata(md)
+	antiCsrfToken := getMetadataValue(md, antiCsrfMetadataKey)
+
+	options := normaliseVerifySessionOptions(config)
+	userContext := supertokens.MakeUserContextFromContext(ctx)
+
+	sessionContainer, err := (*instance.RecipeImpl.GetSession)(accessToken, antiCsrfToken, &options, userContext)
+	if err != nil {
+		return ctx, ToStatusError(err)
+	}
+
+	if sessionContainer == nil {
+		return ctx, nil
+	}
+
+	claimValidators, err := session.GetRequiredClaimValidators(sessionContainer, options.OverrideGlobalClaimValidators, userContext)
+	if err != nil {
+		return ctx, ToStatusError(err)
+	}
+
+	err = session.RefetchStaleClaims(sessionContainer, claimValidators, userContext)
+	if err != nil {
+		return ctx, ToStatusError(err)
+	}
+
+	err = sessionContainer.AssertClaimsWithContext(claimValidators, userContext)
+	if err != nil {
+		return ctx, ToStatusError(err)
+	}
+
+	// The access token may have been regenerated by the core or while updating claims,
+	// so we send the new one back to the client, similar to what happens with headers
+	// in HTTP based auth
+	tokens := sessionContainer.GetAllSessionTokensDangerously()
+	if tokens.AccessAndFrontendTokenUpdated {
+		err = setHeader(ctx, metadata.Pairs(
+			accessTokenMetadataKey, tokens.AccessToken,
+			frontTokenMetadataKey, tokens.FrontToken,
+		))
+		if err != nil {
+			return ctx, err
+		}
+	}
+
+	return context.WithValue(ctx, sessmodels.SessionContext, sessionContainer), nil //nolint:staticcheck // using built-in type as key is a public API, changing would be breaking
+}
+
+func normaliseVerifySessionOptions(config *TypeInput) sessmodels.VerifySessionOptions {
+	True := true
+	False := false
+	options := sessmodels.VerifySessionOptions{}
+	if config != nil && config.VerifySessionOptions != nil {
+		options = *config.VerifySessionOptions
+	}
+	if options.SessionRequired == nil {
+		options.SessionRequired = &True
+	}
+	// Tokens sent via metadata behave like the header transfer method, which does not
+	// need anti-csrf protection unless explicitly asked for
+	if options.AntiCsrfCheck == nil {
+		options.AntiCsrfCheck = &False
+	}
+	return options
+}
+
+func getAccessTokenFromMetadata(md metadata.MD) *string {
+	value := getMetadataValue(md, authorizationMetadataKey)
+	if value == nil || !strings.HasPrefix(*value, "Bearer ") {
+		return nil
+	}
+	token := strings.TrimSpace(strings.TrimPrefix(*value, "Bearer "))
+	if token == "" {
+		return nil
+	}
+	return &token
+}
+
+func getMetadataValue(md metadata.MD, key string) *string {
+	values := md.Get(key)
+	if len(values) == 0 || values[0] == "" {
+		return nil
+	}
+	return &values[0]
+}
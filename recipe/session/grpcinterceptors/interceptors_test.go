@@ -0,0 +1,278 @@
+package grpcinterceptors
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/session"
+	"github.com/supertokens/supertokens-golang/recipe/session/claims"
+	"github.com/supertokens/supertokens-golang/recipe/session/errors"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+	"github.com/supertokens/supertokens-golang/test/unittesting"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestUnaryInterceptorReturnsUnauthorisedWithoutAccessToken(t *testing.T) {
+	BeforeEach()
+	defer AfterEach()
+	initForTest(t, "http://localhost:8080", nil)
+
+	client, sessions, stop := startTestServer(t, nil)
+	defer stop()
+
+	_, err := client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	assert.Equal(t, errors.UnauthorizedErrorStr, getErrorInfo(t, err).Reason)
+	assert.Equal(t, "false", getErrorInfo(t, err).Metadata["clearTokens"])
+	assert.Len(t, *sessions, 0)
+}
+
+func TestUnaryInterceptorReturnsUnauthorisedForMalformedAccessToken(t *testing.T) {
+	BeforeEach()
+	defer AfterEach()
+	initForTest(t, "http://localhost:8080", nil)
+
+	client, _, stop := startTestServer(t, nil)
+	defer stop()
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer not-a-jwt")
+	_, err := client.Check(ctx, &grpc_health_v1.HealthCheckRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	assert.Equal(t, errors.UnauthorizedErrorStr, getErrorInfo(t, err).Reason)
+}
+
+func TestUnaryInterceptorWithOptionalSession(t *testing.T) {
+	BeforeEach()
+	defer AfterEach()
+	initForTest(t, "http://localhost:8080", nil)
+
+	False := false
+	client, sessions, stop := startTestServer(t, &TypeInput{
+		VerifySessionOptions: &sessmodels.VerifySessionOptions{
+			SessionRequired: &False,
+		},
+	})
+	defer stop()
+
+	_, err := client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+	assert.NoError(t, err)
+	assert.Len(t, *sessions, 1)
+	assert.Nil(t, (*sessions)[0])
+}
+
+func TestUnaryInterceptorSkipsMethods(t *testing.T) {
+	BeforeEach()
+	defer AfterEach()
+	initForTest(t, "http://localhost:8080", nil)
+
+	client, sessions, stop := startTestServer(t, &TypeInput{
+		ShouldVerifySession: func(fullMethod string) bool {
+			return fullMethod != "/grpc.health.v1.Health/Check"
+		},
+	})
+	defer stop()
+
+	_, err := client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+	assert.NoError(t, err)
+	assert.Len(t, *sessions, 1)
+	assert.Nil(t, (*sessions)[0])
+}
+
+func TestToStatusErrorMapsSessionErrors(t *testing.T) {
+	err := ToStatusError(errors.TryRefreshTokenError{Msg: "try refresh token"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	assert.Equal(t, errors.TryRefreshTokenErrorStr, getErrorInfo(t, err).Reason)
+	assert.Equal(t, ErrorDomain, getErrorInfo(t, err).Domain)
+
+	err = ToStatusError(errors.InvalidClaimError{
+		Msg: "invalid claims",
+		InvalidClaims: []claims.ClaimValidationError{{
+			ID:     "st-role",
+			Reason: map[string]interface{}{"message": "wrong value"},
+		}},
+	})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.Equal(t, errors.InvalidClaimsErrorStr, getErrorInfo(t, err).Reason)
+	assert.Equal(t, `[{"id":"st-role","reason":{"message":"wrong value"}}]`, getErrorInfo(t, err).Metadata["claimValidationErrors"])
+
+	alreadyAStatus := status.Error(codes.NotFound, "not found")
+	assert.Equal(t, alreadyAStatus, ToStatusError(alreadyAStatus))
+
+	assert.Nil(t, ToStatusError(nil))
+}
+
+func TestUnaryInterceptorAddsSessionToContext(t *testing.T) {
+	BeforeEach()
+	connectionURI := unittesting.StartUpST("localhost", "8080")
+	defer AfterEach()
+	initForTest(t, connectionURI, nil)
+
+	client, sessions, stop := startTestServer(t, nil)
+	defer stop()
+
+	sess, err := session.CreateNewSessionWithoutRequestResponse("public", "testUser", map[string]interface{}{}, map[string]interface{}{}, nil)
+	assert.NoError(t, err)
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+sess.GetAccessToken())
+	_, err = client.Check(ctx, &grpc_health_v1.HealthCheckRequest{})
+	assert.NoError(t, err)
+	assert.Len(t, *sessions, 1)
+	assert.Equal(t, "testUser", (*sessions)[0].GetUserID())
+	assert.Equal(t, sess.GetHandle(), (*sessions)[0].GetHandle())
+}
+
+func TestUnaryInterceptorReturnsInvalidClaims(t *testing.T) {
+	BeforeEach()
+	connectionURI := unittesting.StartUpST("localhost", "8080")
+	defer AfterEach()
+
+	_, falseValidators := claims.BooleanClaim("st-false", func(userId string, tenantId string, userContext supertokens.UserContext) (interface{}, error) {
+		return false, nil
+	}, nil)
+	initForTest(t, connectionURI, &sessmodels.OverrideStruct{
+		Functions: func(originalImplementation sessmodels.RecipeInterface) sessmodels.RecipeInterface {
+			*originalImplementation.GetGlobalClaimValidators = func(userId string, claimValidatorsAddedByOtherRecipes []claims.SessionClaimValidator, tenantId string, userContext supertokens.UserContext) ([]claims.SessionClaimValidator, error) {
+				return append(claimValidatorsAddedByOtherRecipes, falseValidators.IsTrue(nil, nil)), nil
+			}
+			return originalImplementation
+		},
+	})
+
+	client, sessions, stop := startTestServer(t, nil)
+	defer stop()
+
+	sess, err := session.CreateNewSessionWithoutRequestResponse("public", "testUser", map[string]interface{}{}, map[string]interface{}{}, nil)
+	assert.NoError(t, err)
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+sess.GetAccessToken())
+	_, err = client.Check(ctx, &grpc_health_v1.HealthCheckRequest{})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.Equal(t, errors.InvalidClaimsErrorStr, getErrorInfo(t, err).Reason)
+	assert.Contains(t, getErrorInfo(t, err).Metadata["claimValidationErrors"], `"id":"st-false"`)
+	assert.Len(t, *sessions, 0)
+}
+
+func TestStreamInterceptorAddsSessionToContext(t *testing.T) {
+	BeforeEach()
+	connectionURI := unittesting.StartUpST("localhost", "8080")
+	defer AfterEach()
+	initForTest(t, connectionURI, nil)
+
+	client, sessions, stop := startTestServer(t, nil)
+	defer stop()
+
+	stream, err := client.Watch(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+	assert.NoError(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	assert.Equal(t, errors.UnauthorizedErrorStr, getErrorInfo(t, err).Reason)
+
+	sess, err := session.CreateNewSessionWithoutRequestResponse("public", "testUser", map[string]interface{}{}, map[string]interface{}{}, nil)
+	assert.NoError(t, err)
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+sess.GetAccessToken())
+	stream, err = client.Watch(ctx, &grpc_health_v1.HealthCheckRequest{})
+	assert.NoError(t, err)
+	resp, err := stream.Recv()
+	assert.NoError(t, err)
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, resp.Status)
+	assert.Len(t, *sessions, 1)
+	assert.Equal(t, "testUser", (*sessions)[0].GetUserID())
+}
+
+func BeforeEach() {
+	unittesting.CleanupAllCoreApps()
+	supertokens.ResetForTest()
+}
+
+func AfterEach() {
+	unittesting.CleanupAllCoreApps()
+	supertokens.ResetForTest()
+}
+
+func initForTest(t *testing.T, connectionURI string, override *sessmodels.OverrideStruct) {
+	err := supertokens.Init(supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: connectionURI,
+		},
+		AppInfo: supertokens.AppInfo{
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+			APIDomain:     "api.supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{
+			session.Init(&sessmodels.TypeInput{
+				Override: override,
+			}),
+		},
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+}
+
+// healthServer records the session found in the context of every call it receives
+type healthServer struct {
+	grpc_health_v1.UnimplementedHealthServer
+	sessions *[]sessmodels.SessionContainer
+}
+
+func (h *healthServer) Check(ctx context.Context, _ *grpc_health_v1.HealthCheckRequest) (*grpc_health_v1.HealthCheckResponse, error) {
+	*h.sessions = append(*h.sessions, session.GetSessionFromRequestContext(ctx))
+	return &grpc_health_v1.HealthCheckResponse{Status: grpc_health_v1.HealthCheckResponse_SERVING}, nil
+}
+
+func (h *healthServer) Watch(_ *grpc_health_v1.HealthCheckRequest, stream grpc_health_v1.Health_WatchServer) error {
+	*h.sessions = append(*h.sessions, session.GetSessionFromRequestContext(stream.Context()))
+	return stream.Send(&grpc_health_v1.HealthCheckResponse{Status: grpc_health_v1.HealthCheckResponse_SERVING})
+}
+
+func startTestServer(t *testing.T, config *TypeInput) (grpc_health_v1.HealthClient, *[]sessmodels.SessionContainer, func()) {
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer(
+		grpc.UnaryInterceptor(UnaryServerInterceptor(config)),
+		grpc.StreamInterceptor(StreamServerInterceptor(config)),
+	)
+	sessions := []sessmodels.SessionContainer{}
+	grpc_health_v1.RegisterHealthServer(server, &healthServer{sessions: &sessions})
+	go server.Serve(listener)
+
+	conn, err := grpc.DialContext(context.Background(), "bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	return grpc_health_v1.NewHealthClient(conn), &sessions, func() {
+		conn.Close()
+		server.Stop()
+	}
+}
+
+func getErrorInfo(t *testing.T, err error) *errdetails.ErrorInfo {
+	st, ok := status.FromError(err)
+	if !ok {
+		t.Fatal("expected a grpc status error")
+	}
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok {
+			return info
+		}
+	}
+	t.Fatal("expected an ErrorInfo detail")
+	return nil
+}
@@ -127,6 +127,11 @@ func GetSessionWithoutRequestResponse(accessToken string, antiCSRFToken *string,
 			return nil, err
 		}
 
+		err = RefetchStaleClaims(result, claimValidators, userContext[0])
+		if err != nil {
+			return nil, err
+		}
+
 		err = (*result).AssertClaimsWithContext(claimValidators, userContext[0])
 
 		if err != nil {
@@ -419,6 +424,42 @@ func GetClaimValue(sessionHandle string, claim *claims.TypeSessionClaim, userCon
 	return (*instance.RecipeImpl.GetClaimValue)(sessionHandle, claim, userContext[0])
 }
 
+// GetTypedClaimValue is the same as GetClaimValue, but returns the value converted to the type of the claim
+func GetTypedClaimValue[T any](sessionHandle string, claim *claims.TypedSessionClaim[T], userContext ...supertokens.UserContext) (sessmodels.GetTypedClaimValueResult[T], error) {
+	result, err := GetClaimValue(sessionHandle, claim.TypeSessionClaim, userContext...)
+	if err != nil {
+		return sessmodels.GetTypedClaimValueResult[T]{}, err
+	}
+	if result.SessionDoesNotExistError != nil {
+		return sessmodels.GetTypedClaimValueResult[T]{
+			SessionDoesNotExistError: &struct{}{},
+		}, nil
+	}
+	value, err := claims.ConvertClaimValue[T](result.OK.Value)
+	if err != nil {
+		return sessmodels.GetTypedClaimValueResult[T]{}, err
+	}
+	return sessmodels.GetTypedClaimValueResult[T]{
+		OK: &struct{ Value *T }{
+			Value: value,
+		},
+	}, nil
+}
+
+// SetTypedClaimValue is the same as SetClaimValue, but only accepts values of the type of the claim
+func SetTypedClaimValue[T any](sessionHandle string, claim *claims.TypedSessionClaim[T], value T, userContext ...supertokens.UserContext) (bool, error) {
+	return SetClaimValue(sessionHandle, claim.TypeSessionClaim, value, userContext...)
+}
+
+// GetTypedClaimValueFromSession returns the value of the claim in the session converted to the type
+// of the claim, or nil if the claim is not set in the session
+func GetTypedClaimValueFromSession[T any](sessionContainer sessmodels.SessionContainer, claim *claims.TypedSessionClaim[T], userContext ...supertokens.UserContext) (*T, error) {
+	if len(userContext) == 0 {
+		userContext = append(userContext, &map[string]interface{}{})
+	}
+	return claims.ConvertClaimValue[T](sessionContainer.GetClaimValueWithContext(claim.TypeSessionClaim, userContext[0]))
+}
+
 func RemoveClaim(sessionHandle string, claim *claims.TypeSessionClaim, userContext ...supertokens.UserContext) (bool, error) {
 	instance, err := getRecipeInstanceOrThrowError()
 	if err != nil {
@@ -430,6 +471,25 @@ func RemoveClaim(sessionHandle string, claim *claims.TypeSessionClaim, userConte
 	return (*instance.RecipeImpl.RemoveClaim)(sessionHandle, claim, userContext[0])
 }
 
+// MarkClaimsAsStale makes the sessions of the user refetch the claims with the given keys the next
+// time they are verified, if the values in their access token were fetched before this call. It does
+// nothing if the session recipe is not initialised. With the default in-memory ClaimVersionStore,
+// only the sessions verified by this process refetch the claims.
+func MarkClaimsAsStale(userId string, claimKeys []string, userContext ...supertokens.UserContext) error {
+	if len(userContext) == 0 {
+		userContext = append(userContext, &map[string]interface{}{})
+	}
+	return markClaimsAsStale(&userId, claimKeys, userContext[0])
+}
+
+// MarkClaimsAsStaleForAllUsers is the same as MarkClaimsAsStale, but for the sessions of all users.
+func MarkClaimsAsStaleForAllUsers(claimKeys []string, userContext ...supertokens.UserContext) error {
+	if len(userContext) == 0 {
+		userContext = append(userContext, &map[string]interface{}{})
+	}
+	return markClaimsAsStale(nil, claimKeys, userContext[0])
+}
+
 func VerifySession(options *sessmodels.VerifySessionOptions, otherHandler http.HandlerFunc) http.HandlerFunc {
 	instance, err := getRecipeInstanceOrThrowError()
 	if err != nil {
@@ -1,6 +1,7 @@
 package session
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
@@ -8,6 +9,7 @@ import (
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/supertokens/supertokens-golang/supertokens"
@@ -284,3 +286,100 @@ func TestThatParallelCallsHaveIndependentRetryCounters(t *testing.T) {
 	assert.Equal(t, numberOfTimesFirstCalled, 6)
 	assert.Equal(t, numberOfTimesSecondCalled, 6)
 }
+
+func TestThatQuerierUsesTheContextInUserContext(t *testing.T) {
+	resetAll()
+	mux := http.NewServeMux()
+
+	type contextKey string
+	receivedTraceIds := []string{}
+
+	mux.HandleFunc("/testing", func(rw http.ResponseWriter, r *http.Request) {
+		receivedTraceIds = append(receivedTraceIds, r.Header.Get("trace-id"))
+		rw.Header().Set("Content-Type", "application/json")
+		rw.WriteHeader(200)
+		response, err := json.Marshal(map[string]interface{}{})
+		if err != nil {
+			t.Error(err.Error())
+		}
+		rw.Write(response)
+	})
+
+	mux.HandleFunc("/slow", func(rw http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(2 * time.Second):
+		}
+		rw.WriteHeader(200)
+	})
+
+	testServer := httptest.NewServer(mux)
+
+	defer func() {
+		testServer.Close()
+	}()
+
+	config := supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			// We need the querier to call the test server and not the core
+			ConnectionURI: testServer.URL,
+			NetworkInterceptor: func(r *http.Request, uc supertokens.UserContext) (*http.Request, error) {
+				if traceId, ok := r.Context().Value(contextKey("trace-id")).(string); ok {
+					r.Header.Set("trace-id", traceId)
+				}
+				return r, nil
+			},
+		},
+		AppInfo: supertokens.AppInfo{
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+			APIDomain:     "api.supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{
+			Init(nil),
+		},
+	}
+
+	err := supertokens.Init(config)
+
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	q, err := supertokens.GetNewQuerierInstanceOrThrowError("")
+	supertokens.SetQuerierApiVersionForTests("3.0")
+	defer resetQuerier()
+
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	ctx := context.WithValue(context.Background(), contextKey("trace-id"), "trace-1")
+	_, err = q.SendGetRequest("/testing", map[string]string{}, supertokens.MakeUserContextFromContext(ctx))
+	assert.NoError(t, err)
+
+	_, err = q.SendPostRequest("/testing", map[string]interface{}{}, supertokens.MakeUserContextFromContext(ctx))
+	assert.NoError(t, err)
+
+	// The context of the request in the user context is used if no context was set explicitly
+	req := httptest.NewRequest(http.MethodGet, "/", nil).WithContext(context.WithValue(context.Background(), contextKey("trace-id"), "trace-2"))
+	_, err = q.SendGetRequest("/testing", map[string]string{}, supertokens.MakeDefaultUserContextFromAPI(req))
+	assert.NoError(t, err)
+
+	_, err = q.SendGetRequest("/testing", map[string]string{}, nil)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"trace-1", "trace-1", "trace-2", ""}, receivedTraceIds)
+
+	cancelledCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = q.SendGetRequest("/testing", map[string]string{}, supertokens.MakeUserContextFromContext(cancelledCtx))
+	assert.ErrorIs(t, err, context.Canceled)
+
+	timeoutCtx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err = q.SendGetRequest("/slow", map[string]string{}, supertokens.MakeUserContextFromContext(timeoutCtx))
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+}
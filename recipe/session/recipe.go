@@ -98,6 +98,7 @@ func MakeRecipe(recipeId string, appInfo supertokens.NormalisedAppinfo, config *
 	r.OpenIdRecipe = openIdRecipe
 
 	r.RecipeModule.ResetForTest = ResetForTest
+	r.RecipeModule.ExportUserData = r.exportUserData
 
 	return *r, nil
 }
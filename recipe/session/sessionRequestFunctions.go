@@ -286,6 +286,11 @@ func GetSessionFromRequest(req *http.Request, res http.ResponseWriter, config se
 			return nil, err
 		}
 
+		err = RefetchStaleClaims(sessionResult, claimValidators, userContext)
+		if err != nil {
+			return nil, err
+		}
+
 		err = (*sessionResult).AssertClaimsWithContext(claimValidators, userContext)
 		if err != nil {
 			return nil, err
@@ -20,3 +20,11 @@ type GetClaimValueResult struct {
 	}
 	SessionDoesNotExistError *struct{}
 }
+
+type GetTypedClaimValueResult[T any] struct {
+	OK *struct {
+		// Value is nil if the claim is not set in the session
+		Value *T
+	}
+	SessionDoesNotExistError *struct{}
+}
@@ -107,6 +107,11 @@ type TypeInput struct {
 	ExposeAccessTokenToFrontendInCookieBasedAuth bool
 	UseDynamicAccessTokenSigningKey              *bool
 	JWKSRefreshIntervalSec                       *uint64
+	// ClaimVersionStore is used to detect claims whose values changed after they were added to
+	// the access token. It defaults to an in-memory store, which only works within one process:
+	// claims marked as stale by one instance of the backend are not refetched by the other
+	// instances, so a shared store must be set if the backend runs on more than one instance.
+	ClaimVersionStore *ClaimVersionStore
 }
 
 type OverrideStruct struct {
@@ -138,6 +143,17 @@ type TypeNormalisedInput struct {
 	ExposeAccessTokenToFrontendInCookieBasedAuth bool
 	UseDynamicAccessTokenSigningKey              bool
 	JWKSRefreshIntervalSec                       uint64
+	ClaimVersionStore                            ClaimVersionStore
+}
+
+// ClaimVersionStore keeps track of when the values of claims changed. Versions are timestamps in
+// milliseconds: if a claim in the access token payload was fetched before its latest version, it
+// is refetched when the session is verified.
+type ClaimVersionStore struct {
+	// SetClaimVersions records that the values of the claims changed for the user, or for all users if userId is nil
+	SetClaimVersions func(userId *string, claimKeys []string, version int64, userContext supertokens.UserContext) error
+	// GetClaimVersions returns the latest version of the claims that changed for the user, including changes for all users
+	GetClaimVersions func(userId string, userContext supertokens.UserContext) (map[string]int64, error)
 }
 
 type AntiCsrfFunctionOrString struct {
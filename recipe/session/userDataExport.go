@@ -0,0 +1,54 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package session
+
+import (
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func (r *Recipe) exportUserData(userId string, tenantIds []string, userContext supertokens.UserContext) (interface{}, error) {
+	fetchAcrossAllTenants := true
+	sessionHandles, err := (*r.RecipeImpl.GetAllSessionHandlesForUser)(userId, supertokens.DefaultTenantId, &fetchAcrossAllTenants, userContext)
+	if err != nil {
+		return nil, err
+	}
+	sessions := []map[string]interface{}{}
+	for _, sessionHandle := range sessionHandles {
+		sessionInformation, err := (*r.RecipeImpl.GetSessionInformation)(sessionHandle, userContext)
+		if err != nil {
+			return nil, err
+		}
+		if sessionInformation == nil {
+			// the session was revoked in the meantime
+			continue
+		}
+		sessions = append(sessions, map[string]interface{}{
+			"sessionHandle":         sessionInformation.SessionHandle,
+			"tenantId":              sessionInformation.TenantId,
+			"timeCreated":           sessionInformation.TimeCreated,
+			"expiry":                sessionInformation.Expiry,
+			"sessionDataInDatabase": sessionInformation.SessionDataInDatabase,
+			"accessTokenPayload":    sessionInformation.CustomClaimsInAccessTokenPayload,
+		})
+	}
+	if len(sessions) == 0 {
+		return nil, nil
+	}
+	return map[string]interface{}{
+		"sessions": sessions,
+	}, nil
+}
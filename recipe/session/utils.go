@@ -227,6 +227,11 @@ func ValidateAndNormaliseUserInput(appInfo supertokens.NormalisedAppinfo, config
 		jwksRefreshIntervalSec = *config.JWKSRefreshIntervalSec
 	}
 
+	claimVersionStore := makeInMemoryClaimVersionStore()
+	if config.ClaimVersionStore != nil {
+		claimVersionStore = *config.ClaimVersionStore
+	}
+
 	typeNormalisedInput := sessmodels.TypeNormalisedInput{
 		RefreshTokenPath:         appInfo.APIBasePath.AppendPath(refreshAPIPath),
 		CookieDomain:             cookieDomain,
@@ -241,6 +246,7 @@ func ValidateAndNormaliseUserInput(appInfo supertokens.NormalisedAppinfo, config
 		JWKSRefreshIntervalSec:                       jwksRefreshIntervalSec,
 		ErrorHandlers:                                errorHandlers,
 		GetTokenTransferMethod:                       config.GetTokenTransferMethod,
+		ClaimVersionStore:                            claimVersionStore,
 		Override: sessmodels.OverrideStruct{
 			Functions: func(originalImplementation sessmodels.RecipeInterface) sessmodels.RecipeInterface {
 				return originalImplementation
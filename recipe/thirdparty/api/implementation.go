@@ -22,7 +22,10 @@ import (
 	"net/http"
 	"net/url"
 
+	"github.com/supertokens/supertokens-golang/ingredients/emailpolicy"
 	"github.com/supertokens/supertokens-golang/recipe/emailverification"
+	"github.com/supertokens/supertokens-golang/recipe/invitations"
+	"github.com/supertokens/supertokens-golang/recipe/invitations/invitationsmodels"
 	"github.com/supertokens/supertokens-golang/recipe/session"
 	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
 	"github.com/supertokens/supertokens-golang/recipe/thirdparty/tpmodels"
@@ -60,7 +63,9 @@ func MakeAPIImplementation() tpmodels.APIInterface {
 			return tpmodels.SignInUpPOSTResponse{}, err
 		}
 
+		isFakeEmail := false
 		if userInfo.Email == nil && provider.Config.RequireEmail != nil && !*provider.Config.RequireEmail {
+			isFakeEmail = true
 			userInfo.Email = &tpmodels.EmailStruct{
 				ID:         provider.Config.GenerateFakeEmail(userInfo.ThirdPartyUserId, tenantId, userContext),
 				IsVerified: true,
@@ -74,11 +79,69 @@ func MakeAPIImplementation() tpmodels.APIInterface {
 			}, nil
 		}
 
+		if options.Config.EmailPolicy != nil && !isFakeEmail {
+			// existing users can always sign in, the policy only applies to new users
+			user, err := (*options.RecipeImplementation.GetUserByThirdPartyInfo)(provider.ID, userInfo.ThirdPartyUserId, tenantId, userContext)
+			if err != nil {
+				return tpmodels.SignInUpPOSTResponse{}, err
+			}
+			if user == nil {
+				email := emailpolicy.Normalise(options.Config.EmailPolicy, emailInfo.ID, tenantId)
+				reason := emailpolicy.CheckSignUpAllowed(options.Config.EmailPolicy, email, tenantId)
+				if reason != nil {
+					return tpmodels.SignInUpPOSTResponse{
+						EmailNotAllowedError: &struct {
+							Reason emailpolicy.Reason
+						}{
+							Reason: *reason,
+						},
+					}, nil
+				}
+			}
+		}
+
+		var invite *invitationsmodels.Invite
+		invitationsInstance := invitations.GetRecipeInstance()
+		if invitationsInstance != nil && invitationsInstance.IsInviteOnlyTenant(tenantId) {
+			// existing users can always sign in, an invite is only needed to sign up
+			user, err := (*options.RecipeImplementation.GetUserByThirdPartyInfo)(provider.ID, userInfo.ThirdPartyUserId, tenantId, userContext)
+			if err != nil {
+				return tpmodels.SignInUpPOSTResponse{}, err
+			}
+			if user == nil {
+				email := emailpolicy.Normalise(options.Config.EmailPolicy, emailInfo.ID, tenantId)
+				inviteForSignUp, allowed, err := invitationsInstance.GetInviteForSignUp(tenantId, email, options.Config.EmailPolicy, options.Req, userContext)
+				if err != nil {
+					return tpmodels.SignInUpPOSTResponse{}, err
+				}
+				if !allowed {
+					return tpmodels.SignInUpPOSTResponse{
+						InviteRequiredError: &struct{}{},
+					}, nil
+				}
+				invite = inviteForSignUp
+			}
+		}
+
 		response, err := (*options.RecipeImplementation.SignInUp)(provider.ID, userInfo.ThirdPartyUserId, emailInfo.ID, oAuthTokens, userInfo.RawUserInfoFromProvider, tenantId, userContext)
+		if invitationsInstance != nil && (err != nil || !response.OK.CreatedNewUser) {
+			// no user was created, so the invite can be used again
+			cancelErr := invitationsInstance.CancelSignUp(invite, userContext)
+			if err == nil {
+				err = cancelErr
+			}
+		}
 		if err != nil {
 			return tpmodels.SignInUpPOSTResponse{}, err
 		}
 
+		if invitationsInstance != nil && response.OK.CreatedNewUser {
+			err = invitationsInstance.CompleteSignUp(invite, response.OK.User.ID, userContext)
+			if err != nil {
+				return tpmodels.SignInUpPOSTResponse{}, err
+			}
+		}
+
 		if emailInfo.IsVerified {
 			evInstance := emailverification.GetRecipeInstance()
 			if evInstance != nil {
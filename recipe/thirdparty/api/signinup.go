@@ -95,6 +95,15 @@ func SignInUpAPI(apiImplementation tpmodels.APIInterface, tenantId string, optio
 		return supertokens.Send200Response(options.Res, map[string]interface{}{
 			"status": "NO_EMAIL_GIVEN_BY_PROVIDER",
 		})
+	} else if result.EmailNotAllowedError != nil {
+		return supertokens.Send200Response(options.Res, map[string]interface{}{
+			"status": "EMAIL_NOT_ALLOWED_ERROR",
+			"reason": result.EmailNotAllowedError.Reason,
+		})
+	} else if result.InviteRequiredError != nil {
+		return supertokens.Send200Response(options.Res, map[string]interface{}{
+			"status": "INVITE_REQUIRED_ERROR",
+		})
 	} else if result.GeneralError != nil {
 		return supertokens.Send200Response(options.Res, supertokens.ConvertGeneralErrorToJsonResponse(*result.GeneralError))
 	}
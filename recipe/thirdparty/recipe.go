@@ -20,6 +20,7 @@ import (
 	"net/http"
 
 	"github.com/supertokens/supertokens-golang/ingredients/emaildelivery"
+	"github.com/supertokens/supertokens-golang/ingredients/emailpolicy"
 	"github.com/supertokens/supertokens-golang/recipe/emailverification"
 	"github.com/supertokens/supertokens-golang/recipe/emailverification/evmodels"
 	"github.com/supertokens/supertokens-golang/recipe/multitenancy"
@@ -56,7 +57,9 @@ func MakeRecipe(recipeId string, appInfo supertokens.NormalisedAppinfo, config *
 	}
 	r.Config = verifiedConfig
 	r.APIImpl = verifiedConfig.Override.APIs(api.MakeAPIImplementation())
-	r.RecipeImpl = verifiedConfig.Override.Functions(MakeRecipeImplementation(*querierInstance, verifiedConfig.SignInAndUpFeature.Providers))
+	r.RecipeImpl = verifiedConfig.Override.Functions(makeRecipeImplementation(*querierInstance, verifiedConfig.SignInAndUpFeature.Providers, func() *emailpolicy.TypeNormalisedInput {
+		return verifiedConfig.EmailPolicy
+	}))
 	r.Providers = verifiedConfig.SignInAndUpFeature.Providers
 
 	supertokens.AddPostInitCallback(func() error {
@@ -74,6 +77,8 @@ func MakeRecipe(recipeId string, appInfo supertokens.NormalisedAppinfo, config *
 	})
 
 	r.RecipeModule.ResetForTest = ResetForTest
+	r.RecipeModule.GetTenantIdsForUser = r.getTenantIdsForUser
+	r.RecipeModule.ExportUserData = r.exportUserData
 
 	return *r, nil
 }
@@ -18,6 +18,7 @@ package thirdparty
 import (
 	"errors"
 
+	"github.com/supertokens/supertokens-golang/ingredients/emailpolicy"
 	"github.com/supertokens/supertokens-golang/recipe/multitenancy"
 	"github.com/supertokens/supertokens-golang/recipe/multitenancy/multitenancymodels"
 	tpproviders "github.com/supertokens/supertokens-golang/recipe/thirdparty/providers"
@@ -25,7 +26,18 @@ import (
 	"github.com/supertokens/supertokens-golang/supertokens"
 )
 
+// MakeRecipeImplementation uses the EmailPolicy of the initialised thirdparty recipe. If the
+// recipe is not initialised, emails are used as given.
 func MakeRecipeImplementation(querier supertokens.Querier, providers []tpmodels.ProviderInput) tpmodels.RecipeInterface {
+	return makeRecipeImplementation(querier, providers, func() *emailpolicy.TypeNormalisedInput {
+		if singletonInstance == nil {
+			return nil
+		}
+		return singletonInstance.Config.EmailPolicy
+	})
+}
+
+func makeRecipeImplementation(querier supertokens.Querier, providers []tpmodels.ProviderInput, getEmailPolicy func() *emailpolicy.TypeNormalisedInput) tpmodels.RecipeInterface {
 
 	getProvider := func(thirdPartyID string, clientType *string, tenantId string, userContext supertokens.UserContext) (*tpmodels.TypeProvider, error) {
 
@@ -51,7 +63,7 @@ func MakeRecipeImplementation(querier supertokens.Querier, providers []tpmodels.
 		response, err := querier.SendPostRequest(tenantId+"/recipe/signinup", map[string]interface{}{
 			"thirdPartyId":     thirdPartyID,
 			"thirdPartyUserId": thirdPartyUserID,
-			"email":            map[string]interface{}{"id": email},
+			"email":            map[string]interface{}{"id": emailpolicy.Normalise(getEmailPolicy(), email, tenantId)},
 		}, userContext)
 		if err != nil {
 			return tpmodels.SignInUpResponse{}, err
@@ -79,7 +91,7 @@ func MakeRecipeImplementation(querier supertokens.Querier, providers []tpmodels.
 		response, err := querier.SendPostRequest(tenantId+"/recipe/signinup", map[string]interface{}{
 			"thirdPartyId":     thirdPartyID,
 			"thirdPartyUserId": thirdPartyUserID,
-			"email":            map[string]interface{}{"id": email},
+			"email":            map[string]interface{}{"id": emailpolicy.Normalise(getEmailPolicy(), email, tenantId)},
 		}, userContext)
 		if err != nil {
 			return tpmodels.ManuallyCreateOrUpdateUserResponse{}, err
@@ -134,7 +146,7 @@ func MakeRecipeImplementation(querier supertokens.Querier, providers []tpmodels.
 		return nil, nil
 	}
 
-	getUsersByEmail := func(email string, tenantId string, userContext supertokens.UserContext) ([]tpmodels.User, error) {
+	getUsersByEmailInCore := func(email string, tenantId string, userContext supertokens.UserContext) ([]tpmodels.User, error) {
 		response, err := querier.SendGetRequest(tenantId+"/recipe/users/by-email", map[string]string{
 			"email": email,
 		}, userContext)
@@ -148,6 +160,33 @@ func MakeRecipeImplementation(querier supertokens.Querier, providers []tpmodels.
 		return users, nil
 	}
 
+	getUsersByEmail := func(email string, tenantId string, userContext supertokens.UserContext) ([]tpmodels.User, error) {
+		normalisedEmail := emailpolicy.Normalise(getEmailPolicy(), email, tenantId)
+		users, err := getUsersByEmailInCore(normalisedEmail, tenantId, userContext)
+		if err != nil || normalisedEmail == email {
+			return users, err
+		}
+		// users that signed in before the email policy was set keep the email as it was given
+		// until they sign in again
+		usersWithEmail, err := getUsersByEmailInCore(email, tenantId, userContext)
+		if err != nil {
+			return []tpmodels.User{}, err
+		}
+		for _, user := range usersWithEmail {
+			found := false
+			for _, existing := range users {
+				if existing.ID == user.ID {
+					found = true
+					break
+				}
+			}
+			if !found {
+				users = append(users, user)
+			}
+		}
+		return users, nil
+	}
+
 	return tpmodels.RecipeInterface{
 		GetUserByID:                &getUserByID,
 		GetUsersByEmail:            &getUsersByEmail,
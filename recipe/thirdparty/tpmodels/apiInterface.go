@@ -19,6 +19,7 @@ import (
 	"net/http"
 
 	"github.com/supertokens/supertokens-golang/ingredients/emaildelivery"
+	"github.com/supertokens/supertokens-golang/ingredients/emailpolicy"
 	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
 	"github.com/supertokens/supertokens-golang/supertokens"
 )
@@ -49,7 +50,15 @@ type SignInUpPOSTResponse struct {
 		RawUserInfoFromProvider TypeRawUserInfoFromProvider
 	}
 	NoEmailGivenByProviderError *struct{}
-	GeneralError                *supertokens.GeneralErrorResponse
+	// EmailNotAllowedError is returned if a new user signs up with an email rejected by the
+	// EmailPolicy
+	EmailNotAllowedError *struct {
+		Reason emailpolicy.Reason
+	}
+	// InviteRequiredError is returned if a new user signs up in an invite only tenant without a
+	// valid invite for their email
+	InviteRequiredError *struct{}
+	GeneralError        *supertokens.GeneralErrorResponse
 }
 
 type APIOptions struct {
@@ -16,6 +16,7 @@
 package tpmodels
 
 import (
+	"github.com/supertokens/supertokens-golang/ingredients/emailpolicy"
 	"github.com/supertokens/supertokens-golang/supertokens"
 )
 
@@ -89,11 +90,16 @@ type TypeNormalisedInputSignInAndUp struct {
 type TypeInput struct {
 	SignInAndUpFeature TypeInputSignInAndUp
 	Override           *OverrideStruct
+	// EmailPolicy normalises the emails given by the providers before they are stored or looked
+	// up, and restricts the emails that can sign up
+	EmailPolicy *emailpolicy.TypeInput
 }
 
 type TypeNormalisedInput struct {
 	SignInAndUpFeature TypeNormalisedInputSignInAndUp
 	Override           OverrideStruct
+	// EmailPolicy is nil if emails are used as given and all emails can sign up
+	EmailPolicy *emailpolicy.TypeNormalisedInput
 }
 
 type OverrideStruct struct {
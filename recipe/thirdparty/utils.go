@@ -18,6 +18,7 @@ package thirdparty
 import (
 	"encoding/json"
 
+	"github.com/supertokens/supertokens-golang/ingredients/emailpolicy"
 	"github.com/supertokens/supertokens-golang/recipe/thirdparty/tpmodels"
 	"github.com/supertokens/supertokens-golang/supertokens"
 )
@@ -35,6 +36,14 @@ func validateAndNormaliseUserInput(recipeInstance *Recipe, appInfo supertokens.N
 	}
 	typeNormalisedInput.SignInAndUpFeature = signInAndUpFeature
 
+	if config.EmailPolicy != nil {
+		emailPolicy, err := emailpolicy.NormaliseTypeInput(*config.EmailPolicy)
+		if err != nil {
+			return tpmodels.TypeNormalisedInput{}, err
+		}
+		typeNormalisedInput.EmailPolicy = &emailPolicy
+	}
+
 	if config != nil && config.Override != nil {
 		if config.Override.Functions != nil {
 			typeNormalisedInput.Override.Functions = config.Override.Functions
@@ -49,6 +49,7 @@ func MakeRecipe(recipeId string, appInfo supertokens.NormalisedAppinfo, config *
 	r.RecipeModule = recipeModuleInstance
 
 	r.RecipeModule.ResetForTest = resetForTest
+	r.RecipeModule.ExportUserData = r.exportUserData
 
 	return *r, nil
 }
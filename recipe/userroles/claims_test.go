@@ -422,3 +422,49 @@ func TestShouldValidatePermissionsAfterRefetching(t *testing.T) {
 	assert.Contains(t, reason["actualValue"], "a")
 	assert.Contains(t, reason["actualValue"], "b")
 }
+
+func TestShouldRefetchRolesAfterRemovingRoleFromUser(t *testing.T) {
+	BeforeEach()
+	connectionURI := unittesting.StartUpST("localhost", "8080")
+	defer AfterEach()
+	configValue := supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: connectionURI,
+		},
+		AppInfo: supertokens.AppInfo{
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+			APIDomain:     "api.supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{
+			session.Init(nil),
+			Init(nil),
+		},
+	}
+	err := supertokens.Init(configValue)
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	if !canRunTest(t) {
+		return
+	}
+
+	_, err = CreateNewRoleOrAddPermissions("admin", []string{"delete"})
+	assert.NoError(t, err)
+	_, err = AddRoleToUser("public", "userId", "admin")
+	assert.NoError(t, err)
+
+	sessionContainer, err := session.CreateNewSessionWithoutRequestResponse("public", "userId", map[string]interface{}{}, map[string]interface{}{}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{"admin"}, sessionContainer.GetClaimValue(userrolesclaims.UserRoleClaim))
+
+	_, err = RemoveUserRole("public", "userId", "admin")
+	assert.NoError(t, err)
+
+	sessionContainer, err = session.GetSessionWithoutRequestResponse(sessionContainer.GetAccessToken(), nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{}, sessionContainer.GetClaimValue(userrolesclaims.UserRoleClaim))
+	assert.Equal(t, []interface{}{}, sessionContainer.GetClaimValue(userrolesclaims.PermissionClaim))
+	assert.True(t, sessionContainer.GetAllSessionTokensDangerously().AccessAndFrontendTokenUpdated)
+}
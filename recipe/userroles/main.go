@@ -117,6 +117,9 @@ func DeleteRole(role string, userContext ...supertokens.UserContext) (userrolesm
 	if err != nil {
 		return userrolesmodels.DeleteRoleResponse{}, err
 	}
+	if len(userContext) == 0 {
+		userContext = append(userContext, &map[string]interface{}{})
+	}
 	return (*instance.RecipeImpl.DeleteRole)(role, userContext[0])
 }
 
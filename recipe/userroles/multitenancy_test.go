@@ -1,4 +1,4 @@
-package userroles
+package userroles_test
 
 import (
 	"testing"
@@ -8,14 +8,15 @@ import (
 	"github.com/supertokens/supertokens-golang/recipe/multitenancy"
 	"github.com/supertokens/supertokens-golang/recipe/multitenancy/multitenancymodels"
 	"github.com/supertokens/supertokens-golang/recipe/session"
+	"github.com/supertokens/supertokens-golang/recipe/userroles"
 	"github.com/supertokens/supertokens-golang/supertokens"
 	"github.com/supertokens/supertokens-golang/test/unittesting"
 )
 
 func TestDifferentRolesCanBeAssignedToSameUserAcrossTenants(t *testing.T) {
-	BeforeEach()
+	userroles.BeforeEach()
 	connectionURI := unittesting.StartUpSTWithMultitenancy("localhost", "8080")
-	defer AfterEach()
+	defer userroles.AfterEach()
 	configValue := supertokens.TypeInput{
 		Supertokens: &supertokens.ConnectionInfo{
 			ConnectionURI: connectionURI,
@@ -28,7 +29,7 @@ func TestDifferentRolesCanBeAssignedToSameUserAcrossTenants(t *testing.T) {
 		RecipeList: []supertokens.Recipe{
 			emailpassword.Init(nil),
 			session.Init(nil),
-			Init(nil),
+			userroles.Init(nil),
 		},
 	}
 
@@ -61,32 +62,32 @@ func TestDifferentRolesCanBeAssignedToSameUserAcrossTenants(t *testing.T) {
 	multitenancy.AssociateUserToTenant("t2", user.OK.User.ID)
 	multitenancy.AssociateUserToTenant("t3", user.OK.User.ID)
 
-	CreateNewRoleOrAddPermissions("role1", []string{})
-	CreateNewRoleOrAddPermissions("role2", []string{})
-	CreateNewRoleOrAddPermissions("role3", []string{})
+	userroles.CreateNewRoleOrAddPermissions("role1", []string{})
+	userroles.CreateNewRoleOrAddPermissions("role2", []string{})
+	userroles.CreateNewRoleOrAddPermissions("role3", []string{})
 
-	AddRoleToUser("t1", user.OK.User.ID, "role1")
-	AddRoleToUser("t1", user.OK.User.ID, "role2")
+	userroles.AddRoleToUser("t1", user.OK.User.ID, "role1")
+	userroles.AddRoleToUser("t1", user.OK.User.ID, "role2")
 
-	AddRoleToUser("t2", user.OK.User.ID, "role2")
-	AddRoleToUser("t2", user.OK.User.ID, "role3")
+	userroles.AddRoleToUser("t2", user.OK.User.ID, "role2")
+	userroles.AddRoleToUser("t2", user.OK.User.ID, "role3")
 
-	AddRoleToUser("t3", user.OK.User.ID, "role1")
-	AddRoleToUser("t3", user.OK.User.ID, "role3")
+	userroles.AddRoleToUser("t3", user.OK.User.ID, "role1")
+	userroles.AddRoleToUser("t3", user.OK.User.ID, "role3")
 
-	roles, err := GetRolesForUser("t1", user.OK.User.ID)
+	roles, err := userroles.GetRolesForUser("t1", user.OK.User.ID)
 	assert.Nil(t, err)
 	assert.Equal(t, 2, len(roles.OK.Roles))
 	assert.Contains(t, roles.OK.Roles, "role1")
 	assert.Contains(t, roles.OK.Roles, "role2")
 
-	roles, err = GetRolesForUser("t2", user.OK.User.ID)
+	roles, err = userroles.GetRolesForUser("t2", user.OK.User.ID)
 	assert.Nil(t, err)
 	assert.Equal(t, 2, len(roles.OK.Roles))
 	assert.Contains(t, roles.OK.Roles, "role2")
 	assert.Contains(t, roles.OK.Roles, "role3")
 
-	roles, err = GetRolesForUser("t3", user.OK.User.ID)
+	roles, err = userroles.GetRolesForUser("t3", user.OK.User.ID)
 	assert.Nil(t, err)
 	assert.Equal(t, 2, len(roles.OK.Roles))
 	assert.Contains(t, roles.OK.Roles, "role1")
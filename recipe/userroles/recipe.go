@@ -52,6 +52,7 @@ func MakeRecipe(recipeId string, appInfo supertokens.NormalisedAppinfo, config *
 	r.RecipeModule = recipeModuleInstance
 
 	r.RecipeModule.ResetForTest = resetForTest
+	r.RecipeModule.ExportUserData = r.exportUserData
 
 	return *r, nil
 }
@@ -63,6 +64,10 @@ func getRecipeInstanceOrThrowError() (*Recipe, error) {
 	return nil, errors.New("Initialisation not done. Did you forget to call the init function?")
 }
 
+func GetRecipeInstance() *Recipe {
+	return singletonInstance
+}
+
 func recipeInit(config *userrolesmodels.TypeInput) supertokens.Recipe {
 	return func(appInfo supertokens.NormalisedAppinfo, onSuperTokensAPIError func(err error, req *http.Request, res http.ResponseWriter)) (*supertokens.RecipeModule, error) {
 		if singletonInstance == nil {
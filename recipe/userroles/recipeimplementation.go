@@ -54,9 +54,13 @@ func makeRecipeImplementation(querier supertokens.Querier, config userrolesmodel
 		}
 
 		if response["status"] == "OK" {
+			didUserHaveRole := response["didUserHaveRole"].(bool)
+			if didUserHaveRole {
+				markRoleClaimsAsStale(&userID, userContext)
+			}
 			return userrolesmodels.RemoveUserRoleResponse{
 				OK: &struct{ DidUserHaveRole bool }{
-					DidUserHaveRole: response["didUserHaveRole"].(bool),
+					DidUserHaveRole: didUserHaveRole,
 				},
 			}, nil
 		}
@@ -150,6 +154,7 @@ func makeRecipeImplementation(querier supertokens.Querier, config userrolesmodel
 		}
 
 		if response["status"] == "OK" {
+			markRoleClaimsAsStale(nil, userContext)
 			return userrolesmodels.RemovePermissionsFromRoleResponse{
 				OK: &struct{}{},
 			}, nil
@@ -183,9 +188,14 @@ func makeRecipeImplementation(querier supertokens.Querier, config userrolesmodel
 			return userrolesmodels.DeleteRoleResponse{}, err
 		}
 
+		didRoleExist := response["didRoleExist"].(bool)
+		if didRoleExist {
+			// we don't know which users had the role, so the claims of all users are marked as stale
+			markRoleClaimsAsStale(nil, userContext)
+		}
 		return userrolesmodels.DeleteRoleResponse{
 			OK: &struct{ DidRoleExist bool }{
-				DidRoleExist: response["didRoleExist"].(bool),
+				DidRoleExist: didRoleExist,
 			},
 		}, nil
 	}
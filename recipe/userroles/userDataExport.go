@@ -0,0 +1,59 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package userroles
+
+import (
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func (r *Recipe) exportUserData(userId string, tenantIds []string, userContext supertokens.UserContext) (interface{}, error) {
+	if len(tenantIds) == 0 {
+		tenantIds = []string{supertokens.DefaultTenantId}
+	}
+	rolesByTenant := map[string][]map[string]interface{}{}
+	for _, tenantId := range tenantIds {
+		response, err := (*r.RecipeImpl.GetRolesForUser)(userId, tenantId, userContext)
+		if err != nil {
+			return nil, err
+		}
+		if response.OK == nil || len(response.OK.Roles) == 0 {
+			continue
+		}
+		roles := []map[string]interface{}{}
+		for _, role := range response.OK.Roles {
+			permissions := []string{}
+			permissionsResponse, err := (*r.RecipeImpl.GetPermissionsForRole)(role, userContext)
+			if err != nil {
+				return nil, err
+			}
+			if permissionsResponse.OK != nil {
+				permissions = permissionsResponse.OK.Permissions
+			}
+			roles = append(roles, map[string]interface{}{
+				"role":        role,
+				"permissions": permissions,
+			})
+		}
+		rolesByTenant[tenantId] = roles
+	}
+	if len(rolesByTenant) == 0 {
+		return nil, nil
+	}
+	return map[string]interface{}{
+		"roles": rolesByTenant,
+	}, nil
+}
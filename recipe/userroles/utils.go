@@ -16,6 +16,8 @@
 package userroles
 
 import (
+	"github.com/supertokens/supertokens-golang/recipe/session"
+	"github.com/supertokens/supertokens-golang/recipe/userroles/userrolesclaims"
 	"github.com/supertokens/supertokens-golang/recipe/userroles/userrolesmodels"
 	"github.com/supertokens/supertokens-golang/supertokens"
 )
@@ -61,3 +63,20 @@ func convertToStringArray(arr []interface{}) []string {
 	}
 	return result
 }
+
+// markRoleClaimsAsStale makes sessions refetch the role and permission claims, for the user or
+// for all users if userId is nil, so that changes are reflected before the claims expire. It is
+// called once the change is done in the core, so errors are only logged: the claims are then
+// refetched when they expire.
+func markRoleClaimsAsStale(userId *string, userContext supertokens.UserContext) {
+	claimKeys := []string{userrolesclaims.UserRoleClaim.Key, userrolesclaims.PermissionClaim.Key}
+	var err error
+	if userId == nil {
+		err = session.MarkClaimsAsStaleForAllUsers(claimKeys, userContext)
+	} else {
+		err = session.MarkClaimsAsStale(*userId, claimKeys, userContext)
+	}
+	if err != nil {
+		supertokens.LogDebugMessage("userroles: could not mark the role claims as stale: " + err.Error())
+	}
+}
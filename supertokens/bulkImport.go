@@ -0,0 +1,583 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package supertokens
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+const (
+	BulkImportHashingAlgorithmBcrypt         = "bcrypt"
+	BulkImportHashingAlgorithmArgon2         = "argon2"
+	BulkImportHashingAlgorithmFirebaseScrypt = "firebase_scrypt"
+)
+
+// BulkImportUser is a user to import. Since users have a single login method in this version of
+// SuperTokens, every user has exactly one.
+type BulkImportUser struct {
+	// ExternalUserId is the ID of the user in the previous identity provider. If set, a user ID
+	// mapping is created so that the user keeps their ID.
+	ExternalUserId     *string `json:"externalUserId,omitempty"`
+	ExternalUserIdInfo *string `json:"externalUserIdInfo,omitempty"`
+	// TenantIds defaults to the public tenant. The user is created in the first tenant and
+	// associated with the others.
+	TenantIds    []string               `json:"tenantIds,omitempty"`
+	LoginMethod  BulkImportLoginMethod  `json:"loginMethod"`
+	UserRoles    []BulkImportUserRole   `json:"userRoles,omitempty"`
+	UserMetadata map[string]interface{} `json:"userMetadata,omitempty"`
+}
+
+type BulkImportLoginMethod struct {
+	// RecipeId is one of "emailpassword", "thirdparty" or "passwordless"
+	RecipeId string  `json:"recipeId"`
+	Email    *string `json:"email,omitempty"`
+	// IsVerified marks the email of emailpassword and thirdparty users as verified
+	IsVerified bool `json:"isVerified,omitempty"`
+	// PasswordHash and HashingAlgorithm are required for emailpassword users. Firebase scrypt
+	// hashes also require the signer key to be set in the core config.
+	PasswordHash     *string `json:"passwordHash,omitempty"`
+	HashingAlgorithm *string `json:"hashingAlgorithm,omitempty"`
+	ThirdPartyId     *string `json:"thirdPartyId,omitempty"`
+	ThirdPartyUserId *string `json:"thirdPartyUserId,omitempty"`
+	PhoneNumber      *string `json:"phoneNumber,omitempty"`
+}
+
+type BulkImportUserRole struct {
+	Role string `json:"role"`
+	// TenantIds defaults to all the tenants of the user
+	TenantIds []string `json:"tenantIds,omitempty"`
+}
+
+type BulkImportRowError struct {
+	// Row is the 1-based position of the user in the input
+	Row            int     `json:"row"`
+	ExternalUserId *string `json:"externalUserId,omitempty"`
+	// SupertokensUserId is set if the user was created before the error happened
+	SupertokensUserId *string  `json:"supertokensUserId,omitempty"`
+	Errors            []string `json:"errors"`
+}
+
+type BulkImportedUser struct {
+	Row               int    `json:"row"`
+	SupertokensUserId string `json:"supertokensUserId"`
+	// UserId is the external user ID if one was set, and the SuperTokens user ID otherwise
+	UserId              string `json:"userId"`
+	DidUserAlreadyExist bool   `json:"didUserAlreadyExist"`
+}
+
+type BulkImportResult struct {
+	ImportedUsers []BulkImportedUser   `json:"importedUsers"`
+	Errors        []BulkImportRowError `json:"errors"`
+}
+
+type BulkImportConfig struct {
+	// BatchSize is the number of users that are imported in parallel. Defaults to 10.
+	BatchSize *int
+	// OnBatchImported is called after every batch, for example to report the progress
+	OnBatchImported func(importedCount int, errorCount int)
+}
+
+// BulkImportUsers imports the users in the JSON lines format (one BulkImportUser per line) from
+// input. All the users are validated first, and nothing is imported if any of them is invalid.
+func BulkImportUsers(input io.Reader, config *BulkImportConfig, userContext ...UserContext) (BulkImportResult, error) {
+	users, rowErrors, err := ParseBulkImportUsers(input)
+	if err != nil {
+		return BulkImportResult{}, err
+	}
+	if len(rowErrors) > 0 {
+		return BulkImportResult{
+			ImportedUsers: []BulkImportedUser{},
+			Errors:        rowErrors,
+		}, nil
+	}
+	return ImportUsers(users, config, userContext...)
+}
+
+// ImportUsers imports users that were already parsed, for example by ParseAuth0Users. All the
+// users are validated first, and nothing is imported if any of them is invalid.
+func ImportUsers(users []BulkImportUser, config *BulkImportConfig, userContext ...UserContext) (BulkImportResult, error) {
+	if len(userContext) == 0 {
+		userContext = append(userContext, &map[string]interface{}{})
+	}
+	batchSize := 10
+	if config != nil && config.BatchSize != nil {
+		if *config.BatchSize <= 0 {
+			return BulkImportResult{}, errors.New("BatchSize must be greater than 0")
+		}
+		batchSize = *config.BatchSize
+	}
+
+	result := BulkImportResult{
+		ImportedUsers: []BulkImportedUser{},
+		Errors:        ValidateBulkImportUsers(users),
+	}
+	if len(result.Errors) > 0 {
+		return result, nil
+	}
+
+	querier, err := GetNewQuerierInstanceOrThrowError("")
+	if err != nil {
+		return BulkImportResult{}, err
+	}
+	importer := &bulkImporter{
+		querier:      querier,
+		createdRoles: map[string]bool{},
+	}
+
+	for start := 0; start < len(users); start += batchSize {
+		end := start + batchSize
+		if end > len(users) {
+			end = len(users)
+		}
+		imported := make([]*BulkImportedUser, end-start)
+		rowErrors := make([]*BulkImportRowError, end-start)
+		var waitGroup sync.WaitGroup
+		for i := start; i < end; i++ {
+			waitGroup.Add(1)
+			go func(i int) {
+				defer waitGroup.Done()
+				importedUser, rowError := importer.importUser(i+1, normaliseBulkImportUser(users[i]), userContext[0])
+				imported[i-start] = importedUser
+				rowErrors[i-start] = rowError
+			}(i)
+		}
+		waitGroup.Wait()
+
+		for i := range imported {
+			if rowErrors[i] != nil {
+				result.Errors = append(result.Errors, *rowErrors[i])
+			} else {
+				result.ImportedUsers = append(result.ImportedUsers, *imported[i])
+			}
+		}
+		LogDebugMessage(fmt.Sprintf("bulk import: imported %d users, %d failed", len(result.ImportedUsers), len(result.Errors)))
+		if config != nil && config.OnBatchImported != nil {
+			config.OnBatchImported(len(result.ImportedUsers), len(result.Errors))
+		}
+	}
+
+	return result, nil
+}
+
+// ParseBulkImportUsers reads users in the JSON lines format. Empty lines are skipped, and rows
+// that cannot be parsed are returned as errors.
+func ParseBulkImportUsers(input io.Reader) ([]BulkImportUser, []BulkImportRowError, error) {
+	users := []BulkImportUser{}
+	rowErrors := []BulkImportRowError{}
+
+	scanner := bufio.NewScanner(input)
+	scanner.Buffer(make([]byte, 64*1024), 10*1024*1024)
+	row := 0
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		row++
+		var user BulkImportUser
+		err := json.Unmarshal([]byte(line), &user)
+		if err != nil {
+			rowErrors = append(rowErrors, BulkImportRowError{
+				Row:    row,
+				Errors: []string{"invalid JSON: " + err.Error()},
+			})
+			// keep the rows of the users in line with the input
+			user = BulkImportUser{}
+		}
+		users = append(users, user)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+	return users, rowErrors, nil
+}
+
+// ValidateBulkImportUsers returns the errors of every invalid user, including the users that
+// appear more than once
+func ValidateBulkImportUsers(users []BulkImportUser) []BulkImportRowError {
+	rowErrors := []BulkImportRowError{}
+	externalUserIdRows := map[string]int{}
+	loginMethodRows := map[string]int{}
+
+	for i, user := range users {
+		user = normaliseBulkImportUser(user)
+		userErrors := validateBulkImportUser(user)
+
+		if user.ExternalUserId != nil {
+			if firstRow, ok := externalUserIdRows[*user.ExternalUserId]; ok {
+				userErrors = append(userErrors, fmt.Sprintf("externalUserId is the same as in row %d", firstRow))
+			} else {
+				externalUserIdRows[*user.ExternalUserId] = i + 1
+			}
+		}
+		if key := getBulkImportLoginMethodKey(user.LoginMethod); key != "" {
+			if firstRow, ok := loginMethodRows[key]; ok {
+				userErrors = append(userErrors, fmt.Sprintf("loginMethod is the same as in row %d", firstRow))
+			} else {
+				loginMethodRows[key] = i + 1
+			}
+		}
+
+		if len(userErrors) > 0 {
+			rowErrors = append(rowErrors, BulkImportRowError{
+				Row:            i + 1,
+				ExternalUserId: user.ExternalUserId,
+				Errors:         userErrors,
+			})
+		}
+	}
+	return rowErrors
+}
+
+func normaliseBulkImportUser(user BulkImportUser) BulkImportUser {
+	if len(user.TenantIds) == 0 {
+		user.TenantIds = []string{DefaultTenantId}
+	}
+	if user.LoginMethod.Email != nil {
+		email := strings.ToLower(strings.TrimSpace(*user.LoginMethod.Email))
+		user.LoginMethod.Email = &email
+	}
+	if user.LoginMethod.HashingAlgorithm != nil {
+		hashingAlgorithm := strings.ToLower(*user.LoginMethod.HashingAlgorithm)
+		user.LoginMethod.HashingAlgorithm = &hashingAlgorithm
+	}
+	userRoles := []BulkImportUserRole{}
+	for _, userRole := range user.UserRoles {
+		if len(userRole.TenantIds) == 0 {
+			userRole.TenantIds = user.TenantIds
+		}
+		userRoles = append(userRoles, userRole)
+	}
+	user.UserRoles = userRoles
+	return user
+}
+
+func validateBulkImportUser(user BulkImportUser) []string {
+	userErrors := []string{}
+	if user.ExternalUserId != nil && strings.TrimSpace(*user.ExternalUserId) == "" {
+		userErrors = append(userErrors, "externalUserId must not be empty")
+	}
+	tenantIds := map[string]bool{}
+	for _, tenantId := range user.TenantIds {
+		if strings.TrimSpace(tenantId) == "" {
+			userErrors = append(userErrors, "tenantIds must not contain empty values")
+		}
+		tenantIds[tenantId] = true
+	}
+
+	loginMethod := user.LoginMethod
+	isEmpty := func(value *string) bool {
+		return value == nil || strings.TrimSpace(*value) == ""
+	}
+	switch loginMethod.RecipeId {
+	case "emailpassword":
+		if isEmpty(loginMethod.Email) || !strings.Contains(*loginMethod.Email, "@") {
+			userErrors = append(userErrors, "a valid email is required for emailpassword users")
+		}
+		if isEmpty(loginMethod.PasswordHash) || loginMethod.HashingAlgorithm == nil {
+			userErrors = append(userErrors, "passwordHash and hashingAlgorithm are required for emailpassword users")
+		} else if message := validateBulkImportPasswordHash(*loginMethod.PasswordHash, *loginMethod.HashingAlgorithm); message != "" {
+			userErrors = append(userErrors, message)
+		}
+	case "thirdparty":
+		if isEmpty(loginMethod.ThirdPartyId) || isEmpty(loginMethod.ThirdPartyUserId) {
+			userErrors = append(userErrors, "thirdPartyId and thirdPartyUserId are required for thirdparty users")
+		}
+		if isEmpty(loginMethod.Email) || !strings.Contains(*loginMethod.Email, "@") {
+			userErrors = append(userErrors, "a valid email is required for thirdparty users")
+		}
+	case "passwordless":
+		if isEmpty(loginMethod.Email) == isEmpty(loginMethod.PhoneNumber) {
+			userErrors = append(userErrors, "either email or phoneNumber is required for passwordless users")
+		} else if !isEmpty(loginMethod.Email) && !strings.Contains(*loginMethod.Email, "@") {
+			userErrors = append(userErrors, "email must be valid")
+		}
+	default:
+		userErrors = append(userErrors, "loginMethod.recipeId must be one of emailpassword, thirdparty or passwordless")
+	}
+
+	for _, userRole := range user.UserRoles {
+		if strings.TrimSpace(userRole.Role) == "" {
+			userErrors = append(userErrors, "userRoles must not contain empty roles")
+			continue
+		}
+		for _, tenantId := range userRole.TenantIds {
+			if !tenantIds[tenantId] {
+				userErrors = append(userErrors, fmt.Sprintf("role %s is given in tenant %s, which the user does not belong to", userRole.Role, tenantId))
+			}
+		}
+	}
+	return userErrors
+}
+
+func validateBulkImportPasswordHash(passwordHash string, hashingAlgorithm string) string {
+	var prefixes []string
+	switch hashingAlgorithm {
+	case BulkImportHashingAlgorithmBcrypt:
+		prefixes = []string{"$2a$", "$2b$", "$2x$", "$2y$"}
+	case BulkImportHashingAlgorithmArgon2:
+		prefixes = []string{"$argon2id$", "$argon2i$", "$argon2d$"}
+	case BulkImportHashingAlgorithmFirebaseScrypt:
+		prefixes = []string{"$f_scrypt$"}
+	default:
+		return "hashingAlgorithm must be one of bcrypt, argon2 or firebase_scrypt"
+	}
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(passwordHash, prefix) {
+			return ""
+		}
+	}
+	return "passwordHash is not a valid " + hashingAlgorithm + " hash"
+}
+
+// getBulkImportLoginMethodKey identifies the login method, to find users that appear more than once
+func getBulkImportLoginMethodKey(loginMethod BulkImportLoginMethod) string {
+	switch loginMethod.RecipeId {
+	case "emailpassword":
+		if loginMethod.Email != nil {
+			return "emailpassword:" + *loginMethod.Email
+		}
+	case "thirdparty":
+		if loginMethod.ThirdPartyId != nil && loginMethod.ThirdPartyUserId != nil {
+			return "thirdparty:" + *loginMethod.ThirdPartyId + ":" + *loginMethod.ThirdPartyUserId
+		}
+	case "passwordless":
+		if loginMethod.Email != nil {
+			return "passwordless:email:" + *loginMethod.Email
+		} else if loginMethod.PhoneNumber != nil {
+			return "passwordless:phone:" + *loginMethod.PhoneNumber
+		}
+	}
+	return ""
+}
+
+type bulkImporter struct {
+	querier *Querier
+
+	createdRolesLock sync.Mutex
+	createdRoles     map[string]bool
+}
+
+func (b *bulkImporter) importUser(row int, user BulkImportUser, userContext UserContext) (*BulkImportedUser, *BulkImportRowError) {
+	makeRowError := func(supertokensUserId *string, err error) *BulkImportRowError {
+		return &BulkImportRowError{
+			Row:               row,
+			ExternalUserId:    user.ExternalUserId,
+			SupertokensUserId: supertokensUserId,
+			Errors:            []string{err.Error()},
+		}
+	}
+
+	supertokensUserId, didUserAlreadyExist, err := b.createUser(user.TenantIds[0], user.LoginMethod, userContext)
+	if err != nil {
+		return nil, makeRowError(nil, err)
+	}
+	err = b.importUserData(supertokensUserId, user, userContext)
+	if err != nil {
+		return nil, makeRowError(&supertokensUserId, err)
+	}
+
+	userId := supertokensUserId
+	if user.ExternalUserId != nil {
+		userId = *user.ExternalUserId
+	}
+	return &BulkImportedUser{
+		Row:                 row,
+		SupertokensUserId:   supertokensUserId,
+		UserId:              userId,
+		DidUserAlreadyExist: didUserAlreadyExist,
+	}, nil
+}
+
+func (b *bulkImporter) createUser(tenantId string, loginMethod BulkImportLoginMethod, userContext UserContext) (string, bool, error) {
+	var response map[string]interface{}
+	var err error
+	switch loginMethod.RecipeId {
+	case "emailpassword":
+		response, err = b.querier.SendPostRequest(tenantId+"/recipe/user/passwordhash/import", map[string]interface{}{
+			"email":            *loginMethod.Email,
+			"passwordHash":     *loginMethod.PasswordHash,
+			"hashingAlgorithm": strings.ToUpper(*loginMethod.HashingAlgorithm),
+		}, userContext)
+		if err == nil && response["status"] == "OK" {
+			didUserAlreadyExist, _ := response["didUserAlreadyExist"].(bool)
+			response["createdNewUser"] = !didUserAlreadyExist
+		}
+	case "thirdparty":
+		response, err = b.querier.SendPostRequest(tenantId+"/recipe/signinup", map[string]interface{}{
+			"thirdPartyId":     *loginMethod.ThirdPartyId,
+			"thirdPartyUserId": *loginMethod.ThirdPartyUserId,
+			"email":            map[string]interface{}{"id": *loginMethod.Email},
+		}, userContext)
+	case "passwordless":
+		// passwordless users are created by consuming a code
+		body := map[string]interface{}{}
+		if loginMethod.Email != nil {
+			body["email"] = *loginMethod.Email
+		} else {
+			body["phoneNumber"] = *loginMethod.PhoneNumber
+		}
+		response, err = b.querier.SendPostRequest(tenantId+"/recipe/signinup/code", body, userContext)
+		if err == nil && response["status"] == "OK" {
+			response, err = b.querier.SendPostRequest(tenantId+"/recipe/signinup/code/consume", map[string]interface{}{
+				"preAuthSessionId": response["preAuthSessionId"],
+				"linkCode":         response["linkCode"],
+			}, userContext)
+		}
+	default:
+		return "", false, errors.New("should never come here")
+	}
+	if err != nil {
+		return "", false, err
+	}
+	if response["status"] != "OK" {
+		return "", false, fmt.Errorf("creating the user failed with %v", response["status"])
+	}
+	user, ok := response["user"].(map[string]interface{})
+	if !ok {
+		return "", false, errors.New("creating the user returned an invalid response")
+	}
+	createdNewUser, _ := response["createdNewUser"].(bool)
+	return user["id"].(string), !createdNewUser, nil
+}
+
+func (b *bulkImporter) importUserData(supertokensUserId string, user BulkImportUser, userContext UserContext) error {
+	for _, tenantId := range user.TenantIds[1:] {
+		response, err := b.querier.SendPostRequest(tenantId+"/recipe/multitenancy/tenant/user", map[string]interface{}{
+			"userId": supertokensUserId,
+		}, userContext)
+		if err != nil {
+			return err
+		}
+		if response["status"] != "OK" {
+			return fmt.Errorf("adding the user to tenant %s failed with %v", tenantId, response["status"])
+		}
+	}
+
+	// the other recipes know the user by the external user ID
+	userId := supertokensUserId
+	if user.ExternalUserId != nil {
+		mappingResult, err := CreateUserIdMapping(supertokensUserId, *user.ExternalUserId, user.ExternalUserIdInfo, nil, userContext)
+		if err != nil {
+			return err
+		}
+		if mappingResult.UserIdMappingAlreadyExistsError != nil {
+			// importing the same user again is allowed
+			userIdType := UserIdTypeSupertokens
+			existing, err := GetUserIdMapping(supertokensUserId, &userIdType, userContext)
+			if err != nil {
+				return err
+			}
+			if existing.OK == nil || existing.OK.ExternalUserId != *user.ExternalUserId {
+				return errors.New("a user ID mapping already exists for the user or the externalUserId")
+			}
+		} else if mappingResult.OK == nil {
+			return errors.New("creating the user ID mapping failed")
+		}
+		userId = *user.ExternalUserId
+	}
+
+	loginMethod := user.LoginMethod
+	if loginMethod.IsVerified && loginMethod.Email != nil && loginMethod.RecipeId != "passwordless" {
+		err := b.verifyEmail(user.TenantIds[0], userId, *loginMethod.Email, userContext)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, userRole := range user.UserRoles {
+		err := b.createRoleIfNeeded(userRole.Role, userContext)
+		if err != nil {
+			return err
+		}
+		for _, tenantId := range userRole.TenantIds {
+			response, err := b.querier.SendPutRequest(tenantId+"/recipe/user/role", map[string]interface{}{
+				"userId": userId,
+				"role":   userRole.Role,
+			}, userContext)
+			if err != nil {
+				return err
+			}
+			if response["status"] != "OK" {
+				return fmt.Errorf("adding role %s failed with %v", userRole.Role, response["status"])
+			}
+		}
+	}
+
+	if len(user.UserMetadata) > 0 {
+		_, err := b.querier.SendPutRequest("/recipe/user/metadata", map[string]interface{}{
+			"userId":         userId,
+			"metadataUpdate": user.UserMetadata,
+		}, userContext)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *bulkImporter) verifyEmail(tenantId string, userId string, email string, userContext UserContext) error {
+	response, err := b.querier.SendPostRequest(tenantId+"/recipe/user/email/verify/token", map[string]interface{}{
+		"userId": userId,
+		"email":  email,
+	}, userContext)
+	if err != nil {
+		return err
+	}
+	if response["status"] == "EMAIL_ALREADY_VERIFIED_ERROR" {
+		return nil
+	}
+	if response["status"] != "OK" {
+		return fmt.Errorf("verifying the email failed with %v", response["status"])
+	}
+	response, err = b.querier.SendPostRequest(tenantId+"/recipe/user/email/verify", map[string]interface{}{
+		"method": "token",
+		"token":  response["token"],
+	}, userContext)
+	if err != nil {
+		return err
+	}
+	if response["status"] != "OK" {
+		return fmt.Errorf("verifying the email failed with %v", response["status"])
+	}
+	return nil
+}
+
+// createRoleIfNeeded creates the role without permissions, which keeps the permissions of roles
+// that already exist
+func (b *bulkImporter) createRoleIfNeeded(role string, userContext UserContext) error {
+	b.createdRolesLock.Lock()
+	defer b.createdRolesLock.Unlock()
+	if b.createdRoles[role] {
+		return nil
+	}
+	_, err := b.querier.SendPutRequest("/recipe/role", map[string]interface{}{
+		"role":        role,
+		"permissions": []string{},
+	}, userContext)
+	if err != nil {
+		return err
+	}
+	b.createdRoles[role] = true
+	return nil
+}
@@ -0,0 +1,333 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package supertokens
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+var defaultAuth0ThirdPartyIds = map[string]string{
+	"google-oauth2": "google",
+	"github":        "github",
+	"facebook":      "facebook",
+	"apple":         "apple",
+	"linkedin":      "linkedin",
+	"twitter":       "twitter",
+	"windowslive":   "active-directory",
+	"bitbucket":     "bitbucket",
+	"gitlab":        "gitlab",
+}
+
+var defaultFirebaseThirdPartyIds = map[string]string{
+	"google.com":    "google",
+	"github.com":    "github",
+	"facebook.com":  "facebook",
+	"apple.com":     "apple",
+	"twitter.com":   "twitter",
+	"microsoft.com": "active-directory",
+}
+
+type Auth0ImportConfig struct {
+	// PasswordHashes maps the emails of database connection users to their bcrypt password
+	// hashes. Auth0 exports the hashes separately, see ParseAuth0PasswordHashes.
+	PasswordHashes map[string]string
+	// ThirdPartyIds maps Auth0 providers (the part of the user ID before the |) to SuperTokens
+	// third party IDs. It is merged with the defaults, for example google-oauth2 to google.
+	ThirdPartyIds map[string]string
+	// TenantIds are the tenants of the imported users. Defaults to the public tenant.
+	TenantIds []string
+}
+
+type FirebaseImportConfig struct {
+	// SaltSeparator, Rounds and MemCost are the password hash parameters, which are shown in the
+	// Firebase console. The signer key must be set in the core config.
+	SaltSeparator string
+	Rounds        int
+	MemCost       int
+	// ThirdPartyIds maps Firebase provider IDs to SuperTokens third party IDs. It is merged with
+	// the defaults, for example google.com to google.
+	ThirdPartyIds map[string]string
+	// TenantIds are the tenants of the imported users. Defaults to the public tenant.
+	TenantIds []string
+}
+
+type auth0User struct {
+	UserId        string                 `json:"user_id"`
+	Email         string                 `json:"email"`
+	EmailVerified bool                   `json:"email_verified"`
+	PhoneNumber   string                 `json:"phone_number"`
+	UserMetadata  map[string]interface{} `json:"user_metadata"`
+	AppMetadata   map[string]interface{} `json:"app_metadata"`
+}
+
+type firebaseUser struct {
+	LocalId          string `json:"localId"`
+	Email            string `json:"email"`
+	EmailVerified    bool   `json:"emailVerified"`
+	PasswordHash     string `json:"passwordHash"`
+	Salt             string `json:"salt"`
+	PhoneNumber      string `json:"phoneNumber"`
+	DisplayName      string `json:"displayName"`
+	PhotoUrl         string `json:"photoUrl"`
+	CustomAttributes string `json:"customAttributes"`
+	ProviderUserInfo []struct {
+		ProviderId string `json:"providerId"`
+		RawId      string `json:"rawId"`
+		Email      string `json:"email"`
+	} `json:"providerUserInfo"`
+}
+
+// ParseAuth0PasswordHashes reads the password hash export of Auth0, which is in the JSON lines
+// format, and returns the hashes by email
+func ParseAuth0PasswordHashes(input io.Reader) (map[string]string, error) {
+	hashes := map[string]string{}
+	scanner := bufio.NewScanner(input)
+	scanner.Buffer(make([]byte, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var row struct {
+			Email        string `json:"email"`
+			PasswordHash string `json:"passwordHash"`
+		}
+		err := json.Unmarshal([]byte(line), &row)
+		if err != nil {
+			return nil, err
+		}
+		if row.Email != "" && row.PasswordHash != "" {
+			hashes[strings.ToLower(strings.TrimSpace(row.Email))] = row.PasswordHash
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return hashes, nil
+}
+
+// ParseAuth0Users reads a user export job of Auth0 in the JSON lines format. The Auth0 user ID
+// is kept as the external user ID. Users that cannot be converted are returned as errors, with
+// the row of the user in the input.
+func ParseAuth0Users(input io.Reader, config Auth0ImportConfig) ([]BulkImportUser, []BulkImportRowError, error) {
+	thirdPartyIds := mergeThirdPartyIds(defaultAuth0ThirdPartyIds, config.ThirdPartyIds)
+	users := []BulkImportUser{}
+	rowErrors := []BulkImportRowError{}
+
+	scanner := bufio.NewScanner(input)
+	scanner.Buffer(make([]byte, 64*1024), 10*1024*1024)
+	row := 0
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		row++
+		var auth0User auth0User
+		err := json.Unmarshal([]byte(line), &auth0User)
+		if err != nil {
+			rowErrors = append(rowErrors, BulkImportRowError{Row: row, Errors: []string{"invalid JSON: " + err.Error()}})
+			continue
+		}
+		user, err := convertAuth0User(auth0User, config, thirdPartyIds)
+		if err != nil {
+			rowErrors = append(rowErrors, BulkImportRowError{Row: row, ExternalUserId: &auth0User.UserId, Errors: []string{err.Error()}})
+			continue
+		}
+		users = append(users, user)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+	return users, rowErrors, nil
+}
+
+func convertAuth0User(auth0User auth0User, config Auth0ImportConfig, thirdPartyIds map[string]string) (BulkImportUser, error) {
+	// Auth0 user IDs are <provider>|<id>, or oauth2|<connection>|<id> for custom social connections
+	parts := strings.SplitN(auth0User.UserId, "|", 2)
+	if len(parts) != 2 {
+		return BulkImportUser{}, fmt.Errorf("unsupported user_id %s", auth0User.UserId)
+	}
+	provider, providerUserId := parts[0], parts[1]
+	if provider == "oauth2" {
+		parts = strings.SplitN(providerUserId, "|", 2)
+		if len(parts) != 2 {
+			return BulkImportUser{}, fmt.Errorf("unsupported user_id %s", auth0User.UserId)
+		}
+		provider, providerUserId = parts[0], parts[1]
+	}
+
+	email := strings.ToLower(strings.TrimSpace(auth0User.Email))
+	loginMethod := BulkImportLoginMethod{
+		IsVerified: auth0User.EmailVerified,
+	}
+	switch provider {
+	case "auth0":
+		passwordHash, ok := config.PasswordHashes[email]
+		if !ok {
+			return BulkImportUser{}, fmt.Errorf("no password hash found for %s", email)
+		}
+		hashingAlgorithm := BulkImportHashingAlgorithmBcrypt
+		loginMethod.RecipeId = "emailpassword"
+		loginMethod.Email = &email
+		loginMethod.PasswordHash = &passwordHash
+		loginMethod.HashingAlgorithm = &hashingAlgorithm
+	case "email":
+		loginMethod.RecipeId = "passwordless"
+		loginMethod.Email = &email
+	case "sms":
+		loginMethod.RecipeId = "passwordless"
+		loginMethod.PhoneNumber = &auth0User.PhoneNumber
+	default:
+		thirdPartyId, ok := thirdPartyIds[provider]
+		if !ok {
+			return BulkImportUser{}, fmt.Errorf("no third party ID is configured for the Auth0 provider %s", provider)
+		}
+		loginMethod.RecipeId = "thirdparty"
+		loginMethod.ThirdPartyId = &thirdPartyId
+		loginMethod.ThirdPartyUserId = &providerUserId
+		loginMethod.Email = &email
+	}
+
+	userMetadata := map[string]interface{}{}
+	for key, value := range auth0User.UserMetadata {
+		userMetadata[key] = value
+	}
+	if len(auth0User.AppMetadata) > 0 {
+		userMetadata["app_metadata"] = auth0User.AppMetadata
+	}
+
+	externalUserId := auth0User.UserId
+	return BulkImportUser{
+		ExternalUserId: &externalUserId,
+		TenantIds:      config.TenantIds,
+		LoginMethod:    loginMethod,
+		UserMetadata:   userMetadata,
+	}, nil
+}
+
+// ParseFirebaseUsers reads the JSON export of "firebase auth:export". The Firebase user ID is
+// kept as the external user ID. Users that signed in with several providers are imported with
+// their password if they have one, and with their first third party provider otherwise.
+func ParseFirebaseUsers(input io.Reader, config FirebaseImportConfig) ([]BulkImportUser, []BulkImportRowError, error) {
+	var export struct {
+		Users []firebaseUser `json:"users"`
+	}
+	err := json.NewDecoder(input).Decode(&export)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	thirdPartyIds := mergeThirdPartyIds(defaultFirebaseThirdPartyIds, config.ThirdPartyIds)
+	users := []BulkImportUser{}
+	rowErrors := []BulkImportRowError{}
+	for i, firebaseUser := range export.Users {
+		user, err := convertFirebaseUser(firebaseUser, config, thirdPartyIds)
+		if err != nil {
+			rowErrors = append(rowErrors, BulkImportRowError{Row: i + 1, ExternalUserId: &export.Users[i].LocalId, Errors: []string{err.Error()}})
+			continue
+		}
+		users = append(users, user)
+	}
+	return users, rowErrors, nil
+}
+
+func convertFirebaseUser(firebaseUser firebaseUser, config FirebaseImportConfig, thirdPartyIds map[string]string) (BulkImportUser, error) {
+	email := strings.ToLower(strings.TrimSpace(firebaseUser.Email))
+	loginMethod := BulkImportLoginMethod{
+		IsVerified: firebaseUser.EmailVerified,
+	}
+
+	if firebaseUser.PasswordHash != "" {
+		passwordHash := fmt.Sprintf("$f_scrypt$%s$%s$m=%d$r=%d$s=%s", firebaseUser.PasswordHash, firebaseUser.Salt, config.MemCost, config.Rounds, config.SaltSeparator)
+		hashingAlgorithm := BulkImportHashingAlgorithmFirebaseScrypt
+		loginMethod.RecipeId = "emailpassword"
+		loginMethod.Email = &email
+		loginMethod.PasswordHash = &passwordHash
+		loginMethod.HashingAlgorithm = &hashingAlgorithm
+	} else {
+		for _, providerInfo := range firebaseUser.ProviderUserInfo {
+			if providerInfo.ProviderId == "password" || providerInfo.ProviderId == "phone" {
+				continue
+			}
+			thirdPartyId, ok := thirdPartyIds[providerInfo.ProviderId]
+			if !ok {
+				return BulkImportUser{}, fmt.Errorf("no third party ID is configured for the Firebase provider %s", providerInfo.ProviderId)
+			}
+			thirdPartyUserId := providerInfo.RawId
+			if email == "" {
+				email = strings.ToLower(strings.TrimSpace(providerInfo.Email))
+			}
+			loginMethod.RecipeId = "thirdparty"
+			loginMethod.ThirdPartyId = &thirdPartyId
+			loginMethod.ThirdPartyUserId = &thirdPartyUserId
+			loginMethod.Email = &email
+			break
+		}
+	}
+	if loginMethod.RecipeId == "" {
+		if firebaseUser.PhoneNumber != "" {
+			loginMethod.RecipeId = "passwordless"
+			loginMethod.PhoneNumber = &firebaseUser.PhoneNumber
+		} else if email != "" {
+			// users that sign in with email links
+			loginMethod.RecipeId = "passwordless"
+			loginMethod.Email = &email
+		} else {
+			return BulkImportUser{}, fmt.Errorf("the user has no supported login method")
+		}
+	}
+
+	userMetadata := map[string]interface{}{}
+	if firebaseUser.DisplayName != "" {
+		userMetadata["displayName"] = firebaseUser.DisplayName
+	}
+	if firebaseUser.PhotoUrl != "" {
+		userMetadata["photoUrl"] = firebaseUser.PhotoUrl
+	}
+	if firebaseUser.CustomAttributes != "" {
+		var customClaims map[string]interface{}
+		err := json.Unmarshal([]byte(firebaseUser.CustomAttributes), &customClaims)
+		if err != nil {
+			return BulkImportUser{}, fmt.Errorf("invalid customAttributes: %s", err.Error())
+		}
+		userMetadata["customClaims"] = customClaims
+	}
+
+	externalUserId := firebaseUser.LocalId
+	return BulkImportUser{
+		ExternalUserId: &externalUserId,
+		TenantIds:      config.TenantIds,
+		LoginMethod:    loginMethod,
+		UserMetadata:   userMetadata,
+	}, nil
+}
+
+func mergeThirdPartyIds(defaults map[string]string, overrides map[string]string) map[string]string {
+	result := map[string]string{}
+	for key, value := range defaults {
+		result[key] = value
+	}
+	for key, value := range overrides {
+		result[key] = value
+	}
+	return result
+}
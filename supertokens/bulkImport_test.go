@@ -0,0 +1,273 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package supertokens
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeCoreRequest struct {
+	Method string
+	Path   string
+	Body   map[string]interface{}
+}
+
+// startFakeCoreForImportTest responds to the core APIs called by the bulk import, and records
+// the requests other than /apiversion
+func startFakeCoreForImportTest(t *testing.T) (*httptest.Server, func() []fakeCoreRequest) {
+	var lock sync.Mutex
+	requests := []fakeCoreRequest{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		if r.URL.Path == "/apiversion" {
+			w.Write([]byte(`{"versions":["3.1"]}`))
+			return
+		}
+		body := map[string]interface{}{}
+		rawBody, err := ioutil.ReadAll(r.Body)
+		assert.NoError(t, err)
+		if len(rawBody) > 0 {
+			assert.NoError(t, json.Unmarshal(rawBody, &body))
+		}
+		lock.Lock()
+		requests = append(requests, fakeCoreRequest{Method: r.Method, Path: r.URL.Path, Body: body})
+		lock.Unlock()
+
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/recipe/user/passwordhash/import"):
+			w.Write([]byte(`{"status":"OK","didUserAlreadyExist":false,"user":{"id":"ep-` + body["email"].(string) + `"}}`))
+		case strings.HasSuffix(r.URL.Path, "/recipe/signinup"):
+			w.Write([]byte(`{"status":"OK","createdNewUser":true,"user":{"id":"tp-` + body["thirdPartyUserId"].(string) + `"}}`))
+		case strings.HasSuffix(r.URL.Path, "/recipe/signinup/code"):
+			w.Write([]byte(`{"status":"OK","preAuthSessionId":"session","linkCode":"link"}`))
+		case strings.HasSuffix(r.URL.Path, "/recipe/signinup/code/consume"):
+			w.Write([]byte(`{"status":"OK","createdNewUser":false,"user":{"id":"pless"}}`))
+		case strings.HasSuffix(r.URL.Path, "/recipe/user/email/verify/token"):
+			w.Write([]byte(`{"status":"OK","token":"token"}`))
+		case r.URL.Path == "/recipe/userid/map" && r.Method == http.MethodPost:
+			if body["externalUserId"] == "taken" {
+				w.Write([]byte(`{"status":"USER_ID_MAPPING_ALREADY_EXISTS_ERROR","doesSuperTokensUserIdExist":false,"doesExternalUserIdExist":true}`))
+			} else {
+				w.Write([]byte(`{"status":"OK"}`))
+			}
+		case r.URL.Path == "/recipe/userid/map":
+			w.Write([]byte(`{"status":"UNKNOWN_MAPPING_ERROR"}`))
+		default:
+			w.Write([]byte(`{"status":"OK"}`))
+		}
+	}))
+	return server, func() []fakeCoreRequest {
+		lock.Lock()
+		defer lock.Unlock()
+		return append([]fakeCoreRequest{}, requests...)
+	}
+}
+
+func initForImportTest(t *testing.T, connectionURI string) {
+	err := Init(TypeInput{
+		Supertokens: &ConnectionInfo{
+			ConnectionURI: connectionURI,
+		},
+		AppInfo: AppInfo{
+			APIDomain:     "api.supertokens.io",
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+		},
+		RecipeList: []Recipe{
+			makeRecipeForExportTest("emailpassword", nil, nil),
+		},
+	})
+	assert.NoError(t, err)
+}
+
+func findFakeCoreRequests(requests []fakeCoreRequest, path string) []fakeCoreRequest {
+	result := []fakeCoreRequest{}
+	for _, request := range requests {
+		if request.Path == path {
+			result = append(result, request)
+		}
+	}
+	return result
+}
+
+func TestValidateBulkImportUsers(t *testing.T) {
+	input := strings.Join([]string{
+		`{"externalUserId":"a","loginMethod":{"recipeId":"emailpassword","email":"A@example.com","passwordHash":"$2a$10$abc","hashingAlgorithm":"BCRYPT"}}`,
+		`{"externalUserId":"a","loginMethod":{"recipeId":"emailpassword","email":"a@example.com","passwordHash":"abc","hashingAlgorithm":"argon2"}}`,
+		``,
+		`{"loginMethod":{"recipeId":"thirdparty","thirdPartyId":"google","email":"b@example.com"}}`,
+		`{"loginMethod":{"recipeId":"passwordless","email":"c@example.com","phoneNumber":"+14155552671"}}`,
+		`{"tenantIds":["t1"],"loginMethod":{"recipeId":"passwordless","phoneNumber":"+14155552671"},"userRoles":[{"role":"admin","tenantIds":["public"]}]}`,
+		`{"loginMethod":{"recipeId":"unknown"}}`,
+		`not json`,
+	}, "\n")
+
+	users, rowErrors, err := ParseBulkImportUsers(strings.NewReader(input))
+	assert.NoError(t, err)
+	assert.Len(t, users, 7)
+	assert.Equal(t, []BulkImportRowError{{Row: 7, Errors: []string{"invalid JSON: invalid character 'o' in literal null (expecting 'u')"}}}, rowErrors)
+
+	rowErrors = ValidateBulkImportUsers(users[:6])
+	assert.Len(t, rowErrors, 5)
+	assert.Equal(t, 2, rowErrors[0].Row)
+	assert.Equal(t, []string{
+		"passwordHash is not a valid argon2 hash",
+		"externalUserId is the same as in row 1",
+		"loginMethod is the same as in row 1",
+	}, rowErrors[0].Errors)
+	assert.Equal(t, []string{"thirdPartyId and thirdPartyUserId are required for thirdparty users"}, rowErrors[1].Errors)
+	assert.Equal(t, []string{"either email or phoneNumber is required for passwordless users"}, rowErrors[2].Errors)
+	assert.Equal(t, []string{"role admin is given in tenant public, which the user does not belong to"}, rowErrors[3].Errors)
+	assert.Equal(t, []string{"loginMethod.recipeId must be one of emailpassword, thirdparty or passwordless"}, rowErrors[4].Errors)
+}
+
+func TestBulkImportUsersImportsNothingIfARowIsInvalid(t *testing.T) {
+	core, getRequests := startFakeCoreForImportTest(t)
+	defer core.Close()
+	defer ResetForTest()
+	initForImportTest(t, core.URL)
+
+	input := `{"loginMethod":{"recipeId":"passwordless","email":"a@example.com"}}
+{"loginMethod":{"recipeId":"passwordless"}}`
+	result, err := BulkImportUsers(strings.NewReader(input), nil)
+	assert.NoError(t, err)
+	assert.Len(t, result.ImportedUsers, 0)
+	assert.Len(t, result.Errors, 1)
+	assert.Equal(t, 2, result.Errors[0].Row)
+	assert.Len(t, getRequests(), 0)
+}
+
+func TestBulkImportUsers(t *testing.T) {
+	core, getRequests := startFakeCoreForImportTest(t)
+	defer core.Close()
+	defer ResetForTest()
+	initForImportTest(t, core.URL)
+
+	input := strings.Join([]string{
+		`{"externalUserId":"auth0|1","tenantIds":["public","t1"],"loginMethod":{"recipeId":"emailpassword","email":"a@example.com","passwordHash":"$argon2id$v=19$m=65536,t=2,p=1$abc$def","hashingAlgorithm":"argon2","isVerified":true},"userRoles":[{"role":"admin"}],"userMetadata":{"theme":"dark"}}`,
+		`{"loginMethod":{"recipeId":"thirdparty","thirdPartyId":"google","thirdPartyUserId":"g1","email":"b@example.com"}}`,
+		`{"externalUserId":"taken","loginMethod":{"recipeId":"passwordless","phoneNumber":"+14155552671"}}`,
+	}, "\n")
+
+	batchSize := 2
+	batches := 0
+	result, err := BulkImportUsers(strings.NewReader(input), &BulkImportConfig{
+		BatchSize: &batchSize,
+		OnBatchImported: func(importedCount int, errorCount int) {
+			batches++
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, batches)
+	assert.Equal(t, []BulkImportedUser{
+		{Row: 1, SupertokensUserId: "ep-a@example.com", UserId: "auth0|1"},
+		{Row: 2, SupertokensUserId: "tp-g1", UserId: "tp-g1"},
+	}, result.ImportedUsers)
+	assert.Len(t, result.Errors, 1)
+	assert.Equal(t, 3, result.Errors[0].Row)
+	assert.Equal(t, "pless", *result.Errors[0].SupertokensUserId)
+	assert.Equal(t, []string{"a user ID mapping already exists for the user or the externalUserId"}, result.Errors[0].Errors)
+
+	requests := getRequests()
+	importRequests := findFakeCoreRequests(requests, "/public/recipe/user/passwordhash/import")
+	assert.Len(t, importRequests, 1)
+	assert.Equal(t, "ARGON2", importRequests[0].Body["hashingAlgorithm"])
+	assert.Len(t, findFakeCoreRequests(requests, "/t1/recipe/multitenancy/tenant/user"), 1)
+
+	// the data of the other recipes uses the external user ID
+	tokenRequests := findFakeCoreRequests(requests, "/public/recipe/user/email/verify/token")
+	assert.Len(t, tokenRequests, 1)
+	assert.Equal(t, "auth0|1", tokenRequests[0].Body["userId"])
+	assert.Len(t, findFakeCoreRequests(requests, "/recipe/role"), 1)
+	assert.Len(t, findFakeCoreRequests(requests, "/public/recipe/user/role"), 1)
+	assert.Len(t, findFakeCoreRequests(requests, "/t1/recipe/user/role"), 1)
+	metadataRequests := findFakeCoreRequests(requests, "/recipe/user/metadata")
+	assert.Len(t, metadataRequests, 1)
+	assert.Equal(t, map[string]interface{}{"userId": "auth0|1", "metadataUpdate": map[string]interface{}{"theme": "dark"}}, metadataRequests[0].Body)
+}
+
+func TestParseAuth0Users(t *testing.T) {
+	hashes, err := ParseAuth0PasswordHashes(strings.NewReader(`{"_id":{"$oid":"1"},"email":"A@example.com","passwordHash":"$2b$10$hash"}`))
+	assert.NoError(t, err)
+
+	input := strings.Join([]string{
+		`{"user_id":"auth0|1","email":"a@example.com","email_verified":true,"user_metadata":{"theme":"dark"},"app_metadata":{"plan":"pro"}}`,
+		`{"user_id":"google-oauth2|2","email":"b@example.com"}`,
+		`{"user_id":"sms|3","phone_number":"+14155552671"}`,
+		`{"user_id":"oauth2|custom|4","email":"d@example.com"}`,
+		`{"user_id":"auth0|5","email":"nohash@example.com"}`,
+	}, "\n")
+	users, rowErrors, err := ParseAuth0Users(strings.NewReader(input), Auth0ImportConfig{
+		PasswordHashes: hashes,
+		ThirdPartyIds:  map[string]string{"custom": "my-provider"},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, users, 4)
+	assert.Len(t, rowErrors, 1)
+	assert.Equal(t, 5, rowErrors[0].Row)
+	assert.Equal(t, "auth0|5", *rowErrors[0].ExternalUserId)
+	assert.Equal(t, []string{"no password hash found for nohash@example.com"}, rowErrors[0].Errors)
+
+	assert.Equal(t, "auth0|1", *users[0].ExternalUserId)
+	assert.Equal(t, "emailpassword", users[0].LoginMethod.RecipeId)
+	assert.Equal(t, "$2b$10$hash", *users[0].LoginMethod.PasswordHash)
+	assert.True(t, users[0].LoginMethod.IsVerified)
+	assert.Equal(t, map[string]interface{}{"theme": "dark", "app_metadata": map[string]interface{}{"plan": "pro"}}, users[0].UserMetadata)
+	assert.Equal(t, "google", *users[1].LoginMethod.ThirdPartyId)
+	assert.Equal(t, "2", *users[1].LoginMethod.ThirdPartyUserId)
+	assert.Equal(t, "+14155552671", *users[2].LoginMethod.PhoneNumber)
+	assert.Equal(t, "my-provider", *users[3].LoginMethod.ThirdPartyId)
+	assert.Equal(t, "4", *users[3].LoginMethod.ThirdPartyUserId)
+
+	assert.Len(t, ValidateBulkImportUsers(users), 0)
+}
+
+func TestParseFirebaseUsers(t *testing.T) {
+	input := `{"users":[
+		{"localId":"1","email":"a@example.com","emailVerified":true,"passwordHash":"aGFzaA==","salt":"c2FsdA==","displayName":"A","customAttributes":"{\"admin\":true}"},
+		{"localId":"2","email":"b@example.com","providerUserInfo":[{"providerId":"google.com","rawId":"g2","email":"b@example.com"}]},
+		{"localId":"3","phoneNumber":"+14155552671","providerUserInfo":[{"providerId":"phone","rawId":"+14155552671"}]},
+		{"localId":"4","providerUserInfo":[{"providerId":"oidc.custom","rawId":"x"}]}
+	]}`
+	users, rowErrors, err := ParseFirebaseUsers(strings.NewReader(input), FirebaseImportConfig{
+		SaltSeparator: "Bw==",
+		Rounds:        8,
+		MemCost:       14,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, users, 3)
+	assert.Len(t, rowErrors, 1)
+	assert.Equal(t, 4, rowErrors[0].Row)
+	assert.Equal(t, []string{"no third party ID is configured for the Firebase provider oidc.custom"}, rowErrors[0].Errors)
+
+	assert.Equal(t, "$f_scrypt$aGFzaA==$c2FsdA==$m=14$r=8$s=Bw==", *users[0].LoginMethod.PasswordHash)
+	assert.Equal(t, map[string]interface{}{"displayName": "A", "customClaims": map[string]interface{}{"admin": true}}, users[0].UserMetadata)
+	assert.Equal(t, "thirdparty", users[1].LoginMethod.RecipeId)
+	assert.Equal(t, "google", *users[1].LoginMethod.ThirdPartyId)
+	assert.Equal(t, "passwordless", users[2].LoginMethod.RecipeId)
+	assert.Equal(t, "3", *users[2].ExternalUserId)
+
+	assert.Len(t, ValidateBulkImportUsers(users), 0)
+}
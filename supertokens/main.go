@@ -16,6 +16,7 @@
 package supertokens
 
 import (
+	"context"
 	"net/http"
 )
 
@@ -60,7 +61,10 @@ func GetAllCORSHeaders() []string {
 	return instance.getAllCORSHeaders()
 }
 
-func GetUserCount(includeRecipeIds *[]string, tenantId *string) (float64, error) {
+func GetUserCount(includeRecipeIds *[]string, tenantId *string, userContext ...UserContext) (float64, error) {
+	if len(userContext) == 0 {
+		userContext = append(userContext, &map[string]interface{}{})
+	}
 	var includeAllTenants *bool
 	if tenantId == nil {
 		defaultTenantId := DefaultTenantId
@@ -68,21 +72,38 @@ func GetUserCount(includeRecipeIds *[]string, tenantId *string) (float64, error)
 		True := true
 		includeAllTenants = &True
 	}
-	return getUserCount(includeRecipeIds, *tenantId, includeAllTenants)
+	return getUserCount(includeRecipeIds, *tenantId, includeAllTenants, userContext[0])
 }
 
-func GetUsersOldestFirst(tenantId string, paginationToken *string, limit *int, includeRecipeIds *[]string, query map[string]string) (UserPaginationResult, error) {
-	return GetUsersWithSearchParams(tenantId, "ASC", paginationToken, limit, includeRecipeIds, query)
+func GetUsersOldestFirst(tenantId string, paginationToken *string, limit *int, includeRecipeIds *[]string, query map[string]string, userContext ...UserContext) (UserPaginationResult, error) {
+	return GetUsersWithSearchParams(tenantId, "ASC", paginationToken, limit, includeRecipeIds, query, userContext...)
 }
 
-func GetUsersNewestFirst(tenantId string, paginationToken *string, limit *int, includeRecipeIds *[]string, query map[string]string) (UserPaginationResult, error) {
-	return GetUsersWithSearchParams(tenantId, "DESC", paginationToken, limit, includeRecipeIds, query)
+func GetUsersNewestFirst(tenantId string, paginationToken *string, limit *int, includeRecipeIds *[]string, query map[string]string, userContext ...UserContext) (UserPaginationResult, error) {
+	return GetUsersWithSearchParams(tenantId, "DESC", paginationToken, limit, includeRecipeIds, query, userContext...)
 }
 
-func DeleteUser(userId string) error {
-	return deleteUser(userId)
+func DeleteUser(userId string, userContext ...UserContext) error {
+	if len(userContext) == 0 {
+		userContext = append(userContext, &map[string]interface{}{})
+	}
+	return deleteUser(userId, userContext[0])
+}
+
+// ExportUserData returns everything that the initialised recipes hold about the user, for example
+// to answer a data access request. userId can be either the SuperTokens or the external user ID.
+// The result can be serialised to JSON.
+func ExportUserData(userId string, userContext ...UserContext) (UserDataExport, error) {
+	if len(userContext) == 0 {
+		userContext = append(userContext, &map[string]interface{}{})
+	}
+	return exportUserData(userId, userContext[0])
 }
 
 func GetRequestFromUserContext(userContext UserContext) *http.Request {
 	return getRequestFromUserContext(userContext)
 }
+
+func GetContextFromUserContext(userContext UserContext) context.Context {
+	return getContextFromUserContext(userContext)
+}
@@ -86,7 +86,7 @@ func (q *Querier) GetQuerierAPIVersion(userContextIn ...UserContext) (string, er
 	queryString := strings.Join(queryParams, "&")
 
 	response, _, err := q.sendRequestHelper(NormalisedURLPath{value: "/apiversion"}, func(url string) (*http.Response, []byte, error) {
-		req, err := http.NewRequest("GET", url, nil)
+		req, err := http.NewRequestWithContext(getContextFromUserContext(userContext), "GET", url, nil)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -181,7 +181,7 @@ func (q *Querier) SendPostRequest(path string, data map[string]interface{}, user
 		if err != nil {
 			return nil, nil, err
 		}
-		req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
+		req, err := http.NewRequestWithContext(getContextFromUserContext(userContext), "POST", url, bytes.NewBuffer(jsonData))
 		if err != nil {
 			return nil, nil, err
 		}
@@ -224,7 +224,7 @@ func (q *Querier) SendDeleteRequest(path string, data map[string]interface{}, pa
 		if err != nil {
 			return nil, nil, err
 		}
-		req, err := http.NewRequest("DELETE", url, bytes.NewBuffer(jsonData))
+		req, err := http.NewRequestWithContext(getContextFromUserContext(userContext), "DELETE", url, bytes.NewBuffer(jsonData))
 		if err != nil {
 			return nil, nil, err
 		}
@@ -269,7 +269,7 @@ func (q *Querier) SendGetRequest(path string, params map[string]string, userCont
 		return nil, err
 	}
 	resp, _, err := q.sendRequestHelper(nP, func(url string) (*http.Response, []byte, error) {
-		req, err := http.NewRequest("GET", url, nil)
+		req, err := http.NewRequestWithContext(getContextFromUserContext(userContext), "GET", url, nil)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -403,7 +403,7 @@ func (q *Querier) SendGetRequestWithResponseHeaders(path string, params map[stri
 	}
 
 	return q.sendRequestHelper(nP, func(url string) (*http.Response, []byte, error) {
-		req, err := http.NewRequest("GET", url, nil)
+		req, err := http.NewRequestWithContext(getContextFromUserContext(userContext), "GET", url, nil)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -450,7 +450,7 @@ func (q *Querier) SendPutRequest(path string, data map[string]interface{}, userC
 		if err != nil {
 			return nil, nil, err
 		}
-		req, err := http.NewRequest("PUT", url, bytes.NewBuffer(jsonData))
+		req, err := http.NewRequestWithContext(getContextFromUserContext(userContext), "PUT", url, bytes.NewBuffer(jsonData))
 		if err != nil {
 			return nil, nil, err
 		}
@@ -31,6 +31,12 @@ type RecipeModule struct {
 	HandleError                   func(err error, req *http.Request, res http.ResponseWriter, userContext UserContext) (bool, error)
 	OnSuperTokensAPIError         func(err error, req *http.Request, res http.ResponseWriter)
 	ResetForTest                  func()
+	// GetTenantIdsForUser is set by the recipes that have login methods, and returns the tenants
+	// that the user belongs to. It is optional.
+	GetTenantIdsForUser func(userId string, userContext UserContext) ([]string, error)
+	// ExportUserData is optional, and is used by ExportUserData to collect everything that is
+	// stored about a user
+	ExportUserData UserDataExporter
 }
 
 func MakeRecipeModule(
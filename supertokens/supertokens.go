@@ -16,6 +16,7 @@
 package supertokens
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"flag"
@@ -358,7 +359,10 @@ type UserPaginationResult struct {
 }
 
 // TODO: Add tests
-func GetUsersWithSearchParams(tenantId string, timeJoinedOrder string, paginationToken *string, limit *int, includeRecipeIds *[]string, searchParams map[string]string) (UserPaginationResult, error) {
+func GetUsersWithSearchParams(tenantId string, timeJoinedOrder string, paginationToken *string, limit *int, includeRecipeIds *[]string, searchParams map[string]string, userContext ...UserContext) (UserPaginationResult, error) {
+	if len(userContext) == 0 {
+		userContext = append(userContext, &map[string]interface{}{})
+	}
 
 	querier, err := GetNewQuerierInstanceOrThrowError("")
 	if err != nil {
@@ -381,7 +385,7 @@ func GetUsersWithSearchParams(tenantId string, timeJoinedOrder string, paginatio
 		requestBody["includeRecipeIds"] = strings.Join((*includeRecipeIds)[:], ",")
 	}
 
-	resp, err := querier.SendGetRequest(tenantId+"/users", requestBody, nil)
+	resp, err := querier.SendGetRequest(tenantId+"/users", requestBody, userContext[0])
 
 	if err != nil {
 		return UserPaginationResult{}, err
@@ -404,7 +408,7 @@ func GetUsersWithSearchParams(tenantId string, timeJoinedOrder string, paginatio
 }
 
 // TODO: Add tests
-func getUserCount(includeRecipeIds *[]string, tenantId string, includeAllTenants *bool) (float64, error) {
+func getUserCount(includeRecipeIds *[]string, tenantId string, includeAllTenants *bool, userContext UserContext) (float64, error) {
 
 	querier, err := GetNewQuerierInstanceOrThrowError("")
 	if err != nil {
@@ -420,7 +424,7 @@ func getUserCount(includeRecipeIds *[]string, tenantId string, includeAllTenants
 		requestBody["includeAllTenants"] = strconv.FormatBool(*includeAllTenants)
 	}
 
-	resp, err := querier.SendGetRequest(tenantId+"/users/count", requestBody, nil)
+	resp, err := querier.SendGetRequest(tenantId+"/users/count", requestBody, userContext)
 
 	if err != nil {
 		return -1, err
@@ -429,13 +433,13 @@ func getUserCount(includeRecipeIds *[]string, tenantId string, includeAllTenants
 	return resp["count"].(float64), nil
 }
 
-func deleteUser(userId string) error {
+func deleteUser(userId string, userContext UserContext) error {
 	querier, err := GetNewQuerierInstanceOrThrowError("")
 	if err != nil {
 		return err
 	}
 
-	cdiVersion, err := querier.GetQuerierAPIVersion()
+	cdiVersion, err := querier.GetQuerierAPIVersion(userContext)
 	if err != nil {
 		return err
 	}
@@ -443,7 +447,7 @@ func deleteUser(userId string) error {
 	if MaxVersion(cdiVersion, "2.10") == cdiVersion {
 		_, err = querier.SendPostRequest("/user/remove", map[string]interface{}{
 			"userId": userId,
-		}, nil)
+		}, userContext)
 
 		if err != nil {
 			return err
@@ -493,3 +497,23 @@ func getRequestFromUserContext(userContext UserContext) *http.Request {
 	}
 	return requestObj
 }
+
+// getContextFromUserContext returns the context set using SetContextInUserContext. If no context
+// was set, the context of the request in the user context is used (this is the case for all
+// APIs exposed by the SDK). context.Background() is returned if neither is present.
+func getContextFromUserContext(userContext UserContext) context.Context {
+	if userContext != nil {
+		if defaultObj, ok := (*userContext)["_default"].(map[string]interface{}); ok {
+			if ctx, ok := defaultObj["context"].(context.Context); ok && ctx != nil {
+				return ctx
+			}
+		}
+	}
+
+	req := getRequestFromUserContext(userContext)
+	if req != nil {
+		return req.Context()
+	}
+
+	return context.Background()
+}
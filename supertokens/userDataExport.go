@@ -0,0 +1,129 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package supertokens
+
+import (
+	"sort"
+	"time"
+)
+
+// UserDataExporter returns the data that a recipe holds about the user, or nil if it has none.
+// tenantIds are the tenants that the user belongs to, as returned by the GetTenantIdsForUser
+// functions of the initialised recipes. The returned value must be serialisable to JSON.
+type UserDataExporter func(userId string, tenantIds []string, userContext UserContext) (interface{}, error)
+
+type UserDataExportUserIdMapping struct {
+	SupertokensUserId  string  `json:"supertokensUserId"`
+	ExternalUserId     string  `json:"externalUserId"`
+	ExternalUserIdInfo *string `json:"externalUserIdInfo,omitempty"`
+}
+
+type UserDataExport struct {
+	UserId string `json:"userId"`
+	// ExportedAt is the time (in milliseconds) at which the export was created
+	ExportedAt    int64                        `json:"exportedAt"`
+	UserIdMapping *UserDataExportUserIdMapping `json:"userIdMapping"`
+	TenantIds     []string                     `json:"tenantIds"`
+	// Recipes has the data returned by the ExportUserData function of every recipe, by recipe ID
+	Recipes map[string]interface{} `json:"recipes"`
+}
+
+func exportUserData(userId string, userContext UserContext) (UserDataExport, error) {
+	instance, err := GetInstanceOrThrowError()
+	if err != nil {
+		return UserDataExport{}, err
+	}
+	result := UserDataExport{
+		UserId:     userId,
+		ExportedAt: time.Now().UnixNano() / int64(time.Millisecond),
+		TenantIds:  []string{},
+		Recipes:    map[string]interface{}{},
+	}
+
+	mapping, err := getUserIdMappingForExport(userId, userContext)
+	if err != nil {
+		return UserDataExport{}, err
+	}
+	if mapping != nil {
+		result.UserIdMapping = mapping
+		// recipes know the user by the external user ID
+		userId = mapping.ExternalUserId
+	}
+
+	tenantIds := map[string]bool{}
+	for _, recipeModule := range instance.RecipeModules {
+		if recipeModule.GetTenantIdsForUser == nil {
+			continue
+		}
+		recipeTenantIds, err := recipeModule.GetTenantIdsForUser(userId, userContext)
+		if err != nil {
+			return UserDataExport{}, err
+		}
+		for _, tenantId := range recipeTenantIds {
+			tenantIds[tenantId] = true
+		}
+	}
+	for tenantId := range tenantIds {
+		result.TenantIds = append(result.TenantIds, tenantId)
+	}
+	sort.Strings(result.TenantIds)
+
+	for _, recipeModule := range instance.RecipeModules {
+		if recipeModule.ExportUserData == nil {
+			continue
+		}
+		data, err := recipeModule.ExportUserData(userId, result.TenantIds, userContext)
+		if err != nil {
+			return UserDataExport{}, err
+		}
+		if data != nil {
+			result.Recipes[recipeModule.GetRecipeID()] = data
+		}
+	}
+
+	return result, nil
+}
+
+// getUserIdMappingForExport returns nil if the user has no user ID mapping, or if the core does
+// not support user ID mappings
+func getUserIdMappingForExport(userId string, userContext UserContext) (*UserDataExportUserIdMapping, error) {
+	querier, err := GetNewQuerierInstanceOrThrowError("")
+	if err != nil {
+		return nil, err
+	}
+	cdiVersion, err := querier.GetQuerierAPIVersion(userContext)
+	if err != nil {
+		return nil, err
+	}
+	if MaxVersion(cdiVersion, "2.15") != cdiVersion {
+		return nil, nil
+	}
+
+	userIdType := UserIdTypeAny
+	response, err := GetUserIdMapping(userId, &userIdType, userContext)
+	if err != nil {
+		return nil, err
+	}
+	if response.OK == nil {
+		return nil, nil
+	}
+	return &UserDataExportUserIdMapping{
+		SupertokensUserId:  response.OK.SupertokensUserId,
+		ExternalUserId:     response.OK.ExternalUserId,
+		ExternalUserIdInfo: response.OK.ExternalUserIdInfo,
+	}, nil
+}
@@ -0,0 +1,112 @@
+/*
+ * Copyright (c) 2022, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package supertokens
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// startFakeCoreForExportTest responds to the core APIs called by ExportUserData. The user
+// "stUserId" is mapped to "externalUserId".
+func startFakeCoreForExportTest() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		if r.URL.Path == "/apiversion" {
+			w.Write([]byte(`{"versions":["3.1"]}`))
+		} else if r.URL.Path == "/recipe/userid/map" {
+			userId := r.URL.Query().Get("userId")
+			if userId == "stUserId" || userId == "externalUserId" {
+				w.Write([]byte(`{"status":"OK","superTokensUserId":"stUserId","externalUserId":"externalUserId"}`))
+			} else {
+				w.Write([]byte(`{"status":"UNKNOWN_MAPPING_ERROR"}`))
+			}
+		} else {
+			w.WriteHeader(404)
+		}
+	}))
+}
+
+func makeRecipeForExportTest(recipeId string, getTenantIdsForUser func(userId string, userContext UserContext) ([]string, error), exportUserData UserDataExporter) Recipe {
+	return func(appInfo NormalisedAppinfo, onSuperTokensAPIError func(err error, req *http.Request, res http.ResponseWriter)) (*RecipeModule, error) {
+		recipeModule := MakeRecipeModule(recipeId, appInfo, nil, func() []string { return []string{} }, func() ([]APIHandled, error) { return []APIHandled{}, nil }, nil, func(err error, req *http.Request, res http.ResponseWriter, userContext UserContext) (bool, error) {
+			return false, nil
+		}, onSuperTokensAPIError)
+		recipeModule.ResetForTest = func() {}
+		recipeModule.GetTenantIdsForUser = getTenantIdsForUser
+		recipeModule.ExportUserData = exportUserData
+		return &recipeModule, nil
+	}
+}
+
+func TestExportUserData(t *testing.T) {
+	core := startFakeCoreForExportTest()
+	defer core.Close()
+	defer ResetForTest()
+
+	exportedUserIds := []string{}
+	err := Init(TypeInput{
+		Supertokens: &ConnectionInfo{
+			ConnectionURI: core.URL,
+		},
+		AppInfo: AppInfo{
+			APIDomain:     "api.supertokens.io",
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+		},
+		RecipeList: []Recipe{
+			makeRecipeForExportTest("login", func(userId string, userContext UserContext) ([]string, error) {
+				return []string{"tenant1", "public"}, nil
+			}, func(userId string, tenantIds []string, userContext UserContext) (interface{}, error) {
+				exportedUserIds = append(exportedUserIds, userId)
+				return map[string]interface{}{"email": "test@example.com"}, nil
+			}),
+			makeRecipeForExportTest("roles", nil, func(userId string, tenantIds []string, userContext UserContext) (interface{}, error) {
+				return map[string]interface{}{"tenantIds": tenantIds}, nil
+			}),
+			makeRecipeForExportTest("empty", nil, func(userId string, tenantIds []string, userContext UserContext) (interface{}, error) {
+				return nil, nil
+			}),
+			makeRecipeForExportTest("noexporter", nil, nil),
+		},
+	})
+	assert.NoError(t, err)
+
+	result, err := ExportUserData("stUserId")
+	assert.NoError(t, err)
+	assert.Equal(t, "stUserId", result.UserId)
+	assert.Equal(t, "externalUserId", result.UserIdMapping.ExternalUserId)
+	assert.Equal(t, []string{"public", "tenant1"}, result.TenantIds)
+	// the recipes are called with the external user ID
+	assert.Equal(t, []string{"externalUserId"}, exportedUserIds)
+
+	jsonResult, err := json.Marshal(result.Recipes)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"login": {"email": "test@example.com"},
+		"roles": {"tenantIds": ["public", "tenant1"]}
+	}`, string(jsonResult))
+
+	result, err = ExportUserData("otherUserId")
+	assert.NoError(t, err)
+	assert.Nil(t, result.UserIdMapping)
+	assert.Equal(t, "otherUserId", exportedUserIds[1])
+}
@@ -21,12 +21,15 @@ type CreateUserIdMappingResult struct {
 	}
 }
 
-func CreateUserIdMapping(supertokensUserId string, externalUserId string, externalUserIdInfo *string, force *bool) (CreateUserIdMappingResult, error) {
+func CreateUserIdMapping(supertokensUserId string, externalUserId string, externalUserIdInfo *string, force *bool, userContext ...UserContext) (CreateUserIdMappingResult, error) {
+	if len(userContext) == 0 {
+		userContext = append(userContext, &map[string]interface{}{})
+	}
 	querier, err := GetNewQuerierInstanceOrThrowError("")
 	if err != nil {
 		return CreateUserIdMappingResult{}, err
 	}
-	cdiVersion, err := querier.GetQuerierAPIVersion()
+	cdiVersion, err := querier.GetQuerierAPIVersion(userContext[0])
 	if err != nil {
 		return CreateUserIdMappingResult{}, err
 	}
@@ -44,7 +47,7 @@ func CreateUserIdMapping(supertokensUserId string, externalUserId string, extern
 	if externalUserIdInfo != nil {
 		data["externalUserIdInfo"] = *externalUserIdInfo
 	}
-	resp, err := querier.SendPostRequest("/recipe/userid/map", data, nil)
+	resp, err := querier.SendPostRequest("/recipe/userid/map", data, userContext[0])
 	if err != nil {
 		return CreateUserIdMappingResult{}, err
 	}
@@ -78,13 +81,15 @@ type GetUserIdMappingResult struct {
 	UnknownMappingError *struct{}
 }
 
-func GetUserIdMapping(userId string, userIdType *UserIdType) (GetUserIdMappingResult, error) {
-
+func GetUserIdMapping(userId string, userIdType *UserIdType, userContext ...UserContext) (GetUserIdMappingResult, error) {
+	if len(userContext) == 0 {
+		userContext = append(userContext, &map[string]interface{}{})
+	}
 	querier, err := GetNewQuerierInstanceOrThrowError("")
 	if err != nil {
 		return GetUserIdMappingResult{}, err
 	}
-	cdiVersion, err := querier.GetQuerierAPIVersion()
+	cdiVersion, err := querier.GetQuerierAPIVersion(userContext[0])
 	if err != nil {
 		return GetUserIdMappingResult{}, err
 	}
@@ -98,7 +103,7 @@ func GetUserIdMapping(userId string, userIdType *UserIdType) (GetUserIdMappingRe
 	if userIdType != nil {
 		data["userIdType"] = string(*userIdType)
 	}
-	resp, err := querier.SendGetRequest("/recipe/userid/map", data, nil)
+	resp, err := querier.SendGetRequest("/recipe/userid/map", data, userContext[0])
 	if err != nil {
 		return GetUserIdMappingResult{}, err
 	}
@@ -131,12 +136,15 @@ type DeleteUserIdMappingResult struct {
 	}
 }
 
-func DeleteUserIdMapping(userId string, userIdType *UserIdType, force *bool) (DeleteUserIdMappingResult, error) {
+func DeleteUserIdMapping(userId string, userIdType *UserIdType, force *bool, userContext ...UserContext) (DeleteUserIdMappingResult, error) {
+	if len(userContext) == 0 {
+		userContext = append(userContext, &map[string]interface{}{})
+	}
 	querier, err := GetNewQuerierInstanceOrThrowError("")
 	if err != nil {
 		return DeleteUserIdMappingResult{}, err
 	}
-	cdiVersion, err := querier.GetQuerierAPIVersion()
+	cdiVersion, err := querier.GetQuerierAPIVersion(userContext[0])
 	if err != nil {
 		return DeleteUserIdMappingResult{}, err
 	}
@@ -153,7 +161,7 @@ func DeleteUserIdMapping(userId string, userIdType *UserIdType, force *bool) (De
 	if force != nil {
 		data["force"] = *force
 	}
-	resp, err := querier.SendPostRequest("/recipe/userid/map/remove", data, nil)
+	resp, err := querier.SendPostRequest("/recipe/userid/map/remove", data, userContext[0])
 	if err != nil {
 		return DeleteUserIdMappingResult{}, err
 	}
@@ -169,12 +177,15 @@ type UpdateOrDeleteUserIdMappingInfoResult struct {
 	UnknownMappingError *struct{}
 }
 
-func UpdateOrDeleteUserIdMappingInfo(userId string, userIdType *UserIdType, externalUserIdInfo *string) (UpdateOrDeleteUserIdMappingInfoResult, error) {
+func UpdateOrDeleteUserIdMappingInfo(userId string, userIdType *UserIdType, externalUserIdInfo *string, userContext ...UserContext) (UpdateOrDeleteUserIdMappingInfoResult, error) {
+	if len(userContext) == 0 {
+		userContext = append(userContext, &map[string]interface{}{})
+	}
 	querier, err := GetNewQuerierInstanceOrThrowError("")
 	if err != nil {
 		return UpdateOrDeleteUserIdMappingInfoResult{}, err
 	}
-	cdiVersion, err := querier.GetQuerierAPIVersion()
+	cdiVersion, err := querier.GetQuerierAPIVersion(userContext[0])
 	if err != nil {
 		return UpdateOrDeleteUserIdMappingInfoResult{}, err
 	}
@@ -190,7 +201,7 @@ func UpdateOrDeleteUserIdMappingInfo(userId string, userIdType *UserIdType, exte
 		data["userIdType"] = string(*userIdType)
 	}
 
-	resp, err := querier.SendPutRequest("/recipe/userid/external-user-id-info", data, nil)
+	resp, err := querier.SendPutRequest("/recipe/userid/external-user-id-info", data, userContext[0])
 	if err != nil {
 		return UpdateOrDeleteUserIdMappingInfoResult{}, err
 	}
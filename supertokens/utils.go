@@ -17,6 +17,7 @@ package supertokens
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -328,6 +329,30 @@ func SetRequestInUserContextIfNotDefined(userContext *map[string]interface{}, r
 	return &_userContext
 }
 
+// SetContextInUserContext attaches ctx to the user context. All requests made to the SuperTokens
+// core using this user context will be bound to ctx, so cancellation, deadlines and any values
+// used for tracing are propagated to them.
+func SetContextInUserContext(userContext UserContext, ctx context.Context) UserContext {
+	if userContext == nil {
+		userContext = &map[string]interface{}{}
+	}
+
+	defaultObj, ok := (*userContext)["_default"].(map[string]interface{})
+	if !ok {
+		defaultObj = map[string]interface{}{}
+	}
+	defaultObj["context"] = ctx
+	(*userContext)["_default"] = defaultObj
+
+	return userContext
+}
+
+// MakeUserContextFromContext creates a new user context bound to ctx. It can be passed to any
+// recipe function to propagate ctx to the requests made to the SuperTokens core.
+func MakeUserContextFromContext(ctx context.Context) UserContext {
+	return SetContextInUserContext(nil, ctx)
+}
+
 func GetTopLevelDomainForSameSiteResolution(URL string) (string, error) {
 	urlObj, err := url.Parse(URL)
 	if err != nil {